@@ -0,0 +1,181 @@
+// Package alerts checks disk usage rules, such as "~/Downloads stays below
+// 20G" or "node_modules directories total at most 50G", and reports the
+// rules that are broken, e.g. through a hook command.
+package alerts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strconv"
+
+	"github.com/kaczmarekdaniel/folder-search/internal/dirsearch"
+	"github.com/kaczmarekdaniel/folder-search/internal/hook"
+	"github.com/kaczmarekdaniel/folder-search/internal/sizefmt"
+)
+
+// Rule limits the size of a directory, or the total size of the
+// directories with some name.
+type Rule struct {
+	// Name identifies the rule in alerts; empty uses Path or Pattern
+	Name string `json:"name"`
+
+	// Path is the directory whose total size is limited
+	Path string `json:"path"`
+
+	// Pattern, used instead of Path, is a directory name or shell pattern
+	// such as "node_modules"; the total size of all directories matching
+	// it below the checked root is limited
+	Pattern string `json:"pattern"`
+
+	// MaxSize is the size that must not be exceeded, such as "20G"; see
+	// dirsearch.ParseSize
+	MaxSize string `json:"max_size"`
+}
+
+// Validate checks that r sets either Path or Pattern, and a valid MaxSize.
+func (r Rule) Validate() error {
+	if (r.Path == "") == (r.Pattern == "") {
+		return fmt.Errorf("alert rule %q: set either path or pattern", r.label())
+	}
+	if _, err := filepath.Match(r.Pattern, ""); err != nil {
+		return fmt.Errorf("alert rule %q: invalid pattern: %w", r.label(), err)
+	}
+	if r.MaxSize == "" {
+		return fmt.Errorf("alert rule %q: max_size is required", r.label())
+	}
+	if _, err := dirsearch.ParseSize(r.MaxSize); err != nil {
+		return fmt.Errorf("alert rule %q: %w", r.label(), err)
+	}
+	return nil
+}
+
+// label returns the name identifying r.
+func (r Rule) label() string {
+	switch {
+	case r.Name != "":
+		return r.Name
+	case r.Path != "":
+		return r.Path
+	}
+	return r.Pattern
+}
+
+// Alert reports a broken rule.
+type Alert struct {
+	Rule Rule
+
+	// Path is the directory measured, or for pattern rules the root below
+	// which matching directories were totalled
+	Path string
+
+	// Size is the size measured and Limit the MaxSize of the rule, in bytes
+	Size, Limit int64
+
+	// Matches is the number of directories a pattern rule totalled
+	Matches int
+}
+
+// Message describes a in one line with sizes in units, e.g. "Downloads:
+// /home/me/Downloads holds 23.4 GiB, over the limit of 20.0 GiB".
+func (a Alert) Message(units sizefmt.Units) string {
+	if a.Rule.Pattern != "" {
+		return fmt.Sprintf("%s: %d %s directories under %s hold %s, over the limit of %s",
+			a.Rule.label(), a.Matches, a.Rule.Pattern, a.Path, units.Format(a.Size), units.Format(a.Limit))
+	}
+	return fmt.Sprintf("%s: %s holds %s, over the limit of %s",
+		a.Rule.label(), a.Path, units.Format(a.Size), units.Format(a.Limit))
+}
+
+// Env returns the hook environment describing a: hook.MessageVar and
+// hook.EventVar, FOLDER_SEARCH_RULE, FOLDER_SEARCH_PATH, and
+// FOLDER_SEARCH_SIZE and FOLDER_SEARCH_LIMIT in bytes.
+func (a Alert) Env(units sizefmt.Units) map[string]string {
+	return map[string]string{
+		hook.MessageVar:       a.Message(units),
+		hook.EventVar:         "alert",
+		"FOLDER_SEARCH_RULE":  a.Rule.label(),
+		"FOLDER_SEARCH_PATH":  a.Path,
+		"FOLDER_SEARCH_SIZE":  strconv.FormatInt(a.Size, 10),
+		"FOLDER_SEARCH_LIMIT": strconv.FormatInt(a.Limit, 10),
+	}
+}
+
+// Check measures every rule and returns the alerts of those that are
+// broken, in the order of rules.
+//
+// Parameters:
+//   - ctx: stops measuring early
+//   - rules: the rules, already validated
+//   - root: the directory below which pattern rules look for directories
+//
+// Returns the alerts and an error joining the errors of the rules that
+// could not be checked, such as a Path that does not exist; the other
+// rules are still checked.
+func Check(ctx context.Context, rules []Rule, root string) ([]Alert, error) {
+	var alerts []Alert
+	var errs []error
+	for _, r := range rules {
+		limit, err := dirsearch.ParseSize(r.MaxSize)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("alert rule %q: %w", r.label(), err))
+			continue
+		}
+
+		a := Alert{Rule: r, Path: r.Path, Limit: limit}
+		if r.Pattern != "" {
+			a.Path = root
+			a.Size, a.Matches, err = patternSize(ctx, root, r.Pattern)
+		} else {
+			a.Size, err = dirsearch.DirSize(ctx, r.Path, nil)
+		}
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return alerts, ctxErr
+			}
+			errs = append(errs, fmt.Errorf("alert rule %q: %w", r.label(), err))
+			continue
+		}
+		if a.Size > a.Limit {
+			alerts = append(alerts, a)
+		}
+	}
+	return alerts, errors.Join(errs...)
+}
+
+// patternSize returns the total size of the directories below root whose
+// names match pattern and how many there are. Matching directories are
+// measured as a whole, so nested matches are not counted twice, and
+// directories that cannot be read are skipped.
+func patternSize(ctx context.Context, root, pattern string) (int64, int, error) {
+	var total int64
+	matches := 0
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err != nil {
+			if path == root {
+				return err
+			}
+			return nil
+		}
+		if !d.IsDir() || path == root {
+			return nil
+		}
+		if ok, _ := filepath.Match(pattern, d.Name()); !ok {
+			return nil
+		}
+
+		size, err := dirsearch.DirSize(ctx, path, nil)
+		if err != nil {
+			return err
+		}
+		total += size
+		matches++
+		return filepath.SkipDir
+	})
+	return total, matches, err
+}
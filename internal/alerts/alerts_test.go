@@ -0,0 +1,94 @@
+package alerts
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kaczmarekdaniel/folder-search/internal/hook"
+	"github.com/kaczmarekdaniel/folder-search/internal/sizefmt"
+)
+
+func TestCheck(t *testing.T) {
+	root, err := os.MkdirTemp("", "alerts-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(root)
+
+	for path, size := range map[string]int{
+		"downloads/big.iso":                    3000,
+		"a/node_modules/x.js":                  1000,
+		"b/node_modules/y.js":                  1000,
+		"b/node_modules/dep/node_modules/z.js": 500,
+		"c/small/file.txt":                     10,
+	} {
+		full := filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(full, make([]byte, size), 0o644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+	}
+
+	rules := []Rule{
+		{Name: "downloads", Path: filepath.Join(root, "downloads"), MaxSize: "2K"},
+		{Pattern: "node_modules", MaxSize: "2K"},
+		{Path: filepath.Join(root, "c"), MaxSize: "1K"},
+		{Pattern: "vendor", MaxSize: "1"},
+	}
+	found, err := Check(context.Background(), rules, root)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(found) != 2 {
+		t.Fatalf("expected 2 alerts, got %d: %v", len(found), found)
+	}
+	if found[0].Rule.Name != "downloads" || found[0].Size < 3000 {
+		t.Errorf("expected the downloads alert of at least 3000 bytes, got %+v", found[0])
+	}
+	if found[1].Matches != 2 || found[1].Size < 2500 || found[1].Path != root {
+		t.Errorf("expected 2 node_modules totalling at least 2500 bytes, got %+v", found[1])
+	}
+
+	env := found[0].Env(sizefmt.Binary)
+	if env[hook.EventVar] != "alert" || env["FOLDER_SEARCH_LIMIT"] != "2048" {
+		t.Errorf("unexpected hook environment %v", env)
+	}
+	if msg := found[1].Message(sizefmt.Binary); !strings.Contains(msg, "2 node_modules directories") {
+		t.Errorf("expected the message to count the directories, got %q", msg)
+	}
+
+	_, err = Check(context.Background(), []Rule{{Path: filepath.Join(root, "missing"), MaxSize: "1G"}}, root)
+	if err == nil {
+		t.Error("expected error for a missing path, got nil")
+	}
+}
+
+func TestRuleValidate(t *testing.T) {
+	valid := []Rule{
+		{Path: "/data", MaxSize: "20G"},
+		{Pattern: "node_modules", MaxSize: "50G"},
+	}
+	for _, r := range valid {
+		if err := r.Validate(); err != nil {
+			t.Errorf("unexpected error for %+v: %v", r, err)
+		}
+	}
+
+	invalid := []Rule{
+		{MaxSize: "1G"},
+		{Path: "/data", Pattern: "data", MaxSize: "1G"},
+		{Pattern: "[", MaxSize: "1G"},
+		{Path: "/data"},
+		{Path: "/data", MaxSize: "big"},
+	}
+	for _, r := range invalid {
+		if err := r.Validate(); err == nil {
+			t.Errorf("expected error for %+v, got nil", r)
+		}
+	}
+}
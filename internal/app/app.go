@@ -5,12 +5,27 @@
 package app
 
 import (
+	"fmt"
+	"io"
 	"log/slog"
 	"os"
 
+	"github.com/kaczmarekdaniel/folder-search/internal/config"
 	"github.com/kaczmarekdaniel/folder-search/internal/dirsearch"
+	"github.com/kaczmarekdaniel/folder-search/internal/features"
+	"github.com/kaczmarekdaniel/folder-search/internal/jobs"
+	"github.com/kaczmarekdaniel/folder-search/internal/logging"
+	"github.com/kaczmarekdaniel/folder-search/internal/notes"
+	"github.com/kaczmarekdaniel/folder-search/internal/pins"
+	"github.com/kaczmarekdaniel/folder-search/internal/scanhistory"
+	"github.com/kaczmarekdaniel/folder-search/internal/searchhistory"
+	"github.com/kaczmarekdaniel/folder-search/internal/stats"
+	"github.com/kaczmarekdaniel/folder-search/internal/tags"
 )
 
+// jobWorkers is the number of background jobs allowed to run concurrently.
+const jobWorkers = 2
+
 // Application represents the core application structure that holds
 // references to all major components including directory search and logging.
 type Application struct {
@@ -19,28 +34,261 @@ type Application struct {
 
 	// Logger provides structured logging throughout the application
 	Logger *slog.Logger
+
+	// Jobs runs long-running operations (size calculation, etc.) in the background
+	Jobs *jobs.Queue
+
+	// Config holds the user configuration
+	Config *config.Config
+
+	// ScanHistory remembers slow directories so they can be scanned progressively
+	ScanHistory *scanhistory.History
+
+	// SearchHistory records past search patterns and selected directories
+	SearchHistory *searchhistory.History
+
+	// Stats records local usage statistics
+	Stats *stats.Stats
+
+	// Pins holds the directories pinned to the top of their parent
+	Pins *pins.Pins
+
+	// Notes holds the notes attached to directories
+	Notes *notes.Notes
+
+	// Tags holds the user-defined tags of directories
+	Tags *tags.Tags
+
+	// Logs keeps the latest log records of the session for bug reports
+	Logs *logging.Ring
+
+	// Features holds which features are turned on
+	Features features.Set
+
+	// logFile releases the log file, if the log is written to one
+	logFile io.Closer
 }
 
 // NewApplication creates and initializes a new Application instance with default configuration.
 //
 // It sets up:
-//   - A structured logger using slog with INFO level output to stderr
-//   - A directory search instance with default options
+//   - The user configuration, falling back to defaults if no config file exists,
+//     and the features it turns on or off
+//   - A structured logger using slog as configured, by default with INFO level
+//     text output to stderr; a log file that cannot be opened falls back to it.
+//     The latest records are also kept in memory for bug reports
+//   - A directory search instance with default options, logging as the
+//     dirsearch module
+//   - A background job queue
+//   - The scan and search histories, usage statistics, pins, notes and tags, starting empty if they are missing or unreadable
 //
-// Returns an error if initialization fails (currently always returns nil error).
+// Returns an error if the config file exists but cannot be read or parsed.
 func NewApplication() (*Application, error) {
-	// Create structured logger
-	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
-		Level: slog.LevelInfo,
-	}))
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	ring := logging.NewRing(logging.DefaultRingSize)
+	logger, logFile := newLogger(cfg.Log, ring)
+	for _, change := range cfg.Migrations {
+		logger.Warn("config migrated", "version", cfg.Version, "change", change)
+	}
+	flags, unknown := features.New(cfg.Experimental)
+	for _, name := range unknown {
+		logger.Warn("ignoring unknown experimental feature", "feature", name)
+	}
 
 	searchDir := dirsearch.NewDirSearch()
+	searchDir.Logger = logging.Module(logger, logging.ModuleDirsearch)
+	searchDir.Options.Fuzzy = cfg.FuzzyQuery
+	searchDir.Options.IgnorePatterns = append(searchDir.Options.IgnorePatterns, cfg.Ignore...)
+	history := loadScanHistory(logger)
+	searches := loadSearchHistory(logger)
+	usage := loadStats(logger)
+	pinned := loadPins(logger)
+	annotations := loadNotes(logger)
+	tagged := loadTags(logger)
 
 	app := &Application{
-		Dirsearch: searchDir,
-		Logger:    logger,
+		Dirsearch:     searchDir,
+		Logger:        logger,
+		Jobs:          jobs.NewQueue(jobWorkers),
+		Config:        cfg,
+		ScanHistory:   history,
+		SearchHistory: searches,
+		Stats:         usage,
+		Pins:          pinned,
+		Notes:         annotations,
+		Tags:          tagged,
+		Logs:          ring,
+		Features:      flags,
+		logFile:       logFile,
 	}
 
 	logger.Info("application initialized")
 	return app, nil
 }
+
+// Close releases resources held by the application, canceling any
+// background jobs that are still running, saving the scan and search
+// histories, usage statistics, pins, notes and tags, and closing the log
+// file.
+func (a *Application) Close() {
+	a.Jobs.Close()
+	if err := a.ScanHistory.Save(); err != nil {
+		a.Logger.Warn("failed to save scan history", "error", err)
+	}
+	if err := a.SearchHistory.Save(); err != nil {
+		a.Logger.Warn("failed to save search history", "error", err)
+	}
+	if err := a.Stats.Save(); err != nil {
+		a.Logger.Warn("failed to save usage statistics", "error", err)
+	}
+	if err := a.Pins.Save(); err != nil {
+		a.Logger.Warn("failed to save pins", "error", err)
+	}
+	if err := a.Notes.Save(); err != nil {
+		a.Logger.Warn("failed to save notes", "error", err)
+	}
+	if err := a.Tags.Save(); err != nil {
+		a.Logger.Warn("failed to save tags", "error", err)
+	}
+	if a.logFile != nil {
+		a.logFile.Close()
+	}
+}
+
+// ModuleLogger returns the logger of the named module, one of the
+// logging.Module constants, whose level can be set in the config file.
+func (a *Application) ModuleLogger(module string) *slog.Logger {
+	return logging.Module(a.Logger, module)
+}
+
+// newLogger builds the logger described by the log configuration, also
+// keeping its records in ring. If the log file cannot be opened, the log is
+// written to stderr instead and the failure is logged there.
+func newLogger(cfg config.LogConfig, ring *logging.Ring) (*slog.Logger, io.Closer) {
+	// The configuration was validated when it was loaded
+	opts, _ := cfg.Options()
+	opts.Ring = ring
+	logger, closer, err := logging.New(opts, os.Stderr)
+	if err == nil {
+		return logger, closer
+	}
+
+	opts.File = ""
+	logger, closer, _ = logging.New(opts, os.Stderr)
+	logger.Warn("cannot open log file, logging to stderr", "file", cfg.File, "error", err)
+	return logger, closer
+}
+
+// loadScanHistory loads the scan history from the user cache directory.
+// The history is only an optimization, so failures are logged and an empty
+// history is used instead, kept in memory when the file cannot be read.
+func loadScanHistory(logger *slog.Logger) *scanhistory.History {
+	path, err := scanhistory.DefaultPath()
+	if err != nil {
+		logger.Warn("scan history disabled", "error", err)
+		return scanhistory.New("", scanhistory.DefaultThreshold)
+	}
+
+	history, err := scanhistory.Load(path, scanhistory.DefaultThreshold)
+	if err != nil {
+		// Saving would overwrite the file, which may be repairable or
+		// written by a newer version
+		logger.Warn("ignoring unreadable scan history, changes will not be saved", "error", err)
+		return scanhistory.New("", scanhistory.DefaultThreshold)
+	}
+	return history
+}
+
+// loadSearchHistory loads the search history from the user data directory.
+// Failures are logged and an empty history is used instead, kept in memory
+// when the file cannot be read.
+func loadSearchHistory(logger *slog.Logger) *searchhistory.History {
+	path, err := searchhistory.DefaultPath()
+	if err != nil {
+		logger.Warn("search history disabled", "error", err)
+		return searchhistory.New("")
+	}
+
+	h, err := searchhistory.Load(path)
+	if err != nil {
+		logger.Warn("ignoring unreadable search history, changes will not be saved", "error", err)
+		return searchhistory.New("")
+	}
+	return h
+}
+
+// loadStats loads the usage statistics from the user data directory.
+// Failures are logged and empty statistics are used instead, so a damaged
+// file never prevents the application from starting. A damaged file is left
+// untouched: the statistics are then kept in memory.
+func loadStats(logger *slog.Logger) *stats.Stats {
+	path, err := stats.DefaultPath()
+	if err != nil {
+		logger.Warn("usage statistics disabled", "error", err)
+		return stats.New("")
+	}
+
+	s, err := stats.Load(path)
+	if err != nil {
+		logger.Warn("ignoring unreadable usage statistics, changes will not be saved", "error", err)
+		return stats.New("")
+	}
+	return s
+}
+
+// loadPins loads the pinned directories from the user data directory.
+// Failures are logged and no pins are used instead; when the file cannot
+// be read, changes are kept in memory so the file is left untouched.
+func loadPins(logger *slog.Logger) *pins.Pins {
+	path, err := pins.DefaultPath()
+	if err != nil {
+		logger.Warn("pins disabled", "error", err)
+		return pins.New("")
+	}
+
+	p, err := pins.Load(path)
+	if err != nil {
+		logger.Warn("ignoring unreadable pins, changes will not be saved", "error", err)
+		return pins.New("")
+	}
+	return p
+}
+
+// loadNotes loads the directory notes from the user data directory.
+// Failures are logged and no notes are used instead; when the file cannot
+// be read, changes are kept in memory so the file is left untouched.
+func loadNotes(logger *slog.Logger) *notes.Notes {
+	path, err := notes.DefaultPath()
+	if err != nil {
+		logger.Warn("notes disabled", "error", err)
+		return notes.New("")
+	}
+
+	n, err := notes.Load(path)
+	if err != nil {
+		logger.Warn("ignoring unreadable notes, changes will not be saved", "error", err)
+		return notes.New("")
+	}
+	return n
+}
+
+// loadTags loads the directory tags from the user data directory.
+// Failures are logged and no tags are used instead; when the file cannot
+// be read, changes are kept in memory so the file is left untouched.
+func loadTags(logger *slog.Logger) *tags.Tags {
+	path, err := tags.DefaultPath()
+	if err != nil {
+		logger.Warn("tags disabled", "error", err)
+		return tags.New("")
+	}
+
+	t, err := tags.Load(path)
+	if err != nil {
+		logger.Warn("ignoring unreadable tags, changes will not be saved", "error", err)
+		return tags.New("")
+	}
+	return t
+}
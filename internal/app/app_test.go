@@ -1,7 +1,15 @@
 package app
 
 import (
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
 	"testing"
+
+	"github.com/kaczmarekdaniel/folder-search/internal/config"
+	"github.com/kaczmarekdaniel/folder-search/internal/logging"
+	"github.com/kaczmarekdaniel/folder-search/internal/notes"
 )
 
 func TestNewApplication(t *testing.T) {
@@ -23,6 +31,38 @@ func TestNewApplication(t *testing.T) {
 		t.Error("expected Logger to be initialized, got nil")
 	}
 
+	if app.Jobs == nil {
+		t.Error("expected Jobs to be initialized, got nil")
+	}
+
+	if app.Config == nil {
+		t.Error("expected Config to be initialized, got nil")
+	}
+
+	if app.ScanHistory == nil {
+		t.Error("expected ScanHistory to be initialized, got nil")
+	}
+
+	if app.SearchHistory == nil {
+		t.Error("expected SearchHistory to be initialized, got nil")
+	}
+
+	if app.Stats == nil {
+		t.Error("expected Stats to be initialized, got nil")
+	}
+
+	if app.Pins == nil {
+		t.Error("expected Pins to be initialized, got nil")
+	}
+
+	if app.Notes == nil {
+		t.Error("expected Notes to be initialized, got nil")
+	}
+
+	if app.Tags == nil {
+		t.Error("expected Tags to be initialized, got nil")
+	}
+
 	// Verify Dirsearch has default options
 	if app.Dirsearch.Options == nil {
 		t.Error("expected Dirsearch.Options to be initialized, got nil")
@@ -69,3 +109,72 @@ func TestApplicationComponents(t *testing.T) {
 		}
 	})
 }
+
+func TestNewLogger_UnwritableFile(t *testing.T) {
+	dir, err := os.MkdirTemp("", "app-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// A directory cannot be opened as the log file
+	logger, closer := newLogger(config.LogConfig{File: dir}, logging.NewRing(1))
+	if logger == nil || closer == nil {
+		t.Fatal("expected a logger writing to stderr, got nil")
+	}
+	if err := closer.Close(); err != nil {
+		t.Errorf("unexpected error closing: %v", err)
+	}
+}
+
+func TestModuleLogger(t *testing.T) {
+	app, err := NewApplication()
+	if err != nil {
+		t.Fatalf("unexpected error creating application: %v", err)
+	}
+
+	if logger := app.ModuleLogger(logging.ModuleUI); logger == nil {
+		t.Error("expected a module logger, got nil")
+	}
+	if app.Dirsearch.Logger == nil {
+		t.Error("expected Dirsearch.Logger to be initialized, got nil")
+	}
+	if records := app.Logs.Records(); len(records) == 0 {
+		t.Error("expected startup records in Logs, got none")
+	}
+}
+
+func TestLoadNotes_CorruptFileKept(t *testing.T) {
+	dataHome, err := os.MkdirTemp("", "app-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dataHome)
+	t.Setenv("XDG_DATA_HOME", dataHome)
+
+	path, err := notes.DefaultPath()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("failed to create data dir: %v", err)
+	}
+	const corrupt = `{"version": 1, "notes": {"/src": "keep me"`
+	if err := os.WriteFile(path, []byte(corrupt), 0644); err != nil {
+		t.Fatalf("failed to write notes: %v", err)
+	}
+
+	n := loadNotes(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	n.Set("/tmp", "new note")
+	if err := n.Save(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read notes: %v", err)
+	}
+	if string(data) != corrupt {
+		t.Errorf("expected the unreadable notes file to be kept, got %q", data)
+	}
+}
@@ -0,0 +1,56 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// OutputMode controls how the selected directory is printed when the
+// application exits.
+type OutputMode string
+
+const (
+	// OutputAbsolute prints the absolute path of the selected directory.
+	OutputAbsolute OutputMode = "abs"
+
+	// OutputRelative prints the path relative to the directory the
+	// application was started in.
+	OutputRelative OutputMode = "rel"
+
+	// OutputName prints only the base name of the selected directory.
+	OutputName OutputMode = "name"
+)
+
+// ParseOutputMode converts a command-line value into an OutputMode.
+//
+// Returns an error if s is not one of "abs", "rel" or "name".
+func ParseOutputMode(s string) (OutputMode, error) {
+	switch mode := OutputMode(s); mode {
+	case OutputAbsolute, OutputRelative, OutputName:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid output mode %q (expected abs, rel or name)", s)
+	}
+}
+
+// Format renders the selected directory according to the output mode.
+//
+// Parameters:
+//   - selected: absolute path of the selected directory
+//   - startDir: absolute path of the directory the application started in
+//
+// Returns an error if a relative path cannot be computed.
+func (m OutputMode) Format(selected, startDir string) (string, error) {
+	switch m {
+	case OutputRelative:
+		rel, err := filepath.Rel(startDir, selected)
+		if err != nil {
+			return "", fmt.Errorf("cannot make %q relative to %q: %w", selected, startDir, err)
+		}
+		return rel, nil
+	case OutputName:
+		return filepath.Base(selected), nil
+	default:
+		return selected, nil
+	}
+}
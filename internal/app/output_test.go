@@ -0,0 +1,59 @@
+package app
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestParseOutputMode(t *testing.T) {
+	for _, s := range []string{"abs", "rel", "name"} {
+		mode, err := ParseOutputMode(s)
+		if err != nil {
+			t.Errorf("unexpected error for %q: %v", s, err)
+		}
+		if string(mode) != s {
+			t.Errorf("expected mode %q, got %q", s, mode)
+		}
+	}
+
+	if _, err := ParseOutputMode("full"); err == nil {
+		t.Error("expected error for invalid mode, got nil")
+	}
+}
+
+func TestOutputModeFormat(t *testing.T) {
+	startDir := filepath.Join(string(filepath.Separator), "home", "user")
+	selected := filepath.Join(startDir, "code", "project")
+
+	tests := []struct {
+		mode OutputMode
+		want string
+	}{
+		{OutputAbsolute, selected},
+		{OutputRelative, filepath.Join("code", "project")},
+		{OutputName, "project"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			got, err := tt.mode.Format(selected, startDir)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+
+	t.Run("relative outside start dir", func(t *testing.T) {
+		parent := filepath.Dir(startDir)
+		got, err := OutputRelative.Format(parent, startDir)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != ".." {
+			t.Errorf("expected %q, got %q", "..", got)
+		}
+	})
+}
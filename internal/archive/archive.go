@@ -0,0 +1,281 @@
+// Package archive compresses directories into .zip and .tar.gz archives.
+//
+// Archives contain the source directory itself as the top-level entry, so
+// extracting "project.zip" recreates a "project" folder. Progress is reported
+// in bytes of file content processed, and archiving can be canceled through
+// a context at any point.
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kaczmarekdaniel/folder-search/internal/fileops"
+)
+
+// archivePerm is the permission of created archives.
+const archivePerm = 0o644
+
+// Format identifies an archive format.
+type Format string
+
+const (
+	// Zip is a zip archive using deflate compression.
+	Zip Format = "zip"
+
+	// TarGz is a gzip-compressed tar archive.
+	TarGz Format = "tar.gz"
+)
+
+// Ext returns the file extension of the format, including the leading dot.
+func (f Format) Ext() string {
+	return "." + string(f)
+}
+
+// ReportFunc receives the number of bytes processed so far and the total.
+type ReportFunc func(done, total int64)
+
+// entry is a filesystem object scheduled for archiving.
+type entry struct {
+	path string // absolute path on disk
+	name string // slash-separated name inside the archive
+	info fs.FileInfo
+}
+
+// Create compresses the directory src into a new archive at dst.
+//
+// The archive is written to a temporary file next to dst and renamed into
+// place only on success, so a canceled or failed run never leaves a partial
+// archive behind. Symbolic links are stored as links, not followed.
+//
+// Before writing, Create checks that the destination filesystem has room
+// for the content of all files to archive and returns
+// fileops.ErrInsufficientSpace if it does not.
+//
+// Parameters:
+//   - ctx: controls cancellation
+//   - src: the directory to compress
+//   - dst: the archive path; must not exist yet
+//   - format: the archive format
+//   - report: optional progress callback
+//
+// Returns the size of the created archive in bytes.
+func Create(ctx context.Context, src, dst string, format Format, report ReportFunc) (int64, error) {
+	if format != Zip && format != TarGz {
+		return 0, fmt.Errorf("unsupported archive format %q", format)
+	}
+	if _, err := os.Lstat(dst); err == nil {
+		return 0, fmt.Errorf("%s already exists", filepath.Base(dst))
+	}
+
+	entries, total, err := collect(src)
+	if err != nil {
+		return 0, err
+	}
+	// Compressed archives rarely outgrow their content, so its size is the
+	// space to ask for
+	if err := fileops.EnsureFreeSpace(filepath.Dir(dst), total); err != nil {
+		return 0, err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(dst), "."+filepath.Base(dst)+".*.tmp")
+	if err != nil {
+		return 0, fmt.Errorf("cannot create archive: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // no-op after a successful rename
+
+	// Temporary files are private; give the archive regular file permissions
+	if err := tmp.Chmod(archivePerm); err != nil {
+		tmp.Close()
+		return 0, err
+	}
+
+	progress := &progress{ctx: ctx, total: total, report: report}
+	if format == Zip {
+		err = writeZip(tmp, entries, progress)
+	} else {
+		err = writeTarGz(tmp, entries, progress)
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	info, err := os.Stat(tmp.Name())
+	if err != nil {
+		return 0, err
+	}
+	if err := os.Rename(tmp.Name(), dst); err != nil {
+		return 0, fmt.Errorf("cannot create archive: %w", err)
+	}
+	return info.Size(), nil
+}
+
+// collect lists everything below src and the total size of regular files.
+func collect(src string) ([]entry, int64, error) {
+	base := filepath.Dir(filepath.Clean(src))
+
+	var entries []entry
+	var total int64
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(base, path)
+		if err != nil {
+			return err
+		}
+
+		entries = append(entries, entry{path: path, name: filepath.ToSlash(rel), info: info})
+		if info.Mode().IsRegular() {
+			total += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("cannot read %s: %w", src, err)
+	}
+	return entries, total, nil
+}
+
+func writeZip(w io.Writer, entries []entry, p *progress) error {
+	zw := zip.NewWriter(w)
+
+	for _, e := range entries {
+		if err := p.ctx.Err(); err != nil {
+			return err
+		}
+
+		header, err := zip.FileInfoHeader(e.info)
+		if err != nil {
+			return err
+		}
+		header.Name = e.name
+		if e.info.IsDir() {
+			header.Name += "/"
+		} else {
+			header.Method = zip.Deflate
+		}
+
+		fw, err := zw.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		if err := p.copyContent(fw, e); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+func writeTarGz(w io.Writer, entries []entry, p *progress) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	for _, e := range entries {
+		if err := p.ctx.Err(); err != nil {
+			return err
+		}
+
+		link := ""
+		if e.info.Mode()&fs.ModeSymlink != 0 {
+			target, err := os.Readlink(e.path)
+			if err != nil {
+				return err
+			}
+			link = target
+		}
+
+		header, err := tar.FileInfoHeader(e.info, link)
+		if err != nil {
+			return err
+		}
+		header.Name = e.name
+		if e.info.IsDir() && !strings.HasSuffix(header.Name, "/") {
+			header.Name += "/"
+		}
+
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		// Tar stores the link target in the header, not as content
+		if link != "" {
+			continue
+		}
+		if err := p.copyContent(tw, e); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// progress tracks copied bytes and aborts copying once the context is done.
+type progress struct {
+	ctx    context.Context
+	done   int64
+	total  int64
+	report ReportFunc
+}
+
+// copyContent writes the content of a regular file or the target of a
+// symlink (the zip convention) to w.
+func (p *progress) copyContent(w io.Writer, e entry) error {
+	mode := e.info.Mode()
+	switch {
+	case mode&fs.ModeSymlink != 0:
+		target, err := os.Readlink(e.path)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(w, target)
+		return err
+	case !mode.IsRegular():
+		return nil
+	}
+
+	f, err := os.Open(e.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(w, &progressReader{r: f, p: p})
+	return err
+}
+
+// progressReader reports bytes read and stops when the context is canceled.
+type progressReader struct {
+	r io.Reader
+	p *progress
+}
+
+func (pr *progressReader) Read(b []byte) (int, error) {
+	if err := pr.p.ctx.Err(); err != nil {
+		return 0, err
+	}
+	n, err := pr.r.Read(b)
+	pr.p.done += int64(n)
+	if pr.p.report != nil && n > 0 {
+		pr.p.report(pr.p.done, pr.p.total)
+	}
+	return n, err
+}
@@ -0,0 +1,153 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+// newTestTree creates a directory "project" with nested content and returns
+// the temp root containing it.
+func newTestTree(t *testing.T) string {
+	t.Helper()
+
+	tempDir, err := os.MkdirTemp("", "archive-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(tempDir) })
+
+	src := filepath.Join(tempDir, "project")
+	if err := os.MkdirAll(filepath.Join(src, "src"), 0755); err != nil {
+		t.Fatalf("failed to create test dirs: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "README"), []byte("hello"), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "src", "main.go"), []byte("package main"), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	return tempDir
+}
+
+var wantNames = []string{"project/", "project/README", "project/src/", "project/src/main.go"}
+
+func assertNames(t *testing.T, got []string) {
+	t.Helper()
+	sort.Strings(got)
+	if len(got) != len(wantNames) {
+		t.Fatalf("expected entries %v, got %v", wantNames, got)
+	}
+	for i := range wantNames {
+		if got[i] != wantNames[i] {
+			t.Errorf("expected entry %q, got %q", wantNames[i], got[i])
+		}
+	}
+}
+
+func TestCreate_Zip(t *testing.T) {
+	tempDir := newTestTree(t)
+	dst := filepath.Join(tempDir, "project.zip")
+
+	var lastDone, lastTotal int64
+	size, err := Create(context.Background(), filepath.Join(tempDir, "project"), dst, Zip, func(done, total int64) {
+		lastDone, lastTotal = done, total
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if size <= 0 {
+		t.Errorf("expected positive archive size, got %d", size)
+	}
+	if lastTotal != 17 || lastDone != lastTotal {
+		t.Errorf("expected final progress 17/17, got %d/%d", lastDone, lastTotal)
+	}
+
+	zr, err := zip.OpenReader(dst)
+	if err != nil {
+		t.Fatalf("failed to open archive: %v", err)
+	}
+	defer zr.Close()
+
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	assertNames(t, names)
+}
+
+func TestCreate_TarGz(t *testing.T) {
+	tempDir := newTestTree(t)
+	dst := filepath.Join(tempDir, "project.tar.gz")
+
+	if _, err := Create(context.Background(), filepath.Join(tempDir, "project"), dst, TarGz, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	f, err := os.Open(dst)
+	if err != nil {
+		t.Fatalf("failed to open archive: %v", err)
+	}
+	defer f.Close()
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("failed to read gzip: %v", err)
+	}
+	tr := tar.NewReader(gr)
+
+	var names []string
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("failed to read tar: %v", err)
+		}
+		names = append(names, header.Name)
+	}
+	assertNames(t, names)
+}
+
+func TestCreate_ExistingDestination(t *testing.T) {
+	tempDir := newTestTree(t)
+	dst := filepath.Join(tempDir, "project.zip")
+	if err := os.WriteFile(dst, []byte("keep"), 0644); err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+
+	if _, err := Create(context.Background(), filepath.Join(tempDir, "project"), dst, Zip, nil); err == nil {
+		t.Fatal("expected error for existing destination, got nil")
+	}
+	if data, _ := os.ReadFile(dst); string(data) != "keep" {
+		t.Error("existing destination should not be modified")
+	}
+}
+
+func TestCreate_CanceledLeavesNoFile(t *testing.T) {
+	tempDir := newTestTree(t)
+	dst := filepath.Join(tempDir, "project.tar.gz")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := Create(ctx, filepath.Join(tempDir, "project"), dst, TarGz, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	entries, err := os.ReadDir(tempDir)
+	if err != nil {
+		t.Fatalf("failed to read temp dir: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected only the source directory to remain, got %d entries", len(entries))
+	}
+}
@@ -0,0 +1,240 @@
+// Package bench measures the directory scan pipeline without the UI.
+//
+// It scans every directory below a root the same way the UI lists a
+// directory, with the configured search options, and reports throughput,
+// peak memory and the slowest directories so users can tune their settings.
+package bench
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/kaczmarekdaniel/folder-search/internal/dirsearch"
+)
+
+const (
+	// DefaultHotSpots is the number of slowest directories reported
+	DefaultHotSpots = 10
+
+	// memSampleInterval is how often heap usage is sampled during a run
+	memSampleInterval = 10 * time.Millisecond
+)
+
+// Timing is the time taken to list one directory.
+type Timing struct {
+	Dir      string
+	Duration time.Duration
+	Entries  int // Subdirectories found
+}
+
+// Report summarizes a benchmark run.
+type Report struct {
+	Root        string
+	Concurrency int // Directories listed in parallel
+	Dirs        int // Directories listed
+	Errors      int // Directories that could not be read
+	Elapsed     time.Duration
+	PeakHeap    uint64   // Highest heap usage observed, in bytes
+	HotSpots    []Timing // Slowest directories, slowest first
+}
+
+// DirsPerSecond returns the scan throughput.
+func (r Report) DirsPerSecond() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.Dirs) / r.Elapsed.Seconds()
+}
+
+// Run scans every directory below root, breadth first, listing each one as
+// the UI would with opts. The directories of each level are listed by
+// opts.Concurrency workers, or one if it is below one.
+//
+// Parameters:
+//   - ctx: stops the run early; the report covers the directories scanned so far
+//   - root: the directory to start from
+//   - opts: the search options, e.g. a clone of the application's; they are
+//     not modified
+//   - hotSpots: number of slowest directories to report
+//
+// Returns the report, or an error if root cannot be read.
+func Run(ctx context.Context, root string, opts *dirsearch.Options, hotSpots int) (Report, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return Report{}, fmt.Errorf("failed to resolve root: %w", err)
+	}
+
+	stopSampling, peakHeap := sampleHeap()
+	report := Report{Root: root, Concurrency: max(opts.Concurrency, 1)}
+	var timings []Timing
+
+	start := time.Now()
+	level := []string{root}
+	for len(level) > 0 && ctx.Err() == nil {
+		scans := scanLevel(ctx, opts, level, report.Concurrency)
+
+		var next []string
+		for i, scan := range scans {
+			switch {
+			case scan.err == nil:
+			case level[i] == root:
+				stopSampling()
+				return Report{}, fmt.Errorf("failed to scan %s: %w", root, scan.err)
+			case ctx.Err() == nil:
+				report.Errors++
+				continue
+			default:
+				// Canceled before this directory was listed
+				continue
+			}
+
+			report.Dirs++
+			timings = append(timings, scan.timing)
+			next = append(next, scan.subdirs...)
+		}
+		level = next
+	}
+	report.Elapsed = time.Since(start)
+	stopSampling()
+	report.PeakHeap = peakHeap()
+
+	slices.SortFunc(timings, func(a, b Timing) int {
+		return cmp.Compare(b.Duration, a.Duration)
+	})
+	report.HotSpots = timings[:min(len(timings), max(hotSpots, 0))]
+	return report, nil
+}
+
+// scan is the outcome of listing one directory.
+type scan struct {
+	timing  Timing
+	subdirs []string // Absolute paths of the subdirectories found
+	err     error
+}
+
+// scanLevel lists dirs with the given number of workers.
+//
+// Returns the scans in the order of dirs.
+func scanLevel(ctx context.Context, opts *dirsearch.Options, dirs []string, workers int) []scan {
+	scans := make([]scan, len(dirs))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for range min(workers, len(dirs)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				scans[i] = scanDir(ctx, opts, dirs[i])
+			}
+		}()
+	}
+	for i := range dirs {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return scans
+}
+
+// scanDir lists the entries of dir with opts.
+func scanDir(ctx context.Context, opts *dirsearch.Options, dir string) scan {
+	o := opts.Clone()
+	o.StartDir = dir
+	o.StartDirs = nil
+	o.MaxDepth = 0
+
+	start := time.Now()
+	result := dirsearch.SearchContext(ctx, o)
+	elapsed := time.Since(start)
+	if result.Error != nil {
+		return scan{err: result.Error}
+	}
+
+	var subdirs []string
+	for i, name := range result.Directories {
+		if result.Types != nil && result.Types[i] != dirsearch.Dir {
+			continue
+		}
+		subdirs = append(subdirs, filepath.Join(dir, name))
+	}
+	return scan{
+		timing:  Timing{Dir: dir, Duration: elapsed, Entries: len(subdirs)},
+		subdirs: subdirs,
+	}
+}
+
+// sampleHeap tracks the highest heap usage until stop is called.
+//
+// Returns the stop function and a function returning the peak in bytes.
+func sampleHeap() (stop func(), peak func() uint64) {
+	var (
+		mu      sync.Mutex
+		highest uint64
+		done    = make(chan struct{})
+		stopped = make(chan struct{})
+	)
+	sample := func() {
+		var stats runtime.MemStats
+		runtime.ReadMemStats(&stats)
+		mu.Lock()
+		highest = max(highest, stats.HeapAlloc)
+		mu.Unlock()
+	}
+
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(memSampleInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				sample()
+			}
+		}
+	}()
+
+	var once sync.Once
+	stop = func() {
+		once.Do(func() {
+			close(done)
+			<-stopped
+			sample()
+		})
+	}
+	peak = func() uint64 {
+		mu.Lock()
+		defer mu.Unlock()
+		return highest
+	}
+	return stop, peak
+}
+
+// WriteReport prints a human-readable summary of r to w.
+func (r Report) WriteReport(w io.Writer) error {
+	if _, err := fmt.Fprintf(w, "Root:        %s\n", r.Root); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Concurrency: %d\n", r.Concurrency)
+	fmt.Fprintf(w, "Directories: %d (%d unreadable)\n", r.Dirs, r.Errors)
+	fmt.Fprintf(w, "Elapsed:     %s\n", r.Elapsed.Round(time.Microsecond))
+	fmt.Fprintf(w, "Throughput:  %.0f dirs/sec\n", r.DirsPerSecond())
+	fmt.Fprintf(w, "Peak heap:   %.1f MiB\n", float64(r.PeakHeap)/(1<<20))
+
+	fmt.Fprintln(w, "\nSlowest directories:")
+	if len(r.HotSpots) == 0 {
+		fmt.Fprintln(w, "  none")
+	}
+	for _, t := range r.HotSpots {
+		fmt.Fprintf(w, "  %10s  %6d dirs  %s\n", t.Duration.Round(time.Microsecond), t.Entries, t.Dir)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package bench
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kaczmarekdaniel/folder-search/internal/dirsearch"
+)
+
+func TestRun(t *testing.T) {
+	root, err := os.MkdirTemp("", "bench-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(root)
+
+	for _, dir := range []string{"a/b/c", "a/d", "e", "node_modules/pkg"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", dir, err)
+		}
+	}
+
+	opts := dirsearch.DefaultOptions()
+	opts.IgnorePatterns = []string{"node_modules"}
+	opts.Concurrency = 3
+	report, err := Run(context.Background(), root, opts, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// root, a, a/b, a/b/c, a/d, e
+	if report.Dirs != 6 {
+		t.Errorf("expected 6 directories, got %d", report.Dirs)
+	}
+	if len(report.HotSpots) != 2 {
+		t.Errorf("expected 2 hot spots, got %d", len(report.HotSpots))
+	}
+	if report.Concurrency != 3 {
+		t.Errorf("expected concurrency 3, got %d", report.Concurrency)
+	}
+	if report.PeakHeap == 0 {
+		t.Error("expected peak heap to be measured")
+	}
+
+	var out bytes.Buffer
+	if err := report.WriteReport(&out); err != nil {
+		t.Fatalf("unexpected error writing report: %v", err)
+	}
+	if !strings.Contains(out.String(), "Concurrency: 3") {
+		t.Errorf("expected concurrency in report, got:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "dirs/sec") {
+		t.Errorf("expected throughput in report, got:\n%s", out.String())
+	}
+}
+
+func TestRun_MissingRoot(t *testing.T) {
+	if _, err := Run(context.Background(), filepath.Join(os.TempDir(), "bench-test-does-not-exist"), dirsearch.DefaultOptions(), 1); err == nil {
+		t.Error("expected error for missing root, got nil")
+	}
+}
+
+func TestRun_Canceled(t *testing.T) {
+	root, err := os.MkdirTemp("", "bench-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(root)
+	if err := os.MkdirAll(filepath.Join(root, "a", "b"), 0755); err != nil {
+		t.Fatalf("failed to create dirs: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	report, err := Run(ctx, root, dirsearch.DefaultOptions(), 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Dirs != 0 || report.Errors != 0 {
+		t.Errorf("expected no directories scanned after cancel, got %d (%d errors)", report.Dirs, report.Errors)
+	}
+}
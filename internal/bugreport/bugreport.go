@@ -0,0 +1,189 @@
+// Package bugreport bundles what is needed to investigate a problem into a
+// single text file that can be attached to an issue: the version, the
+// platform, the configuration and the latest log records.
+//
+// Personal details are redacted: the home directory is shortened to "~"
+// everywhere, and the preview command, which may hold credentials, is
+// replaced by a placeholder.
+package bugreport
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/kaczmarekdaniel/folder-search/internal/config"
+	"github.com/kaczmarekdaniel/folder-search/internal/termux"
+)
+
+// redacted replaces settings left out of reports.
+const redacted = "[redacted]"
+
+// envVars lists the environment variables describing the terminal, which
+// explain most display problems.
+var envVars = []string{"TERM", "TERM_PROGRAM", "COLORTERM", "LANG", "LC_ALL", "LC_CTYPE", "COLUMNS", "LINES"}
+
+// Report is the content of a bug report.
+type Report struct {
+	// Version is the version of folder-search
+	Version string
+
+	// Time is when the report was created
+	Time time.Time
+
+	// Config is the configuration in use
+	Config *config.Config
+
+	// Logs are the latest log records, oldest first
+	Logs []string
+}
+
+// Write writes the report as text to w, redacting personal details.
+//
+// Returns an error if the configuration cannot be encoded or writing fails.
+func (r Report) Write(w io.Writer) error {
+	home, _ := os.UserHomeDir()
+	return r.write(w, home, os.Getenv)
+}
+
+// write implements Write, shortening home to "~" and reading the
+// environment with getenv.
+func (r Report) write(w io.Writer, home string, getenv func(string) string) error {
+	cfg, err := redactConfig(r.Config)
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "folder-search bug report\n\n")
+	fmt.Fprintf(&b, "version: %s\n", r.Version)
+	fmt.Fprintf(&b, "created: %s\n", r.Time.UTC().Format(time.RFC3339))
+
+	fmt.Fprintf(&b, "\n## Platform\n\n")
+	fmt.Fprintf(&b, "os: %s/%s\n", runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&b, "go: %s\n", runtime.Version())
+	fmt.Fprintf(&b, "cpus: %d\n", runtime.NumCPU())
+	fmt.Fprintf(&b, "android: %t\n", termux.Detected())
+	for _, name := range envVars {
+		if value := getenv(name); value != "" {
+			fmt.Fprintf(&b, "%s: %s\n", name, value)
+		}
+	}
+
+	fmt.Fprintf(&b, "\n## Config\n\n%s\n", cfg)
+
+	fmt.Fprintf(&b, "\n## Log (%d records)\n\n", len(r.Logs))
+	for _, record := range r.Logs {
+		fmt.Fprintln(&b, record)
+	}
+
+	_, err = io.WriteString(w, shortenHome(b.String(), home))
+	return err
+}
+
+// Save writes the report to a new file in dir, named after its time, e.g.
+// folder-search-bugreport-20261015-142314.txt, creating dir if needed.
+//
+// Returns the path of the file, or an error if it cannot be written.
+func (r Report) Save(dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create report directory: %w", err)
+	}
+	path := filepath.Join(dir, FileName(r.Time))
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return "", fmt.Errorf("failed to create report: %w", err)
+	}
+	if err := r.Write(f); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("failed to write report: %w", err)
+	}
+	return path, nil
+}
+
+// FileName returns the name of a report created at t.
+func FileName(t time.Time) string {
+	return "folder-search-bugreport-" + t.Format("20060102-150405") + ".txt"
+}
+
+// DefaultDir returns the directory reports created from the interface are
+// saved to.
+func DefaultDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot locate cache directory: %w", err)
+	}
+	return filepath.Join(dir, "folder-search", "bugreports"), nil
+}
+
+// TailFile returns the last n lines of the file at path, e.g. the latest
+// records of a log file.
+func TailFile(path string, n int) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	lines := []string{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		if len(lines) == n {
+			lines = lines[1:]
+		}
+		lines = append(lines, scanner.Text())
+	}
+	return lines, scanner.Err()
+}
+
+// redactConfig returns cfg as indented JSON without settings that may hold
+// credentials: the shell commands, which often embed tokens or webhook URLs.
+func redactConfig(cfg *config.Config) (string, error) {
+	if cfg == nil {
+		return "(none)", nil
+	}
+	c := *cfg
+	redactCommands(reflect.ValueOf(&c).Elem())
+	data, err := json.MarshalIndent(c, "", "  ")
+	return string(data), err
+}
+
+// redactCommands replaces every non-empty string field of the struct v, or
+// of the structs nested in it, whose name ends in "Command", such as
+// PreviewCommand or Notify.Command. Structs behind pointers, maps or
+// slices are shared with the caller's configuration and left alone.
+func redactCommands(v reflect.Value) {
+	for i := range v.NumField() {
+		field, value := v.Type().Field(i), v.Field(i)
+		switch {
+		case !field.IsExported():
+		case value.Kind() == reflect.Struct:
+			redactCommands(value)
+		case value.Kind() == reflect.String && strings.HasSuffix(field.Name, "Command") && value.String() != "":
+			value.SetString(redacted)
+		}
+	}
+}
+
+// shortenHome replaces the home directory in text with "~", also where it
+// appears in JSON with its backslashes escaped, as on Windows.
+func shortenHome(text, home string) string {
+	if home == "" || home == string(filepath.Separator) {
+		return text
+	}
+	if escaped := strings.ReplaceAll(home, `\`, `\\`); escaped != home {
+		text = strings.ReplaceAll(text, escaped, "~")
+	}
+	return strings.ReplaceAll(text, home, "~")
+}
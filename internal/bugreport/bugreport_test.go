@@ -0,0 +1,117 @@
+package bugreport
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kaczmarekdaniel/folder-search/internal/config"
+)
+
+func TestWrite(t *testing.T) {
+	cfg := config.Default()
+	cfg.PreviewCommand = "curl -H 'Authorization: secret' {}"
+	cfg.Notify.Command = "curl -d \"$FOLDER_SEARCH_MESSAGE\" https://hooks.example.com/notify-token"
+	cfg.Alerts.Command = "curl -H 'X-Token: alert-token' https://hooks.example.com"
+	cfg.Profiles = map[string]config.Profile{"work": {Root: "/home/alex/work"}}
+	r := Report{
+		Version: "1.2.3",
+		Time:    time.Date(2026, 10, 15, 14, 23, 14, 0, time.UTC),
+		Config:  cfg,
+		Logs:    []string{`level=INFO msg="scan finished" dir=/home/alex/src`},
+	}
+	env := map[string]string{"TERM": "xterm-256color", "LANG": "pl_PL.UTF-8"}
+
+	var b strings.Builder
+	if err := r.write(&b, "/home/alex", func(key string) string { return env[key] }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := b.String()
+
+	for _, want := range []string{
+		"version: 1.2.3",
+		"created: 2026-10-15T14:23:14Z",
+		"TERM: xterm-256color",
+		"LANG: pl_PL.UTF-8",
+		`"root": "~/work"`,
+		redacted,
+		"## Log (1 records)",
+		"dir=~/src",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected report to contain %q, got:\n%s", want, out)
+		}
+	}
+	for _, unwanted := range []string{"/home/alex", "secret", "notify-token", "alert-token", "COLORTERM"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("expected report not to contain %q, got:\n%s", unwanted, out)
+		}
+	}
+	if !strings.Contains(cfg.Alerts.Command, "alert-token") {
+		t.Errorf("expected the configuration itself to keep its commands, got %q", cfg.Alerts.Command)
+	}
+}
+
+func TestShortenHome_Escaped(t *testing.T) {
+	got := shortenHome(`{"root": "C:\\Users\\alex\\work"} C:\Users\alex`, `C:\Users\alex`)
+	if want := `{"root": "~\\work"} ~`; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestSave(t *testing.T) {
+	dir, err := os.MkdirTemp("", "bugreport-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	r := Report{Version: "dev", Time: time.Date(2026, 10, 15, 14, 23, 14, 0, time.UTC)}
+	path, err := r.Save(filepath.Join(dir, "reports"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if filepath.Base(path) != "folder-search-bugreport-20261015-142314.txt" {
+		t.Errorf("unexpected report name %s", filepath.Base(path))
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read report: %v", err)
+	}
+	if !strings.Contains(string(data), "version: dev") {
+		t.Errorf("expected version in report, got:\n%s", data)
+	}
+
+	// An existing report is never overwritten
+	if _, err := r.Save(filepath.Join(dir, "reports")); err == nil {
+		t.Error("expected error saving over an existing report, got nil")
+	}
+}
+
+func TestTailFile(t *testing.T) {
+	dir, err := os.MkdirTemp("", "bugreport-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "folder-search.log")
+	if err := os.WriteFile(path, []byte("one\ntwo\nthree\nfour\n"), 0644); err != nil {
+		t.Fatalf("failed to write log: %v", err)
+	}
+
+	lines, err := TailFile(path, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []string{"three", "four"}; !slices.Equal(lines, expected) {
+		t.Errorf("expected %v, got %v", expected, lines)
+	}
+
+	if _, err := TailFile(filepath.Join(dir, "missing.log"), 2); err == nil {
+		t.Error("expected error for missing file, got nil")
+	}
+}
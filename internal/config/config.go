@@ -0,0 +1,650 @@
+// Package config loads user configuration for folder-search.
+//
+// Configuration is read from a JSON file in the user's configuration
+// directory (e.g. ~/.config/folder-search/config.json on Linux). A missing
+// file is not an error: built-in defaults are used instead, and values from
+// the file are applied on top of them.
+//
+// The file carries a "version" field. When the format changes, files with an
+// older version are migrated on load: the original is kept as config.json.bak,
+// the upgraded file is written in its place and the changes are reported in
+// Config.Migrations.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/kaczmarekdaniel/folder-search/internal/alerts"
+	"github.com/kaczmarekdaniel/folder-search/internal/dirmeta"
+	"github.com/kaczmarekdaniel/folder-search/internal/dirsearch"
+	"github.com/kaczmarekdaniel/folder-search/internal/logging"
+	"github.com/kaczmarekdaniel/folder-search/internal/power"
+	"github.com/kaczmarekdaniel/folder-search/internal/sizefmt"
+	"github.com/kaczmarekdaniel/folder-search/internal/statefile"
+	"github.com/kaczmarekdaniel/folder-search/internal/timefmt"
+)
+
+const (
+	// dirName is the application directory inside the user config directory
+	dirName = "folder-search"
+
+	// fileName is the name of the configuration file
+	fileName = "config.json"
+
+	// backupExt is appended to the config path to back up a file before
+	// it is migrated
+	backupExt = ".bak"
+)
+
+// migrations returns the config migrations; migrations[i] upgrades a file
+// from version i to i+1 as for statefile.Load. Files without a version field
+// predate versioning and are version 0. The migrations append a description
+// of each change they make to notes, e.g. "renamed foo to bar".
+func migrations(notes *[]string) []statefile.Migration {
+	return []statefile.Migration{
+		// Version 1 added the version field
+		statefile.Unchanged,
+		// Version 2 no longer hides .git in profiles with their own ignore
+		// list
+		func(fields map[string]json.RawMessage) error {
+			return keepGitIgnored(fields, notes)
+		},
+	}
+}
+
+// currentVersion returns the config schema version written by this release.
+func currentVersion() int {
+	return len(migrations(nil))
+}
+
+// Config holds user configuration.
+type Config struct {
+	// Version is the schema version of the configuration
+	Version int `json:"version"`
+
+	// Migrations describes the changes made while upgrading an older
+	// config file. It is empty if no migration was necessary.
+	Migrations []string `json:"-"`
+
+	// Templates maps a project type (e.g. "go") to the directories created
+	// when scaffolding a new project folder of that type. Paths are relative
+	// to the new folder and may be nested ("src/main").
+	Templates map[string][]string `json:"templates"`
+
+	// NavigationDebounceMs is how long the UI waits after the last
+	// navigation key before scanning, so holding an arrow key only scans
+	// the directory it lands on. Zero scans on every key press.
+	NavigationDebounceMs int `json:"navigation_debounce_ms"`
+
+	// Profiles maps a profile name to a workspace that can be switched to
+	// at runtime.
+	Profiles map[string]Profile `json:"profiles"`
+
+	// Ranking lists boost rules that reorder directory listings and search
+	// results, e.g. to push backups to the bottom. A leading "~" in a
+	// rule's under path is expanded to the user's home directory.
+	Ranking []dirsearch.BoostRule `json:"ranking"`
+
+	// InlineNotes shows directory notes next to their names in the list.
+	// The note of the highlighted directory is always shown below it.
+	InlineNotes bool `json:"inline_notes"`
+
+	// Colors lists rules that color directory names in the list, e.g.
+	// everything under ~/work in blue. The first matching rule wins.
+	Colors []ColorRule `json:"colors"`
+
+	// TitleTemplate is a Go template for the list title. It can use
+	// {{.Path}}, {{.Name}}, {{.Count}}, {{.Profile}}, {{.Branch}}, {{.Tag}},
+	// {{.Query}}, {{.Refinements}} and {{.Hidden}}. Empty shows the current
+	// path and active filters.
+	TitleTemplate string `json:"title_template"`
+
+	// PromptTemplate is a Go template for the marker in front of the
+	// highlighted directory, with the same variables as TitleTemplate.
+	// Empty uses "> ".
+	PromptTemplate string `json:"prompt_template"`
+
+	// PreviewCommand is a shell command whose output is shown below the
+	// list for the highlighted directory, e.g. "ls -la {}". "{}" is
+	// replaced by the directory path. Empty disables the preview pane.
+	PreviewCommand string `json:"preview_command"`
+
+	// FuzzyQuery makes the name filter match fuzzily, so "fsr" finds
+	// "folder-search-results", and lists the best matches first.
+	FuzzyQuery bool `json:"fuzzy_query"`
+
+	// Ignore lists directory names hidden in every profile, in addition to
+	// the profile's own ignore list. Names may use shell wildcards such as
+	// "build-*"; a leading "!" lists matching directories even if another
+	// list ignores them, e.g. "!.git".
+	Ignore []string `json:"ignore"`
+
+	// Decorations lists the metadata shown next to directory names:
+	// "mtime", "entries", "git", "project" and "size". It is collected in
+	// the background after a directory is listed. Empty shows none.
+	Decorations []string `json:"decorations"`
+
+	// LowPower selects when background work is cut down to save energy:
+	// "auto" (or empty) while running on battery or in a power-saving
+	// profile, "on" always and "off" never.
+	LowPower string `json:"low_power"`
+
+	// Time configures how modification times and other timestamps are
+	// shown.
+	Time TimeConfig `json:"time"`
+
+	// SizeUnits selects the units of sizes in decorations, jobs and
+	// reports: "binary" (or empty) for KiB, MiB and GiB, or "si" for kB,
+	// MB and GB. Sizes given to filters are always binary; see
+	// dirsearch.ParseSize.
+	SizeUnits string `json:"size_units"`
+
+	// Alerts configures the disk usage rules checked whenever the index
+	// command refreshes an index.
+	Alerts AlertsConfig `json:"alerts"`
+
+	// Notify configures the command told when a long background job, such
+	// as an archive or an index build, finishes.
+	Notify NotifyConfig `json:"notify"`
+
+	// Log configures the application log.
+	Log LogConfig `json:"log"`
+
+	// Experimental turns features on or off by name, e.g. {"watch":
+	// false}; see the features package for the known names.
+	Experimental map[string]bool `json:"experimental"`
+}
+
+// TimeConfig configures how timestamps are shown in the interface and in
+// reports.
+type TimeConfig struct {
+	// Style is "relative" (or empty) for times such as "3 days ago", or
+	// "absolute" for dates
+	Style string `json:"style"`
+
+	// Locale picks the way dates are written, e.g. "de_DE" for 31.12.2024.
+	// Empty uses the LC_ALL, LC_TIME or LANG environment variable.
+	Locale string `json:"locale"`
+
+	// DateLayout is a Go time layout such as "02 Jan 2006" replacing the
+	// date format of the locale
+	DateLayout string `json:"date_layout"`
+}
+
+// Formatter returns the timestamp formatter described by the configuration.
+//
+// Returns an error for an unknown style.
+func (c TimeConfig) Formatter() (timefmt.Formatter, error) {
+	return timefmt.New(c.Style, c.Locale, c.DateLayout)
+}
+
+// AlertsConfig configures disk usage alerts.
+type AlertsConfig struct {
+	// Command is run through the shell for every broken rule, with the
+	// details in FOLDER_SEARCH_* environment variables, e.g. to show a
+	// desktop notification. Empty only prints the alerts.
+	Command string `json:"command"`
+
+	// Rules limit the size of directories
+	Rules []alerts.Rule `json:"rules"`
+}
+
+// NotifyConfig configures notifications about finished jobs.
+type NotifyConfig struct {
+	// Command is run through the shell when a job that ran for at least
+	// MinSeconds finishes, with the details in FOLDER_SEARCH_* environment
+	// variables, e.g. to show a desktop notification. Empty disables
+	// notifications.
+	Command string `json:"command"`
+
+	// MinSeconds is how long a job must run for its end to be notified,
+	// so quick jobs finishing while the user watches stay quiet. Zero
+	// notifies every job.
+	MinSeconds int `json:"min_seconds"`
+}
+
+// MinDuration returns MinSeconds as a duration. Negative values are
+// treated as zero.
+func (c NotifyConfig) MinDuration() time.Duration {
+	return time.Duration(max(c.MinSeconds, 0)) * time.Second
+}
+
+// LogConfig configures where and how much the application logs.
+type LogConfig struct {
+	// Format is "text" (or empty) or "json"
+	Format string `json:"format"`
+
+	// File is the file the log is appended to instead of stderr. A
+	// leading "~" is expanded to the user's home directory.
+	File string `json:"file"`
+
+	// MaxSizeMB is the size in megabytes after which File is rotated.
+	// Zero never rotates it.
+	MaxSizeMB int `json:"max_size_mb"`
+
+	// MaxFiles is the number of rotated log files kept
+	MaxFiles int `json:"max_files"`
+
+	// Level is the minimum level logged: "debug", "info" (or empty),
+	// "warn" or "error"
+	Level string `json:"level"`
+
+	// Levels overrides Level for the modules "ui", "dirsearch" and
+	// "index"
+	Levels map[string]string `json:"levels"`
+}
+
+// Options returns the logger options described by the configuration.
+//
+// Returns an error for an unknown format, level or module.
+func (c LogConfig) Options() (logging.Options, error) {
+	opts := logging.Options{
+		Format:   c.Format,
+		File:     c.File,
+		MaxSize:  int64(c.MaxSizeMB) << 20,
+		MaxFiles: c.MaxFiles,
+	}
+	switch c.Format {
+	case "", logging.FormatText, logging.FormatJSON:
+	default:
+		return opts, fmt.Errorf("invalid format %q: use %s or %s", c.Format, logging.FormatText, logging.FormatJSON)
+	}
+
+	var err error
+	if opts.Level, err = logging.ParseLevel(c.Level); err != nil {
+		return opts, err
+	}
+	opts.Levels = make(map[string]slog.Level, len(c.Levels))
+	for module, name := range c.Levels {
+		if !slices.Contains(logging.Modules, module) {
+			return opts, fmt.Errorf("unknown module %q: use %s", module, strings.Join(logging.Modules, ", "))
+		}
+		if opts.Levels[module], err = logging.ParseLevel(name); err != nil {
+			return opts, fmt.Errorf("module %s: %w", module, err)
+		}
+	}
+	return opts, nil
+}
+
+// Values of Config.LowPower.
+const (
+	LowPowerAuto = "auto"
+	LowPowerOn   = "on"
+	LowPowerOff  = "off"
+)
+
+// ColorRule colors directories matching a location and/or a name pattern.
+// A rule with both Under and Pattern set only applies to directories
+// matching both.
+type ColorRule struct {
+	// Under matches directories inside this path. A leading "~" is
+	// expanded to the user's home directory.
+	Under string `json:"under,omitempty"`
+
+	// Pattern matches directory names against a shell pattern such as
+	// "*-prod*"
+	Pattern string `json:"pattern,omitempty"`
+
+	// Color is a color name (e.g. "blue"), an ANSI color number or a
+	// "#rrggbb" hex color
+	Color string `json:"color"`
+}
+
+// colorNames maps the supported color names to ANSI color numbers.
+var colorNames = map[string]string{
+	"black":   "0",
+	"red":     "1",
+	"green":   "2",
+	"yellow":  "3",
+	"blue":    "4",
+	"magenta": "5",
+	"cyan":    "6",
+	"white":   "7",
+	"gray":    "8",
+	"grey":    "8",
+}
+
+// TerminalColor returns the rule's color as an ANSI color number or hex
+// color, resolving color names.
+func (r ColorRule) TerminalColor() string {
+	if code, ok := colorNames[strings.ToLower(r.Color)]; ok {
+		return code
+	}
+	return r.Color
+}
+
+// validate expands the rule's under path and checks its pattern and color.
+func (r *ColorRule) validate() error {
+	if r.Under == "" && r.Pattern == "" {
+		return errors.New("color rule needs an under path or a pattern")
+	}
+	if r.Under != "" {
+		under, err := absPath(r.Under)
+		if err != nil {
+			return err
+		}
+		r.Under = under
+	}
+	if _, err := filepath.Match(r.Pattern, ""); err != nil {
+		return fmt.Errorf("invalid pattern %q: %w", r.Pattern, err)
+	}
+
+	color := r.TerminalColor()
+	if n, err := strconv.Atoi(color); err == nil && n >= 0 && n <= 255 {
+		return nil
+	}
+	if len(color) == 7 && color[0] == '#' {
+		if _, err := strconv.ParseUint(color[1:], 16, 32); err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid color %q: use a color name, an ANSI number or #rrggbb", r.Color)
+}
+
+// Profile is a named workspace: a root directory and the directory names
+// hidden while it is active.
+type Profile struct {
+	// Root is the directory opened when switching to the profile. A
+	// leading "~" is expanded to the user's home directory.
+	Root string `json:"root"`
+
+	// Ignore lists directory names to hide. When omitted, the default
+	// ignore list (.git and node_modules) is used.
+	Ignore []string `json:"ignore"`
+}
+
+// RootDir returns the profile root as an absolute path, expanding a
+// leading "~" to the user's home directory.
+func (p Profile) RootDir() (string, error) {
+	return absPath(p.Root)
+}
+
+// absPath returns path as an absolute path, expanding a leading "~" to the
+// user's home directory.
+func absPath(path string) (string, error) {
+	// On Windows, "~\work" is expanded as well
+	if path == "~" || strings.HasPrefix(path, "~/") || strings.HasPrefix(path, "~"+string(filepath.Separator)) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("cannot expand %q: %w", path, err)
+		}
+		path = filepath.Join(home, path[1:])
+	}
+	return filepath.Abs(path)
+}
+
+// Default returns the built-in configuration.
+func Default() *Config {
+	return &Config{
+		Version: currentVersion(),
+		Templates: map[string][]string{
+			"basic":  {"src/", "test/", "docs/"},
+			"go":     {"cmd/", "internal/", "docs/"},
+			"node":   {"src/", "test/", "public/"},
+			"python": {"src/", "tests/", "docs/"},
+		},
+		NavigationDebounceMs: 80,
+		Notify:               NotifyConfig{MinSeconds: 10},
+		Log: LogConfig{
+			MaxSizeMB: 10,
+			MaxFiles:  3,
+		},
+	}
+}
+
+// NavigationDebounce returns the navigation debounce interval as a duration.
+// Negative values are treated as zero.
+func (c *Config) NavigationDebounce() time.Duration {
+	return time.Duration(max(c.NavigationDebounceMs, 0)) * time.Millisecond
+}
+
+// Sizes returns the units sizes are rendered in, binary units if
+// SizeUnits is not valid.
+func (c *Config) Sizes() sizefmt.Units {
+	units, err := sizefmt.ParseUnits(c.SizeUnits)
+	if err != nil {
+		return sizefmt.Binary
+	}
+	return units
+}
+
+// LowPowerEnabled reports whether background work should be cut down,
+// detecting the power state of the machine if LowPower is "auto".
+func (c *Config) LowPowerEnabled() bool {
+	switch c.LowPower {
+	case LowPowerOn:
+		return true
+	case LowPowerOff:
+		return false
+	}
+	return power.Saving()
+}
+
+// Path returns the location of the user configuration file.
+func Path() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot locate config directory: %w", err)
+	}
+	return filepath.Join(dir, dirName, fileName), nil
+}
+
+// Load reads the user configuration file.
+//
+// Returns the default configuration if the file does not exist, or an error
+// if it exists but cannot be read or parsed.
+func Load() (*Config, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	return LoadFile(path)
+}
+
+// LoadFile reads configuration from the given file, applying its values on
+// top of the defaults. Templates from the file are added to the built-in
+// ones, replacing templates with the same name.
+//
+// Files with an older schema version are migrated and saved back, keeping a
+// backup of the original. A failure to save the migrated file is reported in
+// Config.Migrations rather than returned, since the configuration itself was
+// loaded successfully.
+//
+// Returns the default configuration if the file does not exist, or
+// statefile.ErrNewerVersion if the file was written by a newer release.
+func LoadFile(path string) (*Config, error) {
+	cfg := Default()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return cfg, nil
+		}
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+
+	var notes []string
+	version, err := statefile.Migrate(fields, currentVersion(), migrations(&notes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config %s: %w", path, err)
+	}
+
+	migrated, err := json.MarshalIndent(fields, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if version < currentVersion() {
+		if err := saveMigrated(path, data, migrated); err != nil {
+			notes = append(notes, fmt.Sprintf("could not save migrated config: %v", err))
+		}
+	}
+
+	if err := json.Unmarshal(migrated, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	for i, rule := range cfg.Ranking {
+		if rule.Under == "" {
+			continue
+		}
+		if cfg.Ranking[i].Under, err = absPath(rule.Under); err != nil {
+			return nil, fmt.Errorf("invalid ranking rule in config %s: %w", path, err)
+		}
+	}
+	for i := range cfg.Colors {
+		if err := cfg.Colors[i].validate(); err != nil {
+			return nil, fmt.Errorf("invalid color rule in config %s: %w", path, err)
+		}
+	}
+	for _, pattern := range cfg.Ignore {
+		if err := dirsearch.CheckIgnorePattern(pattern); err != nil {
+			return nil, fmt.Errorf("invalid ignore pattern %q in config %s: %w", pattern, path, err)
+		}
+	}
+	switch cfg.LowPower {
+	case "", LowPowerAuto, LowPowerOn, LowPowerOff:
+	default:
+		return nil, fmt.Errorf("invalid low_power %q in config %s: use auto, on or off", cfg.LowPower, path)
+	}
+	for i, rule := range cfg.Alerts.Rules {
+		if err := rule.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid alerts in config %s: %w", path, err)
+		}
+		if rule.Path == "" {
+			continue
+		}
+		if cfg.Alerts.Rules[i].Path, err = absPath(rule.Path); err != nil {
+			return nil, fmt.Errorf("invalid alerts in config %s: %w", path, err)
+		}
+	}
+	if cfg.Log.File != "" {
+		if cfg.Log.File, err = absPath(cfg.Log.File); err != nil {
+			return nil, fmt.Errorf("invalid log file in config %s: %w", path, err)
+		}
+	}
+	if _, err := cfg.Log.Options(); err != nil {
+		return nil, fmt.Errorf("invalid log settings in config %s: %w", path, err)
+	}
+	if _, err := cfg.Time.Formatter(); err != nil {
+		return nil, fmt.Errorf("invalid time settings in config %s: %w", path, err)
+	}
+	if _, err := sizefmt.ParseUnits(cfg.SizeUnits); err != nil {
+		return nil, fmt.Errorf("invalid size_units in config %s: %w", path, err)
+	}
+	if _, err := dirmeta.ParseFields(cfg.Decorations); err != nil {
+		return nil, fmt.Errorf("invalid decorations in config %s: %w", path, err)
+	}
+	for name, text := range map[string]string{"title_template": cfg.TitleTemplate, "prompt_template": cfg.PromptTemplate} {
+		if _, err := template.New(name).Parse(text); err != nil {
+			return nil, fmt.Errorf("invalid %s in config %s: %w", name, path, err)
+		}
+	}
+	cfg.Migrations = notes
+	return cfg, nil
+}
+
+// keepGitIgnored adds .git to the ignore lists of profiles that have one.
+// Such a list replaces the default ignore list, which hides .git since
+// version 2; before, .git was hidden regardless of the lists.
+func keepGitIgnored(fields map[string]json.RawMessage, notes *[]string) error {
+	raw, ok := fields["profiles"]
+	if !ok {
+		return nil
+	}
+	var profiles map[string]map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &profiles); err != nil {
+		return fmt.Errorf("invalid profiles: %w", err)
+	}
+
+	changed := false
+	for _, name := range slices.Sorted(maps.Keys(profiles)) {
+		rawIgnore, ok := profiles[name]["ignore"]
+		if !ok || string(rawIgnore) == "null" {
+			continue
+		}
+		var ignore []string
+		if err := json.Unmarshal(rawIgnore, &ignore); err != nil {
+			return fmt.Errorf("invalid ignore list in profile %s: %w", name, err)
+		}
+		if slices.Contains(ignore, ".git") || slices.Contains(ignore, "!.git") {
+			continue
+		}
+		updated, err := json.Marshal(append(ignore, ".git"))
+		if err != nil {
+			return err
+		}
+		profiles[name]["ignore"] = updated
+		changed = true
+		if notes != nil {
+			*notes = append(*notes, fmt.Sprintf("added .git to the ignore list of profile %s", name))
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	updated, err := json.Marshal(profiles)
+	if err != nil {
+		return err
+	}
+	fields["profiles"] = updated
+	return nil
+}
+
+// saveMigrated backs up the original config file and replaces it with the
+// migrated one.
+func saveMigrated(path string, original, migrated []byte) error {
+	if err := statefile.WriteFile(path+backupExt, original, 0o644); err != nil {
+		return fmt.Errorf("failed to back up config: %w", err)
+	}
+	return statefile.WriteFile(path, migrated, 0o644)
+}
+
+// AddIgnore adds pattern to the ignore list of the config file at path,
+// creating the file if it does not exist. The file is migrated to the
+// current version while holding its lock, as for statefile.Update; its other
+// settings are kept as they are. Adding a pattern that is already listed
+// does nothing.
+//
+// Returns an error if the file cannot be read, parsed, migrated or written.
+func AddIgnore(path, pattern string) error {
+	var ignoreErr error
+	fields := map[string]json.RawMessage{}
+	err := statefile.Update(path, currentVersion(), migrations(nil), &fields, func() {
+		var ignore []string
+		if raw, ok := fields["ignore"]; ok {
+			if err := json.Unmarshal(raw, &ignore); err != nil {
+				ignoreErr = fmt.Errorf("invalid ignore list in config %s: %w", path, err)
+				return
+			}
+		}
+		if slices.Contains(ignore, pattern) {
+			return
+		}
+		raw, err := json.Marshal(append(ignore, pattern))
+		if err != nil {
+			ignoreErr = err
+			return
+		}
+		fields["ignore"] = raw
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update config: %w", err)
+	}
+	return ignoreErr
+}
@@ -0,0 +1,558 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kaczmarekdaniel/folder-search/internal/statefile"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	dir, err := os.MkdirTemp("", "config-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, fileName)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return path
+}
+
+func TestDefault(t *testing.T) {
+	cfg := Default()
+
+	if len(cfg.Templates["basic"]) == 0 {
+		t.Error("expected a built-in basic template")
+	}
+}
+
+func TestLoadFile_Missing(t *testing.T) {
+	cfg, err := LoadFile(filepath.Join(os.TempDir(), "config-test-does-not-exist.json"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.Templates) != len(Default().Templates) {
+		t.Errorf("expected default templates, got %v", cfg.Templates)
+	}
+}
+
+func TestLoadFile_MergesTemplates(t *testing.T) {
+	path := writeConfig(t, `{"templates": {"go": ["cmd/app/"], "rust": ["src/", "benches/"]}}`)
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := cfg.Templates["go"]; len(got) != 1 || got[0] != "cmd/app/" {
+		t.Errorf("expected go template to be replaced, got %v", got)
+	}
+	if got := cfg.Templates["rust"]; len(got) != 2 {
+		t.Errorf("expected rust template to be added, got %v", got)
+	}
+	if _, ok := cfg.Templates["basic"]; !ok {
+		t.Error("expected built-in templates to be kept")
+	}
+}
+
+func TestLoadFile_Invalid(t *testing.T) {
+	path := writeConfig(t, `{"templates": [`)
+
+	if _, err := LoadFile(path); err == nil {
+		t.Error("expected error for invalid config, got nil")
+	}
+}
+
+func TestNavigationDebounce(t *testing.T) {
+	path := writeConfig(t, `{"navigation_debounce_ms": 250}`)
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := cfg.NavigationDebounce(); got != 250*time.Millisecond {
+		t.Errorf("expected 250ms, got %v", got)
+	}
+
+	cfg.NavigationDebounceMs = -1
+	if got := cfg.NavigationDebounce(); got != 0 {
+		t.Errorf("expected negative debounce to be treated as zero, got %v", got)
+	}
+}
+
+func TestLoadFile_Profiles(t *testing.T) {
+	path := writeConfig(t, `{"version": 2, "profiles": {"work": {"root": "~/work", "ignore": ["vendor", "dist"]}}}`)
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	work, ok := cfg.Profiles["work"]
+	if !ok {
+		t.Fatal("expected work profile to be loaded")
+	}
+	if len(work.Ignore) != 2 || work.Ignore[0] != "vendor" {
+		t.Errorf("expected ignore list [vendor dist], got %v", work.Ignore)
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skipf("no home directory: %v", err)
+	}
+	root, err := work.RootDir()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := filepath.Join(home, "work"); root != want {
+		t.Errorf("expected root %q, got %q", want, root)
+	}
+}
+
+func TestProfileRootDir_Relative(t *testing.T) {
+	root, err := Profile{Root: "projects"}.RootDir()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !filepath.IsAbs(root) {
+		t.Errorf("expected absolute path, got %q", root)
+	}
+}
+
+func TestProfileRootDir_Home(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skipf("no home directory: %v", err)
+	}
+
+	// The native separator covers "~\work" on Windows
+	for _, root := range []string{"~/work", "~" + string(filepath.Separator) + "work"} {
+		got, err := Profile{Root: root}.RootDir()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := filepath.Join(home, "work"); got != want {
+			t.Errorf("%s: expected %s, got %s", root, want, got)
+		}
+	}
+}
+
+func TestLoadFile_Migrates(t *testing.T) {
+	original := `{"version": 1, "profiles": {"work": {"root": "~/work", "ignore": ["dist"]}, "home": {"root": "~"}, "all": {"root": "/", "ignore": ["!.git"]}}}`
+	path := writeConfig(t, original)
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := cfg.Profiles["work"].Ignore; !slices.Equal(got, []string{"dist", ".git"}) {
+		t.Errorf("expected .git added to the work profile, got %v", got)
+	}
+	if got := cfg.Profiles["home"].Ignore; got != nil {
+		t.Errorf("expected home profile to keep the default ignore list, got %v", got)
+	}
+	if got := cfg.Profiles["all"].Ignore; !slices.Equal(got, []string{"!.git"}) {
+		t.Errorf("expected all profile to keep listing .git, got %v", got)
+	}
+	if len(cfg.Migrations) != 1 {
+		t.Errorf("expected one migration note, got %v", cfg.Migrations)
+	}
+
+	backup, err := os.ReadFile(path + backupExt)
+	if err != nil {
+		t.Fatalf("expected backup of the original config: %v", err)
+	}
+	if string(backup) != original {
+		t.Errorf("expected backup to hold the original config, got %s", backup)
+	}
+
+	// The migrated file loads without further migrations
+	cfg, err = LoadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error reloading: %v", err)
+	}
+	if len(cfg.Migrations) != 0 || cfg.Version != currentVersion() {
+		t.Errorf("expected migrated file at version %d, got version %d with notes %v", currentVersion(), cfg.Version, cfg.Migrations)
+	}
+	if got := cfg.Profiles["work"].Ignore; !slices.Equal(got, []string{"dist", ".git"}) {
+		t.Errorf("expected migrated work profile to be saved, got %v", got)
+	}
+}
+
+func TestLoadFile_Unversioned(t *testing.T) {
+	// Files without a version field are version 0 and run every migration
+	path := writeConfig(t, `{"profiles": {"work": {"root": "~/work", "ignore": ["dist"]}}}`)
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := cfg.Profiles["work"].Ignore; !slices.Equal(got, []string{"dist", ".git"}) {
+		t.Errorf("expected .git added to the work profile, got %v", got)
+	}
+	if cfg.Version != currentVersion() {
+		t.Errorf("expected version %d, got %d", currentVersion(), cfg.Version)
+	}
+}
+
+func TestLoadFile_NewerVersion(t *testing.T) {
+	path := writeConfig(t, `{"version": 99}`)
+
+	if _, err := LoadFile(path); !errors.Is(err, statefile.ErrNewerVersion) {
+		t.Errorf("expected ErrNewerVersion, got %v", err)
+	}
+}
+
+func TestLoadFile_CurrentVersionNotRewritten(t *testing.T) {
+	path := writeConfig(t, `{"version": 2, "navigation_debounce_ms": 10}`)
+
+	if _, err := LoadFile(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(path + backupExt); !os.IsNotExist(err) {
+		t.Error("expected no backup for an up-to-date config")
+	}
+}
+
+func TestLoadFile_Ranking(t *testing.T) {
+	path := writeConfig(t, `{"ranking": [{"under": "~/work", "boost": 10}, {"contains": "backup", "boost": -5}]}`)
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.Ranking) != 2 {
+		t.Fatalf("expected 2 ranking rules, got %d", len(cfg.Ranking))
+	}
+	if !filepath.IsAbs(cfg.Ranking[0].Under) {
+		t.Errorf("expected under path to be expanded, got %q", cfg.Ranking[0].Under)
+	}
+	if cfg.Ranking[1].Contains != "backup" || cfg.Ranking[1].Boost != -5 {
+		t.Errorf("expected backup penalty, got %+v", cfg.Ranking[1])
+	}
+}
+
+func TestLoadFile_Colors(t *testing.T) {
+	path := writeConfig(t, `{"colors": [{"under": "~/work", "color": "Blue"}, {"pattern": "*-prod*", "color": "#ff0000"}]}`)
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.Colors) != 2 {
+		t.Fatalf("expected 2 color rules, got %d", len(cfg.Colors))
+	}
+	if !filepath.IsAbs(cfg.Colors[0].Under) {
+		t.Errorf("expected under path to be expanded, got %q", cfg.Colors[0].Under)
+	}
+	if got := cfg.Colors[0].TerminalColor(); got != "4" {
+		t.Errorf("expected blue to resolve to ANSI 4, got %q", got)
+	}
+	if got := cfg.Colors[1].TerminalColor(); got != "#ff0000" {
+		t.Errorf("expected hex color to be kept, got %q", got)
+	}
+}
+
+func TestLoadFile_InvalidColors(t *testing.T) {
+	for _, rule := range []string{
+		`{"pattern": "x", "color": "chartreuse"}`,
+		`{"pattern": "[", "color": "red"}`,
+		`{"color": "red"}`,
+	} {
+		path := writeConfig(t, `{"colors": [`+rule+`]}`)
+		if _, err := LoadFile(path); err == nil {
+			t.Errorf("expected error for color rule %s, got nil", rule)
+		}
+	}
+}
+
+func TestLoadFile_Templates(t *testing.T) {
+	path := writeConfig(t, `{"title_template": "{{.Profile}}: {{.Path}}", "prompt_template": "» "}`)
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.TitleTemplate != "{{.Profile}}: {{.Path}}" || cfg.PromptTemplate != "» " {
+		t.Errorf("expected templates to be loaded, got %q and %q", cfg.TitleTemplate, cfg.PromptTemplate)
+	}
+
+	path = writeConfig(t, `{"title_template": "{{.Path"}`)
+	if _, err := LoadFile(path); err == nil {
+		t.Error("expected error for invalid title template, got nil")
+	}
+}
+
+func TestLoadFile_Decorations(t *testing.T) {
+	path := writeConfig(t, `{"decorations": ["mtime", "git"]}`)
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.Decorations) != 2 {
+		t.Errorf("expected 2 decorations, got %v", cfg.Decorations)
+	}
+
+	path = writeConfig(t, `{"decorations": ["owner"]}`)
+	if _, err := LoadFile(path); err == nil {
+		t.Error("expected error for unknown decoration, got nil")
+	}
+}
+
+func TestLoadFile_Ignore(t *testing.T) {
+	cfg, err := LoadFile(writeConfig(t, `{"ignore": ["vendor", "build-*"]}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.Ignore) != 2 || cfg.Ignore[1] != "build-*" {
+		t.Errorf("expected ignore list [vendor build-*], got %v", cfg.Ignore)
+	}
+
+	if _, err := LoadFile(writeConfig(t, `{"ignore": ["[abc"]}`)); err == nil {
+		t.Error("expected error for malformed ignore pattern, got nil")
+	}
+	if _, err := LoadFile(writeConfig(t, `{"ignore": ["src/tmp"]}`)); err == nil {
+		t.Error("expected error for path ignore pattern, got nil")
+	}
+}
+
+func TestLoadFile_LowPower(t *testing.T) {
+	cfg, err := LoadFile(writeConfig(t, `{"low_power": "on"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.LowPowerEnabled() {
+		t.Error("expected low power mode to be enabled")
+	}
+
+	cfg, err = LoadFile(writeConfig(t, `{"low_power": "off"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.LowPowerEnabled() {
+		t.Error("expected low power mode to be disabled")
+	}
+
+	if _, err := LoadFile(writeConfig(t, `{"low_power": "sometimes"}`)); err == nil {
+		t.Error("expected error for invalid low_power, got nil")
+	}
+}
+
+func TestLoadFile_Log(t *testing.T) {
+	cfg, err := LoadFile(writeConfig(t, `{"log": {"format": "json", "file": "~/folder-search.log", "level": "warn", "levels": {"ui": "debug"}}}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	home, _ := os.UserHomeDir()
+	if want := filepath.Join(home, "folder-search.log"); cfg.Log.File != want {
+		t.Errorf("expected log file %q, got %q", want, cfg.Log.File)
+	}
+	if cfg.Log.MaxSizeMB != Default().Log.MaxSizeMB || cfg.Log.MaxFiles != Default().Log.MaxFiles {
+		t.Errorf("expected default rotation, got %d MB and %d files", cfg.Log.MaxSizeMB, cfg.Log.MaxFiles)
+	}
+
+	opts, err := cfg.Log.Options()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.Level != slog.LevelWarn || opts.Levels["ui"] != slog.LevelDebug {
+		t.Errorf("expected warn with ui at debug, got %v and %v", opts.Level, opts.Levels)
+	}
+	if opts.MaxSize != 10<<20 {
+		t.Errorf("expected max size %d, got %d", 10<<20, opts.MaxSize)
+	}
+
+	for _, content := range []string{
+		`{"log": {"format": "xml"}}`,
+		`{"log": {"level": "loud"}}`,
+		`{"log": {"levels": {"network": "debug"}}}`,
+		`{"log": {"levels": {"ui": "loud"}}}`,
+	} {
+		if _, err := LoadFile(writeConfig(t, content)); err == nil {
+			t.Errorf("expected error for %s, got nil", content)
+		}
+	}
+}
+
+func TestLoadFile_Time(t *testing.T) {
+	cfg, err := LoadFile(writeConfig(t, `{"time": {"style": "absolute", "locale": "de_DE.UTF-8"}}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	times, err := cfg.Time.Formatter()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := times.Format(time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), time.Now()); got != "31.12.2024" {
+		t.Errorf("expected 31.12.2024, got %q", got)
+	}
+
+	if _, err := LoadFile(writeConfig(t, `{"time": {"style": "fuzzy"}}`)); err == nil {
+		t.Error("expected error for an unknown time style, got nil")
+	}
+}
+
+func TestLoadFile_SizeUnits(t *testing.T) {
+	cfg, err := LoadFile(writeConfig(t, `{"size_units": "si"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := cfg.Sizes().Format(1500); got != "1.5 kB" {
+		t.Errorf("expected 1.5 kB, got %q", got)
+	}
+	if got := Default().Sizes().Format(1536); got != "1.5 KiB" {
+		t.Errorf("expected binary units by default, got %q", got)
+	}
+
+	if _, err := LoadFile(writeConfig(t, `{"size_units": "decimal"}`)); err == nil {
+		t.Error("expected error for unknown size units, got nil")
+	}
+}
+
+func TestLoadFile_Alerts(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+
+	cfg, err := LoadFile(writeConfig(t, `{"alerts": {"command": "true", "rules": [
+		{"name": "downloads", "path": "~/Downloads", "max_size": "20G"},
+		{"pattern": "node_modules", "max_size": "50G"}]}}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.Alerts.Rules) != 2 {
+		t.Fatalf("expected 2 rules, got %d", len(cfg.Alerts.Rules))
+	}
+	if want := filepath.Join(home, "Downloads"); cfg.Alerts.Rules[0].Path != want {
+		t.Errorf("expected path %s, got %s", want, cfg.Alerts.Rules[0].Path)
+	}
+
+	for _, rules := range []string{
+		`[{"path": "/tmp", "max_size": "lots"}]`,
+		`[{"path": "/tmp", "pattern": "tmp", "max_size": "1G"}]`,
+		`[{"pattern": "node_modules"}]`,
+	} {
+		if _, err := LoadFile(writeConfig(t, `{"alerts": {"rules": `+rules+`}}`)); err == nil {
+			t.Errorf("expected error for rules %s, got nil", rules)
+		}
+	}
+}
+
+func TestLoadFile_Experimental(t *testing.T) {
+	cfg, err := LoadFile(writeConfig(t, `{"experimental": {"watch": false, "daemon": true}}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if on, ok := cfg.Experimental["watch"]; !ok || on {
+		t.Errorf("expected watch to be turned off, got %v", cfg.Experimental)
+	}
+	if !cfg.Experimental["daemon"] {
+		t.Errorf("expected unknown features to be kept for the caller to report, got %v", cfg.Experimental)
+	}
+}
+
+func TestAddIgnore(t *testing.T) {
+	path := writeConfig(t, `{"version": 1, "inline_notes": true, "ignore": ["vendor"]}`)
+
+	if err := AddIgnore(path, "build-*"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := AddIgnore(path, "vendor"); err != nil {
+		t.Fatalf("unexpected error adding a listed pattern: %v", err)
+	}
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.Ignore) != 2 || cfg.Ignore[0] != "vendor" || cfg.Ignore[1] != "build-*" {
+		t.Errorf("expected ignore list [vendor build-*], got %v", cfg.Ignore)
+	}
+	if !cfg.InlineNotes {
+		t.Error("expected other settings to be kept")
+	}
+}
+
+func TestAddIgnore_Migrates(t *testing.T) {
+	path := writeConfig(t, `{"version": 1, "profiles": {"work": {"root": "~/work", "ignore": ["dist"]}}}`)
+
+	if err := AddIgnore(path, "vendor"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var fields map[string]json.RawMessage
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read config: %v", err)
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatalf("failed to parse config: %v", err)
+	}
+	if got := string(fields["version"]); got != fmt.Sprint(currentVersion()) {
+		t.Errorf("expected version %d, got %s", currentVersion(), got)
+	}
+	if !strings.Contains(string(fields["profiles"]), `".git"`) {
+		t.Errorf("expected the work profile to be migrated, got %s", fields["profiles"])
+	}
+}
+
+func TestAddIgnore_WaitsForLock(t *testing.T) {
+	path := writeConfig(t, `{"version": 2}`)
+
+	lock, err := statefile.Acquire(path, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		lock.Release()
+	}()
+
+	if err := AddIgnore(path, "dist"); err != nil {
+		t.Fatalf("expected AddIgnore to wait for the lock, got %v", err)
+	}
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(cfg.Ignore, []string{"dist"}) {
+		t.Errorf("expected ignore list [dist], got %v", cfg.Ignore)
+	}
+}
+
+func TestAddIgnore_CreatesFile(t *testing.T) {
+	dir, err := os.MkdirTemp("", "config-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, dirName, fileName)
+	if err := AddIgnore(path, "dist"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.Ignore) != 1 || cfg.Ignore[0] != "dist" {
+		t.Errorf("expected ignore list [dist], got %v", cfg.Ignore)
+	}
+	if cfg.Version != currentVersion() {
+		t.Errorf("expected version %d, got %d", currentVersion(), cfg.Version)
+	}
+}
@@ -0,0 +1,242 @@
+// Package dirmeta collects the metadata shown next to directory names, such
+// as modification times, entry counts, git branches and sizes.
+//
+// Listing a directory only needs the names of its entries, which a single
+// ReadDir provides. Metadata needs at least one system call per entry and,
+// for sizes, a walk of the whole subtree, so it is collected separately by
+// Collect after the names are shown, on a pool of workers that delivers
+// each directory's metadata as soon as it is known.
+package dirmeta
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kaczmarekdaniel/folder-search/internal/dirsearch"
+	"github.com/kaczmarekdaniel/folder-search/internal/gitinfo"
+)
+
+// DefaultWorkers is the number of directories Collect inspects concurrently
+// when no positive worker count is given.
+const DefaultWorkers = 8
+
+// Field selects a piece of metadata to collect.
+type Field uint8
+
+const (
+	// ModTime is the modification time of the directory
+	ModTime Field = 1 << iota
+
+	// Entries is the number of entries in the directory
+	Entries
+
+	// Git is the branch checked out if the directory is a repository root
+	Git
+
+	// Size is the total size of the files below the directory; it walks
+	// the whole subtree and is by far the most expensive field
+	Size
+
+	// Project is the kinds of project the directory is the root of, such
+	// as a Go module or a git repository
+	Project
+)
+
+// fieldNames maps the names used in the config file to fields, in the
+// order they are shown.
+var fieldNames = []struct {
+	name  string
+	field Field
+}{
+	{"mtime", ModTime},
+	{"entries", Entries},
+	{"git", Git},
+	{"project", Project},
+	{"size", Size},
+}
+
+// ParseFields converts field names such as "mtime" and "git" into a Field
+// set.
+//
+// Returns an error naming the valid fields if a name is unknown.
+func ParseFields(names []string) (Field, error) {
+	var fields Field
+	for _, name := range names {
+		found := false
+		for _, f := range fieldNames {
+			if strings.EqualFold(name, f.name) {
+				fields |= f.field
+				found = true
+				break
+			}
+		}
+		if !found {
+			valid := make([]string, len(fieldNames))
+			for i, f := range fieldNames {
+				valid[i] = f.name
+			}
+			return 0, fmt.Errorf("unknown field %q (valid fields: %s)", name, strings.Join(valid, ", "))
+		}
+	}
+	return fields, nil
+}
+
+// Info holds the metadata of a directory. Only the requested fields are set.
+type Info struct {
+	// Path is the absolute path of the directory
+	Path string
+
+	// ModTime is the modification time of the directory
+	ModTime time.Time
+
+	// Entries is the number of entries in the directory
+	Entries int
+
+	// Branch is the git branch checked out in the directory, empty if it
+	// is not the root of a repository
+	Branch string
+
+	// Size is the total size of the regular files below the directory
+	Size int64
+
+	// Project is the kinds of project the directory is the root of
+	Project dirsearch.ProjectType
+
+	// Err is the first error met while collecting; fields collected
+	// before it are still set
+	Err error
+}
+
+// Collect gathers fields for the subdirectories names of dir and sends the
+// Info of each one on the returned channel as soon as it is complete.
+// Directories are picked up in the order given, so the entries at the top
+// of a listing tend to be decorated first.
+//
+// The channel is closed once every directory has been inspected or ctx is
+// canceled; canceling stops the workers after their current directory.
+//
+// Parameters:
+//   - ctx: cancels the collection
+//   - dir: the directory the names are relative to
+//   - names: the subdirectories to inspect
+//   - fields: the metadata to collect
+//   - workers: number of directories inspected concurrently (DefaultWorkers if not positive)
+func Collect(ctx context.Context, dir string, names []string, fields Field, workers int) <-chan Info {
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+
+	jobs := make(chan string)
+	infos := make(chan Info)
+
+	go func() {
+		defer close(jobs)
+		for _, name := range names {
+			select {
+			case jobs <- filepath.Join(dir, name):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for range min(workers, len(names)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range jobs {
+				info := inspect(ctx, path, fields)
+				select {
+				case infos <- info:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(infos)
+	}()
+	return infos
+}
+
+// inspect collects fields for the directory at path.
+func inspect(ctx context.Context, path string, fields Field) Info {
+	info := Info{Path: path}
+
+	if fields&ModTime != 0 {
+		fi, err := os.Stat(path)
+		if err != nil {
+			info.Err = err
+			return info
+		}
+		info.ModTime = fi.ModTime()
+	}
+
+	if fields&Entries != 0 {
+		n, err := countEntries(path)
+		if err != nil {
+			info.Err = err
+			return info
+		}
+		info.Entries = n
+	}
+
+	if fields&Git != 0 {
+		if _, err := os.Lstat(filepath.Join(path, ".git")); err == nil {
+			branch, err := gitinfo.Branch(path)
+			if err != nil {
+				info.Err = err
+				return info
+			}
+			info.Branch = branch
+		}
+	}
+
+	if fields&Project != 0 {
+		info.Project = dirsearch.DetectProject(nil, path)
+	}
+
+	if fields&Size != 0 {
+		size, err := dirsearch.DirSize(ctx, path, nil)
+		if err != nil {
+			info.Err = err
+			return info
+		}
+		info.Size = size
+	}
+
+	return info
+}
+
+// countEntries returns the number of entries in the directory at path
+// without reading more than their names.
+func countEntries(path string) (int, error) {
+	dir, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer dir.Close()
+
+	count := 0
+	for {
+		names, err := dir.Readdirnames(dirsearch.DefaultBatchSize)
+		count += len(names)
+		if errors.Is(err, io.EOF) {
+			return count, nil
+		}
+		if err != nil {
+			return count, err
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package dirmeta
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kaczmarekdaniel/folder-search/internal/dirsearch"
+)
+
+func TestParseFields(t *testing.T) {
+	fields, err := ParseFields([]string{"mtime", "GIT"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fields != ModTime|Git {
+		t.Errorf("expected mtime and git, got %b", fields)
+	}
+
+	if _, err := ParseFields([]string{"owner"}); err == nil {
+		t.Error("expected error for unknown field")
+	}
+}
+
+func TestCollect(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "dirmeta-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	for _, dir := range []string{"repo/.git", "repo/src", "docs"} {
+		if err := os.MkdirAll(filepath.Join(tempDir, dir), 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", dir, err)
+		}
+	}
+	files := map[string]string{
+		"repo/.git/HEAD": "ref: refs/heads/main\n",
+		"docs/a.txt":     "hello",
+		"docs/b.txt":     "world!",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	mtime := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(filepath.Join(tempDir, "docs"), mtime, mtime); err != nil {
+		t.Fatalf("failed to set mtime: %v", err)
+	}
+
+	infos := make(map[string]Info)
+	for info := range Collect(context.Background(), tempDir, []string{"repo", "docs", "missing"}, ModTime|Entries|Git|Project|Size, 2) {
+		infos[filepath.Base(info.Path)] = info
+	}
+
+	if len(infos) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(infos))
+	}
+	repo, docs := infos["repo"], infos["docs"]
+	if repo.Err != nil || docs.Err != nil {
+		t.Fatalf("unexpected errors: %v, %v", repo.Err, docs.Err)
+	}
+	if repo.Branch != "main" {
+		t.Errorf("expected branch main, got %q", repo.Branch)
+	}
+	if docs.Branch != "" {
+		t.Errorf("expected no branch outside a repository root, got %q", docs.Branch)
+	}
+	if repo.Project != dirsearch.ProjectGit || docs.Project != 0 {
+		t.Errorf("expected only repo to be a git project, got %q and %q", repo.Project, docs.Project)
+	}
+	if repo.Entries != 2 || docs.Entries != 2 {
+		t.Errorf("expected 2 entries each, got %d and %d", repo.Entries, docs.Entries)
+	}
+	if docs.Size != 11 {
+		t.Errorf("expected size 11, got %d", docs.Size)
+	}
+	if !docs.ModTime.Equal(mtime) {
+		t.Errorf("expected mtime %v, got %v", mtime, docs.ModTime)
+	}
+	if infos["missing"].Err == nil {
+		t.Error("expected error for missing directory")
+	}
+}
+
+func TestCollect_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	count := 0
+	for range Collect(ctx, os.TempDir(), []string{"a", "b", "c"}, ModTime, 1) {
+		count++
+	}
+	if count > 1 {
+		t.Errorf("expected collection to stop after cancellation, got %d results", count)
+	}
+}
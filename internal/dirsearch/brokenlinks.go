@@ -0,0 +1,65 @@
+package dirsearch
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// FindBrokenLinks walks the tree rooted at root and returns the paths of
+// symbolic links whose targets do not exist.
+//
+// Directories named in ignorePatterns are not descended into, and links are
+// never followed. Unreadable subdirectories are skipped and reported, like
+// Result.Warnings, with their paths relative to root.
+//
+// Parameters:
+//   - ctx: controls cancellation of the walk
+//   - root: the directory to search
+//   - ignorePatterns: directory names to skip, like Options.IgnorePatterns
+//
+// Returns the broken links in lexical order (as root joined with the relative
+// path) and the skipped subdirectories, or an error if root cannot be read or
+// ctx is canceled.
+func FindBrokenLinks(ctx context.Context, root string, ignorePatterns []string) ([]string, []PathError, error) {
+	broken := []string{}
+	var skipped []PathError
+	ignore := newIgnoreSet(ignorePatterns)
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err != nil {
+			if path == root {
+				return err
+			}
+			rel, relErr := filepath.Rel(root, path)
+			if relErr != nil {
+				rel = path
+			}
+			skipped = append(skipped, newPathError(rel, err))
+			if d != nil && d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if d.IsDir() && path != root && ignore.contains(d.Name()) {
+			return filepath.SkipDir
+		}
+
+		if d.Type()&fs.ModeSymlink != 0 {
+			if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+				broken = append(broken, path)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return broken, skipped, nil
+}
@@ -0,0 +1,109 @@
+package dirsearch
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestFindBrokenLinks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("creating symlinks requires privileges on Windows")
+	}
+
+	tempDir, err := os.MkdirTemp("", "dirsearch-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	writeTree(t, tempDir, map[string]string{
+		"target.txt":                "x",
+		"sub/file.txt":              "y",
+		"node_modules/pkg/index.js": "z",
+	})
+
+	links := map[string]string{
+		"good":                     filepath.Join(tempDir, "target.txt"),
+		"dangling":                 filepath.Join(tempDir, "missing.txt"),
+		"sub/dangling-relative":    "../nowhere",
+		"node_modules/pkg/ignored": "missing",
+	}
+	for name, target := range links {
+		if err := os.Symlink(target, filepath.Join(tempDir, filepath.FromSlash(name))); err != nil {
+			t.Fatalf("failed to create symlink %s: %v", name, err)
+		}
+	}
+
+	broken, skipped, err := FindBrokenLinks(context.Background(), tempDir, []string{"node_modules"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(skipped) != 0 {
+		t.Errorf("expected no skipped directories, got %v", skipped)
+	}
+
+	want := []string{
+		filepath.Join(tempDir, "dangling"),
+		filepath.Join(tempDir, "sub", "dangling-relative"),
+	}
+	if len(broken) != len(want) {
+		t.Fatalf("expected %v, got %v", want, broken)
+	}
+	for i := range want {
+		if broken[i] != want[i] {
+			t.Errorf("expected %q, got %q", want[i], broken[i])
+		}
+	}
+}
+
+func TestFindBrokenLinks_MissingRoot(t *testing.T) {
+	_, _, err := FindBrokenLinks(context.Background(), filepath.Join("testdata-does-not-exist", "missing"), nil)
+	if err == nil {
+		t.Error("expected error for missing root, got nil")
+	}
+}
+
+func TestFindBrokenLinks_Unreadable(t *testing.T) {
+	if runtime.GOOS == "windows" || os.Getuid() == 0 {
+		t.Skip("directory permissions are not enforced")
+	}
+
+	tempDir, err := os.MkdirTemp("", "dirsearch-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	for _, dir := range []string{"a/locked/inner", "b/open"} {
+		if err := os.MkdirAll(filepath.Join(tempDir, dir), 0755); err != nil {
+			t.Fatalf("failed to create test dir: %v", err)
+		}
+	}
+	if err := os.Symlink("missing", filepath.Join(tempDir, "b", "open", "dangling")); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+	locked := filepath.Join(tempDir, "a", "locked")
+	if err := os.Chmod(locked, 0); err != nil {
+		t.Fatalf("failed to lock test dir: %v", err)
+	}
+	defer os.Chmod(locked, 0755)
+
+	broken, skipped, err := FindBrokenLinks(context.Background(), tempDir, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(broken) != 1 {
+		t.Errorf("expected 1 broken link, got %v", broken)
+	}
+	if len(skipped) != 1 || skipped[0].Path != filepath.Join("a", "locked") {
+		t.Fatalf("expected a skipped entry for a/locked, got %v", skipped)
+	}
+	if !errors.Is(skipped[0], fs.ErrPermission) {
+		t.Errorf("expected permission error, got %v", skipped[0].Err)
+	}
+}
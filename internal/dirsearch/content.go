@@ -0,0 +1,127 @@
+package dirsearch
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// maxContentSize is the number of bytes read from each file when matching
+// contents; the rest of larger files is not searched.
+const maxContentSize = 1 << 20
+
+// contentMatcher matches files by their contents.
+type contentMatcher struct {
+	text          []byte // Text searched for, lowercased unless caseSensitive
+	files         string // Shell pattern of the names of files read; empty for all
+	caseSensitive bool
+}
+
+// newContentMatcher compiles the content options of opts.
+//
+// Returns nil if opts.ContentPattern is empty, or an error if
+// opts.ContentFiles is not a valid pattern.
+func newContentMatcher(opts *Options) (*contentMatcher, error) {
+	if opts.ContentPattern == "" {
+		return nil, nil
+	}
+	if _, err := filepath.Match(opts.ContentFiles, ""); err != nil {
+		return nil, fmt.Errorf("invalid content file pattern %q: %w", opts.ContentFiles, err)
+	}
+	c := &contentMatcher{text: []byte(opts.ContentPattern), files: opts.ContentFiles, caseSensitive: opts.CaseSensitive}
+	if !c.caseSensitive {
+		c.text = bytes.ToLower(c.text)
+	}
+	return c, nil
+}
+
+// matchContent reports whether the entry at p passes the content filter of
+// the search: a directory must hold a file whose contents match, a file
+// must match itself. Always true without a content filter; unreadable
+// entries do not match.
+func (m *matcher) matchContent(ctx context.Context, p string, isDir bool) bool {
+	c := m.content
+	if c == nil {
+		return true
+	}
+	if !isDir {
+		return c.matchName(m.base(p)) && c.matchFile(m.fsys, p)
+	}
+
+	// A plain file name is opened directly instead of listing the directory
+	if c.files != "" && !strings.ContainsAny(c.files, `*?[\`) {
+		return c.matchFile(m.fsys, m.join(p, c.files))
+	}
+	entries, err := readDir(m.fsys, p)
+	if err != nil {
+		return false
+	}
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return false
+		}
+		if entry.Type().IsRegular() && c.matchName(entry.Name()) && c.matchFile(m.fsys, m.join(p, entry.Name())) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchName reports whether the contents of the file called name are read.
+func (c *contentMatcher) matchName(name string) bool {
+	if c.files == "" {
+		return true
+	}
+	ok, _ := filepath.Match(c.files, name)
+	return ok
+}
+
+// matchFile reports whether the regular file at p, inside fsys if it is not
+// nil, contains the text within its first maxContentSize bytes.
+func (c *contentMatcher) matchFile(fsys fs.FS, p string) bool {
+	var f fs.File
+	var err error
+	if fsys != nil {
+		f, err = fsys.Open(p)
+	} else {
+		f, err = os.Open(p)
+	}
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	if info, err := f.Stat(); err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+	data, err := io.ReadAll(io.LimitReader(f, maxContentSize))
+	if err != nil {
+		return false
+	}
+	if !c.caseSensitive {
+		data = bytes.ToLower(data)
+	}
+	return bytes.Contains(data, c.text)
+}
+
+// base returns the last element of p, inside the searched filesystem.
+func (m *matcher) base(p string) string {
+	if m.fsys != nil {
+		return path.Base(p)
+	}
+	return filepath.Base(p)
+}
+
+// readDir lists the directory at p, inside fsys if it is not nil.
+func readDir(fsys fs.FS, p string) ([]fs.DirEntry, error) {
+	if fsys != nil {
+		return fs.ReadDir(fsys, p)
+	}
+	return os.ReadDir(p)
+}
@@ -0,0 +1,112 @@
+package dirsearch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSearch_Content(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "dirsearch-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	files := map[string]string{
+		filepath.Join("tui", "go.mod"):               "module tui\n\nrequire github.com/charmbracelet/bubbletea v1.3.4\n",
+		filepath.Join("server", "go.mod"):            "module server\n",
+		filepath.Join("server", "notes.txt"):         "try BubbleTea for the admin console\n",
+		filepath.Join("apps", "cli", "go.mod"):       "module cli\n\nrequire github.com/charmbracelet/bubbletea v1.0.0\n",
+		filepath.Join("web", "package.json"):         `{"name": "web"}`,
+		filepath.Join("big", "go.mod"):               strings.Repeat("x", maxContentSize) + "bubbletea",
+		filepath.Join("deep", "nested", "notes.txt"): "bubbletea",
+	}
+	for name, content := range files {
+		path := filepath.Join(tempDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create test dirs: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to create test file %s: %v", name, err)
+		}
+	}
+
+	tests := []struct {
+		name          string
+		pattern       string
+		files         string
+		caseSensitive bool
+		depth         int
+		concurrency   int
+		expected      []string
+	}{
+		{"named file", "bubbletea", "go.mod", false, 1, 1, []string{"tui"}},
+		{"any file", "bubbletea", "", false, 1, 1, []string{"server", "tui"}},
+		{"case sensitive", "bubbletea", "", true, 1, 1, []string{"tui"}},
+		{"glob", "bubbletea", "*.txt", false, 1, 1, []string{"server"}},
+		{"parallel", "bubbletea", "", false, 1, DefaultConcurrency, []string{"server", "tui"}},
+		{"recursive", "bubbletea", "go.mod", false, UnlimitedDepth, 1, []string{filepath.Join("apps", "cli"), "tui"}},
+		{"recursive parallel", "bubbletea", "go.mod", false, UnlimitedDepth, DefaultConcurrency, []string{filepath.Join("apps", "cli"), "tui"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &Options{
+				StartDir:       tempDir,
+				ContentPattern: tt.pattern,
+				ContentFiles:   tt.files,
+				CaseSensitive:  tt.caseSensitive,
+				MaxDepth:       tt.depth,
+				Concurrency:    tt.concurrency,
+				SortBy:         SortName,
+			}
+			result := Search(opts)
+			if result.Error != nil {
+				t.Fatalf("unexpected error: %v", result.Error)
+			}
+			if !slices.Equal(result.Directories, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, result.Directories)
+			}
+		})
+	}
+
+	// Files match by their own contents
+	opts := &Options{StartDir: filepath.Join(tempDir, "server"), ContentPattern: "bubbletea", IncludeFiles: true}
+	result := Search(opts)
+	if expected := []string{"notes.txt"}; !slices.Equal(result.Directories, expected) {
+		t.Errorf("expected %v, got %v", expected, result.Directories)
+	}
+
+	// Paths from an index are read on disk
+	opts = &Options{StartDir: tempDir, ContentPattern: "bubbletea", ContentFiles: "go.mod", MaxDepth: UnlimitedDepth}
+	result = SearchPaths(context.Background(), opts, []string{"apps", filepath.Join("apps", "cli"), "server", "tui"})
+	if expected := []string{filepath.Join("apps", "cli"), "tui"}; !slices.Equal(result.Directories, expected) {
+		t.Errorf("expected %v, got %v", expected, result.Directories)
+	}
+}
+
+func TestSearch_ContentFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"tui/go.mod":    {Data: []byte("require github.com/charmbracelet/bubbletea v1.3.4")},
+		"server/go.mod": {Data: []byte("module server")},
+	}
+
+	result := Search(&Options{FS: fsys, StartDir: ".", ContentPattern: "bubbletea", ContentFiles: "go.mod"})
+	if result.Error != nil {
+		t.Fatalf("unexpected error: %v", result.Error)
+	}
+	if expected := []string{"tui"}; !slices.Equal(result.Directories, expected) {
+		t.Errorf("expected %v, got %v", expected, result.Directories)
+	}
+}
+
+func TestSearch_ContentInvalidFiles(t *testing.T) {
+	result := Search(&Options{StartDir: ".", ContentPattern: "x", ContentFiles: "[go.mod"})
+	if result.Error == nil {
+		t.Error("expected error for invalid content file pattern, got nil")
+	}
+}
@@ -6,17 +6,36 @@
 package dirsearch
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io/fs"
+	"log/slog"
 	"os"
+	"path/filepath"
+	"regexp"
 	"slices"
 	"strings"
+	"time"
 )
 
+// bundleExtensions lists the directory extensions that macOS Finder presents
+// as single opaque items (application bundles, frameworks and plug-ins).
+var bundleExtensions = []string{".app", ".framework", ".bundle", ".plugin"}
+
 // DirSearch represents a directory search instance with configurable options.
 // It provides methods to scan directories and find matches based on specified criteria.
+//
+// Scans never modify Options: each one searches a snapshot of them, so a
+// DirSearch can be used by several goroutines at once as long as Options is
+// not changed while they scan. Use WithOptions for options that only apply
+// to some scans.
 type DirSearch struct {
 	// Options contains the configuration for search operations
 	Options *Options
+
+	// Logger receives a debug record for each scan, or nil for none
+	Logger *slog.Logger
 }
 
 // NewDirSearch creates a new DirSearch instance with default options.
@@ -32,20 +51,73 @@ func NewDirSearch() *DirSearch {
 	}
 }
 
+// WithOptions returns a DirSearch scanning with opts and logging to the
+// logger of d, leaving d as it is. Together with Options.Clone it adjusts
+// the options of some scans without racing with the others:
+//
+//	opts := d.Options.Clone()
+//	opts.SearchPattern = "src"
+//	result := d.WithOptions(opts).ScanDirs(dir)
+func (d *DirSearch) WithOptions(opts *Options) *DirSearch {
+	return &DirSearch{Options: opts, Logger: d.Logger}
+}
+
 // ScanDirs scans the specified directory and returns all matching subdirectories.
 //
-// It updates the StartDir option and performs the search. Only direct child
-// directories are returned (not nested subdirectories).
+// It searches a snapshot of the options with StartDir set to dir, leaving
+// Options unchanged. Only direct child directories are returned (not nested
+// subdirectories).
 //
 // Parameters:
 //   - dir: the directory path to scan
 //
 // Returns a Result containing the list of matching directories or an error.
 func (d *DirSearch) ScanDirs(dir string) Result {
-	d.Options.StartDir = dir
-	return Search(d.Options)
+	return d.ScanDirsContext(context.Background(), dir)
+}
+
+// ScanDirsContext is ScanDirs with cancellation, see SearchContext.
+func (d *DirSearch) ScanDirsContext(ctx context.Context, dir string) Result {
+	opts := d.snapshot(dir)
+	start := time.Now()
+	result := SearchContext(ctx, opts)
+	d.logScan(ctx, opts, start, result)
+	return result
+}
+
+// snapshot returns a copy of the options of d searching dir, which the
+// search owns.
+func (d *DirSearch) snapshot(dir string) *Options {
+	opts := d.Options.Clone()
+	opts.StartDir = dir
+	return opts
+}
+
+// logScan logs a scan with opts started at start, if d has a logger. The
+// record is logged with ctx, which may carry attributes identifying the
+// scan. Entries skipped by the scan are logged as warnings.
+func (d *DirSearch) logScan(ctx context.Context, opts *Options, start time.Time, result Result) {
+	if d.Logger == nil {
+		return
+	}
+	dir := opts.StartDir
+	d.Logger.DebugContext(ctx, "scan finished", "dir", dir, "pattern", opts.SearchPattern,
+		"count", len(result.Directories), "duration", time.Since(start), "error", result.Error,
+		"skipped", len(result.Warnings))
+	for i, w := range result.Warnings {
+		if i == maxLoggedWarnings {
+			d.Logger.WarnContext(ctx, "more entries skipped", "dir", dir, "count", len(result.Warnings)-i)
+			break
+		}
+		d.Logger.WarnContext(ctx, "skipped unreadable entry", "dir", dir, "path", w.Path, "error", w.Err)
+	}
 }
 
+// maxLoggedWarnings is the number of Result.Warnings logged one by one by a
+// scan; the rest are only counted, so a tree full of unreadable directories
+// does not flood the log.
+const maxLoggedWarnings = 20
+
 // Options configures the behavior of directory search operations.
 type Options struct {
 	// SearchPattern is the pattern to match against directory names.
@@ -55,115 +127,523 @@ type Options struct {
 	// StartDir is the directory where the search begins.
 	StartDir string
 
+	// StartDirs, if not empty, replaces StartDir with several directories
+	// searched one after the other by Search and SearchContext, e.g.
+	// ~/code and ~/work. Their results are merged and sorted together, and
+	// Result.Roots holds the directory each entry was found in. An entry
+	// reached below several roots, because they overlap or one is a symlink
+	// to another, is only listed under the first; entries are compared by
+	// device and inode, or by resolved path where the platform has none.
+	// Other searches only use StartDir.
+	StartDirs []string
+
 	// CaseSensitive determines whether pattern matching is case-sensitive.
 	CaseSensitive bool
 
+	// Regex compiles SearchPattern as a regular expression (RE2 syntax)
+	// matched anywhere in directory names, instead of a plain substring.
+	// An invalid expression makes the search fail with an error.
+	Regex bool
+
+	// Fuzzy matches SearchPattern as a subsequence of directory names, so
+	// "fsr" matches "folder-search-results", and orders results by match
+	// score. It cannot be combined with Regex.
+	Fuzzy bool
+
 	// IgnorePatterns is a list of directory names to skip during traversal.
+	// Patterns may use shell wildcards, e.g. "build-*". A pattern starting
+	// with "!" keeps matching directories listed even if another pattern
+	// ignores them, e.g. "!.git".
 	IgnorePatterns []string
+
+	// Hidden selects which entries whose names start with a dot are
+	// listed. The zero value lists them all except directories whose names
+	// start with ".git"; DefaultOptions lists them all except those in
+	// IgnorePatterns.
+	Hidden HiddenMode
+
+	// MaxDepth is how many levels below StartDir Search descends. Zero and
+	// one read only the immediate children; UnlimitedDepth (or any negative
+	// value) searches the whole tree.
+	MaxDepth int
+
+	// Concurrency is the number of directories a recursive search reads in
+	// parallel. Zero and one read them one at a time.
+	Concurrency int
+
+	// IncludeFiles makes the search return regular files whose names match
+	// as well as directories. Result.Types tells them apart.
+	IncludeFiles bool
+
+	// SortBy selects the order of the results; see Result.Sort
+	SortBy SortKey
+
+	// SortOrder is the direction of SortBy
+	SortOrder SortOrder
+
+	// MinSize and MaxSize, if positive, only keep entries whose size in
+	// bytes is at least MinSize and at most MaxSize. Directories are
+	// measured by the total size of the files inside them, which reads
+	// their whole subtree, so size bounds make searches much slower.
+	MinSize int64
+	MaxSize int64
+
+	// ModifiedAfter and ModifiedBefore, if not zero, only keep entries
+	// last modified after ModifiedAfter and before ModifiedBefore. The
+	// modification time of a directory changes when entries are added to
+	// or removed from it, not when files inside it are edited.
+	ModifiedAfter  time.Time
+	ModifiedBefore time.Time
+
+	// ContentPattern, if set, only keeps directories holding a file whose
+	// contents include this text, e.g. directories with a go.mod that
+	// mentions "bubbletea". Only files directly inside a directory are
+	// read, and only their first megabyte; files returned with
+	// IncludeFiles match by their own contents. Case is ignored unless
+	// CaseSensitive is set.
+	ContentPattern string
+
+	// ContentFiles limits the files read for ContentPattern to those whose
+	// names match this shell pattern, e.g. "go.mod" or "*.toml". Empty
+	// reads every regular file.
+	ContentFiles string
+
+	// ProjectTypes, if not zero, only keeps directories that are the root
+	// of a project of one of these types, e.g. ProjectGit|ProjectGo for
+	// git repositories and Go modules. Files never match.
+	ProjectTypes ProjectType
+
+	// DetectProjects fills Result.Projects with the project types of the
+	// results, which looks for their markers inside every directory.
+	DetectProjects bool
+
+	// Filters exclude entries for reasons the other options cannot
+	// express, e.g. by owner, by a marker file inside a directory or by
+	// mount point. An entry is kept only if every filter returns true, and
+	// a directory excluded by a filter is skipped together with its
+	// subtree. Filters are called during the traversal for entries that
+	// are neither hidden nor ignored, before the size, time and content
+	// checks. Recursive searches with Concurrency above one call them
+	// concurrently, so they must be safe for concurrent use.
+	Filters []FilterFunc
+
+	// MaxResults stops the search once that many matches are found and
+	// marks the Result as truncated. Matches are kept in the order they are
+	// found, before sorting, so a truncated fuzzy search holds the best of
+	// the first matches rather than the best overall. Zero or negative
+	// means no limit.
+	MaxResults int
+
+	// FS is searched instead of the operating system's filesystem when set,
+	// e.g. an in-memory tree. StartDir is then a slash-separated path inside
+	// FS as accepted by fs.ValidPath, such as "." or "src/app", and
+	// recursive results use forward slashes. FS is read by a single worker
+	// whatever Concurrency says.
+	FS fs.FS
+}
+
+// Clone returns a copy of o that can be modified without affecting o, e.g.
+// to change the options of a single search. The slices are copied; FS and
+// the filter functions are shared.
+func (o *Options) Clone() *Options {
+	c := *o
+	c.StartDirs = slices.Clone(o.StartDirs)
+	c.IgnorePatterns = slices.Clone(o.IgnorePatterns)
+	c.Filters = slices.Clone(o.Filters)
+	return &c
+}
+
+// EntryType is the kind of filesystem entry a search result refers to.
+type EntryType uint8
+
+const (
+	// Dir is a directory
+	Dir EntryType = iota
+
+	// File is a regular file
+	File
+)
+
+// String returns "dir" or "file".
+func (t EntryType) String() string {
+	if t == File {
+		return "file"
+	}
+	return "dir"
 }
 
+// UnlimitedDepth makes Search descend into every level below StartDir.
+const UnlimitedDepth = -1
+
 // Result contains the outcome of a directory search operation.
 type Result struct {
-	// Directories is the list of matching directory paths (relative to StartDir)
+	// Directories is the list of matching directory paths (relative to
+	// StartDir). With Options.IncludeFiles it holds matching files too.
 	Directories []string
 
 	// Error contains any error that occurred during the search
 	Error error
+
+	// Scores holds the fuzzy match score of each directory, in the same
+	// order as Directories; higher is better. It is only set by Search and
+	// SearchContext for fuzzy searches with a pattern and the default
+	// SortBy.
+	Scores []int
+
+	// Types holds the type of each entry, in the same order as
+	// Directories. It is only set by searches with Options.IncludeFiles.
+	Types []EntryType
+
+	// Truncated reports that the search stopped at Options.MaxResults and
+	// more entries would have matched.
+	Truncated bool
+
+	// Roots holds the element of Options.StartDirs each entry was found
+	// in, in the same order as Directories, whose paths are relative to it.
+	// It is only set by searches of StartDirs.
+	Roots []string
+
+	// MatchedRanges holds, in the same order as Directories, the byte
+	// ranges of each entry matched by Options.SearchPattern, as [start,
+	// end) offsets into its path in increasing order, so that consumers
+	// can highlight the matched characters. The ranges lie within the last
+	// element of the path: every occurrence of a plain pattern or regular
+	// expression, or the characters picked by a fuzzy pattern. It is only
+	// set by Search, SearchContext and SearchPaths when SearchPattern is
+	// not empty.
+	MatchedRanges [][][2]int
+
+	// Projects holds the project types of each entry, in the same order as
+	// Directories; files have none. It is only set by Search,
+	// SearchContext and SearchPaths with Options.DetectProjects.
+	Projects []ProjectType
+
+	// Warnings lists the entries below StartDir that could not be read and
+	// were skipped, such as subdirectories without read permission, in walk
+	// order. Only recursive searches read below StartDir, so other searches
+	// leave it empty.
+	Warnings []PathError
+}
+
+// PathError records an entry that a search skipped because it could not be
+// read.
+type PathError struct {
+	// Path is the path of the entry relative to StartDir, or for searches
+	// of StartDirs, joined to the root it was found in
+	Path string
+
+	// Err tells why the entry could not be read, e.g. fs.ErrPermission
+	Err error
+}
+
+// newPathError returns the PathError of the entry at rel, unwrapping err
+// from an *fs.PathError that repeats the path.
+func newPathError(rel string, err error) PathError {
+	if pathErr, ok := err.(*fs.PathError); ok {
+		err = pathErr.Err
+	}
+	return PathError{Path: rel, Err: err}
+}
+
+// Error returns the path of the entry and the reason it was skipped.
+func (e PathError) Error() string {
+	return e.Path + ": " + e.Err.Error()
+}
+
+// Unwrap returns Err, so that errors.Is(e, fs.ErrPermission) works.
+func (e PathError) Unwrap() error {
+	return e.Err
+}
+
+// reorder sorts the entries of r by cmp, which compares the entries at two
+// indexes, keeping Scores, Types, Roots, MatchedRanges and Projects
+// aligned with Directories.
+func (r *Result) reorder(cmp func(i, j int) int) {
+	idx := make([]int, len(r.Directories))
+	for i := range idx {
+		idx[i] = i
+	}
+	slices.SortStableFunc(idx, cmp)
+	r.pick(idx)
+}
+
+// Keep removes the entries of r for which keep, called with the index of
+// each entry, returns false. Scores, Types, Roots, MatchedRanges and
+// Projects stay aligned with Directories.
+func (r *Result) Keep(keep func(i int) bool) {
+	idx := make([]int, 0, len(r.Directories))
+	for i := range r.Directories {
+		if keep(i) {
+			idx = append(idx, i)
+		}
+	}
+	r.pick(idx)
+}
+
+// pick replaces the entries of r by those at the indexes idx, in order.
+func (r *Result) pick(idx []int) {
+	r.Directories = permute(r.Directories, idx)
+	if r.Scores != nil {
+		r.Scores = permute(r.Scores, idx)
+	}
+	if r.Types != nil {
+		r.Types = permute(r.Types, idx)
+	}
+	if r.Roots != nil {
+		r.Roots = permute(r.Roots, idx)
+	}
+	if r.MatchedRanges != nil {
+		r.MatchedRanges = permute(r.MatchedRanges, idx)
+	}
+	if r.Projects != nil {
+		r.Projects = permute(r.Projects, idx)
+	}
 }
 
+// permute returns the elements of s in the order given by idx.
+func permute[T any](s []T, idx []int) []T {
+	out := make([]T, len(idx))
+	for i, j := range idx {
+		out[i] = s[j]
+	}
+	return out
+}
+
+// HiddenMode selects which entries whose names start with a dot are listed.
+type HiddenMode uint8
+
+const (
+	// HiddenExceptGit lists hidden entries except directories whose names
+	// start with ".git", such as .git and .github
+	HiddenExceptGit HiddenMode = iota
+
+	// HiddenShow lists hidden entries; only IgnorePatterns leave them out
+	HiddenShow
+
+	// HiddenHide leaves out every entry whose name starts with a dot
+	HiddenHide
+)
+
 // DefaultOptions returns the default search options.
 //
 // Returns Options configured with:
 //   - Empty search pattern (matches all)
 //   - Current directory as start directory
 //   - Case-insensitive matching
-//   - node_modules in ignore list
+//   - .git and node_modules in ignore list
+//   - Hidden entries included
+//   - Immediate children only (MaxDepth 1)
+//   - DefaultConcurrency directories read in parallel by recursive searches
 func DefaultOptions() *Options {
 	return &Options{
 		SearchPattern:  "",
 		StartDir:       ".",
 		CaseSensitive:  false,
-		IgnorePatterns: []string{"node_modules"},
+		IgnorePatterns: []string{".git", "node_modules"},
+		Hidden:         HiddenShow,
+		MaxDepth:       1,
+		Concurrency:    DefaultConcurrency,
 	}
 }
 
 // Search performs a directory search with the given options.
 //
-// It reads only the immediate child directories of opts.StartDir,
-// applying the following rules:
-//   - Skips .git directories automatically
+// By default it reads only the immediate child directories of
+// opts.StartDir, applying the following rules:
+//   - Skips hidden directories as selected by opts.Hidden
 //   - Skips directories matching patterns in opts.IgnorePatterns
 //   - Matches directory names against opts.SearchPattern (if provided)
 //   - Returns only direct child directories (not nested subdirectories)
 //   - Returns relative paths from opts.StartDir
 //
 // The function uses os.ReadDir for non-recursive, efficient directory reading.
-// Permission errors and other read errors are silently skipped.
+// Entries that cannot be read are skipped; recursive searches list them in
+// Result.Warnings.
+//
+// With opts.MaxDepth above one or negative, the tree is traversed with
+// FindDirs instead: nested matches are returned as relative paths such as
+// "src/app", in lexical order, and ignored directories are skipped together
+// with their subtrees.
 //
 // Parameters:
 //   - opts: configuration options for the search
 //
 // Returns a Result with matching directories or an error.
 func Search(opts *Options) Result {
-	foundDirs := []string{}
+	return SearchContext(context.Background(), opts)
+}
 
-	// Prepare pattern for search
-	var pattern string
-	if opts.CaseSensitive {
-		pattern = opts.SearchPattern
+// SearchContext performs the same search as Search and stops early when
+// ctx is canceled. The directory is read in batches of DefaultBatchSize
+// and ctx is checked between batches and, for recursive searches, between
+// directories, so canceling a scan of a huge directory or deep tree takes
+// effect promptly.
+//
+// Parameters:
+//   - ctx: cancels the search
+//   - opts: configuration options for the search
+//
+// Returns a Result with matching directories ordered as selected by
+// opts.SortBy and opts.SortOrder (by default by name, or by score for fuzzy
+// searches), or an error.
+// A canceled search returns ctx.Err() along with the directories found so
+// far.
+func SearchContext(ctx context.Context, opts *Options) Result {
+	var result Result
+	if len(opts.StartDirs) > 0 {
+		result = searchRoots(ctx, opts)
+	} else if opts.MaxDepth > 1 || opts.MaxDepth < 0 {
+		result = searchTree(ctx, opts)
 	} else {
-		pattern = strings.ToLower(opts.SearchPattern)
+		result = SearchStream(ctx, opts, DefaultBatchSize, func([]string) {})
 	}
+	result.finish(ctx, opts)
+	return result
+}
 
-	nameProvided := opts.SearchPattern != ""
+// finish orders the entries of a completed search as selected by opts and
+// fills in MatchedRanges and Projects.
+func (r *Result) finish(ctx context.Context, opts *Options) {
+	r.Sort(ctx, opts)
+	r.setMatchedRanges(opts)
+	r.setProjects(opts)
+}
 
-	// Read only immediate children (non-recursive)
-	entries, err := os.ReadDir(opts.StartDir)
-	if err != nil {
-		return Result{
-			Directories: foundDirs,
-			Error:       err,
-		}
-	}
+// searchTree performs a recursive Search bounded by opts.MaxDepth.
+func searchTree(ctx context.Context, opts *Options) Result {
+	// FindDirs treats depths below one as unlimited
+	depth := max(opts.MaxDepth, 0)
+	return findEntries(ctx, opts, depth, opts.MaxResults)
+}
 
-	// Process each entry
-	for _, entry := range entries {
-		// Skip non-directories
-		if !entry.IsDir() {
-			continue
-		}
+// matcher holds the search options compiled for matching many entries.
+type matcher struct {
+	pattern       string         // Search pattern, normalized and folded for case-insensitive searches
+	re            *regexp.Regexp // Compiled pattern when Options.Regex is set
+	fuzzy         bool
+	includeFiles  bool
+	hiddenMode    HiddenMode
+	caseSensitive bool
+	ignore        ignoreSet
+	minSize       int64           // Smallest size kept; zero or negative for no bound
+	maxSize       int64           // Largest size kept; zero or negative for no bound
+	modAfter      time.Time       // Oldest modification time kept; zero for no bound
+	modBefore     time.Time       // Newest modification time kept; zero for no bound
+	content       *contentMatcher // Matches file contents; nil for no content filter
+	projects      ProjectType     // Project types kept; zero for no project filter
+	fsys          fs.FS           // Filesystem inspected by the filters; nil for the disk
+	sizes         *sizeCache      // Sizes of the directories measured by the size filter
+	filters       []FilterFunc    // Options.Filters
+}
 
-		name := entry.Name()
+// newMatcher compiles opts for matching directory entries.
+//
+// Returns an error if opts.Regex is set and the pattern is not a valid
+// regular expression, if both Regex and Fuzzy are set, or if
+// opts.ContentFiles is not a valid pattern.
+func newMatcher(opts *Options) (*matcher, error) {
+	if opts.Regex && opts.Fuzzy {
+		return nil, errors.New("regex and fuzzy matching cannot be combined")
+	}
 
-		// Skip .git directories
-		if strings.HasPrefix(name, ".git") {
-			continue
+	m := &matcher{
+		pattern:       opts.SearchPattern,
+		fuzzy:         opts.Fuzzy,
+		includeFiles:  opts.IncludeFiles,
+		hiddenMode:    opts.Hidden,
+		caseSensitive: opts.CaseSensitive,
+		ignore:        newIgnoreSet(opts.IgnorePatterns),
+		minSize:       opts.MinSize,
+		maxSize:       opts.MaxSize,
+		modAfter:      opts.ModifiedAfter,
+		modBefore:     opts.ModifiedBefore,
+		projects:      opts.ProjectTypes,
+		fsys:          opts.FS,
+		sizes:         newSizeCache(),
+		filters:       opts.Filters,
+	}
+	content, err := newContentMatcher(opts)
+	if err != nil {
+		return nil, err
+	}
+	m.content = content
+	if opts.Regex && m.pattern != "" {
+		expr := m.pattern
+		if !m.caseSensitive {
+			expr = "(?i)" + expr
 		}
-
-		// Skip directories in ignore patterns
-		if slices.Contains(opts.IgnorePatterns, name) {
-			continue
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid search pattern: %w", err)
 		}
+		m.re = re
+		return m, nil
+	}
+	switch {
+	case m.fuzzy:
+		m.pattern = foldRunes(normalize(m.pattern), m.caseSensitive)
+	case m.caseSensitive:
+		m.pattern = normalize(m.pattern)
+	default:
+		m.pattern = FoldCase(m.pattern)
+	}
+	return m, nil
+}
 
-		// Check if it matches the search pattern
-		var matches bool
-		if !nameProvided {
-			matches = true
-		} else if opts.CaseSensitive {
-			matches = strings.Contains(name, pattern)
-		} else {
-			matches = strings.Contains(strings.ToLower(name), pattern)
-		}
+// skip reports whether a directory name is hidden from results together
+// with its subtree: hidden directories as selected by the HiddenMode and
+// names in the ignore list.
+func (m *matcher) skip(name string) bool {
+	if m.hiddenMode == HiddenExceptGit && strings.HasPrefix(name, ".git") {
+		return true
+	}
+	return m.hidden(name) || m.ignore.contains(name)
+}
+
+// hidden reports whether an entry is left out for being hidden.
+func (m *matcher) hidden(name string) bool {
+	return m.hiddenMode == HiddenHide && strings.HasPrefix(name, ".")
+}
 
-		if matches {
-			foundDirs = append(foundDirs, name)
+// matchEntry reports whether a directory entry should be part of the results.
+func (m *matcher) matchEntry(entry os.DirEntry) bool {
+	name := entry.Name()
+	if entry.IsDir() {
+		if m.skip(name) {
+			return false
 		}
+	} else if !m.includeFiles || !entry.Type().IsRegular() || m.hidden(name) {
+		// Skip non-directories unless files were asked for
+		return false
 	}
+	return m.matchName(name)
+}
 
-	return Result{
-		Directories: foundDirs,
-		Error:       nil,
+// matchName reports whether a name matches the search pattern.
+func (m *matcher) matchName(name string) bool {
+	if m.re != nil {
+		return m.re.MatchString(normalize(name))
+	} else if m.pattern == "" {
+		return true
+	} else if m.fuzzy {
+		_, ok := fuzzyScore(name, m.pattern, m.caseSensitive)
+		return ok
+	} else if m.caseSensitive {
+		return strings.Contains(normalize(name), m.pattern)
 	}
+	return containsFold(name, m.pattern)
+}
+
+// IsBundle reports whether a directory name denotes a macOS bundle, such as
+// an application (.app) or framework (.framework).
+//
+// The check is based on the extension only and is case-insensitive, matching
+// how Finder decides to show a directory as a package.
+func IsBundle(name string) bool {
+	ext := filepath.Ext(name)
+	return ext != name && slices.ContainsFunc(bundleExtensions, func(b string) bool {
+		return strings.EqualFold(ext, b)
+	})
 }
 
 // PrintResults prints the search results in a formatted, human-readable way.
@@ -181,8 +661,37 @@ func PrintResults(result Result) {
 		return
 	}
 
-	fmt.Printf("Found %d directories:\n", len(result.Directories))
-	for i, dir := range result.Directories {
+	paths := result.Directories
+	if result.Roots != nil {
+		paths = make([]string, len(result.Directories))
+		for i, dir := range result.Directories {
+			paths[i] = filepath.Join(result.Roots[i], dir)
+		}
+	}
+
+	if result.Types != nil {
+		fmt.Printf("Found %d entries:\n", len(paths))
+		for i, path := range paths {
+			fmt.Printf("%d. %s (%s)\n", i+1, path, result.Types[i])
+		}
+		printWarnings(result.Warnings)
+		return
+	}
+
+	fmt.Printf("Found %d directories:\n", len(paths))
+	for i, dir := range paths {
 		fmt.Printf("%d. %s\n", i+1, dir)
 	}
+	printWarnings(result.Warnings)
+}
+
+// printWarnings prints the entries a search skipped, if any.
+func printWarnings(warnings []PathError) {
+	if len(warnings) == 0 {
+		return
+	}
+	fmt.Printf("Skipped %d unreadable entries:\n", len(warnings))
+	for _, w := range warnings {
+		fmt.Printf("  %v\n", w)
+	}
 }
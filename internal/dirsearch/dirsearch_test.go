@@ -1,8 +1,13 @@
 package dirsearch
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"sync"
 	"testing"
 )
 
@@ -21,8 +26,20 @@ func TestDefaultOptions(t *testing.T) {
 		t.Error("expected CaseSensitive to be false")
 	}
 
-	if len(opts.IgnorePatterns) != 1 || opts.IgnorePatterns[0] != "node_modules" {
-		t.Errorf("expected IgnorePatterns to be ['node_modules'], got %v", opts.IgnorePatterns)
+	if !slices.Equal(opts.IgnorePatterns, []string{".git", "node_modules"}) {
+		t.Errorf("expected IgnorePatterns to be ['.git' 'node_modules'], got %v", opts.IgnorePatterns)
+	}
+
+	if opts.MaxDepth != 1 {
+		t.Errorf("expected MaxDepth to be 1, got %d", opts.MaxDepth)
+	}
+
+	if opts.Hidden != HiddenShow {
+		t.Errorf("expected Hidden to be HiddenShow, got %v", opts.Hidden)
+	}
+
+	if opts.Concurrency != DefaultConcurrency {
+		t.Errorf("expected Concurrency to be %d, got %d", DefaultConcurrency, opts.Concurrency)
 	}
 }
 
@@ -252,6 +269,316 @@ func TestSearch_GitDirectoriesIgnored(t *testing.T) {
 	}
 }
 
+func TestSearch_Hidden(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "dirsearch-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	for _, dir := range []string{"src", ".git", ".github/workflows", ".config"} {
+		if err := os.MkdirAll(filepath.Join(tempDir, dir), 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", dir, err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, ".env"), nil, 0644); err != nil {
+		t.Fatalf("failed to create .env: %v", err)
+	}
+
+	tests := []struct {
+		hidden HiddenMode
+		depth  int
+		want   []string
+	}{
+		{HiddenHide, 1, []string{"src"}},
+		{HiddenShow, 1, []string{".config", ".env", ".github", "src"}},
+		{HiddenExceptGit, 1, []string{".config", ".env", "src"}},
+		{HiddenHide, UnlimitedDepth, []string{"src"}},
+		{HiddenShow, UnlimitedDepth, []string{".config", ".env", ".github", filepath.Join(".github", "workflows"), "src"}},
+		{HiddenExceptGit, UnlimitedDepth, []string{".config", ".env", "src"}},
+	}
+	for _, tt := range tests {
+		opts := &Options{StartDir: tempDir, Hidden: tt.hidden, MaxDepth: tt.depth, IncludeFiles: true, IgnorePatterns: []string{".git"}}
+		result := Search(opts)
+		if result.Error != nil {
+			t.Fatalf("unexpected error: %v", result.Error)
+		}
+		if !slices.Equal(result.Directories, tt.want) {
+			t.Errorf("hidden mode %v, depth %d: expected %v, got %v", tt.hidden, tt.depth, tt.want, result.Directories)
+		}
+	}
+}
+
+func TestSearch_GitIgnoreRule(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "dirsearch-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	for _, dir := range []string{".git", ".github", ".gitlab", "gitlab-configs"} {
+		if err := os.Mkdir(filepath.Join(tempDir, dir), 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", dir, err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		ignore   []string
+		expected []string
+	}{
+		{"default list hides only .git", DefaultOptions().IgnorePatterns, []string{".github", ".gitlab", "gitlab-configs"}},
+		{"negation brings .git back", append(DefaultOptions().IgnorePatterns, "!.git"), []string{".git", ".github", ".gitlab", "gitlab-configs"}},
+		{"negation wins regardless of order", []string{"!.git*", ".git*"}, []string{".git", ".github", ".gitlab", "gitlab-configs"}},
+		{"no rule", nil, []string{".git", ".github", ".gitlab", "gitlab-configs"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultOptions()
+			opts.StartDir = tempDir
+			opts.IgnorePatterns = tt.ignore
+
+			result := Search(opts)
+			if result.Error != nil {
+				t.Fatalf("unexpected error: %v", result.Error)
+			}
+			if !slices.Equal(result.Directories, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, result.Directories)
+			}
+		})
+	}
+}
+
+func TestSearch_MaxDepth(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "dirsearch-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	for _, dir := range []string{"a/b/c", "d", "node_modules/e"} {
+		if err := os.MkdirAll(filepath.Join(tempDir, dir), 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", dir, err)
+		}
+	}
+
+	tests := []struct {
+		depth int
+		want  []string
+	}{
+		{0, []string{"a", "d"}},
+		{1, []string{"a", "d"}},
+		{2, []string{"a", filepath.Join("a", "b"), "d"}},
+		{UnlimitedDepth, []string{"a", filepath.Join("a", "b"), filepath.Join("a", "b", "c"), "d"}},
+	}
+	for _, tt := range tests {
+		opts := &Options{
+			StartDir:       tempDir,
+			IgnorePatterns: []string{"node_modules"},
+			MaxDepth:       tt.depth,
+		}
+		result := Search(opts)
+		if result.Error != nil {
+			t.Fatalf("depth %d: unexpected error: %v", tt.depth, result.Error)
+		}
+		if !slices.Equal(result.Directories, tt.want) {
+			t.Errorf("depth %d: expected %v, got %v", tt.depth, tt.want, result.Directories)
+		}
+	}
+}
+
+func TestSearch_MaxResults(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "dirsearch-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	for _, dir := range []string{"a/x", "b/y", "c/z", "d"} {
+		if err := os.MkdirAll(filepath.Join(tempDir, dir), 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", dir, err)
+		}
+	}
+
+	tests := []struct {
+		name        string
+		depth       int
+		concurrency int
+		maxResults  int
+		count       int
+		truncated   bool
+	}{
+		{"flat", 1, 0, 2, 2, true},
+		{"flat exact", 1, 0, 4, 4, false},
+		{"flat unlimited", 1, 0, 0, 4, false},
+		{"recursive", UnlimitedDepth, 0, 3, 3, true},
+		{"recursive parallel", UnlimitedDepth, DefaultConcurrency, 3, 3, true},
+		{"recursive exact", UnlimitedDepth, 0, 7, 7, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &Options{
+				StartDir:    tempDir,
+				MaxDepth:    tt.depth,
+				Concurrency: tt.concurrency,
+				MaxResults:  tt.maxResults,
+			}
+			result := Search(opts)
+			if result.Error != nil {
+				t.Fatalf("unexpected error: %v", result.Error)
+			}
+			if len(result.Directories) != tt.count {
+				t.Errorf("expected %d results, got %v", tt.count, result.Directories)
+			}
+			if result.Truncated != tt.truncated {
+				t.Errorf("expected truncated %v, got %v", tt.truncated, result.Truncated)
+			}
+		})
+	}
+}
+
+func TestSearch_Regex(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "dirsearch-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	for _, dir := range []string{"api-v1", "API-v2", "app", "web/api-v3"} {
+		if err := os.MkdirAll(filepath.Join(tempDir, dir), 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", dir, err)
+		}
+	}
+
+	tests := []struct {
+		caseSensitive bool
+		depth         int
+		want          []string
+	}{
+		{false, 1, []string{"API-v2", "api-v1"}},
+		{true, 1, []string{"api-v1"}},
+		{false, UnlimitedDepth, []string{"API-v2", "api-v1", filepath.Join("web", "api-v3")}},
+	}
+	for _, tt := range tests {
+		opts := &Options{
+			SearchPattern: `^api-v\d$`,
+			StartDir:      tempDir,
+			CaseSensitive: tt.caseSensitive,
+			Regex:         true,
+			MaxDepth:      tt.depth,
+		}
+		result := Search(opts)
+		if result.Error != nil {
+			t.Fatalf("unexpected error: %v", result.Error)
+		}
+		if !slices.Equal(result.Directories, tt.want) {
+			t.Errorf("case sensitive %v, depth %d: expected %v, got %v", tt.caseSensitive, tt.depth, tt.want, result.Directories)
+		}
+	}
+}
+
+func TestSearch_InvalidRegex(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "dirsearch-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	for _, depth := range []int{1, UnlimitedDepth} {
+		result := Search(&Options{SearchPattern: "api(", StartDir: tempDir, Regex: true, MaxDepth: depth})
+		if result.Error == nil {
+			t.Errorf("depth %d: expected error for invalid regular expression", depth)
+		}
+		if len(result.Directories) != 0 {
+			t.Errorf("depth %d: expected no directories, got %v", depth, result.Directories)
+		}
+	}
+}
+
+func TestSearch_IncludeFiles(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "dirsearch-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	for _, dir := range []string{"config", "src/config", "node_modules/config"} {
+		if err := os.MkdirAll(filepath.Join(tempDir, dir), 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", dir, err)
+		}
+	}
+	for _, file := range []string{"config.json", "src/config.go", "src/main.go", "node_modules/config.js"} {
+		if err := os.WriteFile(filepath.Join(tempDir, file), nil, 0644); err != nil {
+			t.Fatalf("failed to create %s: %v", file, err)
+		}
+	}
+
+	tests := []struct {
+		depth       int
+		concurrency int
+		want        []string
+		types       []EntryType
+	}{
+		{1, 0, []string{"config", "config.json"}, []EntryType{Dir, File}},
+		{UnlimitedDepth, 0, []string{"config", "config.json", filepath.Join("src", "config"), filepath.Join("src", "config.go")}, []EntryType{Dir, File, Dir, File}},
+		{UnlimitedDepth, 4, []string{"config", "config.json", filepath.Join("src", "config"), filepath.Join("src", "config.go")}, []EntryType{Dir, File, Dir, File}},
+	}
+	for _, tt := range tests {
+		opts := &Options{
+			SearchPattern:  "config",
+			StartDir:       tempDir,
+			IgnorePatterns: []string{"node_modules"},
+			MaxDepth:       tt.depth,
+			Concurrency:    tt.concurrency,
+			IncludeFiles:   true,
+		}
+		result := Search(opts)
+		if result.Error != nil {
+			t.Fatalf("unexpected error: %v", result.Error)
+		}
+		if !slices.Equal(result.Directories, tt.want) {
+			t.Errorf("depth %d, concurrency %d: expected %v, got %v", tt.depth, tt.concurrency, tt.want, result.Directories)
+		}
+		if !slices.Equal(result.Types, tt.types) {
+			t.Errorf("depth %d, concurrency %d: expected types %v, got %v", tt.depth, tt.concurrency, tt.types, result.Types)
+		}
+	}
+
+	// Fuzzy ordering keeps the types aligned with the entries
+	result := Search(&Options{SearchPattern: "cfgj", StartDir: tempDir, Fuzzy: true, IncludeFiles: true})
+	if !slices.Equal(result.Directories, []string{"config.json"}) || !slices.Equal(result.Types, []EntryType{File}) {
+		t.Errorf("expected config.json as a file, got %v %v", result.Directories, result.Types)
+	}
+
+	result = Search(&Options{SearchPattern: "config", StartDir: tempDir})
+	if result.Types != nil {
+		t.Errorf("expected no types without IncludeFiles, got %v", result.Types)
+	}
+}
+
+func TestSearchContext_Canceled(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "dirsearch-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	if err := os.MkdirAll(filepath.Join(tempDir, "a", "b"), 0755); err != nil {
+		t.Fatalf("failed to create directories: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, depth := range []int{1, UnlimitedDepth} {
+		result := SearchContext(ctx, &Options{StartDir: tempDir, MaxDepth: depth})
+		if !errors.Is(result.Error, context.Canceled) {
+			t.Errorf("depth %d: expected context.Canceled, got %v", depth, result.Error)
+		}
+	}
+}
+
 func TestScanDirs(t *testing.T) {
 	tempDir, err := os.MkdirTemp("", "dirsearch-test-*")
 	if err != nil {
@@ -275,8 +602,105 @@ func TestScanDirs(t *testing.T) {
 		t.Errorf("expected 1 directory, got %d", len(result.Directories))
 	}
 
-	// Verify the StartDir was updated
-	if ds.Options.StartDir != tempDir {
-		t.Errorf("expected StartDir to be updated to %q, got %q", tempDir, ds.Options.StartDir)
+	// Scans search a snapshot of the options
+	if ds.Options.StartDir != "." {
+		t.Errorf("expected StartDir to stay '.', got %q", ds.Options.StartDir)
+	}
+}
+
+func TestScanDirs_Concurrent(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "dirsearch-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// Each root holds one subdirectory named after it, so a scan reading
+	// another root's options would list the wrong name
+	roots := make([]string, 8)
+	for i := range roots {
+		roots[i] = filepath.Join(tempDir, fmt.Sprintf("root%d", i))
+		if err := os.MkdirAll(filepath.Join(roots[i], fmt.Sprintf("child%d", i)), 0755); err != nil {
+			t.Fatalf("failed to create test dir: %v", err)
+		}
+	}
+
+	ds := NewDirSearch()
+	var wg sync.WaitGroup
+	for i, root := range roots {
+		want := fmt.Sprintf("child%d", i)
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			for range 20 {
+				if result := ds.ScanDirs(root); !slices.Equal(result.Directories, []string{want}) {
+					t.Errorf("expected [%s] in %s, got %v", want, root, result.Directories)
+					return
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for range 20 {
+				result := ds.ScanDirsStream(context.Background(), root, 1, func([]string) {})
+				if !slices.Equal(result.Directories, []string{want}) {
+					t.Errorf("expected [%s] in %s, got %v", want, root, result.Directories)
+					return
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			// Per-scan options leave the shared ones alone
+			opts := ds.Options.Clone()
+			opts.SearchPattern = "nothing-matches"
+			opts.IgnorePatterns = append(opts.IgnorePatterns, "child*")
+			for range 20 {
+				if result := ds.WithOptions(opts).ScanDirs(root); len(result.Directories) != 0 {
+					t.Errorf("expected no match with per-scan options, got %v", result.Directories)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if ds.Options.SearchPattern != "" || slices.Contains(ds.Options.IgnorePatterns, "child*") {
+		t.Errorf("expected the shared options to be unchanged, got %+v", ds.Options)
+	}
+}
+
+func TestOptionsClone(t *testing.T) {
+	opts := &Options{StartDirs: []string{"a"}, IgnorePatterns: []string{"node_modules"}, Filters: []FilterFunc{func(DirEntry) bool { return true }}}
+	c := opts.Clone()
+	c.StartDirs[0] = "b"
+	c.IgnorePatterns[0] = "vendor"
+	c.Filters = append(c.Filters, nil)
+	c.SearchPattern = "x"
+
+	if opts.StartDirs[0] != "a" || opts.IgnorePatterns[0] != "node_modules" || len(opts.Filters) != 1 || opts.SearchPattern != "" {
+		t.Errorf("expected the original options to be unchanged, got %+v", opts)
+	}
+}
+
+func TestIsBundle(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"Safari.app", true},
+		{"Foundation.framework", true},
+		{"Plugin.bundle", true},
+		{"Xcode.APP", true},
+		{"app", false},
+		{"my.application", false},
+		{"src", false},
+		{".app", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsBundle(tt.name); got != tt.want {
+			t.Errorf("IsBundle(%q) = %v, want %v", tt.name, got, tt.want)
+		}
 	}
 }
@@ -0,0 +1,113 @@
+package dirsearch
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"sync"
+)
+
+// DirEntry is an entry met by a search, as passed to a FilterFunc.
+type DirEntry struct {
+	fs.DirEntry
+
+	// Path is the path of the entry: Options.StartDir joined with the path
+	// of the entry below it, slash-separated inside Options.FS
+	Path string
+}
+
+// FilterFunc reports whether a search keeps an entry; see Options.Filters.
+type FilterFunc func(entry DirEntry) bool
+
+// keep reports whether every filter of the search keeps the entry d found
+// at path.
+func (m *matcher) keep(path string, d fs.DirEntry) bool {
+	for _, filter := range m.filters {
+		if !filter(DirEntry{DirEntry: d, Path: path}) {
+			return false
+		}
+	}
+	return true
+}
+
+// keepPath is keep for an entry known by its path only, which is inspected
+// first. Entries that cannot be inspected are left out when there are
+// filters.
+func (m *matcher) keepPath(path string) bool {
+	if len(m.filters) == 0 {
+		return true
+	}
+	var info fs.FileInfo
+	var err error
+	if m.fsys != nil {
+		info, err = fs.Stat(m.fsys, path)
+	} else {
+		info, err = os.Lstat(path)
+	}
+	if err != nil {
+		return false
+	}
+	return m.keep(path, fs.FileInfoToDirEntry(info))
+}
+
+// matchFilters reports whether the entry at path, a directory if isDir is
+// set, passes the modification time, project, content and size filters of
+// the search. Cheaper checks run first.
+func (m *matcher) matchFilters(ctx context.Context, path string, isDir bool) bool {
+	return m.matchModTime(path) && m.matchProject(path, isDir) &&
+		m.matchContent(ctx, path, isDir) && m.matchSize(ctx, path, isDir)
+}
+
+// costly reports whether matchFilters reads more than the entry itself:
+// the files inside a directory or its whole subtree.
+func (m *matcher) costly() bool {
+	return m.content != nil || m.projects != 0 || m.minSize > 0 || m.maxSize > 0
+}
+
+// filterEntries returns the entries of dir that match the search, in their
+// original order. Costly filters are checked by up to workers entries at a
+// time.
+func (m *matcher) filterEntries(ctx context.Context, dir string, entries []fs.DirEntry, workers int) []fs.DirEntry {
+	candidates := make([]fs.DirEntry, 0, len(entries))
+	for _, entry := range entries {
+		if m.matchEntry(entry) && m.keep(m.join(dir, entry.Name()), entry) {
+			candidates = append(candidates, entry)
+		}
+	}
+
+	passed := make([]bool, len(candidates))
+	check := func(i int) {
+		entry := candidates[i]
+		passed[i] = m.matchFilters(ctx, m.join(dir, entry.Name()), entry.IsDir())
+	}
+	if workers <= 1 || !m.costly() || len(candidates) <= 1 {
+		for i := range candidates {
+			check(i)
+		}
+	} else {
+		next := make(chan int)
+		var wg sync.WaitGroup
+		for range min(workers, len(candidates)) {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range next {
+					check(i)
+				}
+			}()
+		}
+		for i := range candidates {
+			next <- i
+		}
+		close(next)
+		wg.Wait()
+	}
+
+	matched := candidates[:0]
+	for i, entry := range candidates {
+		if passed[i] {
+			matched = append(matched, entry)
+		}
+	}
+	return matched
+}
@@ -0,0 +1,108 @@
+package dirsearch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+// skipMarked is a filter excluding directories holding a .nosearch file.
+func skipMarked(entry DirEntry) bool {
+	if !entry.IsDir() {
+		return true
+	}
+	_, err := os.Stat(filepath.Join(entry.Path, ".nosearch"))
+	return err != nil
+}
+
+func TestSearch_Filters(t *testing.T) {
+	root, err := os.MkdirTemp("", "dirsearch-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(root)
+
+	for _, dir := range []string{"code/app", "code/vendor/app", "scratch/app", "docs"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+	}
+	for _, file := range []string{"scratch/.nosearch", "code/app/main.go", "code/app/main_test.go"} {
+		if err := os.WriteFile(filepath.Join(root, file), nil, 0644); err != nil {
+			t.Fatalf("failed to create file: %v", err)
+		}
+	}
+	noVendor := func(entry DirEntry) bool { return entry.Name() != "vendor" }
+
+	for _, workers := range []int{1, 4} {
+		opts := &Options{
+			StartDir:    root,
+			MaxDepth:    UnlimitedDepth,
+			Concurrency: workers,
+			Filters:     []FilterFunc{skipMarked, noVendor},
+		}
+		result := Search(opts)
+		if result.Error != nil {
+			t.Fatalf("unexpected error: %v", result.Error)
+		}
+		// Excluded directories are skipped with their subtrees
+		expected := []string{"code", filepath.Join("code", "app"), "docs"}
+		if !slices.Equal(result.Directories, expected) {
+			t.Errorf("concurrency %d: expected %v, got %v", workers, expected, result.Directories)
+		}
+
+		opts.IncludeFiles = true
+		opts.Filters = append(opts.Filters, func(entry DirEntry) bool {
+			return entry.IsDir() || filepath.Ext(entry.Path) != ".go" || filepath.Base(entry.Path) == "main.go"
+		})
+		result = Search(opts)
+		expected = []string{"code", filepath.Join("code", "app"), filepath.Join("code", "app", "main.go"), "docs"}
+		if !slices.Equal(result.Directories, expected) {
+			t.Errorf("concurrency %d: expected %v, got %v", workers, expected, result.Directories)
+		}
+	}
+
+	result := Search(&Options{StartDir: root, Filters: []FilterFunc{skipMarked}})
+	expected := []string{"code", "docs"}
+	if !slices.Equal(result.Directories, expected) {
+		t.Errorf("expected %v, got %v", expected, result.Directories)
+	}
+
+	paths := []string{filepath.Join("code", "app"), filepath.Join("scratch", "app"), filepath.Join("code", "vendor", "app"), "missing"}
+	result = SearchPaths(context.Background(), &Options{StartDir: root, MaxDepth: UnlimitedDepth, Filters: []FilterFunc{skipMarked, noVendor}}, paths)
+	expected = []string{filepath.Join("code", "app")}
+	if !slices.Equal(result.Directories, expected) {
+		t.Errorf("expected %v, got %v", expected, result.Directories)
+	}
+}
+
+func TestSearch_FiltersFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"src/app/x":       {},
+		"src/gen/app/x":   {},
+		"docs/app-help/x": {},
+	}
+	var seen []string
+	opts := &Options{
+		SearchPattern: "app",
+		StartDir:      "src",
+		MaxDepth:      UnlimitedDepth,
+		FS:            fsys,
+		Filters: []FilterFunc{func(entry DirEntry) bool {
+			seen = append(seen, entry.Path)
+			return entry.Name() != "gen"
+		}},
+	}
+
+	result := Search(opts)
+	if expected := []string{"app"}; !slices.Equal(result.Directories, expected) {
+		t.Errorf("expected %v, got %v", expected, result.Directories)
+	}
+	// Paths are inside FS, and the subtree of gen is never read
+	if expected := []string{"src/app", "src/gen"}; !slices.Equal(seen, expected) {
+		t.Errorf("expected filters to see %v, got %v", expected, seen)
+	}
+}
@@ -0,0 +1,165 @@
+package dirsearch
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+// errLimitReached stops a walk once enough matches have been found.
+var errLimitReached = errors.New("result limit reached")
+
+// FindDirs walks the tree rooted at opts.StartDir and returns the
+// directories at any depth whose names match opts.SearchPattern. With
+// opts.IncludeFiles, matching regular files are returned as well.
+//
+// The same rules as Search apply to every directory: hidden and ignored
+// directories are skipped together with their subtrees. Symbolic links are
+// not followed and unreadable subdirectories are skipped; SearchContext
+// reports them in Result.Warnings.
+//
+// With opts.Concurrency above one, sibling subtrees are read in parallel by
+// that many workers. The result is the same, except that when limit
+// truncates it, which matches are kept depends on timing.
+//
+// Parameters:
+//   - ctx: controls cancellation of the walk
+//   - opts: the search options
+//   - maxDepth: how many levels below StartDir to search (1 = direct
+//     children only); zero or negative means unlimited
+//   - limit: maximum number of results; zero or negative means unlimited
+//
+// Returns the matching paths relative to opts.StartDir in lexical order and
+// whether the result was truncated by limit, or an error if StartDir cannot
+// be read, the pattern is an invalid regular expression or ctx is canceled.
+func FindDirs(ctx context.Context, opts *Options, maxDepth, limit int) ([]string, bool, error) {
+	r := findEntries(ctx, opts, maxDepth, limit)
+	return r.Directories, r.Truncated, r.Error
+}
+
+// entry is a match found by a walk.
+type entry struct {
+	path string
+	typ  EntryType
+}
+
+// findEntries implements FindDirs, returning a Result that also holds the
+// type of each match and the entries skipped for being unreadable.
+func findEntries(ctx context.Context, opts *Options, maxDepth, limit int) Result {
+	root := opts.StartDir
+	m, err := newMatcher(opts)
+	if err != nil {
+		return Result{Directories: []string{}, Error: err}
+	}
+
+	var found []entry
+	var warnings []PathError
+	var truncated bool
+	if opts.Concurrency > 1 && opts.FS == nil {
+		found, warnings, truncated, err = walkParallel(ctx, root, m, maxDepth, limit, opts.Concurrency)
+	} else {
+		found, warnings, truncated, err = walk(ctx, opts.FS, root, m, maxDepth, limit)
+	}
+	if err != nil {
+		return Result{Directories: []string{}, Error: err}
+	}
+
+	paths := make([]string, len(found))
+	var types []EntryType
+	if opts.IncludeFiles {
+		types = make([]EntryType, len(found))
+	}
+	for i, e := range found {
+		paths[i] = e.path
+		if types != nil {
+			types[i] = e.typ
+		}
+	}
+	return Result{Directories: paths, Types: types, Truncated: truncated, Warnings: warnings}
+}
+
+// walk traverses the tree sequentially with filepath.WalkDir, or with
+// fs.WalkDir if fsys is not nil. It returns the matches, the unreadable
+// entries below root, whether limit truncated the matches, and an error if
+// root cannot be read or ctx is canceled.
+func walk(ctx context.Context, fsys fs.FS, root string, m *matcher, maxDepth, limit int) ([]entry, []PathError, bool, error) {
+	var found []entry
+	var warnings []PathError
+
+	walkDir, relPath, sep := filepath.WalkDir, filepath.Rel, string(filepath.Separator)
+	if fsys != nil {
+		walkDir = func(root string, fn fs.WalkDirFunc) error {
+			return fs.WalkDir(fsys, root, fn)
+		}
+		relPath, sep = relSlash, "/"
+	}
+
+	err := walkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err != nil {
+			if path == root {
+				return err
+			}
+			if rel, relErr := relPath(root, path); relErr == nil {
+				warnings = append(warnings, newPathError(rel, err))
+			}
+			if d != nil && d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if path == root {
+			return nil
+		}
+
+		rel, err := relPath(root, path)
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() {
+			if m.matchEntry(d) && m.keep(path, d) && m.matchFilters(ctx, path, false) {
+				if limit > 0 && len(found) >= limit {
+					return errLimitReached
+				}
+				found = append(found, entry{path: rel, typ: File})
+			}
+			return nil
+		}
+
+		if m.skip(d.Name()) || !m.keep(path, d) {
+			return filepath.SkipDir
+		}
+		if m.matchEntry(d) && m.matchFilters(ctx, path, true) {
+			if limit > 0 && len(found) >= limit {
+				return errLimitReached
+			}
+			found = append(found, entry{path: rel, typ: Dir})
+		}
+
+		if maxDepth > 0 && strings.Count(rel, sep)+1 >= maxDepth {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	if errors.Is(err, errLimitReached) {
+		return found, warnings, true, nil
+	}
+	if err != nil {
+		return nil, nil, false, err
+	}
+	return found, warnings, false, nil
+}
+
+// relSlash is filepath.Rel for the slash-separated paths of an fs.FS, where
+// path is always root or below it.
+func relSlash(root, path string) (string, error) {
+	if root == "." {
+		return path, nil
+	}
+	return strings.TrimPrefix(path, root+"/"), nil
+}
@@ -0,0 +1,79 @@
+package dirsearch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestFindDirs(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "dirsearch-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	for _, dir := range []string{
+		"app/src/api",
+		"app/node_modules/api",
+		"lib/api-client",
+		".git/api",
+		"docs",
+	} {
+		if err := os.MkdirAll(filepath.Join(tempDir, dir), 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", dir, err)
+		}
+	}
+
+	opts := DefaultOptions()
+	opts.StartDir = tempDir
+	opts.SearchPattern = "API"
+
+	found, truncated, err := FindDirs(context.Background(), opts, 0, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{filepath.Join("app", "src", "api"), filepath.Join("lib", "api-client")}
+	if !slices.Equal(found, want) {
+		t.Errorf("expected %v, got %v", want, found)
+	}
+	if truncated {
+		t.Error("expected complete result")
+	}
+
+	// Depth 2 stops before app/src/api
+	found, _, err = FindDirs(context.Background(), opts, 2, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(found, want[1:]) {
+		t.Errorf("expected %v with max depth 2, got %v", want[1:], found)
+	}
+
+	found, truncated, err = FindDirs(context.Background(), opts, 0, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(found) != 1 || !truncated {
+		t.Errorf("expected 1 truncated result, got %v (truncated %v)", found, truncated)
+	}
+}
+
+func TestFindDirs_Canceled(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "dirsearch-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	opts := DefaultOptions()
+	opts.StartDir = tempDir
+	if _, _, err := FindDirs(ctx, opts, 0, 0); err == nil {
+		t.Error("expected error for canceled context, got nil")
+	}
+}
@@ -0,0 +1,98 @@
+package dirsearch
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+)
+
+// Fingerprint summarizes the structure of a directory tree.
+//
+// Two trees with the same relative paths and file sizes have the same
+// fingerprint, regardless of where they are located. File contents are not
+// read, so fingerprints are cheap to compute but will not detect changes
+// that keep every file size intact.
+type Fingerprint struct {
+	// Files is the number of regular files in the tree
+	Files int
+
+	// Size is the total size of regular files in bytes
+	Size int64
+
+	// Hash is the hex-encoded SHA-256 of all relative paths and file sizes
+	Hash string
+}
+
+// ShortHash returns the first 12 characters of the hash, enough to compare
+// fingerprints by eye.
+func (f Fingerprint) ShortHash() string {
+	const n = 12
+	if len(f.Hash) < n {
+		return f.Hash
+	}
+	return f.Hash[:n]
+}
+
+// DirFingerprint computes the fingerprint of the tree rooted at root.
+//
+// Entries are visited in lexical order, so the result is deterministic.
+// Directories contribute their path and regular files their path and size;
+// symlinks contribute their path only and are not followed.
+//
+// Parameters:
+//   - ctx: controls cancellation of the walk
+//   - root: the directory to fingerprint
+//   - progress: optional callback receiving the total size of files seen so far
+//
+// Returns an error if any part of the tree cannot be read, since a partial
+// fingerprint would be misleading, or if ctx is canceled.
+func DirFingerprint(ctx context.Context, root string, progress func(total int64)) (Fingerprint, error) {
+	var fp Fingerprint
+	h := sha256.New()
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if path == root {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+
+		switch {
+		case d.IsDir():
+			fmt.Fprintf(h, "d %s\n", name)
+		case d.Type().IsRegular():
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(h, "f %s %d\n", name, info.Size())
+			fp.Files++
+			fp.Size += info.Size()
+			if progress != nil && fp.Files%sizeProgressInterval == 0 {
+				progress(fp.Size)
+			}
+		default:
+			fmt.Fprintf(h, "o %s\n", name)
+		}
+		return nil
+	})
+	if err != nil {
+		return Fingerprint{}, err
+	}
+
+	fp.Hash = hex.EncodeToString(h.Sum(nil))
+	return fp, nil
+}
@@ -0,0 +1,95 @@
+package dirsearch
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeTree creates the given files (relative path to content) under root.
+func writeTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create dir for %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+}
+
+func TestDirFingerprint(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "dirsearch-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	files := map[string]string{
+		"a.txt":     "hello",
+		"sub/b.txt": "world!",
+	}
+	original := filepath.Join(tempDir, "original")
+	copied := filepath.Join(tempDir, "copy")
+	writeTree(t, original, files)
+	writeTree(t, copied, files)
+
+	fp1, err := DirFingerprint(context.Background(), original, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fp2, err := DirFingerprint(context.Background(), copied, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if fp1 != fp2 {
+		t.Errorf("expected identical trees to match, got %+v and %+v", fp1, fp2)
+	}
+	if fp1.Files != 2 || fp1.Size != 11 {
+		t.Errorf("expected 2 files and 11 bytes, got %d files and %d bytes", fp1.Files, fp1.Size)
+	}
+	if len(fp1.ShortHash()) != 12 {
+		t.Errorf("expected 12 character short hash, got %q", fp1.ShortHash())
+	}
+
+	t.Run("size change", func(t *testing.T) {
+		writeTree(t, copied, map[string]string{"a.txt": "hello again"})
+		fp, err := DirFingerprint(context.Background(), copied, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if fp.Hash == fp1.Hash {
+			t.Error("expected different hash after size change")
+		}
+	})
+
+	t.Run("rename", func(t *testing.T) {
+		renamed := filepath.Join(tempDir, "renamed")
+		writeTree(t, renamed, map[string]string{"a.txt": "hello", "sub/c.txt": "world!"})
+		fp, err := DirFingerprint(context.Background(), renamed, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if fp.Hash == fp1.Hash {
+			t.Error("expected different hash after rename")
+		}
+		if fp.Size != fp1.Size || fp.Files != fp1.Files {
+			t.Error("expected same size and count after rename")
+		}
+	})
+}
+
+func TestDirFingerprint_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := DirFingerprint(ctx, ".", nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+package dirsearch
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// turkishI maps the dotted and dotless i of Turkish, as left by case
+// folding, to a plain i.
+var turkishI = strings.NewReplacer("i̇", "i", "ı", "i")
+
+// FoldCase returns s in the form compared by case-insensitive searches:
+// normalized to NFC, so a name stored decomposed (as macOS does) equals its
+// precomposed spelling, and fully case-folded, so "Straße" folds like
+// "STRASSE" and "ς" like "Σ". The dotted and dotless i of Turkish fold to a
+// plain i, so "istanbul" finds "İstanbul" and "kirmizi" finds "kırmızı".
+func FoldCase(s string) string {
+	if isASCII(s) {
+		return strings.ToLower(s)
+	}
+	// A Caser keeps state, so each call uses its own
+	return turkishI.Replace(cases.Fold().String(norm.NFC.String(s)))
+}
+
+// normalize returns s normalized to NFC, for case-sensitive comparisons.
+func normalize(s string) string {
+	if isASCII(s) || norm.NFC.IsNormalString(s) {
+		return s
+	}
+	return norm.NFC.String(s)
+}
+
+// isASCII reports whether s holds only ASCII characters.
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// containsFold reports whether foldedSubstr occurs in s, ignoring case.
+//
+// It gives the same answer as strings.Contains(FoldCase(s), foldedSubstr).
+// ASCII names, by far the most common, are compared without allocating a
+// folded copy of s, which matters because it runs for every entry of every
+// directory scanned. foldedSubstr must already be folded with FoldCase.
+func containsFold(s, foldedSubstr string) bool {
+	if foldedSubstr == "" {
+		return true
+	}
+	if !isASCII(s) {
+		return strings.Contains(FoldCase(s), foldedSubstr)
+	}
+	for i := 0; i+len(foldedSubstr) <= len(s); i++ {
+		if hasPrefixFold(s[i:], foldedSubstr) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasPrefixFold reports whether the ASCII string s starts with
+// lowerPrefix, ignoring case. A prefix with other characters never matches.
+func hasPrefixFold(s, lowerPrefix string) bool {
+	if len(s) < len(lowerPrefix) {
+		return false
+	}
+	for i := 0; i < len(lowerPrefix); i++ {
+		sc := s[i]
+		if 'A' <= sc && sc <= 'Z' {
+			sc += 'a' - 'A'
+		}
+		if sc != lowerPrefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// foldRune folds r unless matching is case-sensitive. Unlike FoldCase it
+// maps every rune to a single rune, as fuzzy matching compares rune by rune:
+// "ς" folds like "Σ" and the Turkish "ı" and "İ" like "i", but "ß" stays
+// itself.
+func foldRune(r rune, caseSensitive bool) rune {
+	if caseSensitive {
+		return r
+	}
+	return unicode.ToLower(unicode.ToUpper(r))
+}
+
+// foldRunes applies foldRune to every rune of s.
+func foldRunes(s string, caseSensitive bool) string {
+	return strings.Map(func(r rune) rune {
+		return foldRune(r, caseSensitive)
+	}, s)
+}
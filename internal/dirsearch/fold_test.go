@@ -0,0 +1,134 @@
+package dirsearch
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestContainsFold(t *testing.T) {
+	cases := []struct {
+		s, substr string
+		want      bool
+	}{
+		{"Documents", "doc", true},
+		{"Documents", "MENT", true},
+		{"Documents", "docs", false},
+		{"src", "", true},
+		{"", "a", false},
+		{"ÜBERSICHT", "über", true},
+		{"U\u0308bersicht", "Über", true}, // decomposed, as stored by macOS
+		{"Straße", "STRASSE", true},
+		{"STRASSE", "straße", true},
+		{"Kelvin\u212a", "k", true},
+		{"ProjectΣ", "σ", true},
+		{"projectς", "Σ", true},
+		{"résumé", "SUMÉ", true},
+		{"resume", "résumé", false},
+		{"İstanbul", "istanbul", true},
+		{"ISTANBUL", "ıstanbul", true},
+		{"kırmızı", "KIRMIZI", true},
+		{"bad\xffname", "name", true},
+		{"abc", "abcd", false},
+	}
+	for _, c := range cases {
+		if got := containsFold(c.s, FoldCase(c.substr)); got != c.want {
+			t.Errorf("containsFold(%q, %q): expected %v, got %v", c.s, c.substr, c.want, got)
+		}
+	}
+}
+
+func TestSearch_UnicodeNames(t *testing.T) {
+	fsys := fstest.MapFS{
+		"U\u0308berblick/x": {},
+		"Straße/x":          {},
+		"İstanbul/x":        {},
+		"notes/x":           {},
+	}
+
+	tests := []struct {
+		pattern       string
+		caseSensitive bool
+		fuzzy         bool
+		expected      []string
+	}{
+		{pattern: "überblick", expected: []string{"U\u0308berblick"}},
+		{pattern: "Überblick", caseSensitive: true, expected: []string{"U\u0308berblick"}},
+		{pattern: "strasse", expected: []string{"Straße"}},
+		{pattern: "ISTANBUL", expected: []string{"İstanbul"}},
+		{pattern: "üblk", fuzzy: true, expected: []string{"U\u0308berblick"}},
+		{pattern: "istbl", fuzzy: true, expected: []string{"İstanbul"}},
+	}
+	for _, tt := range tests {
+		result := Search(&Options{
+			SearchPattern: tt.pattern,
+			StartDir:      ".",
+			CaseSensitive: tt.caseSensitive,
+			Fuzzy:         tt.fuzzy,
+			FS:            fsys,
+		})
+		if result.Error != nil {
+			t.Fatalf("unexpected error: %v", result.Error)
+		}
+		if !slices.Equal(result.Directories, tt.expected) {
+			t.Errorf("pattern %q: expected %q, got %q", tt.pattern, tt.expected, result.Directories)
+		}
+	}
+}
+
+func TestMatchEntry_NoAllocations(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "dirsearch-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	if err := os.Mkdir(filepath.Join(tempDir, "MyProjects"), 0755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	entries, err := os.ReadDir(tempDir)
+	if err != nil {
+		t.Fatalf("failed to read dir: %v", err)
+	}
+
+	m, err := newMatcher(&Options{SearchPattern: "PROJ", IgnorePatterns: []string{"node_modules"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		if !m.matchEntry(entries[0]) {
+			t.Fatal("expected entry to match")
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("expected 0 allocations per match, got %v", allocs)
+	}
+}
+
+// BenchmarkMatchName compares case-insensitive matching without allocation
+// with lowercasing every name first, the way names were matched before.
+func BenchmarkMatchName(b *testing.B) {
+	names := []string{"Documents", "node_modules", "MyProjects", "src", "Downloads", "internal"}
+	pattern := "proj"
+
+	var hits int
+	b.Run("fold", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if containsFold(names[i%len(names)], pattern) {
+				hits++
+			}
+		}
+	})
+	b.Run("lower", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if strings.Contains(strings.ToLower(names[i%len(names)]), pattern) {
+				hits++
+			}
+		}
+	})
+}
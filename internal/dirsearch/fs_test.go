@@ -0,0 +1,104 @@
+package dirsearch
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+// testFS returns an in-memory tree for searches through Options.FS.
+func testFS() fstest.MapFS {
+	return fstest.MapFS{
+		"src/app/main.go":         {Data: []byte("package main")},
+		"src/lib/util.go":         {Data: []byte("package lib\n\nfunc f() {}")},
+		"docs/readme.md":          {Data: []byte("docs")},
+		".git/HEAD":               {Data: []byte("ref")},
+		"node_modules/x/index.js": {Data: []byte("x")},
+	}
+}
+
+func TestSearch_FS(t *testing.T) {
+	opts := &Options{
+		StartDir:       ".",
+		IgnorePatterns: []string{".git", "node_modules"},
+		Hidden:         HiddenShow,
+		FS:             testFS(),
+	}
+
+	result := Search(opts)
+	if result.Error != nil {
+		t.Fatalf("unexpected error: %v", result.Error)
+	}
+	expected := []string{"docs", "src"}
+	if !slices.Equal(result.Directories, expected) {
+		t.Errorf("expected %v, got %v", expected, result.Directories)
+	}
+
+	opts.StartDir = "src"
+	result = Search(opts)
+	expected = []string{"app", "lib"}
+	if !slices.Equal(result.Directories, expected) {
+		t.Errorf("expected %v, got %v", expected, result.Directories)
+	}
+
+	opts.StartDir = "missing"
+	if result := Search(opts); result.Error == nil {
+		t.Error("expected error for missing directory, got nil")
+	}
+}
+
+func TestSearch_FSRecursive(t *testing.T) {
+	opts := &Options{
+		SearchPattern:  "l",
+		StartDir:       ".",
+		IgnorePatterns: []string{".git", "node_modules"},
+		MaxDepth:       UnlimitedDepth,
+		Concurrency:    DefaultConcurrency,
+		FS:             testFS(),
+	}
+
+	result := Search(opts)
+	if result.Error != nil {
+		t.Fatalf("unexpected error: %v", result.Error)
+	}
+	expected := []string{"src/lib"}
+	if !slices.Equal(result.Directories, expected) {
+		t.Errorf("expected %v, got %v", expected, result.Directories)
+	}
+
+	opts.SearchPattern = ""
+	opts.StartDir = "src"
+	opts.IncludeFiles = true
+	result = Search(opts)
+	expected = []string{"app", "app/main.go", "lib", "lib/util.go"}
+	if !slices.Equal(result.Directories, expected) {
+		t.Errorf("expected %v, got %v", expected, result.Directories)
+	}
+
+	found, truncated, err := FindDirs(context.Background(), opts, 0, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(found) != 1 || !truncated {
+		t.Errorf("expected 1 truncated result, got %v (truncated %v)", found, truncated)
+	}
+}
+
+func TestSearch_FSSortBySize(t *testing.T) {
+	opts := &Options{
+		StartDir:  "src",
+		SortBy:    SortSize,
+		SortOrder: Descending,
+		FS:        testFS(),
+	}
+
+	result := Search(opts)
+	if result.Error != nil {
+		t.Fatalf("unexpected error: %v", result.Error)
+	}
+	expected := []string{"lib", "app"}
+	if !slices.Equal(result.Directories, expected) {
+		t.Errorf("expected %v, got %v", expected, result.Directories)
+	}
+}
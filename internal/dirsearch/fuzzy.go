@@ -0,0 +1,195 @@
+package dirsearch
+
+import (
+	"cmp"
+	"path/filepath"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Fuzzy match scoring, modelled on fzf: every matched character earns
+// scoreMatch, characters at the start of a word earn a bonus and gaps
+// between matched characters cost a penalty, so "fsr" scores higher on
+// "folder-search-results" than on "offsetrange".
+const (
+	scoreMatch        = 16
+	scoreGapStart     = -3
+	scoreGapExtension = -1
+
+	// bonusBoundary is earned by a match at the start of the name or after
+	// a separator such as "-", "_", "." or a space
+	bonusBoundary = scoreMatch / 2
+
+	// bonusCamel is earned by a match at a lower-to-upper case change or
+	// the first digit of a number
+	bonusCamel = bonusBoundary - 1
+
+	// bonusConsecutive is earned by a match directly after another match
+	bonusConsecutive = -(scoreGapStart + scoreGapExtension)
+
+	// bonusFirstCharMultiplier weighs the bonus of the first pattern character
+	bonusFirstCharMultiplier = 2
+)
+
+// charClass groups runes by how they affect word boundaries.
+type charClass int
+
+const (
+	classOther charClass = iota
+	classLower
+	classUpper
+	classDigit
+)
+
+// classOf returns the class of r.
+func classOf(r rune) charClass {
+	switch {
+	case unicode.IsLower(r):
+		return classLower
+	case unicode.IsUpper(r):
+		return classUpper
+	case unicode.IsDigit(r):
+		return classDigit
+	case unicode.IsLetter(r):
+		return classLower
+	}
+	return classOther
+}
+
+// bonusAt returns the bonus for matching a character of class cur that
+// follows a character of class prev.
+func bonusAt(prev, cur charClass) int {
+	switch {
+	case cur == classOther:
+		return 0
+	case prev == classOther:
+		return bonusBoundary
+	case prev == classLower && cur == classUpper, prev != classDigit && cur == classDigit:
+		return bonusCamel
+	}
+	return 0
+}
+
+// fuzzyScore matches pattern against name as a subsequence: "fsr" matches
+// "folder-search-results", see fuzzyWindow.
+//
+// pattern must already be folded with foldRunes and normalized to NFC,
+// which fuzzyScore applies to name as well.
+//
+// Returns the score of the match, higher being better, and whether name
+// matches at all.
+func fuzzyScore(name, pattern string, caseSensitive bool) (int, bool) {
+	if pattern == "" {
+		return 0, true
+	}
+	name = normalize(name)
+
+	start, end, ok := fuzzyWindow(name, pattern, caseSensitive)
+	if !ok {
+		return 0, false
+	}
+	return scoreWindow(name, pattern, start, end, caseSensitive), true
+}
+
+// fuzzyWindow finds the fuzzy match of pattern in name by a forward scan
+// followed by a backward scan that tightens it to the shortest window
+// ending at the same character, like fzf's v1 algorithm.
+//
+// Returns the window name[start:end] holding the match, and whether name
+// matches at all.
+func fuzzyWindow(name, pattern string, caseSensitive bool) (start, end int, ok bool) {
+	// Forward scan: find where the earliest complete match ends
+	pi := 0
+	end = -1
+	for i := 0; i < len(name); {
+		r, n := utf8.DecodeRuneInString(name[i:])
+		p, pn := utf8.DecodeRuneInString(pattern[pi:])
+		if foldRune(r, caseSensitive) == p {
+			pi += pn
+			if pi == len(pattern) {
+				end = i + n
+				break
+			}
+		}
+		i += n
+	}
+	if end < 0 {
+		return 0, 0, false
+	}
+
+	// Backward scan: find the latest start of a match ending at end
+	start = end
+	for pi = len(pattern); pi > 0; {
+		r, n := utf8.DecodeLastRuneInString(name[:start])
+		p, pn := utf8.DecodeLastRuneInString(pattern[:pi])
+		if foldRune(r, caseSensitive) == p {
+			pi -= pn
+		}
+		start -= n
+	}
+	return start, end, true
+}
+
+// scoreWindow scores the match of pattern within name[start:end], matching
+// each pattern character at its first occurrence.
+func scoreWindow(name, pattern string, start, end int, caseSensitive bool) int {
+	prevClass := classOther
+	if start > 0 {
+		r, _ := utf8.DecodeLastRuneInString(name[:start])
+		prevClass = classOf(r)
+	}
+
+	score, pi := 0, 0
+	inGap, consecutive := false, false
+	for i := start; i < end; {
+		r, n := utf8.DecodeRuneInString(name[i:])
+		class := classOf(r)
+		if pi < len(pattern) {
+			p, pn := utf8.DecodeRuneInString(pattern[pi:])
+			if foldRune(r, caseSensitive) == p {
+				bonus := bonusAt(prevClass, class)
+				if pi == 0 {
+					bonus *= bonusFirstCharMultiplier
+				} else if consecutive {
+					bonus = max(bonus, bonusConsecutive)
+				}
+				score += scoreMatch + bonus
+				pi += pn
+				inGap, consecutive = false, true
+				prevClass = class
+				i += n
+				continue
+			}
+		}
+
+		if inGap {
+			score += scoreGapExtension
+		} else {
+			score += scoreGapStart
+		}
+		inGap, consecutive = true, false
+		prevClass = class
+		i += n
+	}
+	return score
+}
+
+// sortByScore scores the directories of result against opts.SearchPattern
+// and orders them best match first. Directories with equal scores are
+// ordered by length, then name, so the closest names come first.
+func sortByScore(result *Result, opts *Options) {
+	pattern := foldRunes(normalize(opts.SearchPattern), opts.CaseSensitive)
+
+	result.Scores = make([]int, len(result.Directories))
+	for i, dir := range result.Directories {
+		result.Scores[i], _ = fuzzyScore(filepath.Base(dir), pattern, opts.CaseSensitive)
+	}
+	result.reorder(func(i, j int) int {
+		a, b := result.Directories[i], result.Directories[j]
+		return cmp.Or(
+			cmp.Compare(result.Scores[j], result.Scores[i]),
+			cmp.Compare(len(a), len(b)),
+			cmp.Compare(a, b),
+		)
+	})
+}
@@ -0,0 +1,100 @@
+package dirsearch
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestFuzzyScore_Matches(t *testing.T) {
+	tests := []struct {
+		name, pattern string
+		caseSensitive bool
+		want          bool
+	}{
+		{"folder-search-results", "fsr", false, true},
+		{"folder-search-results", "FSR", false, true},
+		{"folder-search-results", "rsf", false, false},
+		{"FolderSearch", "fs", true, false},
+		{"FolderSearch", "FS", true, true},
+		{"Übersicht", "üb", false, true},
+		{"U\u0308bersicht", "Üb", false, true},
+		{"Kırmızı", "kirmizi", false, true},
+		{"PROJECTΣ", "pς", false, true},
+		{"src", "", false, true},
+		{"src", "srcs", false, false},
+	}
+	for _, tt := range tests {
+		pattern := foldRunes(normalize(tt.pattern), tt.caseSensitive)
+		if _, ok := fuzzyScore(tt.name, pattern, tt.caseSensitive); ok != tt.want {
+			t.Errorf("fuzzyScore(%q, %q): expected match %v, got %v", tt.name, tt.pattern, tt.want, ok)
+		}
+	}
+}
+
+func TestFuzzyScore_Ranking(t *testing.T) {
+	// Each name should score higher than the one after it
+	tests := []struct {
+		pattern string
+		names   []string
+	}{
+		{"fsr", []string{"fs-reader", "folder-search-results", "offsetrange"}},
+		{"src", []string{"src", "resources"}},
+		{"api", []string{"my-api", "MyApi", "capital"}},
+	}
+	for _, tt := range tests {
+		prev := 0
+		for i, name := range tt.names {
+			score, ok := fuzzyScore(name, tt.pattern, false)
+			if !ok {
+				t.Fatalf("expected %q to match %q", tt.pattern, name)
+			}
+			if i > 0 && score >= prev {
+				t.Errorf("pattern %q: expected %q (%d) to score below %q (%d)", tt.pattern, name, score, tt.names[i-1], prev)
+			}
+			prev = score
+		}
+	}
+}
+
+func TestSearch_Fuzzy(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "dirsearch-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	for _, dir := range []string{"offsetrange", "folder-search-results", "docs", "lib/fs-reader"} {
+		if err := os.MkdirAll(filepath.Join(tempDir, dir), 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", dir, err)
+		}
+	}
+
+	result := Search(&Options{SearchPattern: "fsr", StartDir: tempDir, Fuzzy: true, MaxDepth: UnlimitedDepth})
+	if result.Error != nil {
+		t.Fatalf("unexpected error: %v", result.Error)
+	}
+	want := []string{filepath.Join("lib", "fs-reader"), "folder-search-results", "offsetrange"}
+	if !slices.Equal(result.Directories, want) {
+		t.Errorf("expected %v, got %v", want, result.Directories)
+	}
+	if len(result.Scores) != len(result.Directories) {
+		t.Fatalf("expected %d scores, got %v", len(result.Directories), result.Scores)
+	}
+	if !slices.IsSortedFunc(result.Scores, func(a, b int) int { return b - a }) {
+		t.Errorf("expected scores in descending order, got %v", result.Scores)
+	}
+
+	result = Search(&Options{StartDir: tempDir, Fuzzy: true})
+	if result.Scores != nil {
+		t.Errorf("expected no scores without a pattern, got %v", result.Scores)
+	}
+}
+
+func TestSearch_FuzzyWithRegex(t *testing.T) {
+	result := Search(&Options{SearchPattern: "a", StartDir: ".", Fuzzy: true, Regex: true})
+	if result.Error == nil {
+		t.Error("expected error when combining fuzzy and regex matching")
+	}
+}
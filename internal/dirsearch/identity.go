@@ -0,0 +1,28 @@
+package dirsearch
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// entryKey returns a key identifying the directory or file at p, so that
+// the same one reached through different roots or symlinks gets the same
+// key: its device and inode where the platform reports them, otherwise its
+// path with symlinks resolved. Paths inside fsys are only cleaned, as FS
+// implementations have no symlinks to resolve.
+func entryKey(fsys fs.FS, p string) string {
+	if fsys != nil {
+		return path.Clean(p)
+	}
+	if info, err := os.Stat(p); err == nil {
+		if id, ok := fileID(info); ok {
+			return id
+		}
+	}
+	if resolved, err := filepath.EvalSymlinks(p); err == nil {
+		return resolved
+	}
+	return filepath.Clean(p)
+}
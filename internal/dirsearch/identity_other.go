@@ -0,0 +1,11 @@
+//go:build !linux && !darwin && !freebsd
+
+package dirsearch
+
+import "io/fs"
+
+// fileID reports that the platform has no device and inode numbers, so
+// entries are identified by their resolved paths.
+func fileID(fs.FileInfo) (string, bool) {
+	return "", false
+}
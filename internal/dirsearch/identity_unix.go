@@ -0,0 +1,18 @@
+//go:build linux || darwin || freebsd
+
+package dirsearch
+
+import (
+	"fmt"
+	"io/fs"
+	"syscall"
+)
+
+// fileID returns the device and inode of info as a string.
+func fileID(info fs.FileInfo) (string, bool) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return "", false
+	}
+	return fmt.Sprintf("%d:%d", st.Dev, st.Ino), true
+}
@@ -0,0 +1,182 @@
+package dirsearch
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// ignoreSet is an ignore list compiled for fast lookups.
+//
+// Most ignore patterns are exact directory names, which a set answers. In
+// front of the set sits a small bloom-style filter over the name length and
+// first byte: most entries of a directory are rejected by two bit tests
+// without hashing the name, which keeps large ignore lists from dominating
+// scan time. Patterns with shell wildcards ("build-*") are matched one by
+// one with filepath.Match. Patterns are normalized first, so .gitignore
+// style "**/tmp" and "tmp/" work as "tmp".
+//
+// A pattern starting with "!" negates: names it matches are never ignored,
+// whichever other pattern matches them and in whatever order they appear.
+// This lets a later list, such as the config, bring back names hidden by
+// the default ignore list, e.g. "!.git".
+type ignoreSet struct {
+	names   map[string]struct{}
+	lengths uint64    // Bit n is set if a name of length n (mod 64) is ignored
+	firsts  [4]uint64 // Bit b is set if an ignored name starts with byte b
+	globs   []string
+	keep    []string // Negated patterns, without the "!"
+}
+
+// ErrPathPattern is returned by CheckIgnorePattern for patterns that
+// describe a path rather than a directory name.
+var ErrPathPattern = errors.New("ignore patterns match directory names, not paths")
+
+// CheckIgnorePattern reports whether pattern is a well-formed ignore
+// pattern: a directory name, optionally with shell wildcards, a leading
+// "**/" or "!" and a trailing "/".
+//
+// Returns ErrPathPattern for patterns with other slashes, or the error of
+// filepath.Match for malformed wildcards.
+func CheckIgnorePattern(pattern string) error {
+	name := normalizeIgnore(strings.TrimPrefix(pattern, "!"))
+	// On Windows a backslash is a separator, not an escape character
+	if strings.Contains(name, "/") || (filepath.Separator != '/' && strings.ContainsRune(name, filepath.Separator)) {
+		return fmt.Errorf("%w: %q", ErrPathPattern, pattern)
+	}
+	if _, err := filepath.Match(name, ""); err != nil {
+		return err
+	}
+	return nil
+}
+
+// normalizeIgnore returns the directory name pattern described by an
+// ignore pattern written the way .gitignore files do: "**/tmp" (tmp at any
+// depth, which every pattern matches anyway) and "build/" (only
+// directories, which are the only entries ignored) both mean the name
+// alone. "**" within a name matches like "*".
+func normalizeIgnore(pattern string) string {
+	for {
+		trimmed := strings.TrimSuffix(strings.TrimPrefix(pattern, "**/"), "/")
+		if trimmed == pattern {
+			break
+		}
+		pattern = trimmed
+	}
+	for strings.Contains(pattern, "**") {
+		pattern = strings.ReplaceAll(pattern, "**", "*")
+	}
+	return pattern
+}
+
+// isGlob reports whether an ignore pattern contains shell wildcards rather
+// than naming a directory exactly.
+func isGlob(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?[")
+}
+
+// newIgnoreSet compiles patterns into an ignoreSet.
+func newIgnoreSet(patterns []string) ignoreSet {
+	s := ignoreSet{names: make(map[string]struct{}, len(patterns))}
+	for _, p := range patterns {
+		if p == "" {
+			continue
+		}
+		if kept, ok := strings.CutPrefix(p, "!"); ok {
+			if kept = normalizeIgnore(kept); kept != "" {
+				s.keep = append(s.keep, kept)
+			}
+			continue
+		}
+		if p = normalizeIgnore(p); p == "" {
+			continue
+		}
+		if isGlob(p) {
+			if _, err := filepath.Match(p, ""); err == nil {
+				s.globs = append(s.globs, p)
+			}
+			continue
+		}
+		s.names[p] = struct{}{}
+		s.lengths |= 1 << (len(p) % 64)
+		s.firsts[p[0]/64] |= 1 << (p[0] % 64)
+	}
+	return s
+}
+
+// contains reports whether name is in the ignore list.
+func (s ignoreSet) contains(name string) bool {
+	if name == "" || !s.matches(name) {
+		return false
+	}
+	for _, k := range s.keep {
+		if MatchIgnore(k, name) {
+			return false
+		}
+	}
+	return true
+}
+
+// matches reports whether name matches a pattern that is not negated.
+func (s ignoreSet) matches(name string) bool {
+	if s.lengths&(1<<(len(name)%64)) != 0 && s.firsts[name[0]/64]&(1<<(name[0]%64)) != 0 {
+		if _, ok := s.names[name]; ok {
+			return true
+		}
+	}
+	for _, g := range s.globs {
+		if ok, _ := filepath.Match(g, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchIgnore reports whether a single ignore pattern hides a directory
+// named name, the same way Options.IgnorePatterns are applied. A negated
+// pattern ("!name") never hides anything; see MatchKeep.
+func MatchIgnore(pattern, name string) bool {
+	if strings.HasPrefix(pattern, "!") {
+		return false
+	}
+	pattern = normalizeIgnore(pattern)
+	if isGlob(pattern) {
+		ok, _ := filepath.Match(pattern, name)
+		return ok
+	}
+	return pattern != "" && pattern == name
+}
+
+// MatchKeep reports whether pattern is a negated ignore pattern ("!name")
+// matching name, which keeps the directory listed even if other patterns
+// ignore it.
+func MatchKeep(pattern, name string) bool {
+	kept, ok := strings.CutPrefix(pattern, "!")
+	return ok && MatchIgnore(kept, name)
+}
+
+// EscapeIgnore returns the ignore pattern matching only the directory
+// named name, with its wildcards and a leading "!" escaped.
+//
+// Special characters are put in character classes ("[*]") rather than
+// escaped with a backslash, which filepath.Match treats as a path
+// separator on Windows.
+func EscapeIgnore(name string) string {
+	if !strings.ContainsAny(name, `*?[\`) && !strings.HasPrefix(name, "!") {
+		return name
+	}
+	var b strings.Builder
+	for i, r := range name {
+		switch {
+		case r == '*' || r == '?' || r == '[' || (r == '!' && i == 0):
+			b.WriteString("[" + string(r) + "]")
+		case r == '\\':
+			// Only reachable on Unix, where names may contain backslashes
+			b.WriteString(`[\\]`)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,157 @@
+package dirsearch
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestIgnoreSet(t *testing.T) {
+	s := newIgnoreSet([]string{"node_modules", "vendor", "", "dist"})
+
+	for _, name := range []string{"node_modules", "vendor", "dist"} {
+		if !s.contains(name) {
+			t.Errorf("expected %q to be ignored", name)
+		}
+	}
+	for _, name := range []string{"", "node", "vendors", "Dist", "src"} {
+		if s.contains(name) {
+			t.Errorf("expected %q not to be ignored", name)
+		}
+	}
+}
+
+func TestIgnoreSet_Globs(t *testing.T) {
+	s := newIgnoreSet([]string{"build-*", "*.egg-info", "tmp?", "[", "vendor"})
+
+	for _, name := range []string{"build-2024", "build-", "pkg.egg-info", "tmp1", "vendor"} {
+		if !s.contains(name) {
+			t.Errorf("expected %q to be ignored", name)
+		}
+	}
+	// "[" is a malformed pattern and never matches
+	for _, name := range []string{"build", "rebuild-1", "tmp12", "[", "src"} {
+		if s.contains(name) {
+			t.Errorf("expected %q not to be ignored", name)
+		}
+	}
+}
+
+func TestIgnoreSet_Negation(t *testing.T) {
+	s := newIgnoreSet([]string{".git", "build-*", "!build-keep", "!", "node_modules"})
+
+	for _, name := range []string{".git", "build-1", "node_modules"} {
+		if !s.contains(name) {
+			t.Errorf("expected %q to be ignored", name)
+		}
+	}
+	for _, name := range []string{"build-keep", "!", "!build-keep", "src"} {
+		if s.contains(name) {
+			t.Errorf("expected %q not to be ignored", name)
+		}
+	}
+
+	if !MatchKeep("!build-*", "build-1") || MatchKeep("build-*", "build-1") || MatchKeep("!build-*", "src") {
+		t.Error("expected MatchKeep to match only negated patterns")
+	}
+	if MatchIgnore("!build-1", "build-1") {
+		t.Error("expected negated pattern not to hide anything")
+	}
+}
+
+func TestIgnoreSet_GitignoreStyle(t *testing.T) {
+	s := newIgnoreSet([]string{"**/tmp", "dist/", "**/*.egg-info/", "cache**", "!**/tmp-keep"})
+
+	for _, name := range []string{"tmp", "dist", "pkg.egg-info", "cache", "cache-v2"} {
+		if !s.contains(name) {
+			t.Errorf("expected %q to be ignored", name)
+		}
+	}
+	for _, name := range []string{"tmp-keep", "tmp2", "distro", "egg-info", "src"} {
+		if s.contains(name) {
+			t.Errorf("expected %q not to be ignored", name)
+		}
+	}
+}
+
+func TestCheckIgnorePattern(t *testing.T) {
+	for _, p := range []string{"node_modules", "build-*", "**/tmp", "dist/", "!.git", "!**/keep/", "*.egg-info"} {
+		if err := CheckIgnorePattern(p); err != nil {
+			t.Errorf("CheckIgnorePattern(%q): unexpected error: %v", p, err)
+		}
+	}
+	for _, p := range []string{"src/tmp", "**/src/tmp", "!a/b"} {
+		if err := CheckIgnorePattern(p); !errors.Is(err, ErrPathPattern) {
+			t.Errorf("CheckIgnorePattern(%q): expected ErrPathPattern, got %v", p, err)
+		}
+	}
+	if err := CheckIgnorePattern("[abc"); err == nil {
+		t.Error("expected error for malformed pattern, got nil")
+	}
+}
+
+func TestMatchIgnore(t *testing.T) {
+	patterns := []string{"build-*", "*.egg-info", "tmp?", "[", "vendor", ""}
+	names := []string{"build-2024", "pkg.egg-info", "tmp1", "tmp12", "vendor", "vendors", "[", ""}
+
+	// MatchIgnore must agree with the compiled set for every pattern
+	for _, p := range patterns {
+		s := newIgnoreSet([]string{p})
+		for _, name := range names {
+			if got, want := MatchIgnore(p, name), s.contains(name); got != want {
+				t.Errorf("MatchIgnore(%q, %q): expected %v, got %v", p, name, want, got)
+			}
+		}
+	}
+}
+
+func TestEscapeIgnore(t *testing.T) {
+	names := []string{"plain", "build[1]", "what?", "a*b", "!important", "x!y", "**"}
+	if filepath.Separator == '/' {
+		names = append(names, `back\slash`)
+	}
+
+	for _, name := range names {
+		pattern := EscapeIgnore(name)
+		if err := CheckIgnorePattern(pattern); err != nil {
+			t.Errorf("EscapeIgnore(%q) = %q: unexpected error: %v", name, pattern, err)
+		}
+		if !MatchIgnore(pattern, name) || !newIgnoreSet([]string{pattern}).contains(name) {
+			t.Errorf("EscapeIgnore(%q) = %q: expected the pattern to match the name", name, pattern)
+		}
+		for _, other := range names {
+			if other != name && MatchIgnore(pattern, other) {
+				t.Errorf("EscapeIgnore(%q) = %q: expected %q not to match", name, pattern, other)
+			}
+		}
+	}
+}
+
+// BenchmarkIgnore compares the compiled ignore set with a linear scan of a
+// large ignore list, the way entries were checked before.
+func BenchmarkIgnore(b *testing.B) {
+	patterns := make([]string, 500)
+	for i := range patterns {
+		patterns[i] = fmt.Sprintf("ignored-%d", i)
+	}
+	names := []string{"src", "internal", "docs", "ignored-250", "build", "cmd"}
+
+	var hits int
+	b.Run("set", func(b *testing.B) {
+		s := newIgnoreSet(patterns)
+		for i := 0; i < b.N; i++ {
+			if s.contains(names[i%len(names)]) {
+				hits++
+			}
+		}
+	})
+	b.Run("linear", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if slices.Contains(patterns, names[i%len(names)]) {
+				hits++
+			}
+		}
+	})
+}
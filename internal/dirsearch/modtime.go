@@ -0,0 +1,63 @@
+package dirsearch
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ageUnits maps the units ParseAge accepts on top of those of
+// time.ParseDuration to their length.
+var ageUnits = map[string]time.Duration{
+	"d": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour,
+	"y": 365 * 24 * time.Hour,
+}
+
+// matchModTime reports whether the entry at path was modified within the
+// bounds of the search; always without bounds. Entries that cannot be
+// inspected are left out when there are bounds.
+func (m *matcher) matchModTime(path string) bool {
+	if m.modAfter.IsZero() && m.modBefore.IsZero() {
+		return true
+	}
+
+	var info fs.FileInfo
+	var err error
+	if m.fsys != nil {
+		info, err = fs.Stat(m.fsys, path)
+	} else {
+		info, err = os.Lstat(path)
+	}
+	if err != nil {
+		return false
+	}
+	mtime := info.ModTime()
+	return (m.modAfter.IsZero() || mtime.After(m.modAfter)) &&
+		(m.modBefore.IsZero() || mtime.Before(m.modBefore))
+}
+
+// ParseAge parses an age such as "36h", "7d", "2w" or "1y". On top of the
+// units of time.ParseDuration it accepts a single number of days (d),
+// weeks (w) or years of 365 days (y).
+//
+// Returns an error if s is not a non-negative age.
+func ParseAge(s string) (time.Duration, error) {
+	str := strings.TrimSpace(s)
+	if n := len(str); n > 1 {
+		if unit, ok := ageUnits[str[n-1:]]; ok {
+			value, err := strconv.ParseFloat(str[:n-1], 64)
+			if err == nil && value >= 0 && value*float64(unit) < float64(1<<63-1) {
+				return time.Duration(value * float64(unit)), nil
+			}
+		}
+	}
+	age, err := time.ParseDuration(str)
+	if err != nil || age < 0 {
+		return 0, fmt.Errorf("invalid age %q: use a duration such as 36h, 7d, 2w or 1y", s)
+	}
+	return age, nil
+}
@@ -0,0 +1,111 @@
+package dirsearch
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestSearch_ModTimeBounds(t *testing.T) {
+	root, err := os.MkdirTemp("", "dirsearch-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(root)
+
+	now := time.Now()
+	ages := map[string]time.Duration{
+		"active":                      24 * time.Hour,
+		"recent":                      10 * 24 * time.Hour,
+		"stale":                       2 * 365 * 24 * time.Hour,
+		filepath.Join("stale", "old"): 3 * 365 * 24 * time.Hour,
+	}
+	// Parents are created first, so setting their time is not undone by
+	// creating their children
+	for _, name := range []string{"active", "recent", "stale", filepath.Join("stale", "old")} {
+		if err := os.MkdirAll(filepath.Join(root, name), 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", name, err)
+		}
+	}
+	for name, age := range ages {
+		mtime := now.Add(-age)
+		if err := os.Chtimes(filepath.Join(root, name), mtime, mtime); err != nil {
+			t.Fatalf("failed to set mtime: %v", err)
+		}
+	}
+
+	week, year := now.Add(-7*24*time.Hour), now.Add(-365*24*time.Hour)
+	tests := []struct {
+		name     string
+		after    time.Time
+		before   time.Time
+		depth    int
+		expected []string
+	}{
+		{"last week", week, time.Time{}, 1, []string{"active"}},
+		{"untouched for a year", time.Time{}, year, 1, []string{"stale"}},
+		{"range", year, week, 1, []string{"recent"}},
+		{"recursive", time.Time{}, year, UnlimitedDepth, []string{"stale", filepath.Join("stale", "old")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &Options{StartDir: root, ModifiedAfter: tt.after, ModifiedBefore: tt.before, MaxDepth: tt.depth}
+			result := Search(opts)
+			if result.Error != nil {
+				t.Fatalf("unexpected error: %v", result.Error)
+			}
+			if !slices.Equal(result.Directories, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, result.Directories)
+			}
+		})
+	}
+}
+
+func TestSearch_ModTimeBoundsFS(t *testing.T) {
+	now := time.Now()
+	fsys := fstest.MapFS{
+		"old": {Mode: 0755 | os.ModeDir, ModTime: now.Add(-48 * time.Hour)},
+		"new": {Mode: 0755 | os.ModeDir, ModTime: now},
+	}
+
+	result := Search(&Options{FS: fsys, StartDir: ".", ModifiedAfter: now.Add(-time.Hour)})
+	if result.Error != nil {
+		t.Fatalf("unexpected error: %v", result.Error)
+	}
+	if expected := []string{"new"}; !slices.Equal(result.Directories, expected) {
+		t.Errorf("expected %v, got %v", expected, result.Directories)
+	}
+}
+
+func TestParseAge(t *testing.T) {
+	day := 24 * time.Hour
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"90m", 90 * time.Minute},
+		{"36h", 36 * time.Hour},
+		{"7d", 7 * day},
+		{"1.5d", 36 * time.Hour},
+		{"2w", 14 * day},
+		{"1y", 365 * day},
+		{"0", 0},
+	}
+	for _, tt := range tests {
+		got, err := ParseAge(tt.in)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.in, err)
+		} else if got != tt.want {
+			t.Errorf("%q: expected %v, got %v", tt.in, tt.want, got)
+		}
+	}
+
+	for _, in := range []string{"", "d", "-1d", "-3h", "week", "1000y"} {
+		if _, err := ParseAge(in); err == nil {
+			t.Errorf("%q: expected error, got nil", in)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package dirsearch
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// DefaultPageSize is the number of matches returned per page by Pager.Next
+// when no positive page size is given.
+const DefaultPageSize = 100
+
+// Page is a slice of the matches of a search, as returned by Pager.Next.
+type Page struct {
+	Directories []string    // Matches of the page, in directory order
+	Types       []EntryType // Types[i] is the type of Directories[i]; set only with IncludeFiles
+	Offset      int         // Index of the first match of the page among all matches
+	More        bool        // Whether more matches follow the page
+}
+
+// Pager reads the matches of a search in pages, so that callers can show
+// the first matches of very large directories without holding the whole
+// listing in memory. It keeps the directory open between pages and only
+// reads as far as the requested pages need.
+//
+// Like SearchStream, a Pager reads only the immediate children of StartDir,
+// ignoring MaxDepth, and returns matches in the order the directory lists
+// them: sorting and fuzzy ordering need every match and are not applied.
+// MaxResults has no effect. A Pager is not safe for concurrent use.
+type Pager struct {
+	opts    Options
+	m       *matcher
+	dir     dirReader
+	workers int
+
+	pending []pagedEntry // matches read but not returned yet
+	offset  int          // index of the first pending match
+	eof     bool         // the directory has been read to the end
+}
+
+// pagedEntry is a match buffered by a Pager.
+type pagedEntry struct {
+	name string
+	typ  EntryType
+}
+
+// NewPager opens opts.StartDir for a paged search.
+//
+// Parameters:
+//   - opts: configuration options for the search; later changes to opts do
+//     not affect the Pager
+//
+// Returns the Pager, which must be closed, or an error if the pattern is
+// invalid or the directory cannot be opened.
+func NewPager(opts *Options) (*Pager, error) {
+	m, err := newMatcher(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := openDir(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// FS is read by a single worker, see Options.FS
+	workers := opts.Concurrency
+	if opts.FS != nil {
+		workers = 1
+	}
+
+	return &Pager{opts: *opts, m: m, dir: dir, workers: workers}, nil
+}
+
+// fill reads the directory until at least n matches are pending or the
+// directory ends. Matches read before an error stay pending.
+func (p *Pager) fill(ctx context.Context, n int) error {
+	for len(p.pending) < n && !p.eof {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		entries, err := p.dir.ReadDir(DefaultBatchSize)
+		for _, entry := range p.m.filterEntries(ctx, p.opts.StartDir, entries, p.workers) {
+			p.pending = append(p.pending, pagedEntry{name: entry.Name(), typ: entryType(entry)})
+		}
+		if errors.Is(err, io.EOF) {
+			p.eof = true
+		} else if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// take removes the first n pending matches, which must exist.
+func (p *Pager) take(n int) []pagedEntry {
+	taken := p.pending[:n:n]
+	// Copy the rest so the buffer does not keep growing from its front
+	p.pending = append([]pagedEntry(nil), p.pending[n:]...)
+	p.offset += n
+	return taken
+}
+
+// Next returns the next page of matches.
+//
+// Parameters:
+//   - ctx: cancels reading the directory between batches
+//   - n: maximum number of matches in the page (DefaultPageSize if not positive)
+//
+// Returns the page, which is empty with More unset once every match was
+// returned, or an error if the directory cannot be read or ctx is canceled.
+// On error the page is empty, and the matches read so far are returned by
+// the next call.
+func (p *Pager) Next(ctx context.Context, n int) (Page, error) {
+	if n <= 0 {
+		n = DefaultPageSize
+	}
+
+	// Read one match ahead to tell whether another page follows
+	if err := p.fill(ctx, n+1); err != nil {
+		return Page{Directories: []string{}, Offset: p.offset}, err
+	}
+
+	page := Page{Offset: p.offset, More: len(p.pending) > n}
+	entries := p.take(min(n, len(p.pending)))
+	page.Directories = make([]string, 0, len(entries))
+	if p.opts.IncludeFiles {
+		page.Types = make([]EntryType, 0, len(entries))
+	}
+	for _, entry := range entries {
+		page.Directories = append(page.Directories, entry.name)
+		if page.Types != nil {
+			page.Types = append(page.Types, entry.typ)
+		}
+	}
+	return page, nil
+}
+
+// Skip discards the next n matches, e.g. to resume a listing at an offset.
+// Fewer matches are discarded if the directory ends first.
+//
+// Returns an error if the directory cannot be read or ctx is canceled.
+func (p *Pager) Skip(ctx context.Context, n int) error {
+	for n > 0 {
+		// Read at most a batch ahead so skipping does not buffer n matches
+		err := p.fill(ctx, min(n, DefaultBatchSize))
+		skipped := min(n, len(p.pending))
+		p.take(skipped)
+		n -= skipped
+		if err != nil {
+			return err
+		}
+		if p.eof && len(p.pending) == 0 {
+			break
+		}
+	}
+	return nil
+}
+
+// Offset returns the index, among all matches, of the first match of the
+// next page.
+func (p *Pager) Offset() int {
+	return p.offset
+}
+
+// Close closes the directory read by the Pager.
+func (p *Pager) Close() error {
+	return p.dir.Close()
+}
+
+// SearchPage returns a single page of the matches of a search, for callers
+// that cannot keep a Pager between pages. Matches before offset are read
+// and discarded, so each call costs as much as reading up to the end of the
+// page.
+//
+// Parameters:
+//   - ctx: cancels reading the directory between batches
+//   - opts: configuration options for the search, as for NewPager
+//   - offset: index of the first match of the page
+//   - limit: maximum number of matches in the page (DefaultPageSize if not positive)
+//
+// Returns the page, or an error if the search cannot be performed.
+func SearchPage(ctx context.Context, opts *Options, offset, limit int) (Page, error) {
+	p, err := NewPager(opts)
+	if err != nil {
+		return Page{Directories: []string{}, Offset: offset}, err
+	}
+	defer p.Close()
+
+	if err := p.Skip(ctx, offset); err != nil {
+		return Page{Directories: []string{}, Offset: p.Offset()}, err
+	}
+	return p.Next(ctx, limit)
+}
+
+// Pager is the paged counterpart of ScanDirs.
+//
+// It opens a Pager for dir on a snapshot of the options, leaving Options
+// unchanged.
+func (d *DirSearch) Pager(dir string) (*Pager, error) {
+	return NewPager(d.snapshot(dir))
+}
@@ -0,0 +1,190 @@
+package dirsearch
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// makePagerDirs creates count directories and a few ignored entries in a
+// new temporary directory.
+func makePagerDirs(t *testing.T, count int) string {
+	t.Helper()
+	tempDir, err := os.MkdirTemp("", "dirsearch-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	for i := 0; i < count; i++ {
+		if err := os.Mkdir(filepath.Join(tempDir, fmt.Sprintf("dir%04d", i)), 0755); err != nil {
+			t.Fatalf("failed to create test dir: %v", err)
+		}
+	}
+	for _, name := range []string{"node_modules", ".hidden"} {
+		if err := os.Mkdir(filepath.Join(tempDir, name), 0755); err != nil {
+			t.Fatalf("failed to create test dir: %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "file.txt"), []byte("x"), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	return tempDir
+}
+
+func TestPager(t *testing.T) {
+	// More directories than a batch, so pages span several reads
+	const dirCount = DefaultBatchSize + 90
+	tempDir := makePagerDirs(t, dirCount)
+	defer os.RemoveAll(tempDir)
+
+	opts := &Options{
+		StartDir:       tempDir,
+		IgnorePatterns: []string{"node_modules"},
+	}
+	p, err := NewPager(opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer p.Close()
+
+	ctx := context.Background()
+	var paged []string
+	for {
+		page, err := p.Next(ctx, 100)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if page.Offset != len(paged) {
+			t.Errorf("expected page offset %d, got %d", len(paged), page.Offset)
+		}
+		if len(page.Directories) > 100 {
+			t.Errorf("page larger than page size: %d", len(page.Directories))
+		}
+		if page.Types != nil {
+			t.Errorf("expected no types without IncludeFiles, got %v", page.Types)
+		}
+		paged = append(paged, page.Directories...)
+		if p.Offset() != len(paged) {
+			t.Errorf("expected pager offset %d, got %d", len(paged), p.Offset())
+		}
+		if !page.More {
+			break
+		}
+	}
+
+	// The pages must hold the same directories as Search
+	plain := Search(opts)
+	if plain.Error != nil {
+		t.Fatalf("unexpected error: %v", plain.Error)
+	}
+	slices.Sort(paged)
+	if !slices.Equal(paged, plain.Directories) {
+		t.Errorf("expected paged directories to match Search: got %d, expected %d", len(paged), len(plain.Directories))
+	}
+
+	// Once exhausted, the pager keeps returning empty pages
+	page, err := p.Next(ctx, 100)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.Directories) != 0 || page.More {
+		t.Errorf("expected empty last page, got %d directories, more %v", len(page.Directories), page.More)
+	}
+}
+
+func TestPager_IncludeFiles(t *testing.T) {
+	tempDir := makePagerDirs(t, 3)
+	defer os.RemoveAll(tempDir)
+
+	p, err := NewPager(&Options{StartDir: tempDir, IncludeFiles: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer p.Close()
+
+	page, err := p.Next(context.Background(), 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.Types) != len(page.Directories) {
+		t.Fatalf("expected %d types, got %d", len(page.Directories), len(page.Types))
+	}
+	for i, name := range page.Directories {
+		expected := Dir
+		if name == "file.txt" {
+			expected = File
+		}
+		if page.Types[i] != expected {
+			t.Errorf("expected %s to have type %v, got %v", name, expected, page.Types[i])
+		}
+	}
+	if !slices.Contains(page.Directories, "file.txt") {
+		t.Errorf("expected file.txt in page, got %v", page.Directories)
+	}
+}
+
+func TestSearchPage(t *testing.T) {
+	const dirCount = 250
+	tempDir := makePagerDirs(t, dirCount)
+	defer os.RemoveAll(tempDir)
+
+	opts := &Options{StartDir: tempDir, IgnorePatterns: []string{"node_modules"}, Hidden: HiddenHide}
+	ctx := context.Background()
+
+	// Pages fetched independently must not overlap and must cover every match
+	var all []string
+	for offset := 0; ; offset += 40 {
+		page, err := SearchPage(ctx, opts, offset, 40)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if page.Offset != offset {
+			t.Errorf("expected offset %d, got %d", offset, page.Offset)
+		}
+		all = append(all, page.Directories...)
+		if !page.More {
+			break
+		}
+	}
+	if len(all) != dirCount {
+		t.Errorf("expected %d directories, got %d", dirCount, len(all))
+	}
+	slices.Sort(all)
+	if len(slices.Compact(all)) != dirCount {
+		t.Errorf("expected pages not to overlap")
+	}
+
+	// An offset past the end gives an empty page
+	page, err := SearchPage(ctx, opts, dirCount+10, 40)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.Directories) != 0 || page.More {
+		t.Errorf("expected empty page past the end, got %d directories, more %v", len(page.Directories), page.More)
+	}
+	if page.Offset != dirCount {
+		t.Errorf("expected offset to stop at %d, got %d", dirCount, page.Offset)
+	}
+}
+
+func TestSearchPage_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	if _, err := SearchPage(ctx, &Options{StartDir: "/nonexistent/dirsearch-pager"}, 0, 10); err == nil {
+		t.Error("expected error for missing directory")
+	}
+
+	tempDir := makePagerDirs(t, 1)
+	defer os.RemoveAll(tempDir)
+	if _, err := SearchPage(ctx, &Options{StartDir: tempDir, SearchPattern: "(", Regex: true}, 0, 10); err == nil {
+		t.Error("expected error for invalid pattern")
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := SearchPage(canceled, &Options{StartDir: tempDir}, 0, 10); err == nil {
+		t.Error("expected error for canceled context")
+	}
+}
@@ -0,0 +1,96 @@
+package dirsearch
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+)
+
+// SearchPaths applies the rules of Search to a list of known directory
+// paths instead of reading the disk, e.g. to serve searches from an index.
+//
+// A path matches if its last element matches opts.SearchPattern and none of
+// its elements is hidden (as selected by opts.Hidden) or ignored,
+// just as Search skips such directories together with their subtrees.
+// opts.MaxDepth limits the number of path elements the same way it limits
+// how deep Search descends, and opts.MaxResults keeps the first matches
+// in the order of paths.
+//
+// Size and modification time bounds are checked on disk, as are SortModTime
+// and SortSize. So are opts.Filters, against every element of a path, and
+// paths that cannot be inspected are left out when there are filters.
+//
+// Parameters:
+//   - ctx: cancels measuring directories for size bounds and sorting
+//   - opts: the search options; opts.IncludeFiles has no effect
+//   - paths: directory paths relative to opts.StartDir
+//
+// Returns the matching paths ordered like SearchContext orders them, or an
+// error if the pattern is invalid.
+func SearchPaths(ctx context.Context, opts *Options, paths []string) Result {
+	m, err := newMatcher(opts)
+	if err != nil {
+		return Result{Directories: []string{}, Error: err}
+	}
+
+	maxDepth := opts.MaxDepth
+	if maxDepth == 0 {
+		maxDepth = 1
+	}
+
+	found := []string{}
+	truncated := false
+	kept := make(map[string]bool) // Filter outcome of each parent path
+	for _, p := range paths {
+		elems := strings.Split(p, string(filepath.Separator))
+		if maxDepth > 0 && len(elems) > maxDepth {
+			continue
+		}
+		skipped := false
+		for _, elem := range elems {
+			if m.skip(elem) {
+				skipped = true
+				break
+			}
+		}
+		if skipped || !m.matchName(elems[len(elems)-1]) || !m.keepElems(opts.StartDir, elems, kept) ||
+			!m.matchFilters(ctx, filepath.Join(opts.StartDir, p), true) {
+			continue
+		}
+		if opts.MaxResults > 0 && len(found) >= opts.MaxResults {
+			truncated = true
+			break
+		}
+		found = append(found, p)
+	}
+
+	result := Result{Directories: found, Truncated: truncated}
+	result.Sort(ctx, opts)
+	result.setMatchedRanges(opts)
+	result.setProjects(opts)
+	return result
+}
+
+// keepElems reports whether the filters of the search keep the directory
+// made of elems below root and every directory above it, remembering the
+// outcome for parents in kept.
+func (m *matcher) keepElems(root string, elems []string, kept map[string]bool) bool {
+	if len(m.filters) == 0 {
+		return true
+	}
+	path := root
+	for i, elem := range elems {
+		path = filepath.Join(path, elem)
+		ok, seen := kept[path]
+		if !seen {
+			ok = m.keepPath(path)
+			if i < len(elems)-1 {
+				kept[path] = ok
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
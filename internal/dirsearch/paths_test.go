@@ -0,0 +1,113 @@
+package dirsearch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestSearchPaths(t *testing.T) {
+	paths := []string{
+		"api",
+		"api/v1",
+		"web",
+		"web/node_modules",
+		"web/node_modules/api-client",
+		"tools/.cache",
+		"tools/.cache/api",
+		"deep/a/b/api",
+	}
+
+	tests := []struct {
+		name     string
+		pattern  string
+		maxDepth int
+		hidden   HiddenMode
+		expected []string
+	}{
+		{"substring at any depth", "api", UnlimitedDepth, HiddenShow, []string{"api", "deep/a/b/api", "tools/.cache/api"}},
+		{"hidden parents skipped", "api", UnlimitedDepth, HiddenHide, []string{"api", "deep/a/b/api"}},
+		{"depth limit", "api", 2, HiddenShow, []string{"api"}},
+		{"default depth is one level", "", 0, HiddenShow, []string{"api", "web"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultOptions()
+			opts.SearchPattern = tt.pattern
+			opts.MaxDepth = tt.maxDepth
+			opts.Hidden = tt.hidden
+
+			result := SearchPaths(context.Background(), opts, fromSlash(paths))
+			if result.Error != nil {
+				t.Fatalf("unexpected error: %v", result.Error)
+			}
+			if expected := fromSlash(tt.expected); !slices.Equal(result.Directories, expected) {
+				t.Errorf("expected %v, got %v", expected, result.Directories)
+			}
+		})
+	}
+}
+
+func TestSearchPaths_MaxResults(t *testing.T) {
+	opts := DefaultOptions()
+	opts.MaxDepth = UnlimitedDepth
+	opts.SortBy = SortNone
+	opts.MaxResults = 2
+
+	result := SearchPaths(context.Background(), opts, []string{"a", "b", "c"})
+	if !slices.Equal(result.Directories, []string{"a", "b"}) || !result.Truncated {
+		t.Errorf("expected [a b] truncated, got %v (truncated %v)", result.Directories, result.Truncated)
+	}
+
+	opts.MaxResults = 3
+	result = SearchPaths(context.Background(), opts, []string{"a", "b", "c"})
+	if len(result.Directories) != 3 || result.Truncated {
+		t.Errorf("expected 3 results not truncated, got %v (truncated %v)", result.Directories, result.Truncated)
+	}
+}
+
+// fromSlash converts slash-separated test paths to native paths.
+func fromSlash(paths []string) []string {
+	native := make([]string, len(paths))
+	for i, p := range paths {
+		native[i] = filepath.FromSlash(p)
+	}
+	return native
+}
+
+func TestSearchPaths_MatchesSearch(t *testing.T) {
+	root, err := os.MkdirTemp("", "dirsearch-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(root)
+
+	for _, dir := range []string{"a/b/c", "a-b/src", ".hidden/src", "b/node_modules/src", "src/a/src"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", dir, err)
+		}
+	}
+
+	opts := DefaultOptions()
+	opts.StartDir = root
+	opts.MaxDepth = UnlimitedDepth
+	all, _, err := FindDirs(context.Background(), &Options{StartDir: root, Hidden: HiddenShow}, 0, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, pattern := range []string{"", "a", "src", "zzz"} {
+		for _, hidden := range []HiddenMode{HiddenExceptGit, HiddenShow, HiddenHide} {
+			opts.SearchPattern = pattern
+			opts.Hidden = hidden
+			want := Search(opts)
+			got := SearchPaths(context.Background(), opts, all)
+			if !slices.Equal(got.Directories, want.Directories) {
+				t.Errorf("pattern %q, hidden mode %v: expected %v, got %v", pattern, hidden, want.Directories, got.Directories)
+			}
+		}
+	}
+}
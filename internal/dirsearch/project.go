@@ -0,0 +1,133 @@
+package dirsearch
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ProjectType is a set of kinds of projects a directory is the root of,
+// detected from marker entries such as go.mod. A directory can be several
+// at once, e.g. a Go module that is also a git repository.
+type ProjectType uint8
+
+const (
+	// ProjectGit is a git repository, marked by .git
+	ProjectGit ProjectType = 1 << iota
+
+	// ProjectGo is a Go module, marked by go.mod
+	ProjectGo
+
+	// ProjectNode is a Node.js package, marked by package.json
+	ProjectNode
+
+	// ProjectRust is a Rust crate or workspace, marked by Cargo.toml
+	ProjectRust
+
+	// ProjectPython is a Python project, marked by pyproject.toml
+	ProjectPython
+)
+
+// projectMarkers lists the entries marking each project type and the name
+// of the type, in the order names are listed.
+var projectMarkers = []struct {
+	marker string
+	typ    ProjectType
+	name   string
+}{
+	{".git", ProjectGit, "git"},
+	{"go.mod", ProjectGo, "go"},
+	{"package.json", ProjectNode, "node"},
+	{"Cargo.toml", ProjectRust, "rust"},
+	{"pyproject.toml", ProjectPython, "python"},
+}
+
+// Names returns the names of the types in t, e.g. ["git", "go"].
+func (t ProjectType) Names() []string {
+	var names []string
+	for _, p := range projectMarkers {
+		if t&p.typ != 0 {
+			names = append(names, p.name)
+		}
+	}
+	return names
+}
+
+// String returns the names of the types in t separated by commas, e.g.
+// "git,go", or an empty string for no type.
+func (t ProjectType) String() string {
+	return strings.Join(t.Names(), ",")
+}
+
+// ParseProjectTypes converts names such as "git" and "go", separated by
+// commas or spaces, into a ProjectType set. Case is ignored.
+//
+// Returns an error naming the valid types if a name is unknown.
+func ParseProjectTypes(s string) (ProjectType, error) {
+	var t ProjectType
+	for _, name := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' }) {
+		found := false
+		for _, p := range projectMarkers {
+			if strings.EqualFold(name, p.name) {
+				t |= p.typ
+				found = true
+				break
+			}
+		}
+		if !found {
+			valid := make([]string, len(projectMarkers))
+			for i, p := range projectMarkers {
+				valid[i] = p.name
+			}
+			return 0, fmt.Errorf("unknown project type %q (valid types: %s)", name, strings.Join(valid, ", "))
+		}
+	}
+	return t, nil
+}
+
+// DetectProject returns the types of project the directory at dir is the
+// root of, looking for their markers inside fsys, or on disk if fsys is
+// nil. Markers that cannot be inspected count as missing.
+func DetectProject(fsys fs.FS, dir string) ProjectType {
+	var t ProjectType
+	for _, p := range projectMarkers {
+		var err error
+		if fsys != nil {
+			_, err = fs.Stat(fsys, path.Join(dir, p.marker))
+		} else {
+			_, err = os.Lstat(filepath.Join(dir, p.marker))
+		}
+		if err == nil {
+			t |= p.typ
+		}
+	}
+	return t
+}
+
+// matchProject reports whether the entry at path is the root of a project
+// of one of the types the search asks for; always without such types.
+// Files are never project roots.
+func (m *matcher) matchProject(path string, isDir bool) bool {
+	if m.projects == 0 {
+		return true
+	}
+	return isDir && DetectProject(m.fsys, path)&m.projects != 0
+}
+
+// setProjects fills r.Projects with the project types of each entry if
+// opts.DetectProjects is set.
+func (r *Result) setProjects(opts *Options) {
+	if !opts.DetectProjects {
+		return
+	}
+
+	r.Projects = make([]ProjectType, len(r.Directories))
+	for i := range r.Directories {
+		if r.Types == nil || r.Types[i] == Dir {
+			r.Projects[i] = DetectProject(opts.FS, r.entryPath(opts, i))
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package dirsearch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestParseProjectTypes(t *testing.T) {
+	got, err := ParseProjectTypes("git, Go rust")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := ProjectGit | ProjectGo | ProjectRust; got != expected {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+	if got.String() != "git,go,rust" {
+		t.Errorf("expected git,go,rust, got %q", got.String())
+	}
+
+	if got, err := ParseProjectTypes(""); err != nil || got != 0 {
+		t.Errorf("expected no types, got %v and %v", got, err)
+	}
+	if _, err := ParseProjectTypes("java"); err == nil {
+		t.Error("expected error for unknown type, got nil")
+	}
+}
+
+func TestSearch_Projects(t *testing.T) {
+	fsys := fstest.MapFS{
+		"tool/.git/HEAD":             {},
+		"tool/go.mod":                {},
+		"tool/cmd/main.go":           {},
+		"web/package.json":           {},
+		"web/node_modules/x/go.mod":  {},
+		"crates/parser/Cargo.toml":   {},
+		"scripts/pyproject.toml":     {},
+		"notes/go.mod.txt":           {},
+		"notes/draft/.git/something": {},
+	}
+	opts := &Options{
+		StartDir:       ".",
+		MaxDepth:       UnlimitedDepth,
+		IgnorePatterns: []string{".git", "node_modules"},
+		Hidden:         HiddenShow,
+		DetectProjects: true,
+		FS:             fsys,
+	}
+
+	result := Search(opts)
+	if result.Error != nil {
+		t.Fatalf("unexpected error: %v", result.Error)
+	}
+	projects := make(map[string]ProjectType)
+	for i, dir := range result.Directories {
+		projects[dir] = result.Projects[i]
+	}
+	expected := map[string]ProjectType{
+		"tool":          ProjectGit | ProjectGo,
+		"web":           ProjectNode,
+		"crates/parser": ProjectRust,
+		"scripts":       ProjectPython,
+		"notes/draft":   ProjectGit,
+		"notes":         0,
+		"tool/cmd":      0,
+		"crates":        0,
+	}
+	for dir, typ := range expected {
+		if projects[dir] != typ {
+			t.Errorf("%s: expected %q, got %q", dir, typ, projects[dir])
+		}
+	}
+
+	opts.ProjectTypes = ProjectGit | ProjectRust
+	result = Search(opts)
+	if expected := []string{"crates/parser", "notes/draft", "tool"}; !slices.Equal(result.Directories, expected) {
+		t.Errorf("expected %v, got %v", expected, result.Directories)
+	}
+}
+
+func TestSearchPaths_Projects(t *testing.T) {
+	root, err := os.MkdirTemp("", "dirsearch-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(root)
+
+	for _, dir := range []string{"api", "docs"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, "api", "go.mod"), nil, 0644); err != nil {
+		t.Fatalf("failed to write go.mod: %v", err)
+	}
+
+	opts := &Options{StartDir: root, MaxDepth: UnlimitedDepth, ProjectTypes: ProjectGo, DetectProjects: true}
+	result := SearchPaths(context.Background(), opts, []string{"api", "docs"})
+	if !slices.Equal(result.Directories, []string{"api"}) || !slices.Equal(result.Projects, []ProjectType{ProjectGo}) {
+		t.Errorf("expected api as a Go module, got %v and %v", result.Directories, result.Projects)
+	}
+}
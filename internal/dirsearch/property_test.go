@@ -0,0 +1,225 @@
+package dirsearch
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// propertyNames are the names of generated entries. They include hidden
+// and ignored names, and none differ only by case, so trees look the same
+// on case-insensitive filesystems.
+var propertyNames = []string{
+	"a", "b", "src", "Docs", "lib", "build-1", "build-keep", "node_modules", ".cache", ".git",
+}
+
+// randomTree is a generated tree: the relative paths of its directories
+// and of its files, parents before children.
+type randomTree struct {
+	dirs, files []string
+}
+
+// genTree generates a random tree below dir, at most maxLevels deep.
+func genTree(rng *rand.Rand, tree *randomTree, dir string, maxLevels int) {
+	if maxLevels == 0 {
+		return
+	}
+	used := make(map[string]bool)
+	for range rng.IntN(5) {
+		name := propertyNames[rng.IntN(len(propertyNames))]
+		if used[name] {
+			continue
+		}
+		used[name] = true
+
+		rel := filepath.Join(dir, name)
+		if rng.IntN(4) == 0 {
+			tree.files = append(tree.files, rel)
+			continue
+		}
+		tree.dirs = append(tree.dirs, rel)
+		genTree(rng, tree, rel, maxLevels-1)
+	}
+}
+
+// create makes the tree on disk below root.
+func (tree randomTree) create(t *testing.T, root string) {
+	t.Helper()
+	for _, dir := range tree.dirs {
+		if err := os.Mkdir(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", dir, err)
+		}
+	}
+	for _, file := range tree.files {
+		if err := os.WriteFile(filepath.Join(root, file), []byte("x"), 0644); err != nil {
+			t.Fatalf("failed to create %s: %v", file, err)
+		}
+	}
+}
+
+// expected returns the paths a search of the tree with opts should find,
+// worked out from the documented rules rather than by walking the tree.
+// It supports plain case-insensitive patterns of ASCII text only.
+func (tree randomTree) expected(opts *Options) []string {
+	ignored := func(name string) bool {
+		matched, kept := false, false
+		for _, p := range opts.IgnorePatterns {
+			neg := strings.HasPrefix(p, "!")
+			if ok, _ := filepath.Match(strings.TrimPrefix(p, "!"), name); ok {
+				if neg {
+					kept = true
+				} else {
+					matched = true
+				}
+			}
+		}
+		return matched && !kept
+	}
+	hidden := func(name string) bool {
+		return opts.Hidden == HiddenHide && strings.HasPrefix(name, ".")
+	}
+	// reachable reports whether the search lists entries at rel: no
+	// directory on the way is skipped and rel is not too deep
+	reachable := func(rel string) bool {
+		parts := strings.Split(rel, string(filepath.Separator))
+		if opts.MaxDepth > 0 && len(parts) > opts.MaxDepth {
+			return false
+		}
+		for _, dir := range parts[:len(parts)-1] {
+			if hidden(dir) || ignored(dir) {
+				return false
+			}
+		}
+		return true
+	}
+	matches := func(rel string) bool {
+		return strings.Contains(strings.ToLower(filepath.Base(rel)), strings.ToLower(opts.SearchPattern))
+	}
+
+	var found []string
+	for _, dir := range tree.dirs {
+		name := filepath.Base(dir)
+		if reachable(dir) && !hidden(name) && !ignored(name) && matches(dir) {
+			found = append(found, dir)
+		}
+	}
+	if opts.IncludeFiles {
+		for _, file := range tree.files {
+			if reachable(file) && !hidden(filepath.Base(file)) && matches(file) {
+				found = append(found, file)
+			}
+		}
+	}
+	slices.Sort(found)
+	return found
+}
+
+// TestSearch_Properties checks searches of random trees against invariants
+// that must hold whatever the tree: every result exists and has the
+// reported type, no result is or is inside an ignored or hidden directory,
+// the results are exactly those the documented rules select, and they do
+// not change between runs, with parallel reads, or through Options.FS.
+func TestSearch_Properties(t *testing.T) {
+	trials := 100
+	if testing.Short() {
+		trials = 10
+	}
+
+	for seed := range uint64(trials) {
+		t.Run(fmt.Sprintf("seed-%d", seed), func(t *testing.T) {
+			rng := rand.New(rand.NewPCG(seed, seed))
+			var tree randomTree
+			genTree(rng, &tree, "", 4)
+
+			tempDir, err := os.MkdirTemp("", "dirsearch-test-*")
+			if err != nil {
+				t.Fatalf("failed to create temp dir: %v", err)
+			}
+			defer os.RemoveAll(tempDir)
+			tree.create(t, tempDir)
+
+			ignores := [][]string{nil, {"node_modules"}, {"build-*", ".git"}, {"build-*", "!build-keep", "src"}}
+			opts := &Options{
+				SearchPattern:  []string{"", "b", "S", "uil", "zzz"}[rng.IntN(5)],
+				StartDir:       tempDir,
+				IgnorePatterns: ignores[rng.IntN(len(ignores))],
+				Hidden:         []HiddenMode{HiddenShow, HiddenHide}[rng.IntN(2)],
+				MaxDepth:       []int{1, 2, 3, UnlimitedDepth}[rng.IntN(4)],
+				IncludeFiles:   rng.IntN(2) == 0,
+			}
+			checkProperties(t, tree, opts)
+		})
+	}
+}
+
+// checkProperties searches the tree created at opts.StartDir and reports
+// the invariants it breaks.
+func checkProperties(t *testing.T, tree randomTree, opts *Options) {
+	t.Helper()
+	result := Search(opts)
+	if result.Error != nil {
+		t.Fatalf("unexpected error: %v", result.Error)
+	}
+
+	m, err := newMatcher(opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, rel := range result.Directories {
+		info, err := os.Stat(filepath.Join(opts.StartDir, rel))
+		if err != nil {
+			t.Errorf("result %s does not exist: %v", rel, err)
+			continue
+		}
+		typ := Dir
+		if result.Types != nil {
+			typ = result.Types[i]
+		}
+		if info.IsDir() != (typ == Dir) {
+			t.Errorf("expected %s to be a %s, got mode %v", rel, typ, info.Mode())
+		}
+
+		// The ignore list applies to directories only, files keep any name
+		dirs := strings.Split(rel, string(filepath.Separator))
+		if typ == File {
+			dirs = dirs[:len(dirs)-1]
+		}
+		for _, part := range dirs {
+			if m.ignore.contains(part) {
+				t.Errorf("result %s is inside ignored directory %s", rel, part)
+			}
+			if opts.Hidden == HiddenHide && strings.HasPrefix(part, ".") {
+				t.Errorf("result %s is hidden", rel)
+			}
+		}
+	}
+
+	found := slices.Sorted(slices.Values(result.Directories))
+	if expected := tree.expected(opts); !slices.Equal(found, expected) {
+		t.Errorf("options %+v: expected %v, got %v", *opts, expected, found)
+	}
+
+	again := Search(opts)
+	if !slices.Equal(again.Directories, result.Directories) || !slices.Equal(again.Types, result.Types) {
+		t.Errorf("expected the same results on a second run %v, got %v", result.Directories, again.Directories)
+	}
+
+	parallel := *opts
+	parallel.Concurrency = 4
+	if got := Search(&parallel); !slices.Equal(got.Directories, result.Directories) || !slices.Equal(got.Types, result.Types) {
+		t.Errorf("expected parallel search to find %v, got %v", result.Directories, got.Directories)
+	}
+
+	inFS := *opts
+	inFS.StartDir = "."
+	inFS.FS = os.DirFS(opts.StartDir)
+	got := SearchContext(context.Background(), &inFS)
+	if !slices.Equal(fromSlash(got.Directories), result.Directories) || !slices.Equal(got.Types, result.Types) {
+		t.Errorf("expected search through FS to find %v, got %v", result.Directories, got.Directories)
+	}
+}
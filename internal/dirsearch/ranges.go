@@ -0,0 +1,156 @@
+package dirsearch
+
+import (
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// setMatchedRanges fills r.MatchedRanges with the parts of each entry
+// matched by opts.SearchPattern. Searches without a pattern leave it nil.
+func (r *Result) setMatchedRanges(opts *Options) {
+	if opts.SearchPattern == "" || len(r.Directories) == 0 {
+		return
+	}
+	m, err := newMatcher(opts)
+	if err != nil {
+		return
+	}
+
+	r.MatchedRanges = make([][][2]int, len(r.Directories))
+	for i, path := range r.Directories {
+		name := filepath.Base(path)
+		offset := len(path) - len(name)
+		ranges := m.matchRanges(name)
+		for j := range ranges {
+			ranges[j][0] += offset
+			ranges[j][1] += offset
+		}
+		r.MatchedRanges[i] = ranges
+	}
+}
+
+// matchRanges returns the byte ranges of name matched by the search
+// pattern, in increasing order: every occurrence of a plain pattern or
+// regular expression, or the characters picked by a fuzzy match. Names are
+// matched normalized and folded like matchName does, and a match covering
+// part of a character that folding expanded, such as one "s" of "ß", covers
+// the whole character.
+func (m *matcher) matchRanges(name string) [][2]int {
+	fold := func(s string) string { return s }
+	switch {
+	case m.re != nil:
+		// The expression ignores case itself
+	case m.fuzzy:
+		fold = func(s string) string { return foldRunes(s, m.caseSensitive) }
+	case !m.caseSensitive:
+		fold = FoldCase
+	}
+	folded := newFoldedName(name, fold)
+
+	var ranges [][2]int
+	switch {
+	case m.re != nil:
+		for _, loc := range m.re.FindAllStringIndex(folded.text, -1) {
+			if loc[0] < loc[1] {
+				ranges = append(ranges, [2]int{loc[0], loc[1]})
+			}
+		}
+	case m.pattern == "":
+	case m.fuzzy:
+		ranges = fuzzyRanges(folded.text, m.pattern)
+	default:
+		for i := 0; ; {
+			j := strings.Index(folded.text[i:], m.pattern)
+			if j < 0 {
+				break
+			}
+			ranges = append(ranges, [2]int{i + j, i + j + len(m.pattern)})
+			i += j + len(m.pattern)
+		}
+	}
+	return folded.original(ranges)
+}
+
+// fuzzyRanges returns the byte ranges of the characters of name picked by
+// the fuzzy match of pattern, both folded already, choosing the same
+// characters as scoreWindow. Consecutive characters form a single range.
+func fuzzyRanges(name, pattern string) [][2]int {
+	start, end, ok := fuzzyWindow(name, pattern, true)
+	if !ok {
+		return nil
+	}
+
+	var ranges [][2]int
+	for i, pi := start, 0; i < end && pi < len(pattern); {
+		r, n := utf8.DecodeRuneInString(name[i:])
+		p, pn := utf8.DecodeRuneInString(pattern[pi:])
+		if r == p {
+			ranges = appendRange(ranges, [2]int{i, i + n})
+			pi += pn
+		}
+		i += n
+	}
+	return ranges
+}
+
+// foldedName is a name normalized to NFC and folded, keeping track of the
+// bytes of the original name each byte of the result comes from.
+type foldedName struct {
+	text string
+
+	// starts and ends hold, for each byte of text, the bounds of the
+	// characters of the original name it was folded from. They are nil
+	// when text is as long as the original name, byte for byte.
+	starts, ends []int
+}
+
+// newFoldedName normalizes name to NFC and folds it with fold, which must
+// map every NFC segment on its own, as FoldCase and foldRunes do.
+func newFoldedName(name string, fold func(string) string) foldedName {
+	if isASCII(name) {
+		// Folding ASCII keeps every byte where it was
+		return foldedName{text: fold(name)}
+	}
+
+	var b strings.Builder
+	var starts, ends []int
+	var it norm.Iter
+	it.InitString(norm.NFC, name)
+	for !it.Done() {
+		start := it.Pos()
+		segment := fold(string(it.Next()))
+		end := it.Pos()
+		b.WriteString(segment)
+		for range len(segment) {
+			starts = append(starts, start)
+			ends = append(ends, end)
+		}
+	}
+	return foldedName{text: b.String(), starts: starts, ends: ends}
+}
+
+// original converts ranges of the folded text to ranges of the original
+// name, merging those that touch once widened to whole characters.
+func (f foldedName) original(ranges [][2]int) [][2]int {
+	var converted [][2]int
+	for _, r := range ranges {
+		if f.starts != nil {
+			r = [2]int{f.starts[r[0]], f.ends[r[1]-1]}
+		}
+		converted = appendRange(converted, r)
+	}
+	return converted
+}
+
+// appendRange appends r to ranges, which are in increasing order, merging
+// it into the last range if they overlap or touch.
+func appendRange(ranges [][2]int, r [2]int) [][2]int {
+	if n := len(ranges); n > 0 && r[0] <= ranges[n-1][1] {
+		ranges[n-1][1] = max(ranges[n-1][1], r[1])
+		return ranges
+	}
+	return append(ranges, r)
+}
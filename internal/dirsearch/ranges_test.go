@@ -0,0 +1,108 @@
+package dirsearch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestMatchRanges(t *testing.T) {
+	tests := []struct {
+		name, pattern string
+		caseSensitive bool
+		regex         bool
+		fuzzy         bool
+		expected      [][2]int
+	}{
+		{name: "my-docs", pattern: "DOC", expected: [][2]int{{3, 6}}},
+		{name: "docs-and-docs", pattern: "docs", expected: [][2]int{{0, 4}, {9, 13}}},
+		{name: "aaaa", pattern: "aa", expected: [][2]int{{0, 4}}},
+		{name: "my-docs", pattern: "DOC", caseSensitive: true, expected: nil},
+		{name: "Docs-docs", pattern: "docs", caseSensitive: true, expected: [][2]int{{5, 9}}},
+		{name: "Ärger-docs", pattern: "doc", expected: [][2]int{{7, 10}}},
+		{name: "Straße", pattern: "SS", expected: [][2]int{{4, 6}}},
+		{name: "Straße", pattern: "as", expected: [][2]int{{3, 6}}},
+		{name: "U\u0308bersicht", pattern: "über", expected: [][2]int{{0, 6}}}, // decomposed
+		{name: "\u00dcbersicht-uber", pattern: "U\u0308ber", expected: [][2]int{{0, 5}}},
+		{name: "İstanbul", pattern: "ist", expected: [][2]int{{0, 4}}},
+		{name: "v1.2-v10", pattern: `v\d+`, regex: true, expected: [][2]int{{0, 2}, {5, 8}}},
+		{name: "Build", pattern: "^b", regex: true, expected: [][2]int{{0, 1}}},
+		{name: "folder-search-results", pattern: "fsr", fuzzy: true, expected: [][2]int{{0, 1}, {7, 8}, {10, 11}}},
+		{name: "my-config", pattern: "conf", fuzzy: true, expected: [][2]int{{3, 7}}},
+		{name: "Überblick", pattern: "üb", fuzzy: true, expected: [][2]int{{0, 4}}},
+	}
+
+	for _, tt := range tests {
+		m, err := newMatcher(&Options{
+			SearchPattern: tt.pattern,
+			CaseSensitive: tt.caseSensitive,
+			Regex:         tt.regex,
+			Fuzzy:         tt.fuzzy,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := m.matchRanges(tt.name); !slices.Equal(got, tt.expected) {
+			t.Errorf("%q in %q: expected %v, got %v", tt.pattern, tt.name, tt.expected, got)
+		}
+	}
+}
+
+func TestSearch_MatchedRanges(t *testing.T) {
+	fsys := fstest.MapFS{
+		"src/app/x":      {},
+		"apps/x":         {},
+		"docs/x":         {},
+		"vendor/app-x/x": {},
+	}
+
+	result := Search(&Options{SearchPattern: "app", StartDir: ".", MaxDepth: UnlimitedDepth, FS: fsys})
+	if result.Error != nil {
+		t.Fatalf("unexpected error: %v", result.Error)
+	}
+	expected := []string{"apps", "src/app", "vendor/app-x"}
+	if !slices.Equal(result.Directories, expected) {
+		t.Fatalf("expected %v, got %v", expected, result.Directories)
+	}
+	// The ranges index into the paths, within their last element
+	expectedRanges := [][][2]int{{{0, 3}}, {{4, 7}}, {{7, 10}}}
+	for i, ranges := range result.MatchedRanges {
+		if !slices.Equal(ranges, expectedRanges[i]) {
+			t.Errorf("%s: expected %v, got %v", result.Directories[i], expectedRanges[i], ranges)
+		}
+	}
+	if len(result.MatchedRanges) != len(expectedRanges) {
+		t.Errorf("expected %d ranges, got %v", len(expectedRanges), result.MatchedRanges)
+	}
+
+	// Fuzzy results are reordered by score along with their ranges
+	result = Search(&Options{SearchPattern: "ap", StartDir: ".", Fuzzy: true, MaxDepth: UnlimitedDepth, FS: fsys})
+	for i, dir := range result.Directories {
+		r := result.MatchedRanges[i]
+		if len(r) != 1 || dir[r[0][0]:r[0][1]] != "ap" {
+			t.Errorf("%s: expected a range covering \"ap\", got %v", dir, r)
+		}
+	}
+
+	if result := Search(&Options{StartDir: ".", FS: fsys}); result.MatchedRanges != nil {
+		t.Errorf("expected no ranges without a pattern, got %v", result.MatchedRanges)
+	}
+}
+
+func TestSearchPaths_MatchedRanges(t *testing.T) {
+	root, err := os.MkdirTemp("", "dirsearch-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(root)
+
+	opts := &Options{SearchPattern: "lib", StartDir: root, MaxDepth: UnlimitedDepth}
+	result := SearchPaths(context.Background(), opts, []string{filepath.Join("src", "libs"), "docs"})
+	expected := [][][2]int{{{4, 7}}}
+	if len(result.MatchedRanges) != 1 || !slices.Equal(result.MatchedRanges[0], expected[0]) {
+		t.Errorf("expected %v, got %v", expected, result.MatchedRanges)
+	}
+}
@@ -0,0 +1,78 @@
+package dirsearch
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// BoostRule raises or lowers the position of matching directories in
+// results. A rule with both Under and Contains set only applies to paths
+// matching both.
+type BoostRule struct {
+	// Under matches directories inside this absolute path (not the path itself)
+	Under string `json:"under,omitempty"`
+
+	// Contains matches paths containing this text, ignoring case
+	Contains string `json:"contains,omitempty"`
+
+	// Boost is added to the score of matching directories; negative
+	// values push them down
+	Boost int `json:"boost"`
+}
+
+// matches reports whether the rule applies to the absolute path.
+func (r BoostRule) matches(path string) bool {
+	if r.Under == "" && r.Contains == "" {
+		return false
+	}
+	if r.Under != "" && !IsUnder(r.Under, path) {
+		return false
+	}
+	if r.Contains != "" && !containsFold(path, FoldCase(r.Contains)) {
+		return false
+	}
+	return true
+}
+
+// IsUnder reports whether path lies inside dir. A directory is not inside
+// itself.
+func IsUnder(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// Rank orders directories by the sum of the boosts of all matching rules,
+// highest first. Directories with equal scores keep their original order, so
+// without rules the input order is preserved.
+//
+// Parameters:
+//   - base: the directory the names are relative to; empty if they are absolute
+//   - dirs: directory paths as returned by Search or FindDirs
+//   - rules: the boost rules to apply
+//
+// Returns a new slice; dirs is not modified.
+func Rank(base string, dirs []string, rules []BoostRule) []string {
+	ranked := slices.Clone(dirs)
+	if len(rules) == 0 {
+		return ranked
+	}
+
+	scores := make(map[string]int, len(dirs))
+	for _, d := range dirs {
+		path := d
+		if base != "" {
+			path = filepath.Join(base, d)
+		}
+		for _, r := range rules {
+			if r.matches(path) {
+				scores[d] += r.Boost
+			}
+		}
+	}
+
+	slices.SortStableFunc(ranked, func(a, b string) int {
+		return scores[b] - scores[a]
+	})
+	return ranked
+}
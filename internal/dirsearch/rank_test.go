@@ -0,0 +1,51 @@
+package dirsearch
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRank(t *testing.T) {
+	dirs := []string{"archive", "backup-2023", "src", "work"}
+	rules := []BoostRule{
+		{Contains: "BACKUP", Boost: -5},
+		{Under: "/home/me/work", Boost: 10},
+		{Contains: "src", Boost: 1},
+	}
+
+	got := Rank("/home/me", dirs, rules)
+	// work itself is not under /home/me/work, so only src is boosted
+	want := []string{"src", "archive", "work", "backup-2023"}
+	if !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if dirs[0] != "archive" {
+		t.Error("expected input slice to be left unchanged")
+	}
+
+	got = Rank("/home/me/work", []string{"a", "b"}, rules)
+	if !slices.Equal(got, []string{"a", "b"}) {
+		t.Errorf("expected equal boosts to keep order, got %v", got)
+	}
+}
+
+func TestRank_Absolute(t *testing.T) {
+	dirs := []string{"/srv/old/api", "/home/me/work/api"}
+	got := Rank("", dirs, []BoostRule{{Under: "/home/me/work", Boost: 3}})
+	if got[0] != "/home/me/work/api" {
+		t.Errorf("expected boosted path first, got %v", got)
+	}
+}
+
+func TestBoostRule_CombinedConditions(t *testing.T) {
+	r := BoostRule{Under: "/home/me", Contains: "tmp", Boost: 1}
+	if !r.matches("/home/me/tmp") {
+		t.Error("expected rule to match path satisfying both conditions")
+	}
+	if r.matches("/var/tmp") {
+		t.Error("expected rule not to match outside Under")
+	}
+	if (BoostRule{Boost: 1}).matches("/anything") {
+		t.Error("expected rule without conditions to match nothing")
+	}
+}
@@ -0,0 +1,156 @@
+package dirsearch
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// searchRoots performs SearchContext for each of opts.StartDirs in turn and
+// merges the results, recording the root of each entry in Result.Roots and
+// joining the paths of Result.Warnings to their root.
+// Entries already found below an earlier root, because roots overlap or one
+// is a symlink to another, are left out; see entryKey.
+// opts.MaxResults applies to the merged result. A root that cannot be
+// searched stops the search with an error naming it, returned along with
+// the entries found so far.
+func searchRoots(ctx context.Context, opts *Options) Result {
+	merged := Result{Directories: []string{}, Roots: []string{}}
+	if opts.IncludeFiles {
+		merged.Types = []EntryType{}
+	}
+	seen := make(map[string]bool)
+
+	for _, root := range opts.StartDirs {
+		local := *opts
+		local.StartDir = root
+		local.StartDirs = nil
+		// The merged result is sorted, and its projects detected, at once
+		// by the caller
+		local.SortBy = SortNone
+		local.DetectProjects = false
+		remaining := opts.MaxResults - len(merged.Directories)
+		if opts.MaxResults > 0 {
+			// One extra match tells whether the merged result is truncated,
+			// even if it is already full
+			local.MaxResults = remaining + 1
+		}
+
+		r := SearchContext(ctx, &local)
+		found, types, keys := unseen(opts.FS, root, r, seen)
+		// Duplicates took the place of matches that were not read, so read
+		// the root again with room for as many more
+		for r.Truncated && r.Error == nil && len(found) <= remaining {
+			local.MaxResults = len(r.Directories) + remaining + 1 - len(found)
+			r = SearchContext(ctx, &local)
+			found, types, keys = unseen(opts.FS, root, r, seen)
+		}
+		for _, key := range keys {
+			seen[key] = true
+		}
+		if opts.MaxResults > 0 && len(found) > remaining {
+			found = found[:remaining]
+			if types != nil {
+				types = types[:remaining]
+			}
+			merged.Truncated = true
+		}
+		merged.Directories = append(merged.Directories, found...)
+		merged.Types = append(merged.Types, types...)
+		for range found {
+			merged.Roots = append(merged.Roots, root)
+		}
+		for _, w := range r.Warnings {
+			merged.Warnings = append(merged.Warnings, PathError{Path: filepath.Join(root, w.Path), Err: w.Err})
+		}
+
+		if r.Error != nil {
+			merged.Error = fmt.Errorf("%s: %w", root, r.Error)
+			return merged
+		}
+		if merged.Truncated {
+			break
+		}
+	}
+	return merged
+}
+
+// unseen returns the entries of r, found below root, that are not in seen,
+// with their types and keys; see entryKey.
+func unseen(fsys fs.FS, root string, r Result, seen map[string]bool) ([]string, []EntryType, []string) {
+	keys := make([]string, 0, len(r.Directories))
+	found := make([]string, 0, len(r.Directories))
+	var types []EntryType
+	if r.Types != nil {
+		types = make([]EntryType, 0, len(r.Types))
+	}
+	for i, dir := range r.Directories {
+		key := entryKey(fsys, joinRoot(fsys, root, dir))
+		if seen[key] {
+			continue
+		}
+		keys = append(keys, key)
+		found = append(found, dir)
+		if types != nil {
+			types = append(types, r.Types[i])
+		}
+	}
+	return found, types, keys
+}
+
+// joinRoot returns the path of entry dir below root, slash-separated inside
+// fsys.
+func joinRoot(fsys fs.FS, root, dir string) string {
+	if fsys != nil {
+		return path.Join(root, dir)
+	}
+	return filepath.Join(root, dir)
+}
+
+// RootLabels returns a short label for each of roots, to tell apart entries
+// found below different roots: the last element of the root, extended by
+// as many parent elements as it takes to differ from the labels of the
+// other roots, e.g. "work/app" and "home/app". Equal roots get equal labels.
+func RootLabels(roots []string) []string {
+	elems := make([][]string, len(roots))
+	for i, root := range roots {
+		for _, e := range strings.Split(filepath.ToSlash(filepath.Clean(root)), "/") {
+			if e != "" {
+				elems[i] = append(elems[i], e)
+			}
+		}
+	}
+
+	// Every label starts as the last element and grows while it collides
+	// with the label of a different root
+	lengths := make([]int, len(roots))
+	for i := range lengths {
+		lengths[i] = min(1, len(elems[i]))
+	}
+	labels := make([]string, len(roots))
+	for {
+		for i, e := range elems {
+			labels[i] = path.Join(e[len(e)-lengths[i]:]...)
+			if labels[i] == "" {
+				labels[i] = filepath.Clean(roots[i])
+			}
+		}
+		grown := false
+		for i := range roots {
+			for j := range roots {
+				if labels[i] == labels[j] && !slices.Equal(elems[i], elems[j]) && lengths[i] < len(elems[i]) {
+					lengths[i]++
+					grown = true
+					break
+				}
+			}
+		}
+		if !grown {
+			return labels
+		}
+	}
+}
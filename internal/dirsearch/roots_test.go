@@ -0,0 +1,156 @@
+package dirsearch
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// makeRoots creates two search roots holding the given directories.
+func makeRoots(t *testing.T, first, second []string) (string, string) {
+	t.Helper()
+	base, err := os.MkdirTemp("", "dirsearch-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(base) })
+
+	roots := []string{filepath.Join(base, "code"), filepath.Join(base, "work")}
+	for i, dirs := range [][]string{first, second} {
+		for _, dir := range dirs {
+			if err := os.MkdirAll(filepath.Join(roots[i], dir), 0755); err != nil {
+				t.Fatalf("failed to create %s: %v", dir, err)
+			}
+		}
+	}
+	return roots[0], roots[1]
+}
+
+func TestSearch_StartDirs(t *testing.T) {
+	code, work := makeRoots(t, []string{"api", "web"}, []string{"api-docs", "billing"})
+
+	opts := DefaultOptions()
+	opts.SearchPattern = "api"
+	opts.StartDirs = []string{code, work}
+
+	result := Search(opts)
+	if result.Error != nil {
+		t.Fatalf("unexpected error: %v", result.Error)
+	}
+	expected := []string{"api", "api-docs"}
+	if !slices.Equal(result.Directories, expected) {
+		t.Errorf("expected %v, got %v", expected, result.Directories)
+	}
+	if !slices.Equal(result.Roots, []string{code, work}) {
+		t.Errorf("expected roots %v, got %v", []string{code, work}, result.Roots)
+	}
+
+	// Results are sorted together, keeping their roots
+	opts.SearchPattern = ""
+	opts.SortOrder = Descending
+	result = Search(opts)
+	expected = []string{"web", "billing", "api-docs", "api"}
+	if !slices.Equal(result.Directories, expected) {
+		t.Errorf("expected %v, got %v", expected, result.Directories)
+	}
+	expectedRoots := []string{code, work, work, code}
+	if !slices.Equal(result.Roots, expectedRoots) {
+		t.Errorf("expected roots %v, got %v", expectedRoots, result.Roots)
+	}
+}
+
+func TestSearch_StartDirsMaxResults(t *testing.T) {
+	code, work := makeRoots(t, []string{"a", "b"}, []string{"c"})
+
+	tests := []struct {
+		maxResults int
+		count      int
+		truncated  bool
+	}{
+		{1, 1, true},
+		{2, 2, true},
+		{3, 3, false},
+		{0, 3, false},
+	}
+	for _, tt := range tests {
+		opts := DefaultOptions()
+		opts.StartDirs = []string{code, work}
+		opts.MaxResults = tt.maxResults
+
+		result := Search(opts)
+		if result.Error != nil {
+			t.Fatalf("max %d: unexpected error: %v", tt.maxResults, result.Error)
+		}
+		if len(result.Directories) != tt.count || len(result.Roots) != tt.count {
+			t.Errorf("max %d: expected %d results, got %v in %v", tt.maxResults, tt.count, result.Directories, result.Roots)
+		}
+		if result.Truncated != tt.truncated {
+			t.Errorf("max %d: expected truncated %v, got %v", tt.maxResults, tt.truncated, result.Truncated)
+		}
+	}
+}
+
+func TestSearch_StartDirsMissing(t *testing.T) {
+	code, work := makeRoots(t, []string{"a"}, nil)
+	missing := filepath.Join(work, "missing")
+
+	opts := DefaultOptions()
+	opts.StartDirs = []string{code, missing}
+
+	result := Search(opts)
+	if !errors.Is(result.Error, fs.ErrNotExist) {
+		t.Errorf("expected not exist error, got %v", result.Error)
+	}
+	if !slices.Equal(result.Directories, []string{"a"}) {
+		t.Errorf("expected the results of the first root, got %v", result.Directories)
+	}
+}
+
+func TestSearch_StartDirsOverlap(t *testing.T) {
+	code, work := makeRoots(t, []string{"api/v1", "web"}, []string{"billing"})
+	alias := filepath.Join(filepath.Dir(work), "code-link")
+	if err := os.Symlink(code, alias); err != nil {
+		t.Skipf("cannot create symlinks: %v", err)
+	}
+
+	opts := DefaultOptions()
+	opts.MaxDepth = UnlimitedDepth
+	opts.StartDirs = []string{code, filepath.Join(code, "api"), alias, work}
+
+	result := Search(opts)
+	if result.Error != nil {
+		t.Fatalf("unexpected error: %v", result.Error)
+	}
+	expected := []string{"api", filepath.Join("api", "v1"), "billing", "web"}
+	if !slices.Equal(result.Directories, expected) {
+		t.Errorf("expected %v, got %v", expected, result.Directories)
+	}
+	expectedRoots := []string{code, code, work, code}
+	if !slices.Equal(result.Roots, expectedRoots) {
+		t.Errorf("expected roots %v, got %v", expectedRoots, result.Roots)
+	}
+
+	// Duplicates do not count towards MaxResults
+	opts.MaxResults = 4
+	if result = Search(opts); len(result.Directories) != 4 || result.Truncated {
+		t.Errorf("expected all 4 distinct results, got %v (truncated %v)", result.Directories, result.Truncated)
+	}
+}
+
+func TestRootLabels(t *testing.T) {
+	roots := []string{
+		filepath.Join("/", "home", "ann", "app"),
+		filepath.Join("/", "work", "app"),
+		filepath.Join("/", "home", "ann", "docs"),
+		filepath.Join("/", "work", "app"),
+	}
+
+	got := RootLabels(roots)
+	want := []string{"ann/app", "work/app", "docs", "work/app"}
+	if !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
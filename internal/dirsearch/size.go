@@ -0,0 +1,211 @@
+package dirsearch
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// sizeProgressInterval is the number of visited entries between two progress
+// reports of DirSize.
+const sizeProgressInterval = 256
+
+// DirSize computes the total size in bytes of all regular files under root.
+//
+// Symlinks are not followed and unreadable subdirectories are skipped. The
+// walk stops as soon as ctx is canceled.
+//
+// Parameters:
+//   - ctx: controls cancellation of the walk
+//   - root: the directory to measure
+//   - progress: optional callback receiving the running total
+//
+// Returns the total size, or an error if root cannot be read or ctx is canceled.
+func DirSize(ctx context.Context, root string, progress func(total int64)) (int64, error) {
+	return dirSize(ctx, nil, root, progress)
+}
+
+// dirSize implements DirSize, walking root inside fsys if it is not nil.
+func dirSize(ctx context.Context, fsys fs.FS, root string, progress func(total int64)) (int64, error) {
+	var total int64
+	visited := 0
+
+	walkDir := filepath.WalkDir
+	if fsys != nil {
+		walkDir = func(root string, fn fs.WalkDirFunc) error {
+			return fs.WalkDir(fsys, root, fn)
+		}
+	}
+
+	err := walkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err != nil {
+			// Fail on the root itself, skip unreadable entries below it
+			if path == root {
+				return err
+			}
+			if d != nil && d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err == nil {
+				total += info.Size()
+			}
+		}
+
+		visited++
+		if progress != nil && visited%sizeProgressInterval == 0 {
+			progress(total)
+		}
+		return nil
+	})
+	if err != nil {
+		return total, err
+	}
+
+	if progress != nil {
+		progress(total)
+	}
+	return total, nil
+}
+
+// matchSize reports whether the entry at path, a directory if isDir is set,
+// is within the size bounds of the search; always without bounds. Entries
+// that cannot be measured count as empty.
+func (m *matcher) matchSize(ctx context.Context, path string, isDir bool) bool {
+	if m.minSize <= 0 && m.maxSize <= 0 {
+		return true
+	}
+	size := entrySize(ctx, m.fsys, m.sizes, path, isDir)
+	return (m.minSize <= 0 || size >= m.minSize) && (m.maxSize <= 0 || size <= m.maxSize)
+}
+
+// join returns the path of name in dir, inside the searched filesystem.
+func (m *matcher) join(dir, name string) string {
+	return joinPath(m.fsys, dir, name)
+}
+
+// joinPath returns the path of name in dir, inside fsys if it is not nil.
+func joinPath(fsys fs.FS, dir, name string) string {
+	if fsys != nil {
+		return path.Join(dir, name)
+	}
+	return filepath.Join(dir, name)
+}
+
+// entrySize returns the size of the file at path, or the total size of the
+// files in the directory at path measured through sizes, inside fsys if it
+// is not nil. It returns zero if the entry cannot be inspected.
+func entrySize(ctx context.Context, fsys fs.FS, sizes *sizeCache, path string, isDir bool) int64 {
+	if isDir {
+		return sizes.dirSize(ctx, fsys, path)
+	}
+
+	var info fs.FileInfo
+	var err error
+	if fsys != nil {
+		info, err = fs.Stat(fsys, path)
+	} else {
+		info, err = os.Lstat(path)
+	}
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+// sizeCache remembers the total size of the directories measured during a
+// search. A directory is measured by adding up its files and the sizes of
+// its subdirectories, which are remembered too, so measuring a directory and
+// then each directory below it, as recursive searches do, reads every
+// directory once instead of once per ancestor.
+type sizeCache struct {
+	mu    sync.Mutex
+	sizes map[string]int64
+}
+
+func newSizeCache() *sizeCache {
+	return &sizeCache{sizes: make(map[string]int64)}
+}
+
+// dirSize returns the total size in bytes of all regular files under dir,
+// inside fsys if it is not nil, like DirSize. Unreadable directories count
+// as empty. Sizes measured while ctx is canceled are incomplete and are not
+// remembered.
+func (c *sizeCache) dirSize(ctx context.Context, fsys fs.FS, dir string) int64 {
+	c.mu.Lock()
+	size, ok := c.sizes[dir]
+	c.mu.Unlock()
+	if ok || ctx.Err() != nil {
+		return size
+	}
+
+	// Entries read before an error are still counted
+	entries, _ := readDir(fsys, dir)
+	for _, entry := range entries {
+		switch {
+		case entry.IsDir():
+			size += c.dirSize(ctx, fsys, joinPath(fsys, dir, entry.Name()))
+		case entry.Type().IsRegular():
+			if info, err := entry.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+	}
+
+	if ctx.Err() == nil {
+		c.mu.Lock()
+		c.sizes[dir] = size
+		c.mu.Unlock()
+	}
+	return size
+}
+
+// sizeUnits maps the suffixes accepted by ParseSize, in lower case, to
+// their value.
+var sizeUnits = map[string]float64{
+	"": 1, "b": 1,
+	"k": 1 << 10, "kb": 1 << 10, "kib": 1 << 10,
+	"m": 1 << 20, "mb": 1 << 20, "mib": 1 << 20,
+	"g": 1 << 30, "gb": 1 << 30, "gib": 1 << 30,
+	"t": 1 << 40, "tb": 1 << 40, "tib": 1 << 40,
+}
+
+// ParseSize parses a size in bytes such as "500", "500B", "20K", "1.5G",
+// "1GB" or "1GiB"; case is ignored. The suffixes K, M, G and T, with or
+// without "B" or "iB", are always powers of 1024: size filters do not follow
+// the size_units setting, which only changes how sizes are shown.
+//
+// Returns an error if s is not a non-negative size with one of those
+// suffixes, or is too large to be held in an int64.
+func ParseSize(s string) (int64, error) {
+	str := strings.ToLower(strings.TrimSpace(s))
+	i := len(str)
+	for i > 0 && 'a' <= str[i-1] && str[i-1] <= 'z' {
+		i--
+	}
+	unit := str[i:]
+	multiplier, ok := sizeUnits[unit]
+	value, err := strconv.ParseFloat(strings.TrimSpace(str[:i]), 64)
+	if !ok || err != nil || value < 0 || math.IsInf(value, 0) || math.IsNaN(value) {
+		return 0, fmt.Errorf("invalid size %q: use a number of bytes with an optional K, M, G or T suffix, such as 20K, 20KB or 20KiB", s)
+	}
+	// float64(math.MaxInt64) is 2^63, the first value that overflows
+	if value >= float64(math.MaxInt64)/multiplier {
+		return 0, fmt.Errorf("invalid size %q: larger than %d bytes", s, int64(math.MaxInt64))
+	}
+	return int64(value * multiplier), nil
+}
@@ -0,0 +1,197 @@
+package dirsearch
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"testing/fstest"
+)
+
+func TestDirSize(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "dirsearch-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	if err := os.MkdirAll(filepath.Join(tempDir, "a", "b"), 0755); err != nil {
+		t.Fatalf("failed to create test dirs: %v", err)
+	}
+	files := map[string]int{
+		"root.txt":                         10,
+		filepath.Join("a", "one.txt"):      20,
+		filepath.Join("a", "b", "two.txt"): 30,
+	}
+	for name, size := range files {
+		if err := os.WriteFile(filepath.Join(tempDir, name), make([]byte, size), 0644); err != nil {
+			t.Fatalf("failed to create test file %s: %v", name, err)
+		}
+	}
+
+	var reported int64
+	size, err := DirSize(context.Background(), tempDir, func(total int64) { reported = total })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if size != 60 {
+		t.Errorf("expected size 60, got %d", size)
+	}
+	if reported != size {
+		t.Errorf("expected final progress report %d, got %d", size, reported)
+	}
+}
+
+func TestDirSize_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := DirSize(ctx, ".", nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestDirSize_MissingRoot(t *testing.T) {
+	_, err := DirSize(context.Background(), filepath.Join("testdata-does-not-exist", "missing"), nil)
+	if err == nil {
+		t.Error("expected error for missing root, got nil")
+	}
+}
+
+func TestSearch_SizeBounds(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "dirsearch-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	files := map[string]int{
+		filepath.Join("big", "nested", "data.bin"): 3000,
+		filepath.Join("medium", "data.bin"):        1500,
+		filepath.Join("small", "data.bin"):         100,
+	}
+	for name, size := range files {
+		if err := os.MkdirAll(filepath.Join(tempDir, filepath.Dir(name)), 0755); err != nil {
+			t.Fatalf("failed to create test dirs: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(tempDir, name), make([]byte, size), 0644); err != nil {
+			t.Fatalf("failed to create test file %s: %v", name, err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		minSize  int64
+		maxSize  int64
+		depth    int
+		files    bool
+		expected []string
+	}{
+		{"min size", 1000, 0, 1, false, []string{"big", "medium"}},
+		{"max size", 0, 2000, 1, false, []string{"medium", "small"}},
+		{"range", 1000, 2000, 1, false, []string{"medium"}},
+		{"recursive", 2000, 0, UnlimitedDepth, false, []string{"big", filepath.Join("big", "nested")}},
+		{"files", 2000, 0, UnlimitedDepth, true, []string{"big", filepath.Join("big", "nested"), filepath.Join("big", "nested", "data.bin")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &Options{
+				StartDir:     tempDir,
+				MinSize:      tt.minSize,
+				MaxSize:      tt.maxSize,
+				MaxDepth:     tt.depth,
+				IncludeFiles: tt.files,
+			}
+			result := Search(opts)
+			if result.Error != nil {
+				t.Fatalf("unexpected error: %v", result.Error)
+			}
+			if !slices.Equal(result.Directories, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, result.Directories)
+			}
+		})
+	}
+
+	// Paths from an index are measured on disk
+	opts := &Options{StartDir: tempDir, MinSize: 1000, MaxDepth: UnlimitedDepth}
+	result := SearchPaths(context.Background(), opts, []string{"big", filepath.Join("big", "nested"), "small"})
+	expected := []string{"big", filepath.Join("big", "nested")}
+	if !slices.Equal(result.Directories, expected) {
+		t.Errorf("expected %v, got %v", expected, result.Directories)
+	}
+}
+
+// countingFS counts the directory listings read from an fs.FS.
+type countingFS struct {
+	fstest.MapFS
+	mu    sync.Mutex
+	reads map[string]int
+}
+
+func (c *countingFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	c.mu.Lock()
+	c.reads[name]++
+	c.mu.Unlock()
+	return c.MapFS.ReadDir(name)
+}
+
+func TestSearch_SizeBoundsMeasureOnce(t *testing.T) {
+	fsys := &countingFS{
+		MapFS: fstest.MapFS{"a/b/c/d/e/f/data.bin": {Data: make([]byte, 100)}},
+		reads: make(map[string]int),
+	}
+
+	opts := &Options{StartDir: ".", FS: fsys, MinSize: 50, MaxDepth: UnlimitedDepth}
+	result := Search(opts)
+	if result.Error != nil {
+		t.Fatalf("unexpected error: %v", result.Error)
+	}
+	if len(result.Directories) != 6 {
+		t.Errorf("expected 6 directories, got %v", result.Directories)
+	}
+
+	// Each directory is listed by the search and at most once more to
+	// measure it, however many of its ancestors were measured before
+	for dir, n := range fsys.reads {
+		if n > 2 {
+			t.Errorf("expected %s to be read at most twice, got %d reads", dir, n)
+		}
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int64
+	}{
+		{"0", 0},
+		{"500", 500},
+		{"500B", 500},
+		{"20K", 20 << 10},
+		{"20kb", 20 << 10},
+		{"1.5M", 3 << 19},
+		{"1G", 1 << 30},
+		{"1GiB", 1 << 30},
+		{"2t", 2 << 40},
+		{"3 mib", 3 << 20},
+	}
+	for _, tt := range tests {
+		got, err := ParseSize(tt.in)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.in, err)
+		} else if got != tt.want {
+			t.Errorf("%q: expected %d, got %d", tt.in, tt.want, got)
+		}
+	}
+
+	for _, in := range []string{"", "G", "-1K", "1X", "inf", "NaN", "99999999999T", "8388608T", "9223372036854775808", "1e300", "2i", "2ib", "2bb", "1KBi", "1kk", "1iKB"} {
+		if _, err := ParseSize(in); err == nil {
+			t.Errorf("%q: expected error, got nil", in)
+		}
+	}
+}
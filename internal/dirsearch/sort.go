@@ -0,0 +1,142 @@
+package dirsearch
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// SortKey selects how search results are ordered.
+type SortKey string
+
+const (
+	// SortDefault orders results by name, or by match score for fuzzy
+	// searches with a pattern
+	SortDefault SortKey = ""
+
+	// SortName orders results by name (by path for recursive searches)
+	SortName SortKey = "name"
+
+	// SortModTime orders results by modification time
+	SortModTime SortKey = "mtime"
+
+	// SortSize orders files by size and directories by the total size of
+	// the files inside them, which reads their whole subtree
+	SortSize SortKey = "size"
+
+	// SortNone skips sorting: a single directory is listed in the order
+	// the filesystem returns its entries, recursive searches in walk order
+	SortNone SortKey = "none"
+)
+
+// ParseSortKey returns the SortKey named s: "name", "mtime", "size" or
+// "none". An empty string selects SortDefault.
+func ParseSortKey(s string) (SortKey, error) {
+	switch key := SortKey(s); key {
+	case SortDefault, SortName, SortModTime, SortSize, SortNone:
+		return key, nil
+	}
+	return "", fmt.Errorf("unknown sort key %q: use name, mtime, size or none", s)
+}
+
+// SortOrder is the direction results are sorted in.
+type SortOrder uint8
+
+const (
+	// Ascending puts the smallest key first: A to Z, oldest or smallest
+	Ascending SortOrder = iota
+
+	// Descending puts the largest key first: Z to A, newest or largest
+	Descending
+)
+
+// Sort orders the entries of r as selected by opts.SortBy and
+// opts.SortOrder, keeping Scores, Types, Roots and MatchedRanges aligned.
+// Entries with equal times or sizes are ordered by name from A to Z
+// whatever the SortOrder. Entries are looked up
+// relative to opts.StartDir, or to their element of Roots; entries that
+// cannot be inspected sort as if they were empty and infinitely old.
+//
+// SortOrder does not apply to the score ordering of fuzzy searches, which
+// always lists the best match first. Measuring directory sizes stops early
+// when ctx is canceled, leaving the order partially sorted.
+func (r *Result) Sort(ctx context.Context, opts *Options) {
+	switch opts.SortBy {
+	case SortNone:
+		return
+	case SortDefault:
+		if opts.Fuzzy && opts.SearchPattern != "" {
+			sortByScore(r, opts)
+			return
+		}
+	}
+
+	var values []int64
+	if opts.SortBy == SortModTime || opts.SortBy == SortSize {
+		values = make([]int64, len(r.Directories))
+		sizes := newSizeCache()
+		for i := range r.Directories {
+			values[i] = sortValue(ctx, opts.FS, sizes, r.entryPath(opts, i), opts.SortBy)
+		}
+	}
+
+	dirs := r.Directories
+	descending := opts.SortOrder == Descending
+	r.reorder(func(i, j int) int {
+		if values != nil {
+			if c := cmp.Compare(values[i], values[j]); c != 0 {
+				if descending {
+					return -c
+				}
+				return c
+			}
+			// Ties are broken by name, always ascending
+			return compareWalkOrder(dirs[i], dirs[j])
+		}
+		c := compareWalkOrder(dirs[i], dirs[j])
+		if descending {
+			return -c
+		}
+		return c
+	})
+}
+
+// sortValue returns the modification time or size of the entry at path,
+// inside fsys if it is not nil, or zero if it cannot be inspected. Directory
+// sizes are measured through sizes.
+func sortValue(ctx context.Context, fsys fs.FS, sizes *sizeCache, path string, key SortKey) int64 {
+	var info fs.FileInfo
+	var err error
+	if fsys != nil {
+		info, err = fs.Stat(fsys, path)
+	} else {
+		info, err = os.Lstat(path)
+	}
+	if err != nil {
+		return 0
+	}
+	if key == SortModTime {
+		return info.ModTime().UnixNano()
+	}
+	if !info.IsDir() {
+		return info.Size()
+	}
+	return sizes.dirSize(ctx, fsys, path)
+}
+
+// entryPath returns the path of the entry at index i, joined to its root:
+// opts.StartDir, or the entry's element of Roots if they are set.
+func (r *Result) entryPath(opts *Options, i int) string {
+	root := opts.StartDir
+	if r.Roots != nil {
+		root = r.Roots[i]
+	}
+	if opts.FS != nil {
+		return path.Join(root, r.Directories[i])
+	}
+	return filepath.Join(root, r.Directories[i])
+}
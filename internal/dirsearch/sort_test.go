@@ -0,0 +1,133 @@
+package dirsearch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSearch_SortBy(t *testing.T) {
+	root, err := os.MkdirTemp("", "dirsearch-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(root)
+
+	// name, size of a file inside, age in hours
+	dirs := []struct {
+		name string
+		size int
+		age  int
+	}{
+		{"beta", 300, 1},
+		{"alpha", 100, 3},
+		{"gamma", 200, 2},
+	}
+	for _, d := range dirs {
+		path := filepath.Join(root, d.name)
+		if err := os.Mkdir(path, 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", d.name, err)
+		}
+		if err := os.WriteFile(filepath.Join(path, "data"), make([]byte, d.size), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+		mtime := time.Now().Add(-time.Duration(d.age) * time.Hour)
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatalf("failed to set mtime: %v", err)
+		}
+	}
+
+	tests := []struct {
+		sortBy   SortKey
+		order    SortOrder
+		expected []string
+	}{
+		{SortDefault, Ascending, []string{"alpha", "beta", "gamma"}},
+		{SortName, Descending, []string{"gamma", "beta", "alpha"}},
+		{SortModTime, Ascending, []string{"alpha", "gamma", "beta"}},
+		{SortModTime, Descending, []string{"beta", "gamma", "alpha"}},
+		{SortSize, Ascending, []string{"alpha", "gamma", "beta"}},
+		{SortSize, Descending, []string{"beta", "gamma", "alpha"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.sortBy), func(t *testing.T) {
+			opts := DefaultOptions()
+			opts.StartDir = root
+			opts.SortBy = tt.sortBy
+			opts.SortOrder = tt.order
+
+			result := Search(opts)
+			if result.Error != nil {
+				t.Fatalf("unexpected error: %v", result.Error)
+			}
+			if !slices.Equal(result.Directories, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, result.Directories)
+			}
+		})
+	}
+}
+
+func TestResult_Sort_KeepsTypesAligned(t *testing.T) {
+	root, err := os.MkdirTemp("", "dirsearch-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(root)
+
+	if err := os.Mkdir(filepath.Join(root, "big"), 0755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "big", "data"), make([]byte, 1000), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "small.txt"), make([]byte, 10), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	result := Result{Directories: []string{"big", "small.txt"}, Types: []EntryType{Dir, File}}
+	result.Sort(context.Background(), &Options{StartDir: root, SortBy: SortSize})
+
+	if !slices.Equal(result.Directories, []string{"small.txt", "big"}) {
+		t.Errorf("expected [small.txt big], got %v", result.Directories)
+	}
+	if !slices.Equal(result.Types, []EntryType{File, Dir}) {
+		t.Errorf("expected types to follow their entries, got %v", result.Types)
+	}
+}
+
+func TestResult_Sort_DescendingTies(t *testing.T) {
+	root, err := os.MkdirTemp("", "dirsearch-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(root)
+
+	sizes := map[string]int{"a-small": 10, "b-big": 100, "c-big": 100, "d-small": 10}
+	for name, size := range sizes {
+		if err := os.WriteFile(filepath.Join(root, name), make([]byte, size), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+	}
+
+	result := Result{Directories: []string{"d-small", "c-big", "a-small", "b-big"}}
+	result.Sort(context.Background(), &Options{StartDir: root, SortBy: SortSize, SortOrder: Descending})
+	expected := []string{"b-big", "c-big", "a-small", "d-small"}
+	if !slices.Equal(result.Directories, expected) {
+		t.Errorf("expected ties in name order, %v, got %v", expected, result.Directories)
+	}
+}
+
+func TestParseSortKey(t *testing.T) {
+	for _, s := range []string{"", "name", "mtime", "size", "none"} {
+		if key, err := ParseSortKey(s); err != nil || string(key) != s {
+			t.Errorf("ParseSortKey(%q): expected %q, got %q (%v)", s, s, key, err)
+		}
+	}
+	if _, err := ParseSortKey("ctime"); err == nil {
+		t.Error("expected error for unknown sort key, got nil")
+	}
+}
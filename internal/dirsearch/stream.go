@@ -0,0 +1,142 @@
+package dirsearch
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"time"
+)
+
+// DefaultBatchSize is the number of directory entries read per batch by
+// SearchStream when no positive batch size is given.
+const DefaultBatchSize = 512
+
+// SearchStream performs the same search as Search, but reads the directory
+// in batches and passes the matches of each batch to emit as soon as they are
+// available. This lets callers show partial results for very large
+// directories instead of waiting for the whole listing.
+// Only the immediate children of StartDir are read; MaxDepth is ignored, and
+// fuzzy matches are not ordered by score.
+//
+// Parameters:
+//   - ctx: cancels the search between batches
+//   - opts: configuration options for the search
+//   - batchSize: number of entries read per batch (DefaultBatchSize if not positive)
+//   - emit: called with the matches of each non-empty batch
+//
+// Returns a Result with all matching directories, in the same order as they
+// were emitted, or an error. Matches emitted before a read error or
+// cancellation are included in the Result. Reading stops as soon as
+// opts.MaxResults matches were emitted and another entry matches.
+func SearchStream(ctx context.Context, opts *Options, batchSize int, emit func(dirs []string)) Result {
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	foundDirs := []string{}
+
+	m, err := newMatcher(opts)
+	if err != nil {
+		return Result{Directories: foundDirs, Error: err}
+	}
+
+	dir, err := openDir(opts)
+	if err != nil {
+		return Result{Directories: foundDirs, Error: err}
+	}
+	defer dir.Close()
+
+	// FS is read by a single worker, see Options.FS
+	workers := opts.Concurrency
+	if opts.FS != nil {
+		workers = 1
+	}
+
+	var types []EntryType
+	if opts.IncludeFiles {
+		types = []EntryType{}
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return Result{Directories: foundDirs, Error: err, Types: types}
+		}
+		entries, err := dir.ReadDir(batchSize)
+
+		batch := []string{}
+		truncated := false
+		for _, entry := range m.filterEntries(ctx, opts.StartDir, entries, workers) {
+			if opts.MaxResults > 0 && len(foundDirs)+len(batch) >= opts.MaxResults {
+				truncated = true
+				break
+			}
+			batch = append(batch, entry.Name())
+			if types != nil {
+				types = append(types, entryType(entry))
+			}
+		}
+		if len(batch) > 0 {
+			foundDirs = append(foundDirs, batch...)
+			emit(batch)
+		}
+
+		if truncated {
+			return Result{Directories: foundDirs, Error: nil, Types: types, Truncated: true}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return Result{Directories: foundDirs, Error: err, Types: types}
+		}
+	}
+
+	return Result{Directories: foundDirs, Error: nil, Types: types}
+}
+
+// dirReader reads a directory in batches, like *os.File.
+type dirReader interface {
+	ReadDir(n int) ([]fs.DirEntry, error)
+	Close() error
+}
+
+// openDir opens opts.StartDir for reading, inside opts.FS if it is set.
+func openDir(opts *Options) (dirReader, error) {
+	if opts.FS == nil {
+		return os.Open(opts.StartDir)
+	}
+	f, err := opts.FS.Open(opts.StartDir)
+	if err != nil {
+		return nil, err
+	}
+	dir, ok := f.(fs.ReadDirFile)
+	if !ok {
+		f.Close()
+		return nil, &fs.PathError{Op: "readdir", Path: opts.StartDir, Err: errors.New("not a directory")}
+	}
+	return dir, nil
+}
+
+// entryType returns the type of a matched entry.
+func entryType(entry os.DirEntry) EntryType {
+	if entry.IsDir() {
+		return Dir
+	}
+	return File
+}
+
+// ScanDirsStream is the streaming counterpart of ScanDirs.
+//
+// It searches a snapshot of the options with StartDir set to dir using
+// SearchStream, leaving Options unchanged. Batches are emitted in the order
+// they are read, but once the directory has been read the Result is ordered,
+// and carries MatchedRanges and Projects, as with ScanDirsContext.
+func (d *DirSearch) ScanDirsStream(ctx context.Context, dir string, batchSize int, emit func(dirs []string)) Result {
+	opts := d.snapshot(dir)
+	start := time.Now()
+	result := SearchStream(ctx, opts, batchSize, emit)
+	result.finish(ctx, opts)
+	d.logScan(ctx, opts, start, result)
+	return result
+}
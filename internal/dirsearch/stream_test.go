@@ -0,0 +1,78 @@
+package dirsearch
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSearchStream(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "dirsearch-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	const dirCount = 25
+	for i := 0; i < dirCount; i++ {
+		if err := os.Mkdir(filepath.Join(tempDir, fmt.Sprintf("dir%02d", i)), 0755); err != nil {
+			t.Fatalf("failed to create test dir: %v", err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(tempDir, "node_modules"), 0755); err != nil {
+		t.Fatalf("failed to create test dir: %v", err)
+	}
+
+	opts := &Options{
+		StartDir:       tempDir,
+		IgnorePatterns: []string{"node_modules"},
+	}
+
+	var batches [][]string
+	result := SearchStream(context.Background(), opts, 10, func(dirs []string) {
+		batches = append(batches, dirs)
+	})
+
+	if result.Error != nil {
+		t.Fatalf("unexpected error: %v", result.Error)
+	}
+	if len(result.Directories) != dirCount {
+		t.Errorf("expected %d directories, got %d", dirCount, len(result.Directories))
+	}
+	if len(batches) < 3 {
+		t.Errorf("expected at least 3 batches of at most 10 entries, got %d", len(batches))
+	}
+
+	emitted := 0
+	for _, batch := range batches {
+		if len(batch) > 10 {
+			t.Errorf("batch larger than batch size: %d", len(batch))
+		}
+		emitted += len(batch)
+	}
+	if emitted != len(result.Directories) {
+		t.Errorf("expected emitted total %d to equal result size %d", emitted, len(result.Directories))
+	}
+
+	// The streamed search must find the same directories as Search
+	plain := Search(opts)
+	if len(plain.Directories) != len(result.Directories) {
+		t.Errorf("expected same results as Search, got %d vs %d", len(result.Directories), len(plain.Directories))
+	}
+}
+
+func TestSearchStream_MissingDir(t *testing.T) {
+	opts := &Options{StartDir: filepath.Join("testdata-does-not-exist", "missing")}
+
+	result := SearchStream(context.Background(), opts, 0, func([]string) {
+		t.Error("emit should not be called for a missing directory")
+	})
+	if result.Error == nil {
+		t.Error("expected error for missing directory, got nil")
+	}
+	if result.Directories == nil {
+		t.Error("expected Directories slice to be initialized, got nil")
+	}
+}
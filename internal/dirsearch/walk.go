@@ -0,0 +1,180 @@
+package dirsearch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// DefaultConcurrency is the number of directories a recursive search reads
+// in parallel by default.
+const DefaultConcurrency = 8
+
+// walker traverses a tree with a fixed pool of workers sharing a queue of
+// directories, so sibling subtrees are read concurrently. It finds the same
+// directories as the sequential walk in FindDirs.
+type walker struct {
+	ctx      context.Context
+	root     string
+	m        *matcher
+	maxDepth int
+	limit    int
+
+	mu        sync.Mutex
+	cond      *sync.Cond
+	queue     []string // Directories waiting to be read, relative to root
+	active    int      // Directories being read
+	found     []entry
+	warnings  []PathError
+	truncated bool
+	err       error // Stops the walk: root unreadable or ctx canceled
+}
+
+// walkParallel is the concurrent counterpart of walk, taking the same
+// arguments and returning the same results.
+// Matches and warnings are sorted into walk order at the end; when limit
+// truncates the result, which matches are kept depends on the order
+// directories were read.
+func walkParallel(ctx context.Context, root string, m *matcher, maxDepth, limit, workers int) ([]entry, []PathError, bool, error) {
+	w := &walker{
+		ctx:      ctx,
+		root:     root,
+		m:        m,
+		maxDepth: maxDepth,
+		limit:    limit,
+		queue:    []string{"."},
+	}
+	w.cond = sync.NewCond(&w.mu)
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.work()
+		}()
+	}
+	wg.Wait()
+
+	if w.err != nil {
+		return nil, nil, false, w.err
+	}
+	slices.SortFunc(w.found, func(a, b entry) int {
+		return compareWalkOrder(a.path, b.path)
+	})
+	slices.SortFunc(w.warnings, func(a, b PathError) int {
+		return compareWalkOrder(a.Path, b.Path)
+	})
+	return w.found, w.warnings, w.truncated, nil
+}
+
+// work reads directories from the queue until the walk is finished.
+func (w *walker) work() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for {
+		for len(w.queue) == 0 && w.active > 0 && !w.stopped() {
+			w.cond.Wait()
+		}
+		if len(w.queue) == 0 || w.stopped() {
+			// Wake the other workers so they notice the walk is over
+			w.cond.Broadcast()
+			return
+		}
+
+		dir := w.queue[len(w.queue)-1]
+		w.queue = w.queue[:len(w.queue)-1]
+		w.active++
+
+		w.mu.Unlock()
+		matches, subdirs, skipped, err := w.read(dir)
+		w.mu.Lock()
+
+		w.active--
+		if err != nil && w.err == nil {
+			w.err = err
+		}
+		if skipped != nil {
+			w.warnings = append(w.warnings, *skipped)
+		}
+		for _, match := range matches {
+			if w.limit > 0 && len(w.found) >= w.limit {
+				w.truncated = true
+				break
+			}
+			w.found = append(w.found, match)
+		}
+		w.queue = append(w.queue, subdirs...)
+		w.cond.Broadcast()
+	}
+}
+
+// stopped reports whether the walk has ended early. w.mu must be held.
+func (w *walker) stopped() bool {
+	return w.err != nil || w.truncated
+}
+
+// read lists dir, relative to root, and returns the matching entries and
+// the subdirectories to descend into. Unreadable directories below the root
+// are skipped like in walk and returned as skipped.
+func (w *walker) read(dir string) (matches []entry, subdirs []string, skipped *PathError, err error) {
+	if err := w.ctx.Err(); err != nil {
+		return nil, nil, nil, err
+	}
+
+	entries, err := os.ReadDir(filepath.Join(w.root, dir))
+	if err != nil {
+		if dir == "." {
+			return nil, nil, nil, err
+		}
+		warning := newPathError(dir, err)
+		return nil, nil, &warning, nil
+	}
+
+	for _, e := range entries {
+		if !e.IsDir() {
+			path := filepath.Join(w.root, dir, e.Name())
+			if w.m.matchEntry(e) && w.m.keep(path, e) && w.m.matchFilters(w.ctx, path, false) {
+				matches = append(matches, entry{path: filepath.Join(dir, e.Name()), typ: File})
+			}
+			continue
+		}
+
+		rel := filepath.Join(dir, e.Name())
+		path := filepath.Join(w.root, rel)
+		if w.m.skip(e.Name()) || !w.m.keep(path, e) {
+			continue
+		}
+		if w.m.matchEntry(e) && w.m.matchFilters(w.ctx, path, true) {
+			matches = append(matches, entry{path: rel, typ: Dir})
+		}
+		if w.maxDepth <= 0 || strings.Count(rel, string(filepath.Separator))+1 < w.maxDepth {
+			subdirs = append(subdirs, rel)
+		}
+	}
+	return matches, subdirs, nil, nil
+}
+
+// compareWalkOrder orders relative paths the way filepath.WalkDir visits
+// them: a directory comes right before its contents, so the separator sorts
+// before every other character ("a", "a/b", "a-b").
+func compareWalkOrder(a, b string) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		ca, cb := a[i], b[i]
+		if ca == cb {
+			continue
+		}
+		if ca == filepath.Separator {
+			return -1
+		}
+		if cb == filepath.Separator {
+			return 1
+		}
+		return int(ca) - int(cb)
+	}
+	return len(a) - len(b)
+}
@@ -0,0 +1,102 @@
+package dirsearch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestFindDirs_Concurrency(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "dirsearch-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	for _, dir := range []string{"a/b/c", "a-b/c", "a.b", "b/node_modules/c", "b/d/e/f", ".git/c"} {
+		if err := os.MkdirAll(filepath.Join(tempDir, dir), 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", dir, err)
+		}
+	}
+
+	for _, pattern := range []string{"", "c"} {
+		for _, depth := range []int{0, 1, 2, 3} {
+			opts := &Options{StartDir: tempDir, SearchPattern: pattern, IgnorePatterns: []string{"node_modules"}}
+			want, _, err := FindDirs(context.Background(), opts, depth, 0)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			opts.Concurrency = 4
+			got, truncated, err := FindDirs(context.Background(), opts, depth, 0)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if truncated {
+				t.Error("expected complete result")
+			}
+			if !slices.Equal(got, want) {
+				t.Errorf("pattern %q, depth %d: expected %v, got %v", pattern, depth, want, got)
+			}
+		}
+	}
+
+	opts := &Options{StartDir: tempDir, Concurrency: 4}
+	found, truncated, err := FindDirs(context.Background(), opts, 0, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(found) != 2 || !truncated {
+		t.Errorf("expected 2 truncated results, got %v (truncated %v)", found, truncated)
+	}
+}
+
+func TestFindDirs_ConcurrencyErrors(t *testing.T) {
+	opts := &Options{StartDir: filepath.Join(os.TempDir(), "dirsearch-missing-dir"), Concurrency: 4}
+	if _, _, err := FindDirs(context.Background(), opts, 0, 0); err == nil {
+		t.Error("expected error for missing start directory")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	opts.StartDir = os.TempDir()
+	if _, _, err := FindDirs(ctx, opts, 0, 0); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+// BenchmarkFindDirs compares the sequential walk with parallel walks on a
+// tree of about 2,000 directories.
+func BenchmarkFindDirs(b *testing.B) {
+	tempDir, err := os.MkdirTemp("", "dirsearch-bench-*")
+	if err != nil {
+		b.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	for i := range 20 {
+		for j := range 10 {
+			for k := range 10 {
+				dir := filepath.Join(tempDir, fmt.Sprintf("pkg%d", i), fmt.Sprintf("mod%d", j), fmt.Sprintf("src%d", k))
+				if err := os.MkdirAll(dir, 0755); err != nil {
+					b.Fatalf("failed to create %s: %v", dir, err)
+				}
+			}
+		}
+	}
+
+	for _, concurrency := range []int{1, 4, DefaultConcurrency} {
+		b.Run(fmt.Sprintf("concurrency=%d", concurrency), func(b *testing.B) {
+			opts := &Options{StartDir: tempDir, SearchPattern: "src", Concurrency: concurrency}
+			for i := 0; i < b.N; i++ {
+				if _, _, err := FindDirs(context.Background(), opts, 0, 0); err != nil {
+					b.Fatalf("unexpected error: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package dirsearch
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+// deniedFS is an in-memory tree whose listed directories cannot be read.
+type deniedFS struct {
+	fstest.MapFS
+	denied []string
+}
+
+func (f deniedFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if slices.Contains(f.denied, name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrPermission}
+	}
+	return f.MapFS.ReadDir(name)
+}
+
+func TestSearch_Warnings(t *testing.T) {
+	opts := &Options{
+		StartDir:       ".",
+		IgnorePatterns: []string{".git", "node_modules"},
+		MaxDepth:       UnlimitedDepth,
+		FS:             deniedFS{MapFS: testFS(), denied: []string{"src/lib", "node_modules"}},
+	}
+
+	result := Search(opts)
+	if result.Error != nil {
+		t.Fatalf("unexpected error: %v", result.Error)
+	}
+
+	// The unreadable directory is still a match, only its contents are skipped
+	expected := []string{"docs", "src", "src/app", "src/lib"}
+	if !slices.Equal(result.Directories, expected) {
+		t.Errorf("expected %v, got %v", expected, result.Directories)
+	}
+
+	// Ignored directories are never read, so they cannot fail
+	if len(result.Warnings) != 1 {
+		t.Fatalf("expected 1 warning, got %v", result.Warnings)
+	}
+	w := result.Warnings[0]
+	if w.Path != "src/lib" {
+		t.Errorf("expected warning for src/lib, got %q", w.Path)
+	}
+	if !errors.Is(w, fs.ErrPermission) {
+		t.Errorf("expected permission error, got %v", w.Err)
+	}
+	if expected := "src/lib: permission denied"; w.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, w.Error())
+	}
+
+	// Searches of the immediate children do not read below StartDir
+	opts.MaxDepth = 1
+	if result := Search(opts); len(result.Warnings) != 0 {
+		t.Errorf("expected no warnings for a flat search, got %v", result.Warnings)
+	}
+
+	// A StartDir that cannot be read is still an error
+	opts.StartDir = "src/lib"
+	opts.MaxDepth = UnlimitedDepth
+	if result := Search(opts); result.Error == nil {
+		t.Error("expected error for unreadable start directory, got nil")
+	}
+}
+
+func TestSearch_WarningsStartDirs(t *testing.T) {
+	opts := &Options{
+		StartDirs: []string{"docs", "src"},
+		MaxDepth:  UnlimitedDepth,
+		FS:        deniedFS{MapFS: testFS(), denied: []string{"src/app"}},
+	}
+
+	result := Search(opts)
+	if result.Error != nil {
+		t.Fatalf("unexpected error: %v", result.Error)
+	}
+	if len(result.Warnings) != 1 || result.Warnings[0].Path != filepath.Join("src", "app") {
+		t.Errorf("expected a warning for src/app, got %v", result.Warnings)
+	}
+}
+
+func TestSearch_WarningsParallel(t *testing.T) {
+	if runtime.GOOS == "windows" || os.Getuid() == 0 {
+		t.Skip("directory permissions are not enforced")
+	}
+
+	tempDir, err := os.MkdirTemp("", "dirsearch-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	for _, dir := range []string{"a/locked/inner", "b/open"} {
+		if err := os.MkdirAll(filepath.Join(tempDir, dir), 0755); err != nil {
+			t.Fatalf("failed to create test dir: %v", err)
+		}
+	}
+	locked := filepath.Join(tempDir, "a", "locked")
+	if err := os.Chmod(locked, 0); err != nil {
+		t.Fatalf("failed to lock test dir: %v", err)
+	}
+	defer os.Chmod(locked, 0755)
+
+	result := Search(&Options{StartDir: tempDir, MaxDepth: UnlimitedDepth, Concurrency: DefaultConcurrency})
+	if result.Error != nil {
+		t.Fatalf("unexpected error: %v", result.Error)
+	}
+	if len(result.Warnings) != 1 || result.Warnings[0].Path != filepath.Join("a", "locked") {
+		t.Fatalf("expected a warning for a/locked, got %v", result.Warnings)
+	}
+	if !errors.Is(result.Warnings[0], fs.ErrPermission) {
+		t.Errorf("expected permission error, got %v", result.Warnings[0].Err)
+	}
+}
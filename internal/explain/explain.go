@@ -0,0 +1,131 @@
+// Package explain finds out which rule keeps a path out of folder-search
+// listings, to debug missing results.
+//
+// A directory is hidden if its own name, or the name of one of its parent
+// directories, matches a rule: the dot-name rule when hidden entries are
+// not shown, an ignore list (the defaults, the "ignore" setting of the
+// configuration or a profile) or a pattern of the project ignore file
+// applying to the directory containing it, unless a negated pattern
+// ("!name") keeps it listed.
+package explain
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/kaczmarekdaniel/folder-search/internal/dirsearch"
+	"github.com/kaczmarekdaniel/folder-search/internal/ignorefile"
+)
+
+// Rule is an ignore pattern together with where it comes from.
+type Rule struct {
+	// Pattern is a directory name or shell pattern
+	Pattern string
+
+	// Source describes where the rule is set, e.g. "default ignore list"
+	// or "/repo/.folder-search-ignore:3"
+	Source string
+}
+
+// hiddenRule is the built-in rule of dirsearch hiding dot-names.
+var hiddenRule = Rule{Pattern: ".*", Source: "hidden entries are not shown, press . in the UI to show them"}
+
+// Reason explains why a path is hidden.
+type Reason struct {
+	// Dir is the entry the rule matched: the path itself or the parent
+	// directory hiding it
+	Dir string
+
+	// Rule is the rule that matched the name of Dir
+	Rule Rule
+}
+
+// String describes the reason in one sentence.
+func (r Reason) String() string {
+	return fmt.Sprintf("%s is hidden by %q: %s", r.Dir, r.Rule.Pattern, r.Rule.Source)
+}
+
+// Explainer finds the rule hiding a path.
+type Explainer struct {
+	// Rules are ignore patterns that apply everywhere, in the order they
+	// are checked
+	Rules []Rule
+
+	// ShowHidden is whether names starting with a dot are listed
+	ShowHidden bool
+}
+
+// Explain checks path and its parent directories against the rules, nearest
+// first, and returns the first match.
+//
+// Ignore patterns only apply to directories, so a file is only reported
+// for its own name if it is hidden as a dot-name; its parent directories
+// are checked like for directories.
+//
+// Returns false if no rule hides path, or an error if path does not exist
+// or a project ignore file cannot be read.
+func (e Explainer) Explain(path string) (Reason, bool, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return Reason{}, false, err
+	}
+	info, err := os.Lstat(path)
+	if err != nil {
+		return Reason{}, false, fmt.Errorf("cannot inspect %s: %w", path, err)
+	}
+
+	isDir := info.IsDir()
+	for dir := path; ; dir = filepath.Dir(dir) {
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return Reason{}, false, nil
+		}
+
+		rule, ok, err := e.match(filepath.Base(dir), parent, isDir)
+		if err != nil {
+			return Reason{}, false, err
+		}
+		if ok {
+			return Reason{Dir: dir, Rule: rule}, true, nil
+		}
+		isDir = true
+	}
+}
+
+// match returns the rule hiding an entry called name in the directory parent.
+//
+// A negated rule ("!name") matching name from any source keeps the entry
+// listed, as it does in dirsearch.
+func (e Explainer) match(name, parent string, isDir bool) (Rule, bool, error) {
+	if !e.ShowHidden && strings.HasPrefix(name, ".") {
+		return hiddenRule, true, nil
+	}
+	if !isDir {
+		return Rule{}, false, nil
+	}
+
+	rules := e.Rules
+	if file := ignorefile.Find(parent); file != "" {
+		fileRules, err := ignorefile.ReadRules(file)
+		if err != nil {
+			return Rule{}, false, err
+		}
+		rules = slices.Clone(rules)
+		for _, r := range fileRules {
+			rules = append(rules, Rule{Pattern: r.Pattern, Source: fmt.Sprintf("%s:%d", file, r.Line)})
+		}
+	}
+
+	if slices.ContainsFunc(rules, func(r Rule) bool { return dirsearch.MatchKeep(r.Pattern, name) }) {
+		return Rule{}, false, nil
+	}
+	for _, r := range rules {
+		if dirsearch.MatchIgnore(r.Pattern, name) {
+			return r, true, nil
+		}
+	}
+	return Rule{}, false, nil
+}
@@ -0,0 +1,90 @@
+package explain
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	root, err := os.MkdirTemp("", "explain-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(root)
+
+	for _, dir := range []string{"repo/.git/objects", "repo/node_modules/pkg", "repo/build-1/out", "repo/build-keep", "repo/src", "repo/.cache", "repo/vendor"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", dir, err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, "repo/src/.env"), nil, 0644); err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "repo/node_modules.txt"), nil, 0644); err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "repo/.folder-search-ignore"), []byte("# generated\nbuild-*\n!build-keep\n"), 0644); err != nil {
+		t.Fatalf("failed to write ignore file: %v", err)
+	}
+
+	e := Explainer{
+		Rules: []Rule{
+			{Pattern: ".git", Source: "default ignore list"},
+			{Pattern: "node_modules", Source: "default ignore list"},
+			{Pattern: "vend*", Source: "config"},
+		},
+	}
+
+	tests := []struct {
+		path       string
+		showHidden bool
+		dir        string // Expected hidden entry; empty if the path is listed
+		source     string
+	}{
+		{"repo/.git/objects", true, "repo/.git", "default ignore list"},
+		{"repo/node_modules/pkg", true, "repo/node_modules", "default ignore list"},
+		{"repo/vendor", true, "repo/vendor", "config"},
+		{"repo/build-1/out", true, "repo/build-1", filepath.Join(root, "repo/.folder-search-ignore") + ":2"},
+		// Negated in the ignore file
+		{"repo/build-keep", true, "", ""},
+		{"repo/.cache", false, "repo/.cache", "hidden entries"},
+		{"repo/.cache", true, "", ""},
+		{"repo/src/.env", false, "repo/src/.env", "hidden entries"},
+		{"repo/src", true, "", ""},
+		// Ignore patterns do not apply to files
+		{"repo/node_modules.txt", true, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			e.ShowHidden = tt.showHidden
+			reason, hidden, err := e.Explain(filepath.Join(root, tt.path))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.dir == "" {
+				if hidden {
+					t.Errorf("expected %s to be listed, got %s", tt.path, reason)
+				}
+				return
+			}
+			if !hidden {
+				t.Fatalf("expected %s to be hidden", tt.path)
+			}
+			if reason.Dir != filepath.Join(root, tt.dir) {
+				t.Errorf("expected hidden entry %s, got %s", tt.dir, reason.Dir)
+			}
+			if !strings.HasPrefix(reason.Rule.Source, tt.source) {
+				t.Errorf("expected source %q, got %q", tt.source, reason.Rule.Source)
+			}
+		})
+	}
+}
+
+func TestExplain_Missing(t *testing.T) {
+	if _, _, err := (Explainer{}).Explain(filepath.Join(os.TempDir(), "explain-test-does-not-exist")); err == nil {
+		t.Error("expected error for missing path, got nil")
+	}
+}
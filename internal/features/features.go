@@ -0,0 +1,102 @@
+// Package features lets subsystems be turned on or off in the
+// "experimental" section of the config file, so large new subsystems can
+// ship disabled and be tried without a separate build.
+//
+// A subsystem is registered by adding a Feature constant and its entry to
+// All, and checks Set.Enabled before starting. Graduated features are
+// removed from All; settings naming unknown features are reported but
+// otherwise ignored, so config files keep working across releases.
+package features
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"text/tabwriter"
+)
+
+// Feature names a subsystem that can be turned on or off.
+type Feature string
+
+// Known features.
+const (
+	// Watch refreshes the listing when the current directory changes
+	Watch Feature = "watch"
+)
+
+// Info describes a feature.
+type Info struct {
+	// Name is the key of the feature in the config file
+	Name Feature
+
+	// Description says what the feature does
+	Description string
+
+	// Default is whether the feature is on without a setting
+	Default bool
+}
+
+// All lists the known features.
+var All = []Info{
+	{Name: Watch, Description: "refresh the listing when the current directory changes", Default: true},
+}
+
+// Set holds which features are on. The zero value has every feature in
+// its default state.
+type Set struct {
+	enabled map[Feature]bool
+}
+
+// New returns the features with settings applied on top of the defaults.
+//
+// Parameters:
+//   - settings: maps feature names to whether they are on, as in the
+//     config file
+//
+// Returns the features and the names of unknown features in settings,
+// sorted.
+func New(settings map[string]bool) (Set, []string) {
+	s := Set{enabled: make(map[Feature]bool, len(settings))}
+	var unknown []string
+	for name, on := range settings {
+		if _, ok := lookup(Feature(name)); !ok {
+			unknown = append(unknown, name)
+			continue
+		}
+		s.enabled[Feature(name)] = on
+	}
+	slices.Sort(unknown)
+	return s, unknown
+}
+
+// Enabled reports whether f is on. Unknown features are off.
+func (s Set) Enabled(f Feature) bool {
+	if on, ok := s.enabled[f]; ok {
+		return on
+	}
+	info, _ := lookup(f)
+	return info.Default
+}
+
+// WriteReport writes a table of the known features, whether they are on
+// and what they do to w.
+func (s Set) WriteReport(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, info := range All {
+		state := "off"
+		if s.Enabled(info.Name) {
+			state = "on"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", info.Name, state, info.Description)
+	}
+	return tw.Flush()
+}
+
+// lookup returns the description of f.
+func lookup(f Feature) (Info, bool) {
+	i := slices.IndexFunc(All, func(info Info) bool { return info.Name == f })
+	if i < 0 {
+		return Info{}, false
+	}
+	return All[i], true
+}
@@ -0,0 +1,47 @@
+package features
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	restore := All
+	defer func() { All = restore }()
+	All = []Info{
+		{Name: "stable", Description: "on by default", Default: true},
+		{Name: "preview", Description: "off by default"},
+	}
+
+	s, unknown := New(map[string]bool{"preview": true, "stable": false, "retired": true, "daemon": false})
+	if !s.Enabled("preview") {
+		t.Error("expected preview to be turned on")
+	}
+	if s.Enabled("stable") {
+		t.Error("expected stable to be turned off")
+	}
+	if s.Enabled("retired") {
+		t.Error("expected unknown feature to be off")
+	}
+	if expected := []string{"daemon", "retired"}; !slices.Equal(unknown, expected) {
+		t.Errorf("expected unknown features %v, got %v", expected, unknown)
+	}
+
+	var defaults Set
+	if !defaults.Enabled("stable") || defaults.Enabled("preview") {
+		t.Error("expected the zero Set to use the defaults")
+	}
+}
+
+func TestWriteReport(t *testing.T) {
+	s, _ := New(map[string]bool{string(Watch): false})
+
+	var b strings.Builder
+	if err := s.WriteReport(&b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(b.String(), "watch  off") {
+		t.Errorf("expected watch to be listed as off, got %q", b.String())
+	}
+}
@@ -0,0 +1,71 @@
+// Package fileops implements filesystem-modifying operations triggered from
+// the UI, such as changing permissions or removing broken links, and the
+// free-space checks that guard them.
+package fileops
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Chmod sets the permission bits of path to perm.
+//
+// When recursive is true, the same permissions are applied to every file and
+// directory below path, like chmod -R. Symbolic links are never followed or
+// modified.
+//
+// Parameters:
+//   - path: the file or directory to modify
+//   - perm: the permission bits to set (only fs.ModePerm bits are used)
+//   - recursive: whether to apply perm to the whole subtree
+//
+// Returns the first error encountered. Entries processed before the error keep
+// their new permissions; if the subtree cannot be fully read, nothing is changed.
+func Chmod(path string, perm fs.FileMode, recursive bool) error {
+	perm &= fs.ModePerm
+
+	if !recursive {
+		return os.Chmod(path, perm)
+	}
+
+	// Collect the subtree first and apply permissions deepest-first, so
+	// removing read or execute bits from a directory cannot prevent
+	// reaching its children
+	var paths []string
+	err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type()&fs.ModeSymlink == 0 {
+			paths = append(paths, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := len(paths) - 1; i >= 0; i-- {
+		if err := os.Chmod(paths[i], perm); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveSymlink deletes the symbolic link at path without touching its target.
+//
+// Returns an error if path is not a symbolic link, so a regular file or
+// directory that replaced the link is never deleted by mistake.
+func RemoveSymlink(path string) error {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return err
+	}
+	if info.Mode()&fs.ModeSymlink == 0 {
+		return fmt.Errorf("%s is not a symbolic link", path)
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,118 @@
+package fileops
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestChmod(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permission bits are not supported on Windows")
+	}
+
+	tempDir, err := os.MkdirTemp("", "fileops-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	root := filepath.Join(tempDir, "root")
+	nested := filepath.Join(root, "nested")
+	file := filepath.Join(nested, "file.txt")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatalf("failed to create test dirs: %v", err)
+	}
+	if err := os.WriteFile(file, []byte("x"), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	if err := os.Symlink(file, filepath.Join(root, "link")); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+
+	assertPerm := func(path string, want fs.FileMode) {
+		t.Helper()
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("failed to stat %s: %v", path, err)
+		}
+		if got := info.Mode().Perm(); got != want {
+			t.Errorf("%s: expected %v, got %v", path, want, got)
+		}
+	}
+
+	t.Run("non-recursive", func(t *testing.T) {
+		if err := Chmod(root, 0700, false); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assertPerm(root, 0700)
+		assertPerm(nested, 0755)
+	})
+
+	t.Run("recursive", func(t *testing.T) {
+		if err := Chmod(root, 0750, true); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assertPerm(root, 0750)
+		assertPerm(nested, 0750)
+		assertPerm(file, 0750)
+	})
+
+	t.Run("recursive without directory access", func(t *testing.T) {
+		if err := Chmod(root, 0600, true); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// Restore access to inspect the children
+		if err := os.Chmod(root, 0700); err != nil {
+			t.Fatalf("failed to restore root: %v", err)
+		}
+		if err := os.Chmod(nested, 0700); err != nil {
+			t.Fatalf("failed to restore nested: %v", err)
+		}
+		assertPerm(file, 0600)
+	})
+
+	t.Run("ignores non-permission bits", func(t *testing.T) {
+		if err := Chmod(file, fs.ModeDir|0600, false); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assertPerm(file, 0600)
+	})
+}
+
+func TestRemoveSymlink(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("creating symlinks requires privileges on Windows")
+	}
+
+	tempDir, err := os.MkdirTemp("", "fileops-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	file := filepath.Join(tempDir, "file.txt")
+	link := filepath.Join(tempDir, "link")
+	if err := os.WriteFile(file, []byte("x"), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	if err := os.Symlink(file, link); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+
+	if err := RemoveSymlink(file); err == nil {
+		t.Error("expected error when removing a regular file, got nil")
+	}
+
+	if err := RemoveSymlink(link); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Lstat(link); !os.IsNotExist(err) {
+		t.Error("expected link to be removed")
+	}
+	if _, err := os.Stat(file); err != nil {
+		t.Errorf("expected link target to remain: %v", err)
+	}
+}
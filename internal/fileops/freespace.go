@@ -0,0 +1,46 @@
+package fileops
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnsupported is returned by FreeSpace on platforms where the available
+// disk space cannot be queried.
+var ErrUnsupported = errors.New("free space is not available on this platform")
+
+// ErrInsufficientSpace is returned by EnsureFreeSpace when the destination
+// filesystem cannot hold the data about to be written.
+var ErrInsufficientSpace = errors.New("not enough free space")
+
+// FreeSpace returns the number of bytes available to the current user on the
+// filesystem containing path.
+func FreeSpace(path string) (int64, error) {
+	return freeSpace(path)
+}
+
+// EnsureFreeSpace checks that the filesystem containing dir has at least need
+// bytes available. Operations that write a known amount of data (copying,
+// extracting) call it before starting, so they fail early instead of leaving
+// a partial result behind.
+//
+// Parameters:
+//   - dir: a directory on the destination filesystem
+//   - need: the number of bytes that will be written
+//
+// Returns ErrInsufficientSpace (wrapped with the amounts involved) if the
+// space is insufficient. Platforms where free space cannot be queried pass
+// the check.
+func EnsureFreeSpace(dir string, need int64) error {
+	free, err := FreeSpace(dir)
+	if errors.Is(err, ErrUnsupported) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("cannot check free space: %w", err)
+	}
+	if free < need {
+		return fmt.Errorf("%w: need %d bytes, %d available", ErrInsufficientSpace, need, free)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package fileops
+
+import (
+	"errors"
+	"math"
+	"os"
+	"testing"
+)
+
+func TestFreeSpace(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "fileops-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	free, err := FreeSpace(tempDir)
+	if errors.Is(err, ErrUnsupported) {
+		t.Skip("free space is not available on this platform")
+	}
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if free <= 0 {
+		t.Errorf("expected positive free space, got %d", free)
+	}
+
+	if _, err := FreeSpace(tempDir + "/missing"); err == nil {
+		t.Error("expected error for missing path, got nil")
+	}
+}
+
+func TestEnsureFreeSpace(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "fileops-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	if _, err := FreeSpace(tempDir); errors.Is(err, ErrUnsupported) {
+		t.Skip("free space is not available on this platform")
+	}
+
+	if err := EnsureFreeSpace(tempDir, 1); err != nil {
+		t.Errorf("expected 1 byte to fit, got %v", err)
+	}
+	if err := EnsureFreeSpace(tempDir, math.MaxInt64); !errors.Is(err, ErrInsufficientSpace) {
+		t.Errorf("expected ErrInsufficientSpace, got %v", err)
+	}
+}
@@ -0,0 +1,14 @@
+//go:build linux || darwin || freebsd
+
+package fileops
+
+import "syscall"
+
+func freeSpace(path string) (int64, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return 0, err
+	}
+	// Bavail excludes blocks reserved for the superuser
+	return int64(uint64(st.Bavail) * uint64(st.Bsize)), nil
+}
@@ -0,0 +1,17 @@
+//go:build windows
+
+package fileops
+
+import "golang.org/x/sys/windows"
+
+func freeSpace(path string) (int64, error) {
+	p, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, err
+	}
+	var available, total, totalFree uint64
+	if err := windows.GetDiskFreeSpaceEx(p, &available, &total, &totalFree); err != nil {
+		return 0, err
+	}
+	return int64(available), nil
+}
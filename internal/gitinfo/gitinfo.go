@@ -0,0 +1,86 @@
+// Package gitinfo reads information about the git repository containing a
+// directory without running git.
+package gitinfo
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// shortHashLen is the number of hash characters shown for a detached HEAD.
+const shortHashLen = 7
+
+// Branch returns the name of the branch checked out in the repository
+// containing dir, or the abbreviated commit hash if HEAD is detached.
+//
+// Returns an empty string and no error if dir is not inside a repository.
+func Branch(dir string) (string, error) {
+	gitDir, err := findGitDir(dir)
+	if err != nil || gitDir == "" {
+		return "", err
+	}
+
+	head, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return "", fmt.Errorf("failed to read HEAD: %w", err)
+	}
+
+	ref := strings.TrimSpace(string(head))
+	if name, ok := strings.CutPrefix(ref, "ref: "); ok {
+		return strings.TrimPrefix(name, "refs/heads/"), nil
+	}
+	if len(ref) > shortHashLen {
+		ref = ref[:shortHashLen]
+	}
+	return ref, nil
+}
+
+// findGitDir returns the git directory of the repository containing dir,
+// following the "gitdir:" file used by worktrees and submodules.
+//
+// Returns an empty string if dir is not inside a repository.
+func findGitDir(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		candidate := filepath.Join(dir, ".git")
+		info, err := os.Stat(candidate)
+		switch {
+		case err == nil && info.IsDir():
+			return candidate, nil
+		case err == nil:
+			return readGitFile(candidate)
+		case !errors.Is(err, os.ErrNotExist):
+			return "", err
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// readGitFile resolves a .git file of the form "gitdir: <path>".
+func readGitFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir: ")
+	if !ok {
+		return "", fmt.Errorf("invalid git file %s", path)
+	}
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(filepath.Dir(path), gitDir)
+	}
+	return gitDir, nil
+}
@@ -0,0 +1,79 @@
+package gitinfo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func makeRepo(t *testing.T, head string) string {
+	t.Helper()
+	dir, err := os.MkdirTemp("", "gitinfo-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	if err := os.MkdirAll(filepath.Join(dir, ".git"), 0755); err != nil {
+		t.Fatalf("failed to create .git: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".git", "HEAD"), []byte(head), 0644); err != nil {
+		t.Fatalf("failed to write HEAD: %v", err)
+	}
+	return dir
+}
+
+func TestBranch(t *testing.T) {
+	repo := makeRepo(t, "ref: refs/heads/feature/login\n")
+	sub := filepath.Join(repo, "internal", "app")
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatalf("failed to create subdirectory: %v", err)
+	}
+
+	branch, err := Branch(sub)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if branch != "feature/login" {
+		t.Errorf("expected feature/login, got %q", branch)
+	}
+}
+
+func TestBranch_Detached(t *testing.T) {
+	repo := makeRepo(t, "8d8e1e7a1b2c3d4e5f60718293a4b5c6d7e8f901\n")
+
+	branch, err := Branch(repo)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if branch != "8d8e1e7" {
+		t.Errorf("expected abbreviated hash 8d8e1e7, got %q", branch)
+	}
+}
+
+func TestBranch_Worktree(t *testing.T) {
+	main := makeRepo(t, "ref: refs/heads/main\n")
+	gitDir := filepath.Join(main, ".git", "worktrees", "wt")
+	if err := os.MkdirAll(gitDir, 0755); err != nil {
+		t.Fatalf("failed to create worktree git dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(gitDir, "HEAD"), []byte("ref: refs/heads/hotfix\n"), 0644); err != nil {
+		t.Fatalf("failed to write HEAD: %v", err)
+	}
+
+	wt := filepath.Join(main, "wt")
+	if err := os.MkdirAll(wt, 0755); err != nil {
+		t.Fatalf("failed to create worktree: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(wt, ".git"), []byte("gitdir: "+gitDir+"\n"), 0644); err != nil {
+		t.Fatalf("failed to write .git file: %v", err)
+	}
+
+	branch, err := Branch(wt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if branch != "hotfix" {
+		t.Errorf("expected hotfix, got %q", branch)
+	}
+}
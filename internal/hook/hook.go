@@ -0,0 +1,72 @@
+// Package hook runs commands the user configures to be told about events,
+// such as a directory outgrowing its size limit, e.g. to show a desktop
+// notification with notify-send.
+//
+// The command is run through the system shell with the details of the event
+// in environment variables, so the same command works for every event and
+// can pick the details it needs: "notify-send folder-search
+// \"$FOLDER_SEARCH_MESSAGE\"".
+package hook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kaczmarekdaniel/folder-search/internal/shell"
+)
+
+const (
+	// DefaultTimeout bounds how long a hook command may run
+	DefaultTimeout = 10 * time.Second
+
+	// MessageVar holds a one-line description of the event, suitable for
+	// showing to the user as is
+	MessageVar = "FOLDER_SEARCH_MESSAGE"
+
+	// EventVar holds the kind of event, such as "alert" or "job"
+	EventVar = "FOLDER_SEARCH_EVENT"
+
+	// maxOutput is the number of output bytes of a failing command kept in
+	// its error
+	maxOutput = 512
+)
+
+// Run runs command through the system shell with env added to its
+// environment and waits for it to finish.
+//
+// Parameters:
+//   - ctx: cancels the command; a DefaultTimeout deadline is added
+//   - command: the shell command line
+//   - env: variables set for the command on top of the environment of the
+//     process, such as MessageVar
+//
+// Returns an error holding the start of the command's output if it cannot
+// be started, fails or times out.
+func Run(ctx context.Context, command string, env map[string]string) error {
+	ctx, cancel := context.WithTimeout(ctx, DefaultTimeout)
+	defer cancel()
+
+	cmd := shell.Command(ctx, command)
+	cmd.Env = os.Environ()
+	for _, name := range slices.Sorted(maps.Keys(env)) {
+		cmd.Env = append(cmd.Env, name+"="+env[name])
+	}
+
+	out, err := cmd.CombinedOutput()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("hook command timed out after %s", DefaultTimeout)
+	}
+	if err != nil {
+		if output := strings.TrimSpace(string(out[:min(len(out), maxOutput)])); output != "" {
+			return fmt.Errorf("hook command failed: %w: %s", err, output)
+		}
+		return fmt.Errorf("hook command failed: %w", err)
+	}
+	return nil
+}
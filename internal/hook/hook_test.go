@@ -0,0 +1,43 @@
+package hook
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test commands use a POSIX shell")
+	}
+
+	dir, err := os.MkdirTemp("", "hook-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	out := filepath.Join(dir, "out")
+	env := map[string]string{MessageVar: "Downloads holds 21 GiB", EventVar: "alert"}
+	if err := Run(context.Background(), `printf '%s %s' "$FOLDER_SEARCH_EVENT" "$FOLDER_SEARCH_MESSAGE" > `+out, env); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	if got := string(data); got != "alert Downloads holds 21 GiB" {
+		t.Errorf("expected the event and message, got %q", got)
+	}
+
+	err = Run(context.Background(), "echo no notifier >&2; exit 3", nil)
+	if err == nil {
+		t.Fatal("expected error for a failing command, got nil")
+	}
+	if !strings.Contains(err.Error(), "no notifier") {
+		t.Errorf("expected the error to hold the command output, got %v", err)
+	}
+}
@@ -0,0 +1,143 @@
+// Package ignorefile reads and writes per-project ignore files.
+//
+// A project ignore file is named .folder-search-ignore and lists one
+// directory name or shell pattern per line, like the "ignore" setting of the
+// configuration. Blank lines and lines starting with "#" are skipped. The
+// file applies to the directory it is in and everything below it; the
+// nearest file wins, so nested projects can have their own.
+package ignorefile
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/kaczmarekdaniel/folder-search/internal/dirsearch"
+)
+
+// Name is the file name of project ignore files.
+const Name = ".folder-search-ignore"
+
+// Rule is a pattern of an ignore file and the line it is on.
+type Rule struct {
+	Pattern string
+	Line    int
+}
+
+// Find returns the path of the ignore file applying to dir: the one in dir
+// or its nearest ancestor. Returns "" if there is none.
+func Find(dir string) string {
+	for {
+		path := filepath.Join(dir, Name)
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+			return path
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// ProjectRoot returns the directory whose ignore file applies to dir, or
+// should be created for it: the nearest directory that has an ignore file
+// or is a git repository, or dir itself if there is none.
+func ProjectRoot(dir string) string {
+	for d := dir; ; {
+		for _, marker := range []string{Name, ".git"} {
+			if _, err := os.Stat(filepath.Join(d, marker)); err == nil {
+				return d
+			}
+		}
+		parent := filepath.Dir(d)
+		if parent == d {
+			return dir
+		}
+		d = parent
+	}
+}
+
+// Load returns the patterns of the ignore file applying to dir, or nil if
+// there is none.
+//
+// Returns an error if the file cannot be read or holds a malformed pattern.
+func Load(dir string) ([]string, error) {
+	path := Find(dir)
+	if path == "" {
+		return nil, nil
+	}
+	return Read(path)
+}
+
+// Read returns the patterns listed in the ignore file at path.
+//
+// Returns an error if the file cannot be read or holds a malformed pattern.
+func Read(path string) ([]string, error) {
+	rules, err := ReadRules(path)
+	if err != nil {
+		return nil, err
+	}
+	var patterns []string
+	for _, r := range rules {
+		patterns = append(patterns, r.Pattern)
+	}
+	return patterns, nil
+}
+
+// ReadRules is like Read but also returns the line of each pattern.
+func ReadRules(path string) ([]Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ignore file: %w", err)
+	}
+
+	var rules []Rule
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		pattern := strings.TrimSpace(scanner.Text())
+		if pattern == "" || strings.HasPrefix(pattern, "#") {
+			continue
+		}
+		if err := dirsearch.CheckIgnorePattern(pattern); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q at %s:%d: %w", pattern, path, line, err)
+		}
+		rules = append(rules, Rule{Pattern: pattern, Line: line})
+	}
+	return rules, scanner.Err()
+}
+
+// Append adds pattern to the ignore file at path, creating the file if it
+// does not exist. Adding a pattern that is already listed does nothing.
+//
+// Returns an error if the file cannot be read or written.
+func Append(path, pattern string) error {
+	patterns, err := Read(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if slices.Contains(patterns, pattern) {
+		return nil
+	}
+
+	// Start on a new line if the file does not end with one
+	var prefix string
+	if data, err := os.ReadFile(path); err == nil && len(data) > 0 && data[len(data)-1] != '\n' {
+		prefix = "\n"
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open ignore file: %w", err)
+	}
+	if _, err := f.WriteString(prefix + pattern + "\n"); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write ignore file: %w", err)
+	}
+	return f.Close()
+}
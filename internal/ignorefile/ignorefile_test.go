@@ -0,0 +1,108 @@
+package ignorefile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func makeDirs(t *testing.T, dirs ...string) string {
+	t.Helper()
+	root, err := os.MkdirTemp("", "ignorefile-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	for _, dir := range dirs {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", dir, err)
+		}
+	}
+	return root
+}
+
+func TestLoad(t *testing.T) {
+	root := makeDirs(t, "project/src/pkg", "other")
+	defer os.RemoveAll(root)
+
+	content := "# generated output\nbuild-*\n\n  dist  \n"
+	if err := os.WriteFile(filepath.Join(root, "project", Name), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write ignore file: %v", err)
+	}
+
+	patterns, err := Load(filepath.Join(root, "project/src/pkg"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(patterns, ",") != "build-*,dist" {
+		t.Errorf("expected [build-* dist], got %v", patterns)
+	}
+
+	patterns, err = Load(filepath.Join(root, "other"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if patterns != nil {
+		t.Errorf("expected no patterns outside the project, got %v", patterns)
+	}
+}
+
+func TestLoad_InvalidPattern(t *testing.T) {
+	root := makeDirs(t)
+	defer os.RemoveAll(root)
+
+	if err := os.WriteFile(filepath.Join(root, Name), []byte("ok\n[abc\n"), 0644); err != nil {
+		t.Fatalf("failed to write ignore file: %v", err)
+	}
+	_, err := Load(root)
+	if err == nil || !strings.Contains(err.Error(), ":2:") {
+		t.Errorf("expected error pointing at line 2, got %v", err)
+	}
+}
+
+func TestProjectRoot(t *testing.T) {
+	root := makeDirs(t, "repo/.git", "repo/a/b", "loose/c")
+	defer os.RemoveAll(root)
+
+	if got := ProjectRoot(filepath.Join(root, "repo/a/b")); got != filepath.Join(root, "repo") {
+		t.Errorf("expected repository root, got %s", got)
+	}
+	// The temp dir is outside any repository
+	if dir := filepath.Join(root, "loose/c"); ProjectRoot(dir) != dir {
+		t.Errorf("expected the directory itself, got %s", ProjectRoot(dir))
+	}
+}
+
+func TestAppend(t *testing.T) {
+	root := makeDirs(t)
+	defer os.RemoveAll(root)
+
+	path := filepath.Join(root, Name)
+	if err := os.WriteFile(path, []byte("# notes\nvendor"), 0644); err != nil {
+		t.Fatalf("failed to write ignore file: %v", err)
+	}
+	for _, pattern := range []string{"tmp-*", "vendor", "tmp-*"} {
+		if err := Append(path, pattern); err != nil {
+			t.Fatalf("unexpected error appending %q: %v", pattern, err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read ignore file: %v", err)
+	}
+	if expected := "# notes\nvendor\ntmp-*\n"; string(data) != expected {
+		t.Errorf("expected %q, got %q", expected, string(data))
+	}
+
+	created := filepath.Join(root, "new", Name)
+	if err := os.MkdirAll(filepath.Dir(created), 0755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	if err := Append(created, "dist"); err != nil {
+		t.Fatalf("unexpected error creating ignore file: %v", err)
+	}
+	if patterns, err := Read(created); err != nil || len(patterns) != 1 || patterns[0] != "dist" {
+		t.Errorf("expected [dist], got %v (%v)", patterns, err)
+	}
+}
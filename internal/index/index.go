@@ -0,0 +1,198 @@
+// Package index keeps a persistent list of the directories under a root, so
+// searching a large tree such as the home directory does not walk it again
+// every time.
+//
+// An index is built by walking the root once and saved to the user cache
+// directory (e.g. ~/.cache/folder-search/index on Linux), one file per root.
+// Searches are then answered from the saved list with the same matching
+// rules as a live search. An index does not notice new directories: callers
+// rebuild it once it is older than they accept, see Index.Stale. Matches
+// that were deleted since the index was built are left out of results and
+// mark the index outdated.
+package index
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kaczmarekdaniel/folder-search/internal/dirsearch"
+	"github.com/kaczmarekdaniel/folder-search/internal/statefile"
+)
+
+const (
+	// DefaultMaxAge is how old an index may get before it is rebuilt
+	DefaultMaxAge = 24 * time.Hour
+
+	// schemaVersion is the current version of the index file format
+	schemaVersion = 1
+)
+
+// Index is the list of directories under a root.
+type Index struct {
+	// Root is the absolute path of the indexed directory
+	Root string `json:"root"`
+
+	// Built is when the root was walked
+	Built time.Time `json:"built"`
+
+	// Ignore is the ignore list the root was walked with; ignored
+	// directories and their subtrees are not in the index
+	Ignore []string `json:"ignore"`
+
+	// Dirs holds every directory under Root, relative to it, in lexical order.
+	// Hidden directories are included unless ignored.
+	Dirs []string `json:"dirs"`
+
+	// Outdated is set when a search found indexed directories that no
+	// longer exist; Stale then reports the index for rebuilding
+	Outdated bool `json:"outdated,omitempty"`
+}
+
+// DefaultDir returns the directory in which indexes are stored.
+func DefaultDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot locate cache directory: %w", err)
+	}
+	return filepath.Join(dir, "folder-search", "index"), nil
+}
+
+// fileName returns the name of the index file of root.
+func fileName(root string) string {
+	sum := sha256.Sum256([]byte(root))
+	return hex.EncodeToString(sum[:8]) + ".json"
+}
+
+// Build walks root and returns its index.
+//
+// Parameters:
+//   - ctx: cancels the walk
+//   - root: the directory to index
+//   - ignore: directory names skipped together with their subtrees
+//
+// Returns an error if root cannot be read or ctx is canceled.
+func Build(ctx context.Context, root string, ignore []string) (*Index, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := dirsearch.DefaultOptions()
+	opts.StartDir = root
+	opts.IgnorePatterns = ignore
+	opts.Hidden = dirsearch.HiddenShow
+	dirs, _, err := dirsearch.FindDirs(ctx, opts, 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to index %s: %w", root, err)
+	}
+	return &Index{Root: root, Built: time.Now(), Ignore: slices.Clone(ignore), Dirs: dirs}, nil
+}
+
+// Save writes the index to dir, replacing an earlier index of the same root.
+func (ix *Index) Save(dir string) error {
+	if err := statefile.Save(filepath.Join(dir, fileName(ix.Root)), schemaVersion, ix); err != nil {
+		return fmt.Errorf("failed to save index: %w", err)
+	}
+	return nil
+}
+
+// Load reads the index of root from dir.
+//
+// Returns nil without an error if root has not been indexed.
+func Load(dir, root string) (*Index, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var ix Index
+	ok, err := statefile.Load(filepath.Join(dir, fileName(root)), schemaVersion, nil, &ix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load index: %w", err)
+	}
+	// Different roots could share a file name in theory, so check
+	if !ok || ix.Root != root {
+		return nil, nil
+	}
+	return &ix, nil
+}
+
+// Lookup returns the index covering root: the index of root itself or of
+// its nearest indexed parent.
+//
+// Returns nil without an error if neither root nor any parent is indexed.
+func Lookup(dir, root string) (*Index, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	for d := root; ; {
+		ix, err := Load(dir, d)
+		if ix != nil || err != nil {
+			return ix, err
+		}
+		parent := filepath.Dir(d)
+		if parent == d {
+			return nil, nil
+		}
+		d = parent
+	}
+}
+
+// Stale reports whether the index is older than maxAge at now, was built
+// with a different ignore list than ignore, or is Outdated.
+func (ix *Index) Stale(now time.Time, maxAge time.Duration, ignore []string) bool {
+	return ix.Outdated || now.Sub(ix.Built) > maxAge || !slices.Equal(ix.Ignore, ignore)
+}
+
+// Search answers a search from the index instead of walking opts.StartDir,
+// which must be Root or a directory below it.
+//
+// The result holds the same directories a live search with opts would find
+// when the index was built, provided opts.IgnorePatterns contains the
+// ignore list of the index, less those deleted since then: every match is
+// checked on disk, and finding a deleted one sets Outdated. Paths are
+// relative to opts.StartDir.
+func (ix *Index) Search(ctx context.Context, opts *dirsearch.Options) dirsearch.Result {
+	start, err := filepath.Abs(opts.StartDir)
+	if err != nil {
+		return dirsearch.Result{Directories: []string{}, Error: err}
+	}
+	rel, err := filepath.Rel(ix.Root, start)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return dirsearch.Result{Directories: []string{}, Error: fmt.Errorf("%s is not indexed by %s", start, ix.Root)}
+	}
+
+	dirs := ix.Dirs
+	if rel != "." {
+		prefix := rel + string(filepath.Separator)
+		dirs = nil
+		for _, d := range ix.Dirs {
+			if strings.HasPrefix(d, prefix) {
+				dirs = append(dirs, d[len(prefix):])
+			}
+		}
+	}
+
+	local := *opts
+	local.StartDir = start
+	result := dirsearch.SearchPaths(ctx, &local, dirs)
+	result.Keep(func(i int) bool {
+		_, err := os.Lstat(filepath.Join(start, result.Directories[i]))
+		if errors.Is(err, fs.ErrNotExist) {
+			ix.Outdated = true
+			return false
+		}
+		return true
+	})
+	return result
+}
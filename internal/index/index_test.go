@@ -0,0 +1,174 @@
+package index
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/kaczmarekdaniel/folder-search/internal/dirsearch"
+)
+
+// makeTree creates the given directories under a new temporary root.
+func makeTree(t *testing.T, dirs ...string) string {
+	t.Helper()
+	root, err := os.MkdirTemp("", "index-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(root) })
+	for _, dir := range dirs {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", dir, err)
+		}
+	}
+	return root
+}
+
+func TestBuild(t *testing.T) {
+	root := makeTree(t, "src/app", ".config", ".git/objects", "node_modules/pkg")
+
+	ix, err := Build(context.Background(), root, dirsearch.DefaultOptions().IgnorePatterns)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{".config", "src", filepath.Join("src", "app")}
+	if !slices.Equal(ix.Dirs, expected) {
+		t.Errorf("expected %v, got %v", expected, ix.Dirs)
+	}
+	if ix.Root != root {
+		t.Errorf("expected root %s, got %s", root, ix.Root)
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	root := makeTree(t, "a/b")
+	dir := makeTree(t)
+
+	if ix, err := Load(dir, root); err != nil || ix != nil {
+		t.Fatalf("expected no index before saving, got %v (%v)", ix, err)
+	}
+
+	ix, err := Build(context.Background(), root, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := ix.Save(dir); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+
+	loaded, err := Load(dir, root)
+	if err != nil || loaded == nil {
+		t.Fatalf("expected saved index, got %v (%v)", loaded, err)
+	}
+	if !slices.Equal(loaded.Dirs, ix.Dirs) {
+		t.Errorf("expected %v, got %v", ix.Dirs, loaded.Dirs)
+	}
+
+	// A subdirectory is served by the index of its parent
+	found, err := Lookup(dir, filepath.Join(root, "a"))
+	if err != nil || found == nil || found.Root != root {
+		t.Errorf("expected lookup to find index of %s, got %v (%v)", root, found, err)
+	}
+	if found, _ := Lookup(dir, filepath.Dir(root)); found != nil {
+		t.Errorf("expected no index above root, got %s", found.Root)
+	}
+}
+
+func TestStale(t *testing.T) {
+	now := time.Now()
+	ix := &Index{Built: now.Add(-2 * time.Hour), Ignore: []string{"node_modules"}}
+
+	if ix.Stale(now, 3*time.Hour, []string{"node_modules"}) {
+		t.Error("expected fresh index")
+	}
+	if !ix.Stale(now, time.Hour, []string{"node_modules"}) {
+		t.Error("expected index older than max age to be stale")
+	}
+	if !ix.Stale(now, 3*time.Hour, nil) {
+		t.Error("expected index with different ignore list to be stale")
+	}
+}
+
+func TestSearch_MatchesLiveSearch(t *testing.T) {
+	root := makeTree(t, "api/v1", "web/api-client", "web/.cache/api", "tools", "node_modules/api")
+	ignore := []string{"node_modules"}
+
+	ix, err := Build(context.Background(), root, ignore)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, start := range []string{root, filepath.Join(root, "web")} {
+		for _, pattern := range []string{"", "api"} {
+			opts := dirsearch.DefaultOptions()
+			opts.StartDir = start
+			opts.SearchPattern = pattern
+			opts.MaxDepth = dirsearch.UnlimitedDepth
+			opts.IgnorePatterns = ignore
+
+			want := dirsearch.Search(opts)
+			got := ix.Search(context.Background(), opts)
+			if got.Error != nil {
+				t.Fatalf("unexpected error: %v", got.Error)
+			}
+			if !slices.Equal(got.Directories, want.Directories) {
+				t.Errorf("%s %q: expected %v, got %v", start, pattern, want.Directories, got.Directories)
+			}
+		}
+	}
+}
+
+func TestSearch_OutsideRoot(t *testing.T) {
+	root := makeTree(t, "a")
+	ix := &Index{Root: filepath.Join(root, "a")}
+
+	opts := dirsearch.DefaultOptions()
+	opts.StartDir = root
+	if result := ix.Search(context.Background(), opts); result.Error == nil {
+		t.Error("expected error for directory outside the index, got nil")
+	}
+}
+
+func TestSearch_DeletedDirectory(t *testing.T) {
+	root := makeTree(t, "api/v1", "web/api-client")
+	dir := makeTree(t)
+
+	ix, err := Build(context.Background(), root, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := os.RemoveAll(filepath.Join(root, "web")); err != nil {
+		t.Fatalf("failed to remove web: %v", err)
+	}
+
+	opts := dirsearch.DefaultOptions()
+	opts.StartDir = root
+	opts.SearchPattern = "api"
+	opts.MaxDepth = dirsearch.UnlimitedDepth
+	result := ix.Search(context.Background(), opts)
+	if result.Error != nil {
+		t.Fatalf("unexpected error: %v", result.Error)
+	}
+	if expected := []string{"api"}; !slices.Equal(result.Directories, expected) {
+		t.Errorf("expected %v, got %v", expected, result.Directories)
+	}
+
+	// The index is rebuilt by the next search, even if it is saved first
+	if !ix.Outdated {
+		t.Error("expected the index to be outdated")
+	}
+	if err := ix.Save(dir); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+	loaded, err := Load(dir, root)
+	if err != nil || loaded == nil {
+		t.Fatalf("failed to load: %v", err)
+	}
+	if !loaded.Stale(loaded.Built, DefaultMaxAge, nil) {
+		t.Error("expected the saved index to be stale")
+	}
+}
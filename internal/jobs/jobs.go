@@ -0,0 +1,290 @@
+// Package jobs runs long-running operations in the background.
+//
+// A Queue executes submitted jobs on a bounded number of workers, tracks their
+// progress and allows canceling individual jobs without affecting the others.
+// Consumers such as the UI poll Snapshot after receiving a notification on the
+// Updates channel.
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Status describes the lifecycle stage of a job.
+type Status int
+
+const (
+	// Pending jobs are waiting for a free worker.
+	Pending Status = iota
+
+	// Running jobs are currently executing.
+	Running
+
+	// Done jobs finished successfully.
+	Done
+
+	// Failed jobs returned an error.
+	Failed
+
+	// Canceled jobs were stopped before finishing.
+	Canceled
+)
+
+// String returns a short human-readable name of the status.
+func (s Status) String() string {
+	switch s {
+	case Pending:
+		return "pending"
+	case Running:
+		return "running"
+	case Done:
+		return "done"
+	case Failed:
+		return "failed"
+	case Canceled:
+		return "canceled"
+	default:
+		return "unknown"
+	}
+}
+
+// Finished reports whether the job has reached a terminal status.
+func (s Status) Finished() bool {
+	return s == Done || s == Failed || s == Canceled
+}
+
+// ReportFunc is called by a running job to publish its progress.
+// A total of zero means the amount of work is not known in advance.
+type ReportFunc func(done, total int64)
+
+// Func is the work performed by a job.
+//
+// It must return promptly once ctx is canceled. The returned string is a short
+// summary of the result shown to the user (e.g. "1.2 GB").
+type Func func(ctx context.Context, report ReportFunc) (string, error)
+
+// Info is a point-in-time copy of a job's state.
+type Info struct {
+	// ID uniquely identifies the job within its queue
+	ID int
+
+	// Name is the human-readable description given on submission
+	Name string
+
+	// Status is the current lifecycle stage
+	Status Status
+
+	// Done and Total describe progress; Total is zero when unknown
+	Done  int64
+	Total int64
+
+	// Result is the summary returned by a successful job
+	Result string
+
+	// Err is the error returned by a failed job
+	Err error
+
+	// Started is when the job started running and Ended when it finished;
+	// each is zero until then. Jobs canceled while pending never start.
+	Started, Ended time.Time
+}
+
+// Duration returns how long the job ran, or has been running so far, or
+// zero if it never started.
+func (i Info) Duration() time.Duration {
+	switch {
+	case i.Started.IsZero():
+		return 0
+	case i.Ended.IsZero():
+		return time.Since(i.Started)
+	}
+	return i.Ended.Sub(i.Started)
+}
+
+// Percent returns the completed fraction in the range [0, 1], or -1 if the
+// total amount of work is unknown.
+func (i Info) Percent() float64 {
+	if i.Total <= 0 {
+		return -1
+	}
+	if i.Done >= i.Total {
+		return 1
+	}
+	return float64(i.Done) / float64(i.Total)
+}
+
+type job struct {
+	info   Info
+	fn     Func
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// Queue executes jobs in the background with bounded concurrency.
+type Queue struct {
+	mu      sync.Mutex
+	jobs    []*job
+	nextID  int
+	sem     chan struct{}
+	updates chan struct{}
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// NewQueue creates a queue that runs at most workers jobs at the same time.
+// A non-positive workers value is treated as one.
+func NewQueue(workers int) *Queue {
+	if workers < 1 {
+		workers = 1
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Queue{
+		sem:     make(chan struct{}, workers),
+		updates: make(chan struct{}, 1),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// Updates returns a channel that receives a value whenever the state of any
+// job changes. Notifications are coalesced, so a receiver should call
+// Snapshot to read the current state.
+func (q *Queue) Updates() <-chan struct{} {
+	return q.updates
+}
+
+// Submit adds a job to the queue and returns its ID.
+//
+// Parameters:
+//   - name: human-readable description of the job
+//   - fn: the work to perform
+func (q *Queue) Submit(name string, fn Func) int {
+	ctx, cancel := context.WithCancel(q.ctx)
+
+	q.mu.Lock()
+	q.nextID++
+	j := &job{
+		info:   Info{ID: q.nextID, Name: name, Status: Pending},
+		fn:     fn,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	q.jobs = append(q.jobs, j)
+	q.mu.Unlock()
+
+	q.wg.Add(1)
+	go q.run(j)
+	q.notify()
+
+	return j.info.ID
+}
+
+// Cancel stops the job with the given ID.
+//
+// Returns false if no such job exists or it has already finished.
+func (q *Queue) Cancel(id int) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for _, j := range q.jobs {
+		if j.info.ID == id && !j.info.Status.Finished() {
+			j.cancel()
+			return true
+		}
+	}
+	return false
+}
+
+// Snapshot returns the state of all jobs in submission order.
+func (q *Queue) Snapshot() []Info {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	infos := make([]Info, 0, len(q.jobs))
+	for _, j := range q.jobs {
+		infos = append(infos, j.info)
+	}
+	return infos
+}
+
+// Prune removes finished jobs from the queue.
+func (q *Queue) Prune() {
+	q.mu.Lock()
+	kept := q.jobs[:0]
+	for _, j := range q.jobs {
+		if !j.info.Status.Finished() {
+			kept = append(kept, j)
+		}
+	}
+	q.jobs = kept
+	q.mu.Unlock()
+
+	q.notify()
+}
+
+// Close cancels all jobs and waits for them to return.
+func (q *Queue) Close() {
+	q.cancel()
+	q.wg.Wait()
+}
+
+func (q *Queue) run(j *job) {
+	defer q.wg.Done()
+	defer j.cancel()
+
+	select {
+	case q.sem <- struct{}{}:
+		defer func() { <-q.sem }()
+	case <-j.ctx.Done():
+		q.finish(j, "", j.ctx.Err())
+		return
+	}
+
+	q.update(j, func(info *Info) {
+		info.Status = Running
+		info.Started = time.Now()
+	})
+
+	result, err := j.fn(j.ctx, func(done, total int64) {
+		q.update(j, func(info *Info) {
+			info.Done = done
+			info.Total = total
+		})
+	})
+	q.finish(j, result, err)
+}
+
+func (q *Queue) finish(j *job, result string, err error) {
+	q.update(j, func(info *Info) {
+		info.Ended = time.Now()
+		switch {
+		case errors.Is(err, context.Canceled) || (err != nil && j.ctx.Err() != nil):
+			info.Status = Canceled
+		case err != nil:
+			info.Status = Failed
+			info.Err = err
+		default:
+			info.Status = Done
+			info.Result = result
+		}
+	})
+}
+
+func (q *Queue) update(j *job, fn func(info *Info)) {
+	q.mu.Lock()
+	fn(&j.info)
+	q.mu.Unlock()
+
+	q.notify()
+}
+
+func (q *Queue) notify() {
+	select {
+	case q.updates <- struct{}{}:
+	default:
+	}
+}
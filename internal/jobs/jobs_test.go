@@ -0,0 +1,169 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// waitFor polls the queue until the job reaches a finished status.
+func waitFor(t *testing.T, q *Queue, id int) Info {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		for _, info := range q.Snapshot() {
+			if info.ID == id && info.Status.Finished() {
+				return info
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("job %d did not finish in time", id)
+	return Info{}
+}
+
+func TestQueue_Success(t *testing.T) {
+	q := NewQueue(1)
+	defer q.Close()
+
+	id := q.Submit("count", func(_ context.Context, report ReportFunc) (string, error) {
+		report(5, 10)
+		report(10, 10)
+		return "10 items", nil
+	})
+
+	info := waitFor(t, q, id)
+	if info.Status != Done {
+		t.Fatalf("expected status done, got %v", info.Status)
+	}
+	if info.Result != "10 items" {
+		t.Errorf("expected result %q, got %q", "10 items", info.Result)
+	}
+	if info.Percent() != 1 {
+		t.Errorf("expected percent 1, got %v", info.Percent())
+	}
+	if info.Name != "count" {
+		t.Errorf("expected name %q, got %q", "count", info.Name)
+	}
+}
+
+func TestQueue_Failure(t *testing.T) {
+	q := NewQueue(1)
+	defer q.Close()
+
+	boom := errors.New("boom")
+	id := q.Submit("fail", func(context.Context, ReportFunc) (string, error) {
+		return "", boom
+	})
+
+	info := waitFor(t, q, id)
+	if info.Status != Failed {
+		t.Fatalf("expected status failed, got %v", info.Status)
+	}
+	if !errors.Is(info.Err, boom) {
+		t.Errorf("expected error %v, got %v", boom, info.Err)
+	}
+}
+
+func TestQueue_CancelSingleJob(t *testing.T) {
+	q := NewQueue(2)
+	defer q.Close()
+
+	started := make(chan struct{})
+	blocking := q.Submit("blocking", func(ctx context.Context, _ ReportFunc) (string, error) {
+		close(started)
+		<-ctx.Done()
+		return "", ctx.Err()
+	})
+	other := q.Submit("other", func(context.Context, ReportFunc) (string, error) {
+		return "ok", nil
+	})
+
+	<-started
+	if !q.Cancel(blocking) {
+		t.Fatal("expected Cancel to report a running job")
+	}
+
+	if info := waitFor(t, q, blocking); info.Status != Canceled {
+		t.Errorf("expected canceled job, got %v", info.Status)
+	}
+	if info := waitFor(t, q, other); info.Status != Done {
+		t.Errorf("expected other job to finish, got %v", info.Status)
+	}
+
+	if q.Cancel(blocking) {
+		t.Error("expected Cancel on a finished job to return false")
+	}
+}
+
+func TestQueue_CancelPendingJob(t *testing.T) {
+	q := NewQueue(1)
+	defer q.Close()
+
+	release := make(chan struct{})
+	first := q.Submit("first", func(context.Context, ReportFunc) (string, error) {
+		<-release
+		return "", nil
+	})
+	// The first job must hold the only worker before the second is queued
+	for deadline := time.Now().Add(2 * time.Second); q.Snapshot()[0].Status != Running; time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("first job did not start in time")
+		}
+	}
+	pending := q.Submit("pending", func(context.Context, ReportFunc) (string, error) {
+		t.Error("canceled pending job should not run")
+		return "", nil
+	})
+
+	q.Cancel(pending)
+	info := waitFor(t, q, pending)
+	if info.Status != Canceled {
+		t.Errorf("expected canceled job, got %v", info.Status)
+	}
+	if info.Duration() != 0 {
+		t.Errorf("expected a job that never started to have no duration, got %v", info.Duration())
+	}
+
+	close(release)
+	waitFor(t, q, first)
+}
+
+func TestQueue_Prune(t *testing.T) {
+	q := NewQueue(1)
+	defer q.Close()
+
+	id := q.Submit("done", func(context.Context, ReportFunc) (string, error) {
+		return "", nil
+	})
+	waitFor(t, q, id)
+
+	q.Prune()
+	if n := len(q.Snapshot()); n != 0 {
+		t.Errorf("expected no jobs after prune, got %d", n)
+	}
+}
+
+func TestInfoPercent(t *testing.T) {
+	if p := (Info{Done: 5}).Percent(); p != -1 {
+		t.Errorf("expected -1 for unknown total, got %v", p)
+	}
+	if p := (Info{Done: 1, Total: 4}).Percent(); p != 0.25 {
+		t.Errorf("expected 0.25, got %v", p)
+	}
+}
+
+func TestInfoDuration(t *testing.T) {
+	q := NewQueue(1)
+	defer q.Close()
+
+	id := q.Submit("sleep", func(context.Context, ReportFunc) (string, error) {
+		time.Sleep(20 * time.Millisecond)
+		return "", nil
+	})
+	info := waitFor(t, q, id)
+	if d := info.Duration(); d < 20*time.Millisecond || d > time.Second {
+		t.Errorf("expected a duration of about 20ms, got %v", d)
+	}
+}
@@ -0,0 +1,200 @@
+// Package logging builds the application logger.
+//
+// Records are written as text or JSON to stderr or to a file that is rotated
+// once it grows past a size limit. Each module of the application (the
+// interface, directory search and the search index) logs through a logger
+// tagged with its name, so its level can be set separately, e.g. to debug
+// only the interface.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+// ModuleKey is the attribute naming the module a record comes from.
+const ModuleKey = "module"
+
+// Names of the modules whose level can be configured.
+const (
+	ModuleUI        = "ui"
+	ModuleDirsearch = "dirsearch"
+	ModuleIndex     = "index"
+)
+
+// Modules lists the modules whose level can be configured.
+var Modules = []string{ModuleUI, ModuleDirsearch, ModuleIndex}
+
+// Formats of the records.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Options configures the logger built by New.
+type Options struct {
+	// Format is FormatText (or empty) or FormatJSON
+	Format string
+
+	// File is the file records are appended to. Empty writes to the
+	// writer passed to New.
+	File string
+
+	// MaxSize is the size in bytes after which File is rotated. Zero or
+	// less never rotates it.
+	MaxSize int64
+
+	// MaxFiles is the number of rotated files kept next to File
+	MaxFiles int
+
+	// Level is the minimum level of records from modules without a level
+	// of their own
+	Level slog.Level
+
+	// Levels maps module names to their minimum level
+	Levels map[string]slog.Level
+
+	// Ring, if set, also receives every record logged, e.g. to include
+	// the latest records in a bug report
+	Ring *Ring
+}
+
+// New builds a logger from opts.
+//
+// Parameters:
+//   - opts: the format, destination and levels of the logger
+//   - w: the destination if opts.File is empty, usually stderr
+//
+// Returns the logger and a closer releasing its file, or an error if the
+// log file cannot be opened.
+func New(opts Options, w io.Writer) (*slog.Logger, io.Closer, error) {
+	var closer io.Closer = nopCloser{}
+	if opts.File != "" {
+		f, err := OpenRotatingFile(opts.File, opts.MaxSize, opts.MaxFiles)
+		if err != nil {
+			return nil, nil, err
+		}
+		w, closer = f, f
+	}
+	if opts.Ring != nil {
+		w = io.MultiWriter(opts.Ring, w)
+	}
+
+	// The wrapped handler accepts everything any module may log; the
+	// module handler filters by module
+	lowest := opts.Level
+	for _, level := range opts.Levels {
+		lowest = min(lowest, level)
+	}
+	handlerOpts := &slog.HandlerOptions{Level: lowest}
+
+	var handler slog.Handler
+	switch opts.Format {
+	case "", FormatText:
+		handler = slog.NewTextHandler(w, handlerOpts)
+	case FormatJSON:
+		handler = slog.NewJSONHandler(w, handlerOpts)
+	default:
+		closer.Close()
+		return nil, nil, fmt.Errorf("unknown log format %q: use %s or %s", opts.Format, FormatText, FormatJSON)
+	}
+	return slog.New(&moduleHandler{next: handler, level: opts.Level, levels: opts.Levels}), closer, nil
+}
+
+// Module returns logger tagged with the given module name, so records are
+// filtered by the level of that module.
+func Module(logger *slog.Logger, name string) *slog.Logger {
+	return logger.With(ModuleKey, name)
+}
+
+// ParseLevel parses a level name such as "debug", "info", "warn" or
+// "error", case insensitively. An empty name is info.
+func ParseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	if s == "" {
+		return slog.LevelInfo, nil
+	}
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(s))); err != nil {
+		return 0, fmt.Errorf("invalid log level %q: use debug, info, warn or error", s)
+	}
+	return level, nil
+}
+
+// contextKey is the key of the attributes stored by NewContext.
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying the given attributes, as
+// key-value pairs or slog.Attr values like the arguments of Logger.Info.
+// Records logged with ctx through a logger built by New get these
+// attributes, e.g. to correlate the records of one request across
+// goroutines.
+func NewContext(ctx context.Context, args ...any) context.Context {
+	attrs := slices.Clone(contextAttrs(ctx))
+	r := slog.Record{}
+	r.Add(args...)
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	return context.WithValue(ctx, contextKey{}, attrs)
+}
+
+// contextAttrs returns the attributes stored in ctx by NewContext.
+func contextAttrs(ctx context.Context) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+	attrs, _ := ctx.Value(contextKey{}).([]slog.Attr)
+	return attrs
+}
+
+// moduleHandler drops records below the level of the module they come from
+// and adds the attributes of their context. The module is taken from a
+// ModuleKey attribute added with Logger.With; one passed with a single
+// record does not change its level.
+type moduleHandler struct {
+	next    slog.Handler
+	level   slog.Level
+	levels  map[string]slog.Level
+	grouped bool // Attributes are inside a group, so none names the module
+}
+
+func (h *moduleHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level && h.next.Enabled(ctx, level)
+}
+
+func (h *moduleHandler) Handle(ctx context.Context, r slog.Record) error {
+	if attrs := contextAttrs(ctx); len(attrs) > 0 {
+		r = r.Clone()
+		r.AddAttrs(attrs...)
+	}
+	return h.next.Handle(ctx, r)
+}
+
+func (h *moduleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	level := h.level
+	if !h.grouped {
+		for _, a := range attrs {
+			if a.Key != ModuleKey {
+				continue
+			}
+			if l, ok := h.levels[a.Value.String()]; ok {
+				level = l
+			}
+		}
+	}
+	return &moduleHandler{next: h.next.WithAttrs(attrs), level: level, levels: h.levels, grouped: h.grouped}
+}
+
+func (h *moduleHandler) WithGroup(name string) slog.Handler {
+	return &moduleHandler{next: h.next.WithGroup(name), level: h.level, levels: h.levels, grouped: true}
+}
+
+// nopCloser is the closer of loggers that do not own their writer.
+type nopCloser struct{}
+
+func (nopCloser) Close() error { return nil }
@@ -0,0 +1,149 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNew_ModuleLevels(t *testing.T) {
+	var buf bytes.Buffer
+	logger, closer, err := New(Options{
+		Level:  slog.LevelWarn,
+		Levels: map[string]slog.Level{ModuleUI: slog.LevelDebug},
+	}, &buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer closer.Close()
+
+	logger.Info("main info")
+	logger.Warn("main warn")
+	Module(logger, ModuleUI).Debug("ui debug")
+	Module(logger, ModuleIndex).Info("index info")
+	Module(logger, ModuleIndex).Error("index error")
+
+	out := buf.String()
+	for _, want := range []string{"main warn", "ui debug", "index error", "module=ui"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got %q", want, out)
+		}
+	}
+	for _, unwanted := range []string{"main info", "index info"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("expected output not to contain %q, got %q", unwanted, out)
+		}
+	}
+}
+
+func TestNew_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	logger, closer, err := New(Options{Format: FormatJSON}, &buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer closer.Close()
+
+	Module(logger, ModuleDirsearch).Info("scan completed", "count", 3)
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("expected a JSON record, got %q: %v", buf.String(), err)
+	}
+	if record["msg"] != "scan completed" || record[ModuleKey] != ModuleDirsearch {
+		t.Errorf("expected scan completed record from dirsearch, got %v", record)
+	}
+}
+
+func TestNew_File(t *testing.T) {
+	dir, err := os.MkdirTemp("", "logging-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "logs", "folder-search.log")
+	var buf bytes.Buffer
+	logger, closer, err := New(Options{File: path}, &buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	logger.Info("to file")
+	if err := closer.Close(); err != nil {
+		t.Fatalf("unexpected error closing log: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read log: %v", err)
+	}
+	if !strings.Contains(string(data), "to file") {
+		t.Errorf("expected record in log file, got %q", data)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected nothing written to the writer, got %q", buf.String())
+	}
+}
+
+func TestNew_InvalidFormat(t *testing.T) {
+	if _, _, err := New(Options{Format: "xml"}, &bytes.Buffer{}); err == nil {
+		t.Error("expected error for unknown format, got nil")
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		in   string
+		want slog.Level
+	}{
+		{"", slog.LevelInfo},
+		{"debug", slog.LevelDebug},
+		{"INFO", slog.LevelInfo},
+		{"warn", slog.LevelWarn},
+		{"error", slog.LevelError},
+	}
+	for _, tt := range tests {
+		got, err := ParseLevel(tt.in)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.in, err)
+		} else if got != tt.want {
+			t.Errorf("%q: expected %v, got %v", tt.in, tt.want, got)
+		}
+	}
+
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Error("expected error for unknown level, got nil")
+	}
+}
+
+func TestNewContext(t *testing.T) {
+	var buf bytes.Buffer
+	logger, closer, err := New(Options{}, &buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer closer.Close()
+
+	ctx := NewContext(context.Background(), "request", 7)
+	ctx = NewContext(ctx, slog.String("dir", "/tmp"))
+	Module(logger, ModuleUI).InfoContext(ctx, "scan requested")
+	logger.Info("no context")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 records, got %q", buf.String())
+	}
+	for _, want := range []string{"request=7", "dir=/tmp", "module=ui"} {
+		if !strings.Contains(lines[0], want) {
+			t.Errorf("expected %q in record, got %q", want, lines[0])
+		}
+	}
+	if strings.Contains(lines[1], "request=") {
+		t.Errorf("expected no context attributes, got %q", lines[1])
+	}
+}
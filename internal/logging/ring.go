@@ -0,0 +1,47 @@
+package logging
+
+import (
+	"slices"
+	"strings"
+	"sync"
+)
+
+// DefaultRingSize is the number of records an application keeps in memory
+// for bug reports.
+const DefaultRingSize = 500
+
+// Ring keeps the latest records written to it in memory, dropping the
+// oldest once it is full. Handlers write each record in a single call, so
+// every write is kept as one record. It is safe for concurrent use.
+type Ring struct {
+	mu      sync.Mutex
+	records []string
+	next    int // Index the next record is written to once full
+}
+
+// NewRing returns a ring keeping the latest size records.
+func NewRing(size int) *Ring {
+	return &Ring{records: make([]string, 0, max(size, 1))}
+}
+
+// Write stores p as a record, without its trailing newline.
+func (r *Ring) Write(p []byte) (int, error) {
+	record := strings.TrimSuffix(string(p), "\n")
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.records) < cap(r.records) {
+		r.records = append(r.records, record)
+	} else {
+		r.records[r.next] = record
+		r.next = (r.next + 1) % len(r.records)
+	}
+	return len(p), nil
+}
+
+// Records returns the stored records, oldest first.
+func (r *Ring) Records() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Concat(r.records[r.next:], r.records[:r.next])
+}
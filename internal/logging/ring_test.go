@@ -0,0 +1,53 @@
+package logging
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRing(t *testing.T) {
+	r := NewRing(3)
+	if got := r.Records(); len(got) != 0 {
+		t.Errorf("expected no records, got %v", got)
+	}
+
+	for i := range 5 {
+		fmt.Fprintf(r, "record %d\n", i)
+	}
+	expected := []string{"record 2", "record 3", "record 4"}
+	got := r.Records()
+	if !slices.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	// The returned slice is a copy
+	got[0] = "changed"
+	if r.Records()[0] != "record 2" {
+		t.Error("expected records to be unaffected by changes to the returned slice")
+	}
+}
+
+func TestNew_Ring(t *testing.T) {
+	var buf bytes.Buffer
+	ring := NewRing(10)
+	logger, closer, err := New(Options{Ring: ring}, &buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer closer.Close()
+
+	logger.Debug("filtered out")
+	logger.Info("first")
+	logger.Warn("second")
+
+	records := ring.Records()
+	if len(records) != 2 || !strings.Contains(records[0], "first") || !strings.Contains(records[1], "second") {
+		t.Errorf("expected the two logged records, got %q", records)
+	}
+	if !strings.Contains(buf.String(), "second") {
+		t.Errorf("expected records written to the log too, got %q", buf.String())
+	}
+}
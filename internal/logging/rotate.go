@@ -0,0 +1,129 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// RotatingFile is a log file that is rotated once it grows past a size
+// limit: app.log is renamed to app.log.1, app.log.1 to app.log.2 and so on,
+// dropping the oldest, and a new app.log is started. It is safe for
+// concurrent use.
+type RotatingFile struct {
+	mu       sync.Mutex
+	path     string
+	maxSize  int64
+	maxFiles int
+	file     *os.File
+	size     int64
+}
+
+// OpenRotatingFile opens the log file at path for appending, creating it
+// and its directory if needed.
+//
+// Parameters:
+//   - path: the log file
+//   - maxSize: the size in bytes after which the file is rotated; zero or
+//     less never rotates it
+//   - maxFiles: the number of rotated files to keep; zero discards the old
+//     file on rotation
+//
+// Returns an error if the file cannot be created or opened.
+func OpenRotatingFile(path string, maxSize int64, maxFiles int) (*RotatingFile, error) {
+	f := &RotatingFile{path: path, maxSize: maxSize, maxFiles: max(maxFiles, 0)}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Write appends p to the file, rotating it first if p would take it past
+// the size limit. A single write is never split across files.
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return 0, fs.ErrClosed
+	}
+	if f.maxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Close closes the file.
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+// open opens the current file and reads its size.
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	f.file, f.size = file, info.Size()
+	return nil
+}
+
+// rotate shifts the rotated files by one, moves the current file to the
+// first of them and starts a new one.
+func (f *RotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+	f.file = nil
+
+	if err := removeIfExists(f.rotated(f.maxFiles)); err != nil {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+	for i := f.maxFiles - 1; i >= 0; i-- {
+		err := os.Rename(f.rotated(i), f.rotated(i+1))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to rotate log file: %w", err)
+		}
+	}
+	return f.open()
+}
+
+// rotated returns the path of the i-th rotated file; the current file is
+// the 0th.
+func (f *RotatingFile) rotated(i int) string {
+	if i == 0 {
+		return f.path
+	}
+	return fmt.Sprintf("%s.%d", f.path, i)
+}
+
+// removeIfExists removes path, ignoring a missing file.
+func removeIfExists(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
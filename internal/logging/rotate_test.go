@@ -0,0 +1,79 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRotatingFile(t *testing.T) {
+	dir, err := os.MkdirTemp("", "logging-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "app.log")
+	f, err := OpenRotatingFile(path, 10, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := range 4 {
+		if _, err := fmt.Fprintf(f, "line %d\n", i); err != nil {
+			t.Fatalf("unexpected error writing: %v", err)
+		}
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("unexpected error closing: %v", err)
+	}
+
+	// Each 7-byte line fills a file, so the oldest line was dropped
+	expected := map[string]string{
+		path:        "line 3\n",
+		path + ".1": "line 2\n",
+		path + ".2": "line 1\n",
+	}
+	for name, want := range expected {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", name, err)
+		}
+		if string(data) != want {
+			t.Errorf("%s: expected %q, got %q", filepath.Base(name), want, data)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("expected no third rotated file, got %v", err)
+	}
+}
+
+func TestRotatingFile_Appends(t *testing.T) {
+	dir, err := os.MkdirTemp("", "logging-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "app.log")
+	for _, line := range []string{"first\n", "second\n"} {
+		f, err := OpenRotatingFile(path, 0, 1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		f.Write([]byte(line))
+		f.Close()
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read log: %v", err)
+	}
+	if string(data) != "first\nsecond\n" {
+		t.Errorf("expected both lines, got %q", data)
+	}
+	if _, err := (&RotatingFile{}).Write([]byte("x")); err == nil || !strings.Contains(err.Error(), "closed") {
+		t.Errorf("expected error writing to a closed file, got %v", err)
+	}
+}
@@ -0,0 +1,205 @@
+// Package mcp serves folder-search's directory search as a Model Context
+// Protocol tool server.
+//
+// The server speaks JSON-RPC 2.0 over a byte stream (standard input and
+// output when run as "folder-search mcp"), one message per line, so AI
+// assistants and other automation can find directories programmatically.
+// Only the tools capability is implemented.
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+const (
+	// protocolVersion is the MCP revision implemented by the server
+	protocolVersion = "2024-11-05"
+
+	// serverName identifies the server during initialization
+	serverName = "folder-search"
+
+	// maxMessageSize bounds the size of a single request line
+	maxMessageSize = 1 << 20
+)
+
+// JSON-RPC 2.0 error codes.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// request is an incoming JSON-RPC message. Requests without an ID are
+// notifications and get no response.
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Server answers MCP requests using a set of tools.
+type Server struct {
+	tools   []Tool
+	version string
+	logger  *slog.Logger
+	out     io.Writer
+}
+
+// NewServer creates a server offering the given tools.
+//
+// Parameters:
+//   - version: the folder-search version reported to clients
+//   - logger: receives diagnostics; it must not write to the server's output
+//   - tools: the tools to expose
+func NewServer(version string, logger *slog.Logger, tools ...Tool) *Server {
+	return &Server{tools: tools, version: version, logger: logger}
+}
+
+// Serve reads requests from in and writes responses to out until in is
+// exhausted or ctx is canceled. Requests are handled one at a time.
+func (s *Server) Serve(ctx context.Context, in io.Reader, out io.Writer) error {
+	s.out = out
+
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxMessageSize)
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if err := s.handle(ctx, []byte(line)); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// handle processes a single message and writes its response, if any.
+// Only failures to write the response are returned.
+func (s *Server) handle(ctx context.Context, data []byte) error {
+	var req request
+	if err := json.Unmarshal(data, &req); err != nil {
+		return s.write(response{ID: json.RawMessage("null"), Error: &rpcError{codeParseError, "parse error"}})
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return s.write(response{ID: idOrNull(req.ID), Error: &rpcError{codeInvalidRequest, "invalid request"}})
+	}
+
+	result, rerr := s.dispatch(ctx, req)
+	if req.ID == nil {
+		// Notifications are never answered, not even with errors
+		return nil
+	}
+	if rerr != nil {
+		s.logger.Debug("mcp request failed", "method", req.Method, "error", rerr.Message)
+		return s.write(response{ID: req.ID, Error: rerr})
+	}
+	return s.write(response{ID: req.ID, Result: result})
+}
+
+func (s *Server) dispatch(ctx context.Context, req request) (any, *rpcError) {
+	switch req.Method {
+	case "initialize":
+		return map[string]any{
+			"protocolVersion": protocolVersion,
+			"capabilities":    map[string]any{"tools": map[string]any{}},
+			"serverInfo":      map[string]any{"name": serverName, "version": s.version},
+		}, nil
+	case "notifications/initialized", "notifications/cancelled":
+		return nil, nil
+	case "ping":
+		return map[string]any{}, nil
+	case "tools/list":
+		tools := make([]map[string]any, 0, len(s.tools))
+		for _, t := range s.tools {
+			tools = append(tools, map[string]any{
+				"name":        t.Name,
+				"description": t.Description,
+				"inputSchema": t.InputSchema,
+			})
+		}
+		return map[string]any{"tools": tools}, nil
+	case "tools/call":
+		return s.callTool(ctx, req.Params)
+	default:
+		return nil, &rpcError{codeMethodNotFound, "method not found: " + req.Method}
+	}
+}
+
+// callTool runs a tool. Tool failures are reported in the result with
+// isError set, as MCP requires; only malformed calls are protocol errors.
+func (s *Server) callTool(ctx context.Context, params json.RawMessage) (any, *rpcError) {
+	var call struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := json.Unmarshal(params, &call); err != nil {
+		return nil, &rpcError{codeInvalidParams, "invalid params: " + err.Error()}
+	}
+
+	for _, t := range s.tools {
+		if t.Name != call.Name {
+			continue
+		}
+		args := call.Arguments
+		if len(args) == 0 {
+			args = json.RawMessage("{}")
+		}
+		text, err := t.Handler(ctx, args)
+		if err != nil {
+			s.logger.Info("mcp tool failed", "tool", t.Name, "error", err)
+			return toolResult(err.Error(), true), nil
+		}
+		return toolResult(text, false), nil
+	}
+	return nil, &rpcError{codeInvalidParams, fmt.Sprintf("unknown tool %q", call.Name)}
+}
+
+func toolResult(text string, isError bool) map[string]any {
+	return map[string]any{
+		"content": []map[string]any{{"type": "text", "text": text}},
+		"isError": isError,
+	}
+}
+
+func (s *Server) write(resp response) error {
+	resp.JSONRPC = "2.0"
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return err
+	}
+	if _, err := s.out.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write response: %w", err)
+	}
+	return nil
+}
+
+func idOrNull(id json.RawMessage) json.RawMessage {
+	if id == nil {
+		return json.RawMessage("null")
+	}
+	return id
+}
@@ -0,0 +1,185 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// serve runs a server over the given request lines and returns the decoded
+// responses.
+func serve(t *testing.T, lines ...string) []map[string]any {
+	t.Helper()
+	s := NewServer("test", slog.New(slog.NewTextHandler(io.Discard, nil)),
+		FindDirectoriesTool([]string{"node_modules"}, nil), ListDirectoriesTool(nil))
+
+	var out bytes.Buffer
+	if err := s.Serve(context.Background(), strings.NewReader(strings.Join(lines, "\n")), &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var responses []map[string]any
+	dec := json.NewDecoder(&out)
+	for dec.More() {
+		var resp map[string]any
+		if err := dec.Decode(&resp); err != nil {
+			t.Fatalf("invalid response: %v", err)
+		}
+		responses = append(responses, resp)
+	}
+	return responses
+}
+
+func TestServe_Initialize(t *testing.T) {
+	responses := serve(t,
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+	)
+
+	// The notification must not be answered
+	if len(responses) != 2 {
+		t.Fatalf("expected 2 responses, got %d", len(responses))
+	}
+
+	result := responses[0]["result"].(map[string]any)
+	if result["protocolVersion"] != protocolVersion {
+		t.Errorf("expected protocol version %s, got %v", protocolVersion, result["protocolVersion"])
+	}
+
+	tools := responses[1]["result"].(map[string]any)["tools"].([]any)
+	if len(tools) != 2 {
+		t.Errorf("expected 2 tools, got %d", len(tools))
+	}
+}
+
+func TestServe_Errors(t *testing.T) {
+	responses := serve(t,
+		`not json`,
+		`{"jsonrpc":"2.0","id":1,"method":"nope"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"nope"}}`,
+	)
+	if len(responses) != 3 {
+		t.Fatalf("expected 3 responses, got %d", len(responses))
+	}
+
+	for i, want := range []float64{codeParseError, codeMethodNotFound, codeInvalidParams} {
+		rerr, ok := responses[i]["error"].(map[string]any)
+		if !ok {
+			t.Errorf("response %d: expected error, got %v", i, responses[i])
+			continue
+		}
+		if rerr["code"] != want {
+			t.Errorf("response %d: expected code %v, got %v", i, want, rerr["code"])
+		}
+	}
+}
+
+func TestServe_FindDirectories(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "mcp-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	for _, dir := range []string{"src/handlers", "node_modules/handlers", "docs"} {
+		if err := os.MkdirAll(filepath.Join(tempDir, dir), 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", dir, err)
+		}
+	}
+
+	call, _ := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "tools/call",
+		"params": map[string]any{
+			"name":      "find_directories",
+			"arguments": map[string]any{"root": tempDir, "pattern": "handler"},
+		},
+	})
+	missingRoot := `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"find_directories","arguments":{}}}`
+
+	responses := serve(t, string(call), missingRoot)
+	if len(responses) != 2 {
+		t.Fatalf("expected 2 responses, got %d", len(responses))
+	}
+
+	result := responses[0]["result"].(map[string]any)
+	if result["isError"] != false {
+		t.Fatalf("expected success, got %v", result)
+	}
+	text := result["content"].([]any)[0].(map[string]any)["text"]
+	if want := filepath.Join(tempDir, "src", "handlers"); text != want {
+		t.Errorf("expected %q, got %q", want, text)
+	}
+
+	// Tool failures are results with isError, not protocol errors
+	result = responses[1]["result"].(map[string]any)
+	if result["isError"] != true {
+		t.Errorf("expected isError for missing root, got %v", result)
+	}
+}
+
+func TestServe_ListDirectoriesPaged(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "mcp-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	for _, dir := range []string{"a", "b", "c"} {
+		if err := os.Mkdir(filepath.Join(tempDir, dir), 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", dir, err)
+		}
+	}
+
+	var lines []string
+	for i, offset := range []int{0, 2} {
+		call, _ := json.Marshal(map[string]any{
+			"jsonrpc": "2.0",
+			"id":      i + 1,
+			"method":  "tools/call",
+			"params": map[string]any{
+				"name":      "list_directories",
+				"arguments": map[string]any{"root": tempDir, "offset": offset, "limit": 2},
+			},
+		})
+		lines = append(lines, string(call))
+	}
+
+	responses := serve(t, lines...)
+	if len(responses) != 2 {
+		t.Fatalf("expected 2 responses, got %d", len(responses))
+	}
+
+	var texts []string
+	for _, resp := range responses {
+		result := resp["result"].(map[string]any)
+		if result["isError"] != false {
+			t.Fatalf("expected success, got %v", result)
+		}
+		texts = append(texts, result["content"].([]any)[0].(map[string]any)["text"].(string))
+	}
+
+	first := strings.Split(texts[0], "\n")
+	if len(first) != 3 || !strings.Contains(first[2], "offset=2") {
+		t.Errorf("expected 2 paths and a next page hint, got %q", texts[0])
+	}
+	if strings.Contains(texts[1], "\n") || strings.Contains(texts[1], "offset=") {
+		t.Errorf("expected a single path on the last page, got %q", texts[1])
+	}
+
+	// The two pages together list every directory once
+	listed := map[string]bool{first[0]: true, first[1]: true, texts[1]: true}
+	for _, dir := range []string{"a", "b", "c"} {
+		if !listed[filepath.Join(tempDir, dir)] {
+			t.Errorf("expected %s in the pages, got %q and %q", dir, texts[0], texts[1])
+		}
+	}
+}
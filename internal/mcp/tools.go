@@ -0,0 +1,174 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/kaczmarekdaniel/folder-search/internal/dirsearch"
+)
+
+// defaultFindLimit caps the results of find_directories unless the client
+// asks for a different limit.
+const defaultFindLimit = 200
+
+// Tool is an operation exposed to MCP clients.
+type Tool struct {
+	// Name identifies the tool in tools/call requests
+	Name string
+
+	// Description tells the client what the tool does
+	Description string
+
+	// InputSchema is the JSON Schema of the tool's arguments
+	InputSchema map[string]any
+
+	// Handler runs the tool with the raw JSON arguments and returns the
+	// text shown to the client
+	Handler func(ctx context.Context, args json.RawMessage) (string, error)
+}
+
+// searchArgs are the arguments shared by the search tools.
+type searchArgs struct {
+	Root          string `json:"root"`
+	Pattern       string `json:"pattern"`
+	CaseSensitive bool   `json:"case_sensitive"`
+	MaxDepth      int    `json:"max_depth"`
+	Limit         int    `json:"limit"`
+	Offset        int    `json:"offset"`
+}
+
+// parse decodes args and resolves the root to an absolute path.
+func (a *searchArgs) parse(args json.RawMessage) error {
+	if err := json.Unmarshal(args, a); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	if a.Root == "" {
+		return errors.New("invalid arguments: root is required")
+	}
+	root, err := filepath.Abs(a.Root)
+	if err != nil {
+		return err
+	}
+	a.Root = root
+	return nil
+}
+
+// options builds search options from the arguments.
+func (a *searchArgs) options(ignore []string) *dirsearch.Options {
+	opts := dirsearch.DefaultOptions()
+	opts.StartDir = a.Root
+	opts.SearchPattern = a.Pattern
+	opts.CaseSensitive = a.CaseSensitive
+	opts.IgnorePatterns = ignore
+	return opts
+}
+
+// FindDirectoriesTool returns the find_directories tool, which searches a
+// tree for directories whose names contain a pattern.
+//
+// Parameters:
+//   - ignore: directory names that are never searched, like Options.IgnorePatterns
+//   - ranking: boost rules ordering the matches found within the limit
+func FindDirectoriesTool(ignore []string, ranking []dirsearch.BoostRule) Tool {
+	return Tool{
+		Name:        "find_directories",
+		Description: "Find directories under root whose names contain pattern, at any depth. Returns absolute paths, one per line. .git and ignored directories (e.g. node_modules) are skipped.",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"root":           map[string]any{"type": "string", "description": "Directory to search"},
+				"pattern":        map[string]any{"type": "string", "description": "Substring to match against directory names; empty matches all"},
+				"case_sensitive": map[string]any{"type": "boolean", "description": "Match case exactly (default false)"},
+				"max_depth":      map[string]any{"type": "integer", "description": "Levels below root to search; 0 for unlimited"},
+				"limit":          map[string]any{"type": "integer", "description": fmt.Sprintf("Maximum number of results (default %d)", defaultFindLimit)},
+			},
+			"required": []string{"root"},
+		},
+		Handler: func(ctx context.Context, raw json.RawMessage) (string, error) {
+			var args searchArgs
+			if err := args.parse(raw); err != nil {
+				return "", err
+			}
+			if args.Limit <= 0 {
+				args.Limit = defaultFindLimit
+			}
+
+			found, truncated, err := dirsearch.FindDirs(ctx, args.options(ignore), args.MaxDepth, args.Limit)
+			if err != nil {
+				return "", err
+			}
+			text := formatPaths(args.Root, dirsearch.Rank(args.Root, found, ranking))
+			if truncated {
+				text += fmt.Sprintf("\n(showing the first %d matches; narrow the pattern or raise limit for more)", args.Limit)
+			}
+			return text, nil
+		},
+	}
+}
+
+// ListDirectoriesTool returns the list_directories tool, which lists the
+// direct subdirectories of a directory like the interactive browser does.
+//
+// Parameters:
+//   - ignore: directory names that are never listed, like Options.IgnorePatterns
+func ListDirectoriesTool(ignore []string) Tool {
+	return Tool{
+		Name:        "list_directories",
+		Description: "List the direct subdirectories of root, optionally only those whose names contain pattern. Returns absolute paths, one per line. For very large directories, pass offset and limit to fetch the listing in pages.",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"root":           map[string]any{"type": "string", "description": "Directory to list"},
+				"pattern":        map[string]any{"type": "string", "description": "Substring to match against directory names; empty matches all"},
+				"case_sensitive": map[string]any{"type": "boolean", "description": "Match case exactly (default false)"},
+				"offset":         map[string]any{"type": "integer", "description": "Number of matches to skip when fetching a page (default 0)"},
+				"limit":          map[string]any{"type": "integer", "description": "Maximum number of matches in a page; 0 lists everything unless offset is set"},
+			},
+			"required": []string{"root"},
+		},
+		Handler: func(ctx context.Context, raw json.RawMessage) (string, error) {
+			var args searchArgs
+			if err := args.parse(raw); err != nil {
+				return "", err
+			}
+			if args.Offset < 0 || args.Limit < 0 {
+				return "", errors.New("invalid arguments: offset and limit must not be negative")
+			}
+
+			// Pages come in directory order, as sorting needs the whole listing
+			if args.Offset > 0 || args.Limit > 0 {
+				page, err := dirsearch.SearchPage(ctx, args.options(ignore), args.Offset, args.Limit)
+				if err != nil {
+					return "", err
+				}
+				text := formatPaths(args.Root, page.Directories)
+				if page.More {
+					text += fmt.Sprintf("\n(more matches follow; pass offset=%d for the next page)", page.Offset+len(page.Directories))
+				}
+				return text, nil
+			}
+
+			result := dirsearch.Search(args.options(ignore))
+			if result.Error != nil {
+				return "", result.Error
+			}
+			return formatPaths(args.Root, result.Directories), nil
+		},
+	}
+}
+
+// formatPaths joins paths relative to root into absolute paths, one per line.
+func formatPaths(root string, rel []string) string {
+	if len(rel) == 0 {
+		return "no matching directories"
+	}
+	lines := make([]string, 0, len(rel))
+	for _, p := range rel {
+		lines = append(lines, filepath.Join(root, p))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,126 @@
+// Package notes keeps short notes attached to directories.
+//
+// A note is a single line of free text, e.g. "prod config, don't touch",
+// shown next to the directory in the UI. Notes are stored by absolute path in
+// notes.json in the user data directory.
+package notes
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/kaczmarekdaniel/folder-search/internal/statefile"
+)
+
+// schemaVersion is the current version of the notes file format.
+const schemaVersion = 1
+
+// migrations upgrade older notes files to schemaVersion.
+var migrations = []statefile.Migration{
+	// Files without a version field are read as they are
+	statefile.Unchanged,
+}
+
+// Notes is a persistent mapping from directories to their notes.
+type Notes struct {
+	mu      sync.Mutex
+	path    string
+	notes   map[string]string
+	changed map[string]bool // Directories whose note was set since the last save
+}
+
+// fileFormat is the on-disk representation of the notes.
+type fileFormat struct {
+	// Notes maps an absolute directory path to its note
+	Notes map[string]string `json:"notes"`
+}
+
+// DefaultPath returns the location of the notes file in the user data directory.
+func DefaultPath() (string, error) {
+	return statefile.DataPath("notes.json")
+}
+
+// New returns an empty set of notes stored at path. An empty path keeps the
+// notes in memory only.
+func New(path string) *Notes {
+	return &Notes{path: path, notes: make(map[string]string), changed: make(map[string]bool)}
+}
+
+// Load reads the notes from path. A missing file yields no notes.
+//
+// Returns an error if the file exists but cannot be read or parsed.
+func Load(path string) (*Notes, error) {
+	n := New(path)
+
+	var f fileFormat
+	if _, err := statefile.Load(path, schemaVersion, migrations, &f); err != nil {
+		return nil, fmt.Errorf("failed to load notes: %w", err)
+	}
+	for dir, note := range f.Notes {
+		n.notes[dir] = note
+	}
+	return n, nil
+}
+
+// Get returns the note attached to dir, or an empty string if it has none.
+func (n *Notes) Get(dir string) string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.notes[filepath.Clean(dir)]
+}
+
+// Set attaches note to dir, replacing any previous note. Line breaks are
+// replaced by spaces so a note always fits on one line, and an empty note
+// removes the existing one.
+func (n *Notes) Set(dir, note string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	dir = filepath.Clean(dir)
+	note = strings.TrimSpace(strings.Join(strings.Fields(note), " "))
+	if n.notes[dir] == note {
+		return
+	}
+	if note == "" {
+		delete(n.notes, dir)
+	} else {
+		n.notes[dir] = note
+	}
+	n.changed[dir] = true
+}
+
+// Save writes the notes back to their file if they changed since loading.
+// The notes set since then are merged into those saved by other processes
+// in the meantime, which are loaded back. In-memory notes are never written.
+func (n *Notes) Save() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if len(n.changed) == 0 || n.path == "" {
+		return nil
+	}
+
+	var f fileFormat
+	err := statefile.Update(n.path, schemaVersion, migrations, &f, func() {
+		if f.Notes == nil {
+			f.Notes = make(map[string]string)
+		}
+		for dir := range n.changed {
+			if note, ok := n.notes[dir]; ok {
+				f.Notes[dir] = note
+			} else {
+				delete(f.Notes, dir)
+			}
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write notes: %w", err)
+	}
+
+	n.notes = f.Notes
+	clear(n.changed)
+	return nil
+}
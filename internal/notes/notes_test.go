@@ -0,0 +1,84 @@
+package notes
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSetAndGet(t *testing.T) {
+	n := New("")
+
+	n.Set("/srv/app/config/", "  prod config,\n don't touch ")
+	if got := n.Get("/srv/app/config"); got != "prod config, don't touch" {
+		t.Errorf("expected normalized note, got %q", got)
+	}
+
+	n.Set("/srv/app/config", "")
+	if got := n.Get("/srv/app/config"); got != "" {
+		t.Errorf("expected empty note to remove it, got %q", got)
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	dir, err := os.MkdirTemp("", "notes-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "notes.json")
+
+	n, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	n.Set("/srv/data", "backups live here")
+	if err := n.Save(); err != nil {
+		t.Fatalf("unexpected error saving: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error loading: %v", err)
+	}
+	if got := loaded.Get("/srv/data"); got != "backups live here" {
+		t.Errorf("expected note to survive a save/load round trip, got %q", got)
+	}
+}
+
+func TestSave_MergesConcurrentChanges(t *testing.T) {
+	dir, err := os.MkdirTemp("", "notes-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "notes.json")
+
+	// Two processes load the same notes and change different directories
+	first, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	first.Set("/srv/a", "first")
+	second.Set("/srv/b", "second")
+	for _, n := range []*Notes{first, second} {
+		if err := n.Save(); err != nil {
+			t.Fatalf("unexpected error saving: %v", err)
+		}
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error loading: %v", err)
+	}
+	if a, b := loaded.Get("/srv/a"), loaded.Get("/srv/b"); a != "first" || b != "second" {
+		t.Errorf("expected both notes to be saved, got %q and %q", a, b)
+	}
+	if got := second.Get("/srv/a"); got != "first" {
+		t.Errorf("expected the notes of other processes to be loaded back on save, got %q", got)
+	}
+}
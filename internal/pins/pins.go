@@ -0,0 +1,162 @@
+// Package pins keeps the set of directories the user pinned.
+//
+// A pinned directory is always listed at the top of its parent directory.
+// Pins are stored as absolute paths in pins.json in the user data directory.
+package pins
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/kaczmarekdaniel/folder-search/internal/statefile"
+)
+
+// schemaVersion is the current version of the pins file format.
+const schemaVersion = 1
+
+// migrations upgrade older pins files to schemaVersion.
+var migrations = []statefile.Migration{
+	// Files without a version field are read as they are
+	statefile.Unchanged,
+}
+
+// Pins is a persistent set of pinned directories.
+type Pins struct {
+	mu      sync.Mutex
+	path    string
+	dirs    map[string]bool
+	changed map[string]bool // Directories toggled since the last save
+}
+
+// fileFormat is the on-disk representation of the pins.
+type fileFormat struct {
+	// Dirs lists the pinned directories in alphabetical order
+	Dirs []string `json:"dirs"`
+}
+
+// DefaultPath returns the location of the pins file in the user data directory.
+func DefaultPath() (string, error) {
+	return statefile.DataPath("pins.json")
+}
+
+// New returns an empty set of pins stored at path. An empty path keeps the
+// pins in memory only.
+func New(path string) *Pins {
+	return &Pins{path: path, dirs: make(map[string]bool), changed: make(map[string]bool)}
+}
+
+// Load reads the pins from path. A missing file yields no pins.
+//
+// Returns an error if the file exists but cannot be read or parsed.
+func Load(path string) (*Pins, error) {
+	p := New(path)
+
+	var f fileFormat
+	if _, err := statefile.Load(path, schemaVersion, migrations, &f); err != nil {
+		return nil, fmt.Errorf("failed to load pins: %w", err)
+	}
+	for _, dir := range f.Dirs {
+		p.dirs[dir] = true
+	}
+	return p, nil
+}
+
+// Toggle pins dir if it is not pinned and unpins it otherwise.
+//
+// Returns whether dir is pinned afterwards.
+func (p *Pins) Toggle(dir string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	dir = filepath.Clean(dir)
+	pinned := !p.dirs[dir]
+	if pinned {
+		p.dirs[dir] = true
+	} else {
+		delete(p.dirs, dir)
+	}
+	p.changed[dir] = true
+	return pinned
+}
+
+// IsPinned reports whether dir is pinned.
+func (p *Pins) IsPinned(dir string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.dirs[filepath.Clean(dir)]
+}
+
+// Order moves the pinned entries of a directory listing to the top, keeping
+// the relative order of pinned and unpinned entries.
+//
+// Parameters:
+//   - parent: the listed directory
+//   - names: entry names relative to parent
+//
+// Returns a new slice; names is not modified.
+func (p *Pins) Order(parent string, names []string) []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ordered := slices.Clone(names)
+	if len(p.dirs) == 0 {
+		return ordered
+	}
+	slices.SortStableFunc(ordered, func(a, b string) int {
+		pa := p.dirs[filepath.Join(parent, a)]
+		pb := p.dirs[filepath.Join(parent, b)]
+		switch {
+		case pa && !pb:
+			return -1
+		case pb && !pa:
+			return 1
+		default:
+			return 0
+		}
+	})
+	return ordered
+}
+
+// Save writes the pins back to their file if they changed since loading.
+// The directories pinned or unpinned since then are merged into the pins
+// saved by other processes in the meantime, which are loaded back.
+// In-memory pins are never written.
+func (p *Pins) Save() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.changed) == 0 || p.path == "" {
+		return nil
+	}
+
+	var f fileFormat
+	merged := make(map[string]bool)
+	err := statefile.Update(p.path, schemaVersion, migrations, &f, func() {
+		for _, dir := range f.Dirs {
+			merged[dir] = true
+		}
+		for dir := range p.changed {
+			if p.dirs[dir] {
+				merged[dir] = true
+			} else {
+				delete(merged, dir)
+			}
+		}
+		f.Dirs = make([]string, 0, len(merged))
+		for dir := range merged {
+			f.Dirs = append(f.Dirs, dir)
+		}
+		sort.Strings(f.Dirs)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write pins: %w", err)
+	}
+
+	p.dirs = merged
+	clear(p.changed)
+	return nil
+}
@@ -0,0 +1,59 @@
+package pins
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestToggleAndOrder(t *testing.T) {
+	p := New("")
+
+	if !p.Toggle("/home/me/work") {
+		t.Fatal("expected first toggle to pin")
+	}
+	p.Toggle("/home/me/notes/")
+
+	got := p.Order("/home/me", []string{"a", "notes", "b", "work"})
+	want := []string{"notes", "work", "a", "b"}
+	if !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	if p.Toggle("/home/me/work") {
+		t.Error("expected second toggle to unpin")
+	}
+	if p.IsPinned("/home/me/work") {
+		t.Error("expected /home/me/work to be unpinned")
+	}
+	if !p.IsPinned("/home/me/notes") {
+		t.Error("expected trailing slash to be ignored")
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	dir, err := os.MkdirTemp("", "pins-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "pins.json")
+
+	p, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p.Toggle("/srv/data")
+	if err := p.Save(); err != nil {
+		t.Fatalf("unexpected error saving: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error loading: %v", err)
+	}
+	if !loaded.IsPinned("/srv/data") {
+		t.Error("expected pin to survive a save/load round trip")
+	}
+}
@@ -0,0 +1,26 @@
+// Package power detects whether the machine is trying to save energy, so
+// background work can be cut down on laptops.
+package power
+
+import "strings"
+
+// Saving reports whether the machine runs on battery or a power-saving
+// profile is active. It returns false where neither can be detected, except
+// on Android, where apps cannot read the battery state and it returns true.
+func Saving() bool {
+	return saving()
+}
+
+// parsePmset reports whether the output of "pmset -g batt" or "pmset -g"
+// on macOS shows battery power or Low Power Mode.
+func parsePmset(output string) bool {
+	for line := range strings.Lines(output) {
+		if strings.Contains(line, "'Battery Power'") {
+			return true
+		}
+		if fields := strings.Fields(line); len(fields) == 2 && fields[0] == "lowpowermode" && fields[1] == "1" {
+			return true
+		}
+	}
+	return false
+}
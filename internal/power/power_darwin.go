@@ -0,0 +1,21 @@
+//go:build darwin
+
+package power
+
+import (
+	"context"
+	"os/exec"
+	"time"
+)
+
+func saving() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	for _, args := range [][]string{{"-g", "batt"}, {"-g"}} {
+		out, err := exec.CommandContext(ctx, "pmset", args...).Output()
+		if err == nil && parsePmset(string(out)) {
+			return true
+		}
+	}
+	return false
+}
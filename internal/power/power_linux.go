@@ -0,0 +1,47 @@
+//go:build linux
+
+package power
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+func saving() bool {
+	if runtime.GOOS == "android" {
+		// Apps cannot read the battery state on Android, and phones
+		// mostly run on battery
+		return true
+	}
+	return savingSysfs("/sys")
+}
+
+// savingSysfs reports whether a battery under the sysfs mount point root is
+// discharging or the ACPI platform profile is set to low power.
+func savingSysfs(root string) bool {
+	if profile, err := os.ReadFile(filepath.Join(root, "firmware/acpi/platform_profile")); err == nil {
+		if strings.TrimSpace(string(profile)) == "low-power" {
+			return true
+		}
+	}
+
+	supplies, _ := filepath.Glob(filepath.Join(root, "class/power_supply/*"))
+	for _, supply := range supplies {
+		if read(filepath.Join(supply, "type")) == "Battery" && read(filepath.Join(supply, "status")) == "Discharging" {
+			return true
+		}
+	}
+	return false
+}
+
+// read returns the trimmed content of a sysfs attribute, or "" if it
+// cannot be read.
+func read(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
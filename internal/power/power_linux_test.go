@@ -0,0 +1,57 @@
+package power
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSavingSysfs(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		expected bool
+	}{
+		{"no power supply", nil, false},
+		{"battery discharging", map[string]string{
+			"class/power_supply/AC/type":     "Mains\n",
+			"class/power_supply/AC/online":   "0\n",
+			"class/power_supply/BAT0/type":   "Battery\n",
+			"class/power_supply/BAT0/status": "Discharging\n",
+		}, true},
+		{"battery charging", map[string]string{
+			"class/power_supply/BAT0/type":   "Battery\n",
+			"class/power_supply/BAT0/status": "Charging\n",
+		}, false},
+		{"low power profile", map[string]string{
+			"firmware/acpi/platform_profile": "low-power\n",
+		}, true},
+		{"balanced profile", map[string]string{
+			"firmware/acpi/platform_profile": "balanced\n",
+		}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := os.MkdirTemp("", "power-test-*")
+			if err != nil {
+				t.Fatalf("failed to create temp dir: %v", err)
+			}
+			defer os.RemoveAll(root)
+
+			for name, content := range tt.files {
+				path := filepath.Join(root, name)
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatalf("failed to create directory: %v", err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+					t.Fatalf("failed to write %s: %v", name, err)
+				}
+			}
+
+			if got := savingSysfs(root); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,7 @@
+//go:build !linux && !darwin
+
+package power
+
+func saving() bool {
+	return false
+}
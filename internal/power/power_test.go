@@ -0,0 +1,24 @@
+package power
+
+import "testing"
+
+func TestParsePmset(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		expected bool
+	}{
+		{"battery", "Now drawing from 'Battery Power'\n -InternalBattery-0 (id=1)\t80%; discharging\n", true},
+		{"charger", "Now drawing from 'AC Power'\n -InternalBattery-0 (id=1)\t80%; charging\n", false},
+		{"low power mode", "System-wide power settings:\nCurrently in use:\n lowpowermode         1\n sleep                1\n", true},
+		{"low power mode off", "Currently in use:\n lowpowermode         0\n", false},
+		{"empty", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parsePmset(tt.output); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
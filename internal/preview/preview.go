@@ -0,0 +1,87 @@
+// Package preview runs a user-configured shell command to preview a
+// directory, in the style of fzf --preview.
+//
+// The command is run through the system shell with every "{}" replaced by
+// the quoted path of the directory, e.g. "ls -la {}" or "tree -L 1 {}".
+package preview
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/kaczmarekdaniel/folder-search/internal/shell"
+)
+
+const (
+	// Placeholder is replaced by the directory path in preview commands
+	Placeholder = "{}"
+
+	// DefaultTimeout bounds how long a preview command may run
+	DefaultTimeout = 2 * time.Second
+
+	// maxOutput is the number of output bytes kept from a preview command
+	maxOutput = 64 << 10
+)
+
+// Expand substitutes the quoted path for every placeholder in command. A
+// command without a placeholder gets the path appended as last argument.
+func Expand(command, path string) string {
+	quoted := quote(path)
+	if !strings.Contains(command, Placeholder) {
+		return command + " " + quoted
+	}
+	return strings.ReplaceAll(command, Placeholder, quoted)
+}
+
+// Run executes the preview command for path and returns its combined
+// standard output and error, truncated to a size suitable for display.
+//
+// Parameters:
+//   - ctx: cancels the command; a DefaultTimeout deadline is added
+//   - command: the preview command, see Expand
+//   - path: the directory to preview, also used as the working directory
+//
+// Returns the output and an error if the command cannot be started, fails
+// or times out. The output gathered so far is returned along with the error.
+func Run(ctx context.Context, command, path string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, DefaultTimeout)
+	defer cancel()
+
+	cmd := shell.Command(ctx, Expand(command, path))
+	cmd.Dir = path
+
+	var out limitedBuffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err := cmd.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("preview timed out after %s", DefaultTimeout)
+	}
+	return out.String(), err
+}
+
+// quote quotes path as a single argument for the system shell.
+func quote(path string) string {
+	if runtime.GOOS == "windows" {
+		return `"` + path + `"`
+	}
+	return "'" + strings.ReplaceAll(path, "'", `'\''`) + "'"
+}
+
+// limitedBuffer keeps the first maxOutput bytes written to it and silently
+// discards the rest, so a chatty command cannot exhaust memory.
+type limitedBuffer struct {
+	bytes.Buffer
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := maxOutput - b.Len(); room > 0 {
+		b.Buffer.Write(p[:min(len(p), room)])
+	}
+	return len(p), nil
+}
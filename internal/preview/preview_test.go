@@ -0,0 +1,50 @@
+package preview
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestExpand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX shell quoting")
+	}
+
+	if got := Expand("ls -la {}", "/tmp/it's here"); got != `ls -la '/tmp/it'\''s here'` {
+		t.Errorf("expected quoted path to replace the placeholder, got %q", got)
+	}
+	if got := Expand("tree -L 1", "/srv"); got != "tree -L 1 '/srv'" {
+		t.Errorf("expected path to be appended, got %q", got)
+	}
+}
+
+func TestRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+
+	dir, err := os.MkdirTemp("", "preview-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	if err := os.Mkdir(filepath.Join(dir, "child dir"), 0755); err != nil {
+		t.Fatalf("failed to create child: %v", err)
+	}
+
+	out, err := Run(context.Background(), "ls {}", dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "child dir") {
+		t.Errorf("expected listing to contain 'child dir', got %q", out)
+	}
+
+	if _, err := Run(context.Background(), "exit 3", dir); err == nil {
+		t.Error("expected error for failing command, got nil")
+	}
+}
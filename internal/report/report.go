@@ -0,0 +1,395 @@
+// Package report runs saved searches and housekeeping audits without the UI.
+//
+// A report file lists searches (directories matching a pattern under a
+// root) and audits (empty directories, stale projects, large directories).
+// Run executes all of them and returns a Report that can be written as JSON
+// or Markdown, which makes the report command suitable for cron jobs.
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kaczmarekdaniel/folder-search/internal/dirsearch"
+	"github.com/kaczmarekdaniel/folder-search/internal/sizefmt"
+	"github.com/kaczmarekdaniel/folder-search/internal/timefmt"
+)
+
+// Audit kinds.
+const (
+	// EmptyDirs finds directories without any entries
+	EmptyDirs = "empty_dirs"
+
+	// StaleProjects finds git repositories in which nothing was modified
+	// for StaleDays
+	StaleProjects = "stale_projects"
+
+	// LargeDirs finds directories holding at least MinSizeMB of files
+	LargeDirs = "large_dirs"
+)
+
+// Kind of the sections produced by saved searches.
+const searchKind = "search"
+
+const (
+	// DefaultStaleDays is the age after which a project counts as stale
+	DefaultStaleDays = 180
+
+	// DefaultMinSizeMB is the size from which a directory counts as large
+	DefaultMinSizeMB = 1024
+
+	// defaultLargeDirsDepth limits large_dirs audits to the children of the
+	// root unless a depth is given, since every candidate is walked in full
+	defaultLargeDirsDepth = 1
+)
+
+// Config is the content of a report file.
+type Config struct {
+	// Searches are saved searches whose matches are listed
+	Searches []Search `json:"searches"`
+
+	// Audits are housekeeping checks
+	Audits []Audit `json:"audits"`
+}
+
+// Search is a saved search, like the find_directories MCP tool.
+type Search struct {
+	// Name is the section title; it defaults to the pattern
+	Name string `json:"name"`
+
+	// Root is the directory searched. A leading "~" is expanded to the
+	// user's home directory.
+	Root string `json:"root"`
+
+	// Roots lists more directories searched along with Root, expanded the
+	// same way; the findings of all of them are sorted together
+	Roots []string `json:"roots"`
+
+	// Pattern is matched against entry names as a substring, unless Regex
+	// or Fuzzy is set
+	Pattern string `json:"pattern"`
+
+	Regex         bool `json:"regex"`
+	Fuzzy         bool `json:"fuzzy"`
+	CaseSensitive bool `json:"case_sensitive"`
+	IncludeFiles  bool `json:"include_files"`
+
+	// MaxDepth is how many levels below Root are searched; zero searches
+	// the whole tree
+	MaxDepth int `json:"max_depth"`
+}
+
+// Audit is a housekeeping check.
+type Audit struct {
+	// Name is the section title; it defaults to the kind
+	Name string `json:"name"`
+
+	// Kind is EmptyDirs, StaleProjects or LargeDirs
+	Kind string `json:"kind"`
+
+	// Root is the directory audited. A leading "~" is expanded to the
+	// user's home directory.
+	Root string `json:"root"`
+
+	// MaxDepth is how many levels below Root are checked; zero checks the
+	// whole tree, except for large_dirs which checks the children of Root
+	MaxDepth int `json:"max_depth"`
+
+	// StaleDays is the age of the newest change after which a project is
+	// stale (stale_projects only; DefaultStaleDays if zero)
+	StaleDays int `json:"stale_days"`
+
+	// MinSizeMB is the size from which a directory is large (large_dirs
+	// only; DefaultMinSizeMB if zero)
+	MinSizeMB int64 `json:"min_size_mb"`
+}
+
+// Report is the outcome of running a Config.
+type Report struct {
+	GeneratedAt time.Time `json:"generated_at"`
+	Sections    []Section `json:"sections"`
+
+	// Times renders the timestamps of Markdown output; JSON output always
+	// holds RFC 3339 timestamps
+	Times timefmt.Formatter `json:"-"`
+
+	// Sizes are the units of sizes in Markdown output; JSON output always
+	// holds sizes in bytes
+	Sizes sizefmt.Units `json:"-"`
+}
+
+// Section holds the findings of one search or audit.
+type Section struct {
+	Name     string    `json:"name"`
+	Kind     string    `json:"kind"`
+	Root     string    `json:"root"`
+	Findings []Finding `json:"findings"`
+
+	// Roots lists the directories searched along with Root, if any
+	Roots []string `json:"roots,omitempty"`
+
+	// Error is set if the search or audit could not run to completion
+	Error string `json:"error,omitempty"`
+}
+
+// Finding is a path reported by a section.
+type Finding struct {
+	Path    string    `json:"path"`
+	Size    int64     `json:"size,omitempty"`
+	ModTime time.Time `json:"mod_time,omitzero"`
+}
+
+// Failed reports whether any section could not run to completion.
+func (r Report) Failed() bool {
+	return slices.ContainsFunc(r.Sections, func(s Section) bool { return s.Error != "" })
+}
+
+// LoadConfig reads and validates the report file at path.
+//
+// Returns an error if the file cannot be read or parsed, or if a search or
+// audit has no root or an unknown kind.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report file: %w", err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse report file %s: %w", path, err)
+	}
+	if len(cfg.Searches) == 0 && len(cfg.Audits) == 0 {
+		return nil, fmt.Errorf("report file %s has no searches or audits", path)
+	}
+
+	for i, s := range cfg.Searches {
+		if cfg.Searches[i].Root, err = expandRoot(s.Root); err != nil {
+			return nil, fmt.Errorf("invalid search %d in %s: %w", i+1, path, err)
+		}
+		for j, root := range s.Roots {
+			if cfg.Searches[i].Roots[j], err = expandRoot(root); err != nil {
+				return nil, fmt.Errorf("invalid search %d in %s: %w", i+1, path, err)
+			}
+		}
+	}
+	for i, a := range cfg.Audits {
+		if a.Kind != EmptyDirs && a.Kind != StaleProjects && a.Kind != LargeDirs {
+			return nil, fmt.Errorf("invalid audit %d in %s: unknown kind %q (use %s, %s or %s)", i+1, path, a.Kind, EmptyDirs, StaleProjects, LargeDirs)
+		}
+		if cfg.Audits[i].Root, err = expandRoot(a.Root); err != nil {
+			return nil, fmt.Errorf("invalid audit %d in %s: %w", i+1, path, err)
+		}
+	}
+	return &cfg, nil
+}
+
+// expandRoot returns root as an absolute path, expanding a leading "~".
+func expandRoot(root string) (string, error) {
+	if root == "" {
+		return "", errors.New("root is required")
+	}
+	// On Windows, "~\work" is expanded as well
+	if root == "~" || strings.HasPrefix(root, "~/") || strings.HasPrefix(root, "~"+string(filepath.Separator)) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("cannot expand %q: %w", root, err)
+		}
+		root = filepath.Join(home, root[1:])
+	}
+	return filepath.Abs(root)
+}
+
+// Run executes every search and audit of cfg. A failing search or audit
+// records its error in its section and does not stop the others.
+//
+// Parameters:
+//   - ctx: stops the run early; unfinished sections report the error
+//   - cfg: the searches and audits to run
+//   - ignore: directory names skipped together with their subtrees
+//   - now: the time staleness is measured against
+func Run(ctx context.Context, cfg *Config, ignore []string, now time.Time) Report {
+	r := Report{GeneratedAt: now, Sections: []Section{}}
+	for _, s := range cfg.Searches {
+		r.Sections = append(r.Sections, runSearch(ctx, s, ignore))
+	}
+	for _, a := range cfg.Audits {
+		r.Sections = append(r.Sections, runAudit(ctx, a, ignore, now))
+	}
+	return r
+}
+
+// runSearch lists the entries matching a saved search.
+func runSearch(ctx context.Context, s Search, ignore []string) Section {
+	section := Section{Name: cmpOr(s.Name, s.Pattern, "all directories"), Kind: searchKind, Root: s.Root, Roots: s.Roots, Findings: []Finding{}}
+
+	opts := dirsearch.DefaultOptions()
+	opts.StartDirs = append([]string{s.Root}, s.Roots...)
+	opts.SearchPattern = s.Pattern
+	opts.Regex = s.Regex
+	opts.Fuzzy = s.Fuzzy
+	opts.CaseSensitive = s.CaseSensitive
+	opts.IncludeFiles = s.IncludeFiles
+	opts.IgnorePatterns = ignore
+	opts.MaxDepth = s.MaxDepth
+	if opts.MaxDepth <= 0 {
+		opts.MaxDepth = dirsearch.UnlimitedDepth
+	}
+
+	result := dirsearch.SearchContext(ctx, opts)
+	if result.Error != nil {
+		section.Error = result.Error.Error()
+		return section
+	}
+	for i, rel := range result.Directories {
+		section.Findings = append(section.Findings, Finding{Path: filepath.Join(result.Roots[i], rel)})
+	}
+	return section
+}
+
+// runAudit runs one housekeeping check.
+func runAudit(ctx context.Context, a Audit, ignore []string, now time.Time) Section {
+	section := Section{Name: cmpOr(a.Name, a.Kind), Kind: a.Kind, Root: a.Root, Findings: []Finding{}}
+
+	depth := a.MaxDepth
+	if depth <= 0 && a.Kind == LargeDirs {
+		depth = defaultLargeDirsDepth
+	}
+	opts := dirsearch.DefaultOptions()
+	opts.StartDir = a.Root
+	opts.IgnorePatterns = ignore
+	candidates, _, err := dirsearch.FindDirs(ctx, opts, depth, 0)
+	if err != nil {
+		section.Error = err.Error()
+		return section
+	}
+
+	var check func(dir string) (Finding, bool, error)
+	switch a.Kind {
+	case EmptyDirs:
+		check = isEmpty
+	case StaleProjects:
+		staleDays := a.StaleDays
+		if staleDays <= 0 {
+			staleDays = DefaultStaleDays
+		}
+		cutoff := now.AddDate(0, 0, -staleDays)
+		check = func(dir string) (Finding, bool, error) { return isStale(ctx, dir, cutoff) }
+	case LargeDirs:
+		minSizeMB := a.MinSizeMB
+		if minSizeMB <= 0 {
+			minSizeMB = DefaultMinSizeMB
+		}
+		check = func(dir string) (Finding, bool, error) { return isLarge(ctx, dir, minSizeMB<<20) }
+	}
+
+	for _, rel := range candidates {
+		if err := ctx.Err(); err != nil {
+			section.Error = err.Error()
+			return section
+		}
+		finding, ok, err := check(filepath.Join(a.Root, rel))
+		if err != nil {
+			// An unreadable candidate is skipped like during searches
+			continue
+		}
+		if ok {
+			section.Findings = append(section.Findings, finding)
+		}
+	}
+	return section
+}
+
+// isEmpty reports whether dir has no entries at all.
+func isEmpty(dir string) (Finding, bool, error) {
+	f, err := os.Open(dir)
+	if err != nil {
+		return Finding{}, false, err
+	}
+	defer f.Close()
+
+	names, err := f.Readdirnames(1)
+	if len(names) > 0 {
+		return Finding{}, false, nil
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		return Finding{}, false, err
+	}
+	return Finding{Path: dir}, true, nil
+}
+
+// isStale reports whether dir is the root of a git repository in which
+// nothing was modified after cutoff.
+func isStale(ctx context.Context, dir string, cutoff time.Time) (Finding, bool, error) {
+	if _, err := os.Lstat(filepath.Join(dir, ".git")); err != nil {
+		return Finding{}, false, nil
+	}
+
+	newest, err := newestModTime(ctx, dir)
+	if err != nil {
+		return Finding{}, false, err
+	}
+	if newest.After(cutoff) {
+		return Finding{}, false, nil
+	}
+	return Finding{Path: dir, ModTime: newest}, true, nil
+}
+
+// newestModTime returns the latest modification time of dir and any entry
+// below it, not counting the .git directory.
+func newestModTime(ctx context.Context, dir string) (time.Time, error) {
+	var newest time.Time
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err != nil {
+			if path == dir {
+				return err
+			}
+			return nil
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		if t := info.ModTime(); t.After(newest) {
+			newest = t
+		}
+		return nil
+	})
+	return newest, err
+}
+
+// isLarge reports whether the files below dir add up to at least minSize bytes.
+func isLarge(ctx context.Context, dir string, minSize int64) (Finding, bool, error) {
+	size, err := dirsearch.DirSize(ctx, dir, nil)
+	if err != nil {
+		return Finding{}, false, err
+	}
+	if size < minSize {
+		return Finding{}, false, nil
+	}
+	return Finding{Path: dir, Size: size}, true, nil
+}
+
+// cmpOr returns the first non-empty value.
+func cmpOr(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,238 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kaczmarekdaniel/folder-search/internal/sizefmt"
+	"github.com/kaczmarekdaniel/folder-search/internal/timefmt"
+)
+
+// makeTree creates dirs below root and returns root.
+func makeTree(t *testing.T, dirs ...string) string {
+	t.Helper()
+	root, err := os.MkdirTemp("", "report-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	for _, dir := range dirs {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", dir, err)
+		}
+	}
+	return root
+}
+
+func writeFile(t *testing.T, path string, size int) {
+	t.Helper()
+	if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+func paths(s Section) []string {
+	var out []string
+	for _, f := range s.Findings {
+		out = append(out, f.Path)
+	}
+	return out
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := makeTree(t)
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{"valid", `{"searches":[{"root":".","pattern":"src"}],"audits":[{"kind":"empty_dirs","root":"."}]}`, false},
+		{"invalid json", `{"searches":`, true},
+		{"nothing to run", `{}`, true},
+		{"search without root", `{"searches":[{"pattern":"src"}]}`, true},
+		{"unknown audit kind", `{"audits":[{"kind":"huge_dirs","root":"."}]}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, "report.json")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("failed to write report file: %v", err)
+			}
+			cfg, err := LoadConfig(path)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !filepath.IsAbs(cfg.Searches[0].Root) || !filepath.IsAbs(cfg.Audits[0].Root) {
+				t.Errorf("expected roots to be made absolute, got %q and %q", cfg.Searches[0].Root, cfg.Audits[0].Root)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	root := makeTree(t, "projects/old/.git", "projects/new/.git", "projects/plain", "media/big", "media/small", "empty", "node_modules/pkg")
+	defer os.RemoveAll(root)
+
+	writeFile(t, filepath.Join(root, "projects/old/main.go"), 10)
+	writeFile(t, filepath.Join(root, "projects/new/main.go"), 10)
+	writeFile(t, filepath.Join(root, "projects/plain/notes.txt"), 10)
+	writeFile(t, filepath.Join(root, "media/big/video.bin"), 2<<20)
+	writeFile(t, filepath.Join(root, "media/small/clip.bin"), 10)
+
+	now := time.Now()
+	old := now.AddDate(0, 0, -400)
+	for _, p := range []string{"projects/old/main.go", "projects/old", "projects/plain/notes.txt", "projects/plain"} {
+		if err := os.Chtimes(filepath.Join(root, p), old, old); err != nil {
+			t.Fatalf("failed to age %s: %v", p, err)
+		}
+	}
+
+	cfg := &Config{
+		Searches: []Search{{Root: root, Pattern: "proj"}},
+		Audits: []Audit{
+			{Kind: EmptyDirs, Root: root},
+			{Kind: StaleProjects, Root: filepath.Join(root, "projects")},
+			{Kind: LargeDirs, Root: filepath.Join(root, "media"), MinSizeMB: 1},
+			{Name: "missing", Kind: EmptyDirs, Root: filepath.Join(root, "does-not-exist")},
+		},
+	}
+	r := Run(context.Background(), cfg, []string{".git", "node_modules"}, now)
+
+	if len(r.Sections) != 5 {
+		t.Fatalf("expected 5 sections, got %d", len(r.Sections))
+	}
+
+	expected := [][]string{
+		{filepath.Join(root, "projects")},
+		{filepath.Join(root, "empty")},
+		{filepath.Join(root, "projects/old")},
+		{filepath.Join(root, "media/big")},
+	}
+	for i, want := range expected {
+		s := r.Sections[i]
+		if s.Error != "" {
+			t.Errorf("section %s: unexpected error: %s", s.Name, s.Error)
+		}
+		if got := paths(s); strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("section %s: expected %v, got %v", s.Name, want, got)
+		}
+	}
+
+	if r.Sections[0].Name != "proj" {
+		t.Errorf("expected search section to be named after its pattern, got %q", r.Sections[0].Name)
+	}
+	if f := r.Sections[3].Findings; len(f) == 1 && f[0].Size != 2<<20 {
+		t.Errorf("expected large directory size %d, got %d", 2<<20, f[0].Size)
+	}
+	if f := r.Sections[2].Findings; len(f) == 1 && f[0].ModTime.After(now.AddDate(0, 0, -DefaultStaleDays)) {
+		t.Errorf("expected stale project to report its last change, got %v", f[0].ModTime)
+	}
+
+	if r.Sections[4].Error == "" {
+		t.Error("expected error for missing audit root")
+	}
+	if !r.Failed() {
+		t.Error("expected report to be marked as failed")
+	}
+}
+
+func TestRun_Roots(t *testing.T) {
+	root := makeTree(t, "code/api", "work/api-docs", "work/billing")
+	defer os.RemoveAll(root)
+
+	cfg := &Config{
+		Searches: []Search{{Root: filepath.Join(root, "work"), Roots: []string{filepath.Join(root, "code")}, Pattern: "api"}},
+	}
+	r := Run(context.Background(), cfg, nil, time.Now())
+
+	want := []string{filepath.Join(root, "code/api"), filepath.Join(root, "work/api-docs")}
+	if got := paths(r.Sections[0]); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	var md bytes.Buffer
+	if err := r.Write(&md, FormatMarkdown); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := fmt.Sprintf("under `%s`, `%s`", filepath.Join(root, "work"), filepath.Join(root, "code")); !strings.Contains(md.String(), want) {
+		t.Errorf("expected Markdown to contain %q, got:\n%s", want, md.String())
+	}
+}
+
+func TestReport_Write(t *testing.T) {
+	r := Report{
+		GeneratedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Sections: []Section{
+			{Name: "big", Kind: LargeDirs, Root: "/data", Findings: []Finding{{Path: "/data/video", Size: 3 << 30}}},
+			{Name: "none", Kind: EmptyDirs, Root: "/data", Findings: []Finding{}},
+			{Name: "broken", Kind: StaleProjects, Root: "/gone", Findings: []Finding{}, Error: "no such directory"},
+		},
+	}
+
+	var md bytes.Buffer
+	if err := r.Write(&md, FormatMarkdown); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"## big", "- `/data/video` — 3.0 GiB", "Nothing found.", "**Error:** no such directory"} {
+		if !strings.Contains(md.String(), want) {
+			t.Errorf("expected Markdown to contain %q, got:\n%s", want, md.String())
+		}
+	}
+
+	// Ages are relative to the time of the report unless dates are asked for
+	r.Sections = append(r.Sections, Section{Name: "stale", Kind: StaleProjects, Root: "/code",
+		Findings: []Finding{{Path: "/code/old", ModTime: r.GeneratedAt.AddDate(0, 0, -45)}}})
+	md.Reset()
+	if err := r.Write(&md, FormatMarkdown); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "- `/code/old` — last modified 1 month ago"; !strings.Contains(md.String(), want) {
+		t.Errorf("expected Markdown to contain %q, got:\n%s", want, md.String())
+	}
+	r.Times = timefmt.Formatter{Style: timefmt.Absolute, DateLayout: "02.01.2006", DateTimeLayout: "02.01.2006 15:04"}
+	r.Sizes = sizefmt.SI
+	md.Reset()
+	if err := r.Write(&md, FormatMarkdown); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"Generated 01.05.2024 12:00.", "- `/code/old` — last modified 17.03.2024", "- `/data/video` — 3.2 GB"} {
+		if !strings.Contains(md.String(), want) {
+			t.Errorf("expected Markdown to contain %q, got:\n%s", want, md.String())
+		}
+	}
+	r.Sections = r.Sections[:3]
+
+	var js bytes.Buffer
+	if err := r.Write(&js, FormatJSON); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var decoded Report
+	if err := json.Unmarshal(js.Bytes(), &decoded); err != nil {
+		t.Fatalf("failed to decode JSON report: %v", err)
+	}
+	if len(decoded.Sections) != 3 || decoded.Sections[0].Findings[0].Size != 3<<30 {
+		t.Errorf("expected JSON report to round-trip, got %+v", decoded)
+	}
+	if strings.Contains(js.String(), "mod_time") {
+		t.Errorf("expected zero modification times to be omitted, got:\n%s", js.String())
+	}
+
+	if err := r.Write(&js, "yaml"); err == nil {
+		t.Error("expected error for unknown format, got nil")
+	}
+}
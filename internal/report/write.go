@@ -0,0 +1,75 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/kaczmarekdaniel/folder-search/internal/sizefmt"
+	"github.com/kaczmarekdaniel/folder-search/internal/timefmt"
+)
+
+// Output formats of a report.
+const (
+	FormatJSON     = "json"
+	FormatMarkdown = "markdown"
+)
+
+// Write writes r to w in the given format.
+//
+// Returns an error if the format is unknown or writing fails.
+func (r Report) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatJSON:
+		return r.WriteJSON(w)
+	case FormatMarkdown:
+		return r.WriteMarkdown(w)
+	}
+	return fmt.Errorf("unknown report format %q: use %s or %s", format, FormatJSON, FormatMarkdown)
+}
+
+// WriteJSON writes r to w as indented JSON.
+func (r Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteMarkdown writes r to w as a Markdown document with one section per
+// search or audit.
+func (r Report) WriteMarkdown(w io.Writer) error {
+	if _, err := fmt.Fprintf(w, "# folder-search report\n\nGenerated %s.\n", r.Times.DateTime(r.GeneratedAt)); err != nil {
+		return err
+	}
+	for _, s := range r.Sections {
+		fmt.Fprintf(w, "\n## %s\n\n", s.Name)
+		roots := "`" + strings.Join(append([]string{s.Root}, s.Roots...), "`, `") + "`"
+		fmt.Fprintf(w, "%s under %s\n\n", s.Kind, roots)
+		if s.Error != "" {
+			fmt.Fprintf(w, "**Error:** %s\n", s.Error)
+			continue
+		}
+		if len(s.Findings) == 0 {
+			fmt.Fprintln(w, "Nothing found.")
+			continue
+		}
+		for _, f := range s.Findings {
+			fmt.Fprintf(w, "- `%s`%s\n", f.Path, f.detail(r.Times, r.Sizes, r.GeneratedAt))
+		}
+	}
+	return nil
+}
+
+// detail renders the size or age of f for Markdown output, with ages
+// rendered by times as seen at now and sizes in sizes.
+func (f Finding) detail(times timefmt.Formatter, sizes sizefmt.Units, now time.Time) string {
+	switch {
+	case f.Size > 0:
+		return " — " + sizes.Format(f.Size)
+	case !f.ModTime.IsZero():
+		return " — last modified " + times.Format(f.ModTime, now)
+	}
+	return ""
+}
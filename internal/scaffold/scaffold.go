@@ -0,0 +1,58 @@
+// Package scaffold creates directory trees from templates.
+//
+// A template is a list of directory paths relative to the folder being
+// created, such as "src/", "test/" and "docs/api/".
+package scaffold
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// dirPerm is the permission used for created directories (before umask).
+const dirPerm = 0o755
+
+// Create makes the directory root and every directory listed in template
+// inside it.
+//
+// Root must not exist yet, so an existing folder is never modified. Template
+// paths must be relative and stay inside root.
+//
+// Parameters:
+//   - root: the new folder to create
+//   - template: directory paths relative to root
+//
+// Returns an error if root already exists, a template path is invalid, or a
+// directory cannot be created.
+func Create(root string, template []string) error {
+	for _, dir := range template {
+		if err := validate(dir); err != nil {
+			return err
+		}
+	}
+
+	if err := os.Mkdir(root, dirPerm); err != nil {
+		return err
+	}
+
+	for _, dir := range template {
+		if err := os.MkdirAll(filepath.Join(root, filepath.FromSlash(dir)), dirPerm); err != nil {
+			return fmt.Errorf("failed to create %q: %w", dir, err)
+		}
+	}
+	return nil
+}
+
+// validate ensures a template path is relative and cannot escape the root.
+func validate(dir string) error {
+	clean := filepath.Clean(filepath.FromSlash(dir))
+	if dir == "" || filepath.IsAbs(clean) || filepath.VolumeName(clean) != "" {
+		return fmt.Errorf("invalid template path %q: must be relative", dir)
+	}
+	if clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("invalid template path %q: must stay inside the new folder", dir)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCreate(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "scaffold-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	root := filepath.Join(tempDir, "project")
+	if err := Create(root, []string{"src/", "test/", "docs/api"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, dir := range []string{"src", "test", filepath.Join("docs", "api")} {
+		info, err := os.Stat(filepath.Join(root, dir))
+		if err != nil {
+			t.Errorf("expected %s to exist: %v", dir, err)
+			continue
+		}
+		if !info.IsDir() {
+			t.Errorf("expected %s to be a directory", dir)
+		}
+	}
+}
+
+func TestCreate_EmptyTemplate(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "scaffold-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	root := filepath.Join(tempDir, "empty")
+	if err := Create(root, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(root); err != nil {
+		t.Errorf("expected root to exist: %v", err)
+	}
+}
+
+func TestCreate_ExistingRoot(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "scaffold-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	if err := Create(tempDir, []string{"src/"}); err == nil {
+		t.Error("expected error for existing root, got nil")
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "src")); !os.IsNotExist(err) {
+		t.Error("existing root should not be modified")
+	}
+}
+
+func TestCreate_InvalidPaths(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "scaffold-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	for _, dir := range []string{"../escape", "/abs", "", "a/../../b"} {
+		root := filepath.Join(tempDir, "project")
+		if err := Create(root, []string{dir}); err == nil {
+			t.Errorf("expected error for template path %q, got nil", dir)
+		}
+		if _, err := os.Stat(root); !os.IsNotExist(err) {
+			t.Errorf("root should not be created for invalid template path %q", dir)
+			os.RemoveAll(root)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+// Package scanhistory remembers which directories are slow to scan.
+//
+// The UI uses this history to pick a scan strategy: directories that took
+// long to scan in the past are read in batches so partial results and a
+// progress indicator appear immediately, while all other directories keep
+// the simpler single-read path. Only slow directories are stored, which keeps
+// the history file small.
+package scanhistory
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/kaczmarekdaniel/folder-search/internal/statefile"
+)
+
+const (
+	// DefaultThreshold is the scan duration from which a directory is
+	// considered slow.
+	DefaultThreshold = 300 * time.Millisecond
+
+	// maxEntries bounds the number of remembered directories.
+	maxEntries = 1000
+
+	// schemaVersion is the current version of the history file format.
+	schemaVersion = 1
+)
+
+// migrations upgrade older history files to schemaVersion.
+var migrations = []statefile.Migration{
+	// Version 0 files had no version field but the same layout
+	statefile.Unchanged,
+}
+
+// History records slow directory scans.
+type History struct {
+	mu        sync.Mutex
+	path      string
+	threshold time.Duration
+	slow      map[string]time.Duration
+	changed   map[string]bool // Directories recorded since the last save
+}
+
+// fileFormat is the on-disk representation of the history.
+type fileFormat struct {
+	// SlowDirs maps a directory to its last scan duration in milliseconds
+	SlowDirs map[string]int64 `json:"slow_dirs"`
+}
+
+// DefaultPath returns the location of the history file in the user cache
+// directory.
+func DefaultPath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot locate cache directory: %w", err)
+	}
+	return filepath.Join(dir, "folder-search", "scan-history.json"), nil
+}
+
+// New returns an empty history stored at path. An empty path keeps the
+// history in memory only.
+func New(path string, threshold time.Duration) *History {
+	return &History{
+		path:      path,
+		threshold: threshold,
+		slow:      make(map[string]time.Duration),
+		changed:   make(map[string]bool),
+	}
+}
+
+// Load reads the history from path. A missing file yields an empty history.
+//
+// Parameters:
+//   - path: the history file
+//   - threshold: the scan duration from which a directory counts as slow
+//
+// Returns an error if the file exists but cannot be read or parsed.
+func Load(path string, threshold time.Duration) (*History, error) {
+	h := New(path, threshold)
+
+	var f fileFormat
+	if _, err := statefile.Load(path, schemaVersion, migrations, &f); err != nil {
+		return nil, fmt.Errorf("failed to load scan history: %w", err)
+	}
+	for dir, ms := range f.SlowDirs {
+		h.slow[dir] = time.Duration(ms) * time.Millisecond
+	}
+	return h, nil
+}
+
+// Record stores how long scanning dir took. Fast scans remove dir from the
+// history, so a directory that got smaller returns to the fast path.
+func (h *History) Record(dir string, d time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if d < h.threshold {
+		if _, ok := h.slow[dir]; ok {
+			delete(h.slow, dir)
+			h.changed[dir] = true
+		}
+		return
+	}
+
+	if _, ok := h.slow[dir]; !ok && len(h.slow) >= maxEntries {
+		evictFastest(h.slow)
+	}
+	h.slow[dir] = d
+	h.changed[dir] = true
+}
+
+// IsSlow reports whether the last recorded scan of dir was slow.
+func (h *History) IsSlow(dir string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	_, ok := h.slow[dir]
+	return ok
+}
+
+// Save writes the history back to its file if it changed since loading.
+// The directories recorded since then are merged into the history saved by
+// other processes in the meantime, which is loaded back. In-memory
+// histories are never written.
+func (h *History) Save() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.changed) == 0 || h.path == "" {
+		return nil
+	}
+
+	var f fileFormat
+	merged := make(map[string]time.Duration)
+	err := statefile.Update(h.path, schemaVersion, migrations, &f, func() {
+		for dir, ms := range f.SlowDirs {
+			merged[dir] = time.Duration(ms) * time.Millisecond
+		}
+		for dir := range h.changed {
+			if d, ok := h.slow[dir]; ok {
+				merged[dir] = d
+			} else {
+				delete(merged, dir)
+			}
+		}
+		for len(merged) > maxEntries {
+			evictFastest(merged)
+		}
+
+		f.SlowDirs = make(map[string]int64, len(merged))
+		for dir, d := range merged {
+			f.SlowDirs[dir] = d.Milliseconds()
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write scan history: %w", err)
+	}
+
+	h.slow = merged
+	clear(h.changed)
+	return nil
+}
+
+// evictFastest drops the least slow directory of slow to make room for a
+// new one.
+func evictFastest(slow map[string]time.Duration) {
+	var fastest string
+	for dir, d := range slow {
+		if fastest == "" || d < slow[fastest] {
+			fastest = dir
+		}
+	}
+	delete(slow, fastest)
+}
@@ -0,0 +1,129 @@
+package scanhistory
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func tempPath(t *testing.T) string {
+	t.Helper()
+	dir, err := os.MkdirTemp("", "scanhistory-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return filepath.Join(dir, "nested", "history.json")
+}
+
+func TestRecordAndIsSlow(t *testing.T) {
+	h, err := Load(tempPath(t), 100*time.Millisecond)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	h.Record("/fast", 10*time.Millisecond)
+	h.Record("/slow", 500*time.Millisecond)
+
+	if h.IsSlow("/fast") {
+		t.Error("expected /fast not to be slow")
+	}
+	if !h.IsSlow("/slow") {
+		t.Error("expected /slow to be slow")
+	}
+
+	// A fast scan resets the directory to the fast path
+	h.Record("/slow", 20*time.Millisecond)
+	if h.IsSlow("/slow") {
+		t.Error("expected /slow to be forgotten after a fast scan")
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	path := tempPath(t)
+
+	h, err := Load(path, 100*time.Millisecond)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	h.Record("/slow", time.Second)
+	if err := h.Save(); err != nil {
+		t.Fatalf("unexpected error saving: %v", err)
+	}
+
+	loaded, err := Load(path, 100*time.Millisecond)
+	if err != nil {
+		t.Fatalf("unexpected error loading: %v", err)
+	}
+	if !loaded.IsSlow("/slow") {
+		t.Error("expected /slow to survive a save/load round trip")
+	}
+}
+
+func TestSave_Unchanged(t *testing.T) {
+	path := tempPath(t)
+
+	h, err := Load(path, time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := h.Save(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("expected no file to be written for an unchanged history")
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	path := tempPath(t)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte("{"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	if _, err := Load(path, time.Second); err == nil {
+		t.Error("expected error for invalid history, got nil")
+	}
+}
+
+func TestRecord_Eviction(t *testing.T) {
+	h, err := Load(tempPath(t), time.Millisecond)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i := 0; i < maxEntries; i++ {
+		h.Record(filepath.Join("/dir", string(rune('a'+i%26)), time.Duration(i).String()), time.Second+time.Duration(i))
+	}
+	h.Record("/newest", time.Hour)
+
+	if len(h.slow) != maxEntries {
+		t.Errorf("expected history to be capped at %d entries, got %d", maxEntries, len(h.slow))
+	}
+	if !h.IsSlow("/newest") {
+		t.Error("expected newest entry to be kept")
+	}
+}
+
+func TestLoad_Unversioned(t *testing.T) {
+	path := tempPath(t)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	// Files written before the history was versioned
+	if err := os.WriteFile(path, []byte(`{"slow_dirs": {"/slow": 900}}`), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	h, err := Load(path, time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !h.IsSlow("/slow") {
+		t.Error("expected /slow to be loaded from an unversioned file")
+	}
+}
@@ -0,0 +1,206 @@
+// Package searchhistory keeps the patterns the user searched for and the
+// directories they selected, so both can be recalled later.
+//
+// Entries are kept newest last, without duplicates: searching for a pattern
+// again, or selecting a directory again, moves it to the end. Only the
+// latest MaxEntries of each are kept. The history is stored in history.json
+// in the user data directory.
+package searchhistory
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kaczmarekdaniel/folder-search/internal/statefile"
+)
+
+// MaxEntries is the number of searches, and of selections, kept.
+const MaxEntries = 100
+
+// schemaVersion is the current version of the history file format.
+const schemaVersion = 1
+
+// migrations upgrade older history files to schemaVersion. There are none
+// yet, as history files have carried a version from the start.
+var migrations = []statefile.Migration{}
+
+// Search is a pattern the user searched for.
+type Search struct {
+	// Pattern is the search pattern as typed, without surrounding spaces
+	Pattern string `json:"pattern"`
+
+	// Time is when the pattern was last searched for
+	Time time.Time `json:"time"`
+}
+
+// Selection is a directory the user selected.
+type Selection struct {
+	// Path is the absolute path of the directory
+	Path string `json:"path"`
+
+	// Pattern is the search pattern that listed the directory, empty if
+	// it was selected without searching
+	Pattern string `json:"pattern,omitempty"`
+
+	// Time is when the directory was last selected
+	Time time.Time `json:"time"`
+}
+
+// History is a persistent record of searches and selections.
+type History struct {
+	mu         sync.Mutex
+	path       string
+	searches   []Search
+	selections []Selection
+
+	// Searches and selections recorded since the last save
+	newSearches   []Search
+	newSelections []Selection
+}
+
+// fileFormat is the on-disk representation of the history.
+type fileFormat struct {
+	// Searches lists the searches, oldest first
+	Searches []Search `json:"searches"`
+
+	// Selections lists the selections, oldest first
+	Selections []Selection `json:"selections"`
+}
+
+// DefaultPath returns the location of the history file in the user data
+// directory.
+func DefaultPath() (string, error) {
+	return statefile.DataPath("history.json")
+}
+
+// New returns an empty history stored at path. An empty path keeps the
+// history in memory only.
+func New(path string) *History {
+	return &History{path: path}
+}
+
+// Load reads the history from path. A missing file yields an empty history.
+//
+// Returns an error if the file exists but cannot be read or parsed.
+func Load(path string) (*History, error) {
+	h := New(path)
+
+	var f fileFormat
+	if _, err := statefile.Load(path, schemaVersion, migrations, &f); err != nil {
+		return nil, fmt.Errorf("failed to load search history: %w", err)
+	}
+	h.searches = f.Searches
+	h.selections = f.Selections
+	return h, nil
+}
+
+// AddSearch records a search for pattern. Empty patterns are ignored.
+func (h *History) AddSearch(pattern string) {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s := Search{Pattern: pattern, Time: time.Now()}
+	h.searches = addSearch(h.searches, s)
+	h.newSearches = append(h.newSearches, s)
+}
+
+// AddSelection records that the directory at path was selected.
+//
+// Parameters:
+//   - path: the absolute path of the directory
+//   - pattern: the search pattern that listed it, empty if none
+func (h *History) AddSelection(path, pattern string) {
+	path = filepath.Clean(path)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s := Selection{Path: path, Pattern: strings.TrimSpace(pattern), Time: time.Now()}
+	h.selections = addSelection(h.selections, s)
+	h.newSelections = append(h.newSelections, s)
+}
+
+// addSearch moves or appends s to the end of searches, keeping the latest
+// MaxEntries.
+func addSearch(searches []Search, s Search) []Search {
+	searches = slices.DeleteFunc(searches, func(old Search) bool { return old.Pattern == s.Pattern })
+	searches = append(searches, s)
+	return searches[max(len(searches)-MaxEntries, 0):]
+}
+
+// addSelection moves or appends s to the end of selections, keeping the
+// latest MaxEntries.
+func addSelection(selections []Selection, s Selection) []Selection {
+	selections = slices.DeleteFunc(selections, func(old Selection) bool { return old.Path == s.Path })
+	selections = append(selections, s)
+	return selections[max(len(selections)-MaxEntries, 0):]
+}
+
+// Searches returns the patterns searched for that start with prefix,
+// ignoring case, newest first. An empty prefix returns all of them.
+func (h *History) Searches(prefix string) []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	prefix = strings.ToLower(prefix)
+	var patterns []string
+	for _, s := range slices.Backward(h.searches) {
+		if strings.HasPrefix(strings.ToLower(s.Pattern), prefix) {
+			patterns = append(patterns, s.Pattern)
+		}
+	}
+	return patterns
+}
+
+// Selections returns at most n of the latest selections, newest first, or
+// all of them if n is not positive.
+func (h *History) Selections(n int) []Selection {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if n <= 0 || n > len(h.selections) {
+		n = len(h.selections)
+	}
+	latest := slices.Clone(h.selections[len(h.selections)-n:])
+	slices.Reverse(latest)
+	return latest
+}
+
+// Save writes the history back to its file if it changed since loading.
+// The searches and selections recorded since then are added to the history
+// saved by other processes in the meantime, which is loaded back. In-memory
+// histories are never written.
+func (h *History) Save() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.newSearches) == 0 && len(h.newSelections) == 0 || h.path == "" {
+		return nil
+	}
+
+	var f fileFormat
+	err := statefile.Update(h.path, schemaVersion, migrations, &f, func() {
+		for _, s := range h.newSearches {
+			f.Searches = addSearch(f.Searches, s)
+		}
+		for _, s := range h.newSelections {
+			f.Selections = addSelection(f.Selections, s)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write search history: %w", err)
+	}
+
+	h.searches, h.selections = f.Searches, f.Selections
+	h.newSearches, h.newSelections = nil, nil
+	return nil
+}
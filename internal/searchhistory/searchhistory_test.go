@@ -0,0 +1,87 @@
+package searchhistory
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestSearches(t *testing.T) {
+	h := New("")
+	h.AddSearch("proj")
+	h.AddSearch("  ")
+	h.AddSearch("notes")
+	h.AddSearch(" Projects ")
+	h.AddSearch("proj")
+
+	if got, want := h.Searches(""), []string{"proj", "Projects", "notes"}; !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if got, want := h.Searches("PRO"), []string{"proj", "Projects"}; !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	for i := range MaxEntries + 5 {
+		h.AddSearch(fmt.Sprintf("pattern %d", i))
+	}
+	got := h.Searches("")
+	if len(got) != MaxEntries {
+		t.Fatalf("expected %d searches, got %d", MaxEntries, len(got))
+	}
+	if want := fmt.Sprintf("pattern %d", MaxEntries+4); got[0] != want {
+		t.Errorf("expected the newest search %q first, got %q", want, got[0])
+	}
+}
+
+func TestSelections(t *testing.T) {
+	h := New("")
+	h.AddSelection("/home/me/work", "wo")
+	h.AddSelection("/home/me/notes/", "")
+	h.AddSelection("/home/me/work", "work")
+
+	got := h.Selections(0)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 selections, got %v", got)
+	}
+	if got[0].Path != "/home/me/work" || got[0].Pattern != "work" {
+		t.Errorf("expected the reselected directory first with its latest pattern, got %+v", got[0])
+	}
+	if got[1].Path != "/home/me/notes" {
+		t.Errorf("expected a clean path, got %q", got[1].Path)
+	}
+	if latest := h.Selections(1); len(latest) != 1 || latest[0].Path != "/home/me/work" {
+		t.Errorf("expected only the latest selection, got %v", latest)
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	dir, err := os.MkdirTemp("", "searchhistory-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "history.json")
+
+	h, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	h.AddSearch("src")
+	h.AddSelection("/srv/src", "src")
+	if err := h.Save(); err != nil {
+		t.Fatalf("unexpected error saving: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error loading: %v", err)
+	}
+	if got := loaded.Searches(""); !slices.Equal(got, []string{"src"}) {
+		t.Errorf("expected searches to survive a save/load round trip, got %v", got)
+	}
+	if got := loaded.Selections(0); len(got) != 1 || got[0].Path != "/srv/src" {
+		t.Errorf("expected selections to survive a save/load round trip, got %v", got)
+	}
+}
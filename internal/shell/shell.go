@@ -0,0 +1,18 @@
+// Package shell runs command lines the user configures, such as preview,
+// alert and notify commands, through the system shell.
+package shell
+
+import (
+	"context"
+	"os/exec"
+	"runtime"
+)
+
+// Command returns a command running line through the system shell: sh -c
+// on Unix and cmd /C on Windows. The command is killed when ctx is done.
+func Command(ctx context.Context, line string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", line)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", line)
+}
@@ -0,0 +1,51 @@
+// Package sizefmt renders byte counts for people, such as "1.5 GiB" in
+// binary units or "1.6 GB" in SI units.
+package sizefmt
+
+import "fmt"
+
+// Units selects the units sizes are rendered in.
+type Units string
+
+const (
+	// Binary renders sizes in powers of 1024: KiB, MiB, GiB and so on
+	Binary Units = "binary"
+
+	// SI renders sizes in powers of 1000: kB, MB, GB and so on, as
+	// storage vendors and macOS do
+	SI Units = "si"
+)
+
+// ParseUnits returns the Units named by s: "binary", "si" or empty for
+// binary.
+//
+// Returns an error for any other name.
+func ParseUnits(s string) (Units, error) {
+	switch Units(s) {
+	case "", Binary:
+		return Binary, nil
+	case SI:
+		return SI, nil
+	}
+	return "", fmt.Errorf("invalid size units %q: use %s or %s", s, Binary, SI)
+}
+
+// Format renders n bytes with one decimal in the largest unit it reaches,
+// e.g. "512 B", "1.5 GiB" or "1.6 GB". The zero Units renders binary units.
+func (u Units) Format(n int64) string {
+	base, suffix := int64(1024), "iB"
+	prefixes := "KMGTPE"
+	if u == SI {
+		base, suffix = 1000, "B"
+		prefixes = "kMGTPE"
+	}
+	if n < base {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := base, 0
+	for m := n / base; m >= base; m /= base {
+		div *= base
+		exp++
+	}
+	return fmt.Sprintf("%.1f %c%s", float64(n)/float64(div), prefixes[exp], suffix)
+}
@@ -0,0 +1,43 @@
+package sizefmt
+
+import "testing"
+
+func TestUnits_Format(t *testing.T) {
+	tests := []struct {
+		units    Units
+		n        int64
+		expected string
+	}{
+		{Binary, 0, "0 B"},
+		{Binary, 1023, "1023 B"},
+		{Binary, 1024, "1.0 KiB"},
+		{Binary, 3 << 29, "1.5 GiB"},
+		{Binary, 1 << 62, "4.0 EiB"},
+		{"", 1536, "1.5 KiB"},
+		{SI, 999, "999 B"},
+		{SI, 1000, "1.0 kB"},
+		{SI, 3 << 29, "1.6 GB"},
+		{SI, 2_500_000_000_000, "2.5 TB"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.units.Format(tt.n); got != tt.expected {
+			t.Errorf("%d in %q: expected %q, got %q", tt.n, tt.units, tt.expected, got)
+		}
+	}
+}
+
+func TestParseUnits(t *testing.T) {
+	for in, expected := range map[string]Units{"": Binary, "binary": Binary, "si": SI} {
+		got, err := ParseUnits(in)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", in, err)
+		} else if got != expected {
+			t.Errorf("%q: expected %q, got %q", in, expected, got)
+		}
+	}
+
+	if _, err := ParseUnits("decimal"); err == nil {
+		t.Error("expected error for unknown units, got nil")
+	}
+}
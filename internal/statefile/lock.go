@@ -0,0 +1,108 @@
+package statefile
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// lockExt is appended to a state file path to name its lock file
+	lockExt = ".lock"
+
+	// lockRetryInterval is how often Acquire retries while the lock is held
+	lockRetryInterval = 20 * time.Millisecond
+
+	// saveLockTimeout is how long Save waits for another process to
+	// finish writing the same file
+	saveLockTimeout = 2 * time.Second
+)
+
+// errLocked is returned by tryLock when another process holds the lock.
+var errLocked = errors.New("locked")
+
+// LockedError reports that a state file is locked by another process.
+type LockedError struct {
+	// Path is the locked state file
+	Path string
+
+	// PID is the process holding the lock, zero if it is not known
+	PID int
+}
+
+func (e *LockedError) Error() string {
+	if e.PID == 0 {
+		return fmt.Sprintf("%s is locked by another process", e.Path)
+	}
+	return fmt.Sprintf("%s is locked by process %d", e.Path, e.PID)
+}
+
+// Lock is an advisory lock on a state file, held by the current process.
+type Lock struct {
+	f *os.File
+}
+
+// Acquire takes the advisory lock on the state file at path, waiting up to
+// timeout for another process to release it.
+//
+// The lock is an operating system lock (flock, or LockFileEx on Windows) on
+// a file next to path, which records the owner's PID for error messages.
+// The system releases it when its owner exits, so a crashed process never
+// leaves the file locked. The lock file itself is left in place. Every
+// process writing the file must use the same lock for it to be effective;
+// on platforms without file locking, Acquire always succeeds.
+//
+// Returns a *LockedError naming the owner if the lock is still held after
+// timeout.
+func Acquire(path string, timeout time.Duration) (*Lock, error) {
+	f, err := os.OpenFile(path+lockExt, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		err := tryLock(f)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, errLocked) {
+			f.Close()
+			return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+		if time.Now().After(deadline) {
+			pid, _ := readLockOwner(f)
+			f.Close()
+			return nil, &LockedError{Path: path, PID: pid}
+		}
+		time.Sleep(lockRetryInterval)
+	}
+
+	// The PID is informational: failing to record it leaves the lock held
+	if err := f.Truncate(0); err == nil {
+		f.WriteAt([]byte(strconv.Itoa(os.Getpid())), 0)
+	}
+	return &Lock{f: f}, nil
+}
+
+// Release releases the lock.
+func (l *Lock) Release() error {
+	err := unlock(l.f)
+	if closeErr := l.f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// readLockOwner returns the PID recorded in a lock file.
+func readLockOwner(f *os.File) (int, error) {
+	data, err := io.ReadAll(io.NewSectionReader(f, 0, 32))
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(data)))
+}
@@ -0,0 +1,14 @@
+//go:build !linux && !darwin && !freebsd && !windows
+
+package statefile
+
+import "os"
+
+// tryLock always succeeds: there is no file locking on this platform.
+func tryLock(*os.File) error {
+	return nil
+}
+
+func unlock(*os.File) error {
+	return nil
+}
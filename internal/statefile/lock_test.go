@@ -0,0 +1,130 @@
+package statefile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestAcquire(t *testing.T) {
+	path := tempPath(t)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+
+	lock, err := Acquire(path, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Our own process is alive, so a second attempt must time out
+	_, err = Acquire(path, 50*time.Millisecond)
+	var locked *LockedError
+	if !errors.As(err, &locked) {
+		t.Fatalf("expected LockedError, got %v", err)
+	}
+	if locked.PID != os.Getpid() {
+		t.Errorf("expected lock held by PID %d, got %d", os.Getpid(), locked.PID)
+	}
+
+	if err := lock.Release(); err != nil {
+		t.Fatalf("unexpected error releasing: %v", err)
+	}
+	lock, err = Acquire(path, 0)
+	if err != nil {
+		t.Fatalf("expected lock to be free after release, got %v", err)
+	}
+	lock.Release()
+}
+
+func TestAcquire_StaleLock(t *testing.T) {
+	path := tempPath(t)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+
+	// A crashed process leaves its lock file behind, but no longer locked
+	if err := os.WriteFile(path+lockExt, []byte(strconv.Itoa(1<<30)), 0644); err != nil {
+		t.Fatalf("failed to write lock: %v", err)
+	}
+
+	lock, err := Acquire(path, 0)
+	if err != nil {
+		t.Fatalf("expected stale lock to be taken over, got %v", err)
+	}
+	lock.Release()
+}
+
+func TestSave_WaitsForLock(t *testing.T) {
+	path := tempPath(t)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+
+	lock, err := Acquire(path, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		lock.Release()
+	}()
+
+	if err := Save(path, 1, state{Name: "a"}); err != nil {
+		t.Fatalf("expected Save to wait for the lock, got %v", err)
+	}
+	after, err := Acquire(path, 0)
+	if err != nil {
+		t.Fatalf("expected lock to be free after Save, got %v", err)
+	}
+	after.Release()
+}
+
+func TestUpdate_MergesUnderLock(t *testing.T) {
+	path := tempPath(t)
+	if err := Save(path, 1, state{Name: "a", Count: 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Another process saves its state while this one holds a stale copy
+	if err := Save(path, 1, state{Name: "b", Count: 2}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var s state
+	err := Update(path, 1, nil, &s, func() {
+		if _, err := Acquire(path, 0); err == nil {
+			t.Error("expected the lock to be held while merging")
+		}
+		s.Count++
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got state
+	if _, err := Load(path, 1, nil, &got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != (state{Name: "b", Count: 3}) {
+		t.Errorf("expected the change merged into the saved state, got %+v", got)
+	}
+}
+
+func TestUpdate_UnreadableFile(t *testing.T) {
+	path := tempPath(t)
+	if err := WriteFile(path, []byte("{"), 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var s state
+	if err := Update(path, 1, nil, &s, func() { t.Error("expected no merge") }); err == nil {
+		t.Fatal("expected error for an unreadable file, got nil")
+	}
+	if data, _ := os.ReadFile(path); string(data) != "{" {
+		t.Errorf("expected the file to be left untouched, got %q", data)
+	}
+}
@@ -0,0 +1,30 @@
+//go:build windows
+
+package statefile
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// lockOffset is where the locked byte lies. Windows locks keep other
+// processes from reading the locked range, so it lies past the recorded PID.
+const lockOffset = 1 << 32
+
+// tryLock takes an exclusive lock on f without waiting.
+func tryLock(f *os.File) error {
+	ol := windows.Overlapped{OffsetHigh: lockOffset >> 32}
+	err := windows.LockFileEx(windows.Handle(f.Fd()),
+		windows.LOCKFILE_EXCLUSIVE_LOCK|windows.LOCKFILE_FAIL_IMMEDIATELY, 0, 1, 0, &ol)
+	if errors.Is(err, windows.ERROR_LOCK_VIOLATION) {
+		return errLocked
+	}
+	return err
+}
+
+func unlock(f *os.File) error {
+	ol := windows.Overlapped{OffsetHigh: lockOffset >> 32}
+	return windows.UnlockFileEx(windows.Handle(f.Fd()), 0, 1, 0, &ol)
+}
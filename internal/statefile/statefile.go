@@ -0,0 +1,252 @@
+// Package statefile reads and writes the JSON files in which folder-search
+// keeps its state (scan history, usage statistics, ...).
+//
+// Files are written atomically: data goes to a temporary file in the same
+// directory, which is synced and then renamed over the destination, so a
+// crash leaves either the old or the new file but never a truncated one.
+//
+// Every file carries a top-level "version" field. Loading a file written by
+// an older release runs the registered migrations in order before decoding,
+// and files written by a newer release are rejected rather than misread.
+//
+// Save and Update hold an advisory lock on the file while writing, so
+// several folder-search processes sharing a state file take turns. Update
+// also holds it while reloading the file, so each process merges its changes
+// into those of the others instead of overwriting them.
+package statefile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// versionKey is the top-level JSON field holding the schema version.
+const versionKey = "version"
+
+// ErrNewerVersion is returned by Load and Migrate for files written with a
+// schema version newer than the caller understands.
+var ErrNewerVersion = errors.New("file was written by a newer version of folder-search")
+
+// appDir is the application directory inside the user data directory.
+const appDir = "folder-search"
+
+// DataPath returns the location of the named state file in the user data
+// directory: $XDG_DATA_HOME/folder-search, falling back to
+// ~/.local/share/folder-search when XDG_DATA_HOME is not set.
+func DataPath(name string) (string, error) {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("cannot locate data directory: %w", err)
+		}
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dataHome, appDir, name), nil
+}
+
+// Migration upgrades the top-level fields of a state file by one schema
+// version. Fields may be added, renamed or removed in place.
+type Migration func(fields map[string]json.RawMessage) error
+
+// WriteFile atomically replaces path with data.
+//
+// Missing parent directories are created. On failure the previous content
+// of path, if any, is left untouched.
+func WriteFile(path string, data []byte, perm fs.FileMode) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	// Remove the temporary file unless it was renamed into place
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+
+	syncDir(dir)
+	return nil
+}
+
+// Save encodes v as JSON, stamps it with version and writes it atomically
+// while holding the file's lock. v must encode to a JSON object.
+//
+// Save replaces whatever the file holds; state that other processes also
+// change should be written with Update instead.
+//
+// Returns a *LockedError if another process holds the lock for too long.
+func Save(path string, version int, v any) error {
+	data, err := encode(version, v)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	lock, err := Acquire(path, saveLockTimeout)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	return WriteFile(path, data, 0o644)
+}
+
+// Update merges changes into the state file at path, holding the file's
+// lock from reading to writing so that changes saved by other processes in
+// the meantime are kept.
+//
+// Parameters:
+//   - path, version, migrations: as for Load
+//   - v: pointer to an empty value, into which the file is loaded
+//   - merge: applies the caller's changes to v once it is loaded
+//
+// The merged v is written as by Save. Nothing is written if the file cannot
+// be loaded. Returns a *LockedError if another process holds the lock for
+// too long.
+func Update(path string, version int, migrations []Migration, v any, merge func()) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	lock, err := Acquire(path, saveLockTimeout)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	if _, err := Load(path, version, migrations, v); err != nil {
+		return err
+	}
+	merge()
+
+	data, err := encode(version, v)
+	if err != nil {
+		return err
+	}
+	return WriteFile(path, data, 0o644)
+}
+
+// encode encodes v as indented JSON stamped with version.
+func encode(version int, v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("state must be a JSON object: %w", err)
+	}
+	fields[versionKey] = json.RawMessage(fmt.Sprint(version))
+
+	return json.MarshalIndent(fields, "", "  ")
+}
+
+// Load reads the state file at path into v, migrating it to version first.
+//
+// Parameters:
+//   - path: the state file
+//   - version: the schema version v corresponds to
+//   - migrations: migrations[i] upgrades a file from version i to i+1;
+//     files without a version field are treated as version 0
+//   - v: pointer to the value to decode into
+//
+// Returns false if the file does not exist, leaving v untouched. Returns
+// ErrNewerVersion for files with a version above the given one.
+func Load(path string, version int, migrations []Migration, v any) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return false, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	if _, err := Migrate(fields, version, migrations); err != nil {
+		return false, fmt.Errorf("%s: %w", path, err)
+	}
+	delete(fields, versionKey)
+
+	data, err = json.Marshal(fields)
+	if err != nil {
+		return false, err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	return true, nil
+}
+
+// Migrate upgrades the top-level fields of a decoded state file to version
+// in place and stamps them with it. migrations are as for Load.
+//
+// Returns the version the fields had before, or ErrNewerVersion if it is
+// above the given one.
+func Migrate(fields map[string]json.RawMessage, version int, migrations []Migration) (int, error) {
+	from := 0
+	if raw, ok := fields[versionKey]; ok {
+		if err := json.Unmarshal(raw, &from); err != nil {
+			return 0, fmt.Errorf("invalid version: %w", err)
+		}
+	}
+	if from > version {
+		return from, fmt.Errorf("%w: version %d, expected at most %d", ErrNewerVersion, from, version)
+	}
+
+	for v := from; v < version; v++ {
+		if v >= len(migrations) || migrations[v] == nil {
+			return from, fmt.Errorf("no migration from version %d", v)
+		}
+		if err := migrations[v](fields); err != nil {
+			return from, fmt.Errorf("failed to migrate from version %d: %w", v, err)
+		}
+	}
+	fields[versionKey] = json.RawMessage(fmt.Sprint(version))
+	return from, nil
+}
+
+// Unchanged is a Migration for version bumps that kept the file layout, such
+// as adding the version field to files that had none.
+func Unchanged(map[string]json.RawMessage) error { return nil }
+
+// syncDir flushes the directory entry of a rename to disk. Errors are
+// ignored: not every platform supports syncing directories, and the rename
+// itself has already succeeded.
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	d.Sync()
+	d.Close()
+}
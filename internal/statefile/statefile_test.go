@@ -0,0 +1,149 @@
+package statefile
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type state struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func tempPath(t *testing.T) string {
+	t.Helper()
+	dir, err := os.MkdirTemp("", "statefile-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return filepath.Join(dir, "nested", "state.json")
+}
+
+func TestWriteFile(t *testing.T) {
+	path := tempPath(t)
+
+	if err := WriteFile(path, []byte("first"), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := WriteFile(path, []byte("second"), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	if string(data) != "second" {
+		t.Errorf("expected %q, got %q", "second", data)
+	}
+
+	// No temporary files may be left behind
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatalf("failed to read dir: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected only the state file, got %d entries", len(entries))
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	path := tempPath(t)
+
+	if err := Save(path, 2, state{Name: "a", Count: 3}); err != nil {
+		t.Fatalf("unexpected error saving: %v", err)
+	}
+
+	var got state
+	found, err := Load(path, 2, []Migration{Unchanged, Unchanged}, &got)
+	if err != nil {
+		t.Fatalf("unexpected error loading: %v", err)
+	}
+	if !found {
+		t.Fatal("expected file to be found")
+	}
+	if got != (state{Name: "a", Count: 3}) {
+		t.Errorf("expected round-tripped state, got %+v", got)
+	}
+}
+
+func TestLoad_Missing(t *testing.T) {
+	var got state
+	found, err := Load(tempPath(t), 1, nil, &got)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if found {
+		t.Error("expected missing file to be reported as not found")
+	}
+}
+
+func TestLoad_Migrates(t *testing.T) {
+	path := tempPath(t)
+	// An unversioned file that still uses the old "title" field
+	if err := WriteFile(path, []byte(`{"title": "old", "count": 1}`), 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	renameTitle := func(fields map[string]json.RawMessage) error {
+		fields["name"] = fields["title"]
+		delete(fields, "title")
+		return nil
+	}
+
+	var got state
+	if _, err := Load(path, 2, []Migration{Unchanged, renameTitle}, &got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Name != "old" || got.Count != 1 {
+		t.Errorf("expected migrated state, got %+v", got)
+	}
+}
+
+func TestLoad_NewerVersion(t *testing.T) {
+	path := tempPath(t)
+	if err := Save(path, 3, state{}); err != nil {
+		t.Fatalf("unexpected error saving: %v", err)
+	}
+
+	var got state
+	if _, err := Load(path, 2, []Migration{Unchanged, Unchanged}, &got); !errors.Is(err, ErrNewerVersion) {
+		t.Errorf("expected ErrNewerVersion, got %v", err)
+	}
+}
+
+func TestLoad_MissingMigration(t *testing.T) {
+	path := tempPath(t)
+	if err := WriteFile(path, []byte(`{}`), 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	var got state
+	if _, err := Load(path, 1, nil, &got); err == nil {
+		t.Error("expected error for missing migration, got nil")
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	fields := map[string]json.RawMessage{"version": json.RawMessage("1"), "title": json.RawMessage(`"old"`)}
+	renameTitle := func(fields map[string]json.RawMessage) error {
+		fields["name"] = fields["title"]
+		delete(fields, "title")
+		return nil
+	}
+
+	from, err := Migrate(fields, 2, []Migration{Unchanged, renameTitle})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if from != 1 {
+		t.Errorf("expected original version 1, got %d", from)
+	}
+	if string(fields["name"]) != `"old"` || string(fields["version"]) != "2" {
+		t.Errorf("expected renamed field at version 2, got %v", fields)
+	}
+}
@@ -0,0 +1,248 @@
+// Package stats records local usage statistics.
+//
+// Statistics cover the directories a user visits, the actions they trigger
+// and how long directory scans take. They are stored in a JSON file in the
+// user data directory, shown by the stats command and never sent anywhere.
+package stats
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kaczmarekdaniel/folder-search/internal/statefile"
+)
+
+// schemaVersion is the current version of the statistics file format.
+const schemaVersion = 1
+
+// migrations upgrade older statistics files to schemaVersion.
+var migrations = []statefile.Migration{
+	// Version 0 files had no version field but the same layout
+	statefile.Unchanged,
+}
+
+// Stats holds usage statistics and the file they are persisted to.
+type Stats struct {
+	mu      sync.Mutex
+	path    string
+	data    fileFormat
+	pending fileFormat // Counts recorded since the last save
+	dirty   bool
+}
+
+// Count is a named counter, e.g. a directory and its number of visits.
+type Count struct {
+	Name  string
+	Count int
+}
+
+// fileFormat is the on-disk representation of the statistics.
+type fileFormat struct {
+	// Visits counts how often each directory was displayed
+	Visits map[string]int `json:"visits"`
+
+	// Actions counts how often each UI action was used
+	Actions map[string]int `json:"actions"`
+
+	// Scans and ScanTotalMs give the average directory scan time
+	Scans       int   `json:"scans"`
+	ScanTotalMs int64 `json:"scan_total_ms"`
+}
+
+// DefaultPath returns the location of the statistics file:
+// $XDG_DATA_HOME/folder-search/stats.json, falling back to
+// ~/.local/share when XDG_DATA_HOME is not set.
+func DefaultPath() (string, error) {
+	return statefile.DataPath("stats.json")
+}
+
+// New returns empty statistics stored at path. An empty path keeps the
+// statistics in memory only.
+func New(path string) *Stats {
+	return &Stats{
+		path:    path,
+		data:    newFileFormat(),
+		pending: newFileFormat(),
+	}
+}
+
+// newFileFormat returns statistics with nothing counted.
+func newFileFormat() fileFormat {
+	return fileFormat{
+		Visits:  make(map[string]int),
+		Actions: make(map[string]int),
+	}
+}
+
+// add adds the counts of other to f.
+func (f *fileFormat) add(other fileFormat) {
+	if f.Visits == nil {
+		f.Visits = make(map[string]int)
+	}
+	if f.Actions == nil {
+		f.Actions = make(map[string]int)
+	}
+	for dir, n := range other.Visits {
+		f.Visits[dir] += n
+	}
+	for name, n := range other.Actions {
+		f.Actions[name] += n
+	}
+	f.Scans += other.Scans
+	f.ScanTotalMs += other.ScanTotalMs
+}
+
+// Load reads statistics from path. A missing file yields empty statistics.
+//
+// Returns an error if the file exists but cannot be read or parsed.
+func Load(path string) (*Stats, error) {
+	s := New(path)
+
+	var f fileFormat
+	if _, err := statefile.Load(path, schemaVersion, migrations, &f); err != nil {
+		return nil, fmt.Errorf("failed to load stats: %w", err)
+	}
+	s.data.add(f)
+	return s, nil
+}
+
+// RecordVisit counts a visit to dir.
+func (s *Stats) RecordVisit(dir string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data.Visits[dir]++
+	s.pending.Visits[dir]++
+	s.dirty = true
+}
+
+// RecordAction counts a use of the named action.
+func (s *Stats) RecordAction(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data.Actions[name]++
+	s.pending.Actions[name]++
+	s.dirty = true
+}
+
+// RecordScan adds the duration of a directory scan to the average.
+func (s *Stats) RecordScan(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	scan := fileFormat{Scans: 1, ScanTotalMs: d.Milliseconds()}
+	s.data.add(scan)
+	s.pending.add(scan)
+	s.dirty = true
+}
+
+// TopVisits returns up to n directories ordered by number of visits.
+func (s *Stats) TopVisits(n int) []Count {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return top(s.data.Visits, n)
+}
+
+// TopActions returns up to n actions ordered by number of uses.
+func (s *Stats) TopActions(n int) []Count {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return top(s.data.Actions, n)
+}
+
+// AverageScan returns the mean scan duration, or zero if nothing was scanned.
+func (s *Stats) AverageScan() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.data.Scans == 0 {
+		return 0
+	}
+	return time.Duration(s.data.ScanTotalMs/int64(s.data.Scans)) * time.Millisecond
+}
+
+// WriteReport prints a human-readable summary of the statistics to w.
+//
+// Parameters:
+//   - w: destination of the report
+//   - n: maximum number of directories and actions listed
+func (s *Stats) WriteReport(w io.Writer, n int) error {
+	visits := s.TopVisits(n)
+	actions := s.TopActions(n)
+
+	s.mu.Lock()
+	scans := s.data.Scans
+	s.mu.Unlock()
+
+	if _, err := fmt.Fprintln(w, "Most visited directories:"); err != nil {
+		return err
+	}
+	if len(visits) == 0 {
+		fmt.Fprintln(w, "  none yet")
+	}
+	for _, c := range visits {
+		fmt.Fprintf(w, "  %6d  %s\n", c.Count, c.Name)
+	}
+
+	fmt.Fprintln(w, "\nMost used actions:")
+	if len(actions) == 0 {
+		fmt.Fprintln(w, "  none yet")
+	}
+	for _, c := range actions {
+		fmt.Fprintf(w, "  %6d  %s\n", c.Count, c.Name)
+	}
+
+	_, err := fmt.Fprintf(w, "\nDirectory scans: %d (average %s)\n", scans, s.AverageScan())
+	return err
+}
+
+// Save writes the statistics back to their file if they changed since
+// loading. The counts recorded since then are added to those saved by other
+// processes in the meantime, which are loaded back. In-memory statistics are
+// never written.
+func (s *Stats) Save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.dirty || s.path == "" {
+		return nil
+	}
+
+	var f fileFormat
+	err := statefile.Update(s.path, schemaVersion, migrations, &f, func() {
+		f.add(s.pending)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write stats: %w", err)
+	}
+
+	s.data = f
+	s.pending = newFileFormat()
+	s.dirty = false
+	return nil
+}
+
+// top returns up to n entries of counts, highest count first and ties
+// broken by name.
+func top(counts map[string]int, n int) []Count {
+	result := make([]Count, 0, len(counts))
+	for name, count := range counts {
+		result = append(result, Count{Name: name, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Name < result[j].Name
+	})
+	if n >= 0 && len(result) > n {
+		result = result[:n]
+	}
+	return result
+}
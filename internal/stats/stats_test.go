@@ -0,0 +1,149 @@
+package stats
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func tempPath(t *testing.T) string {
+	t.Helper()
+	dir, err := os.MkdirTemp("", "stats-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return filepath.Join(dir, "nested", "stats.json")
+}
+
+func TestRecordAndTop(t *testing.T) {
+	s := New("")
+
+	s.RecordVisit("/b")
+	s.RecordVisit("/a")
+	s.RecordVisit("/b")
+	s.RecordVisit("/c")
+	s.RecordAction("size")
+
+	visits := s.TopVisits(2)
+	if len(visits) != 2 {
+		t.Fatalf("expected 2 visits, got %d", len(visits))
+	}
+	if visits[0] != (Count{Name: "/b", Count: 2}) {
+		t.Errorf("expected /b with 2 visits first, got %+v", visits[0])
+	}
+	// Ties are ordered by name
+	if visits[1].Name != "/a" {
+		t.Errorf("expected /a second, got %q", visits[1].Name)
+	}
+
+	if actions := s.TopActions(10); len(actions) != 1 || actions[0].Name != "size" {
+		t.Errorf("expected single size action, got %+v", actions)
+	}
+}
+
+func TestAverageScan(t *testing.T) {
+	s := New("")
+	if avg := s.AverageScan(); avg != 0 {
+		t.Errorf("expected zero average without scans, got %v", avg)
+	}
+
+	s.RecordScan(100 * time.Millisecond)
+	s.RecordScan(300 * time.Millisecond)
+	if avg := s.AverageScan(); avg != 200*time.Millisecond {
+		t.Errorf("expected 200ms average, got %v", avg)
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	path := tempPath(t)
+
+	s, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s.RecordVisit("/home")
+	s.RecordAction("archive")
+	s.RecordScan(time.Second)
+	if err := s.Save(); err != nil {
+		t.Fatalf("unexpected error saving: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error loading: %v", err)
+	}
+	if v := loaded.TopVisits(1); len(v) != 1 || v[0].Name != "/home" {
+		t.Errorf("expected /home visit after reload, got %+v", v)
+	}
+	if a := loaded.TopActions(1); len(a) != 1 || a[0].Name != "archive" {
+		t.Errorf("expected archive action after reload, got %+v", a)
+	}
+	if avg := loaded.AverageScan(); avg != time.Second {
+		t.Errorf("expected 1s average after reload, got %v", avg)
+	}
+}
+
+func TestSave_AddsConcurrentCounts(t *testing.T) {
+	path := tempPath(t)
+
+	// Two processes load the same statistics and count visits
+	first, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	first.RecordVisit("/home")
+	second.RecordVisit("/home")
+	second.RecordVisit("/home")
+	for _, s := range []*Stats{first, second, first} {
+		if err := s.Save(); err != nil {
+			t.Fatalf("unexpected error saving: %v", err)
+		}
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error loading: %v", err)
+	}
+	if v := loaded.TopVisits(1); len(v) != 1 || v[0].Count != 3 {
+		t.Errorf("expected 3 visits to /home, got %+v", v)
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	path := tempPath(t)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte("not json"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	if _, err := Load(path); err == nil {
+		t.Error("expected error for invalid stats, got nil")
+	}
+}
+
+func TestWriteReport(t *testing.T) {
+	s := New("")
+	s.RecordVisit("/projects")
+	s.RecordScan(50 * time.Millisecond)
+
+	var b strings.Builder
+	if err := s.WriteReport(&b, 5); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	report := b.String()
+	for _, want := range []string{"/projects", "none yet", "Directory scans: 1 (average 50ms)"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("expected report to contain %q, got:\n%s", want, report)
+		}
+	}
+}
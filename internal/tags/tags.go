@@ -0,0 +1,180 @@
+// Package tags keeps user-defined tags on directories.
+//
+// Tags are short labels such as "work", "archive" or "todo" used to filter
+// directory listings. They are stored by absolute path in tags.json in the
+// user data directory.
+package tags
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/kaczmarekdaniel/folder-search/internal/statefile"
+)
+
+// schemaVersion is the current version of the tags file format.
+const schemaVersion = 1
+
+// migrations upgrade older tags files to schemaVersion.
+var migrations = []statefile.Migration{
+	// Files without a version field are read as they are
+	statefile.Unchanged,
+}
+
+// Tags is a persistent mapping from directories to their tags.
+type Tags struct {
+	mu      sync.Mutex
+	path    string
+	tags    map[string][]string
+	changed map[string]bool // Directories whose tags were set since the last save
+}
+
+// fileFormat is the on-disk representation of the tags.
+type fileFormat struct {
+	// Tags maps an absolute directory path to its sorted tags
+	Tags map[string][]string `json:"tags"`
+}
+
+// DefaultPath returns the location of the tags file in the user data directory.
+func DefaultPath() (string, error) {
+	return statefile.DataPath("tags.json")
+}
+
+// New returns an empty set of tags stored at path. An empty path keeps the
+// tags in memory only.
+func New(path string) *Tags {
+	return &Tags{path: path, tags: make(map[string][]string), changed: make(map[string]bool)}
+}
+
+// Load reads the tags from path. A missing file yields no tags.
+//
+// Returns an error if the file exists but cannot be read or parsed.
+func Load(path string) (*Tags, error) {
+	t := New(path)
+
+	var f fileFormat
+	if _, err := statefile.Load(path, schemaVersion, migrations, &f); err != nil {
+		return nil, fmt.Errorf("failed to load tags: %w", err)
+	}
+	for dir, list := range f.Tags {
+		if list = normalize(list); len(list) > 0 {
+			t.tags[dir] = list
+		}
+	}
+	return t, nil
+}
+
+// Parse splits a user-typed tag list such as "work, todo" into tags.
+// Tags are separated by commas or spaces, a leading "#" is dropped and
+// duplicates are removed.
+func Parse(s string) []string {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+	return normalize(fields)
+}
+
+// Get returns the sorted tags of dir.
+func (t *Tags) Get(dir string) []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return slices.Clone(t.tags[filepath.Clean(dir)])
+}
+
+// Has reports whether dir carries tag.
+func (t *Tags) Has(dir, tag string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	_, found := slices.BinarySearch(t.tags[filepath.Clean(dir)], strings.ToLower(tag))
+	return found
+}
+
+// Set replaces the tags of dir. An empty list removes all its tags.
+func (t *Tags) Set(dir string, list []string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	dir = filepath.Clean(dir)
+	list = normalize(list)
+	if slices.Equal(t.tags[dir], list) {
+		return
+	}
+	if len(list) == 0 {
+		delete(t.tags, dir)
+	} else {
+		t.tags[dir] = list
+	}
+	t.changed[dir] = true
+}
+
+// Dirs returns the directories carrying tag in alphabetical order.
+func (t *Tags) Dirs(tag string) []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tag = strings.ToLower(tag)
+	var dirs []string
+	for dir, list := range t.tags {
+		if _, found := slices.BinarySearch(list, tag); found {
+			dirs = append(dirs, dir)
+		}
+	}
+	slices.Sort(dirs)
+	return dirs
+}
+
+// Save writes the tags back to their file if they changed since loading.
+// The tags set since then are merged into those saved by other processes in
+// the meantime, which are loaded back. In-memory tags are never written.
+func (t *Tags) Save() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.changed) == 0 || t.path == "" {
+		return nil
+	}
+
+	var f fileFormat
+	merged := make(map[string][]string)
+	err := statefile.Update(t.path, schemaVersion, migrations, &f, func() {
+		for dir, list := range f.Tags {
+			if list = normalize(list); len(list) > 0 {
+				merged[dir] = list
+			}
+		}
+		for dir := range t.changed {
+			if list, ok := t.tags[dir]; ok {
+				merged[dir] = list
+			} else {
+				delete(merged, dir)
+			}
+		}
+		f.Tags = merged
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write tags: %w", err)
+	}
+
+	t.tags = merged
+	clear(t.changed)
+	return nil
+}
+
+// normalize lowercases tags, strips a leading "#" and returns them sorted
+// without duplicates or empty entries.
+func normalize(list []string) []string {
+	result := make([]string, 0, len(list))
+	for _, tag := range list {
+		tag = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+		if tag != "" {
+			result = append(result, tag)
+		}
+	}
+	slices.Sort(result)
+	return slices.Compact(result)
+}
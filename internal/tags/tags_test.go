@@ -0,0 +1,64 @@
+package tags
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	got := Parse("Work, #todo  work,,archive")
+	want := []string{"archive", "todo", "work"}
+	if !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestSetAndQuery(t *testing.T) {
+	tg := New("")
+
+	tg.Set("/srv/app/", []string{"work", "prod"})
+	tg.Set("/srv/old", []string{"archive", "work"})
+
+	if got := tg.Get("/srv/app"); !slices.Equal(got, []string{"prod", "work"}) {
+		t.Errorf("expected [prod work], got %v", got)
+	}
+	if !tg.Has("/srv/old", "Archive") {
+		t.Error("expected tag lookup to be case-insensitive")
+	}
+	if got := tg.Dirs("work"); !slices.Equal(got, []string{"/srv/app", "/srv/old"}) {
+		t.Errorf("expected both directories tagged work, got %v", got)
+	}
+
+	tg.Set("/srv/old", nil)
+	if got := tg.Dirs("archive"); len(got) != 0 {
+		t.Errorf("expected no directories tagged archive, got %v", got)
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	dir, err := os.MkdirTemp("", "tags-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "tags.json")
+
+	tg, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tg.Set("/srv/data", []string{"todo"})
+	if err := tg.Save(); err != nil {
+		t.Fatalf("unexpected error saving: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error loading: %v", err)
+	}
+	if !loaded.Has("/srv/data", "todo") {
+		t.Error("expected tag to survive a save/load round trip")
+	}
+}
@@ -0,0 +1,127 @@
+// Package terminal detects the terminal emulator hosting the application and
+// drives its remote-control interface.
+//
+// It currently supports opening a new tab already located in a given directory
+// for kitty, WezTerm and iTerm2. Each terminal is controlled through its own
+// command-line tool (kitty @, wezterm cli, osascript).
+package terminal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Kind identifies a terminal emulator.
+type Kind string
+
+const (
+	// Unknown is returned when the hosting terminal could not be identified
+	// or is not supported.
+	Unknown Kind = ""
+
+	// Kitty is the kitty terminal (https://sw.kovidgoyal.net/kitty/).
+	Kitty Kind = "kitty"
+
+	// WezTerm is the WezTerm terminal (https://wezfurlong.org/wezterm/).
+	WezTerm Kind = "wezterm"
+
+	// ITerm is iTerm2 on macOS.
+	ITerm Kind = "iterm"
+)
+
+// ErrUnsupported is returned when the hosting terminal has no known
+// remote-control protocol.
+var ErrUnsupported = errors.New("unsupported terminal: cannot open a new tab")
+
+// iTermScript opens a new tab in the current iTerm2 window and changes into
+// the directory passed as the first script argument.
+var iTermScript = []string{
+	"on run argv",
+	`tell application "iTerm2"`,
+	"tell current window",
+	"create tab with default profile",
+	`tell current session to write text "cd " & quoted form of (item 1 of argv)`,
+	"end tell",
+	"end tell",
+	"end run",
+}
+
+// Detect identifies the terminal emulator from the process environment.
+//
+// Returns Unknown if none of the supported terminals is detected.
+func Detect() Kind {
+	return detect(os.Getenv)
+}
+
+// detect identifies the terminal using the given environment lookup function.
+//
+// Terminal-specific variables are checked before TERM_PROGRAM, because the
+// latter is often inherited by nested sessions (e.g. ssh or tmux).
+func detect(getenv func(string) string) Kind {
+	if getenv("KITTY_WINDOW_ID") != "" || getenv("TERM") == "xterm-kitty" {
+		return Kitty
+	}
+	if getenv("WEZTERM_PANE") != "" {
+		return WezTerm
+	}
+
+	switch getenv("TERM_PROGRAM") {
+	case "WezTerm":
+		return WezTerm
+	case "iTerm.app":
+		return ITerm
+	}
+
+	return Unknown
+}
+
+// NewTabCommand builds the command that opens a new tab in dir for the given
+// terminal.
+//
+// Parameters:
+//   - kind: the terminal to control
+//   - dir: the working directory of the new tab
+//
+// Returns ErrUnsupported if kind has no known remote-control protocol.
+func NewTabCommand(kind Kind, dir string) (*exec.Cmd, error) {
+	switch kind {
+	case Kitty:
+		return exec.Command("kitty", "@", "launch", "--type=tab", "--cwd="+dir), nil
+	case WezTerm:
+		return exec.Command("wezterm", "cli", "spawn", "--cwd", dir), nil
+	case ITerm:
+		args := make([]string, 0, 2*len(iTermScript)+1)
+		for _, line := range iTermScript {
+			args = append(args, "-e", line)
+		}
+		args = append(args, dir)
+		return exec.Command("osascript", args...), nil
+	default:
+		return nil, ErrUnsupported
+	}
+}
+
+// OpenTab opens a new tab of the hosting terminal with dir as its working
+// directory.
+//
+// Returns ErrUnsupported if the terminal could not be detected, or an error
+// including the command output if the remote-control command fails.
+func OpenTab(dir string) error {
+	cmd, err := NewTabCommand(Detect(), dir)
+	if err != nil {
+		return err
+	}
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		msg := strings.TrimSpace(string(out))
+		if msg == "" {
+			return fmt.Errorf("%s: %w", cmd.Args[0], err)
+		}
+		return fmt.Errorf("%s: %w: %s", cmd.Args[0], err, msg)
+	}
+
+	return nil
+}
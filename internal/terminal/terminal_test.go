@@ -0,0 +1,86 @@
+package terminal
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want Kind
+	}{
+		{"kitty window id", map[string]string{"KITTY_WINDOW_ID": "1"}, Kitty},
+		{"kitty term", map[string]string{"TERM": "xterm-kitty"}, Kitty},
+		{"wezterm pane", map[string]string{"WEZTERM_PANE": "0"}, WezTerm},
+		{"wezterm program", map[string]string{"TERM_PROGRAM": "WezTerm"}, WezTerm},
+		{"iterm program", map[string]string{"TERM_PROGRAM": "iTerm.app"}, ITerm},
+		{"kitty wins over inherited program", map[string]string{"KITTY_WINDOW_ID": "1", "TERM_PROGRAM": "iTerm.app"}, Kitty},
+		{"unknown", map[string]string{"TERM_PROGRAM": "Apple_Terminal"}, Unknown},
+		{"empty", map[string]string{}, Unknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := detect(func(key string) string { return tt.env[key] })
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNewTabCommand(t *testing.T) {
+	const dir = "/tmp/some dir"
+
+	t.Run("kitty", func(t *testing.T) {
+		cmd, err := NewTabCommand(Kitty, dir)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []string{"kitty", "@", "launch", "--type=tab", "--cwd=" + dir}
+		assertArgs(t, cmd.Args, want)
+	})
+
+	t.Run("wezterm", func(t *testing.T) {
+		cmd, err := NewTabCommand(WezTerm, dir)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []string{"wezterm", "cli", "spawn", "--cwd", dir}
+		assertArgs(t, cmd.Args, want)
+	})
+
+	t.Run("iterm passes directory as argument", func(t *testing.T) {
+		cmd, err := NewTabCommand(ITerm, dir)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cmd.Args[0] != "osascript" {
+			t.Errorf("expected osascript, got %q", cmd.Args[0])
+		}
+		if last := cmd.Args[len(cmd.Args)-1]; last != dir {
+			t.Errorf("expected directory as last argument, got %q", last)
+		}
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		_, err := NewTabCommand(Unknown, dir)
+		if !errors.Is(err, ErrUnsupported) {
+			t.Errorf("expected ErrUnsupported, got %v", err)
+		}
+	})
+}
+
+func assertArgs(t *testing.T, got, want []string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("expected args %q, got %q", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("arg %d: expected %q, got %q", i, want[i], got[i])
+		}
+	}
+}
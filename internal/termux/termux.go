@@ -0,0 +1,86 @@
+// Package termux adapts folder-search to Android, where it runs inside the
+// Termux terminal app.
+//
+// Android differs from other Linux systems in ways that matter to a
+// directory browser: screens are small, shared storage (/sdcard) can only be
+// read once Termux has been granted access, and the private data of other
+// apps is off limits, including their folders in Android/data.
+package termux
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"runtime"
+	"strings"
+)
+
+// Concurrency is the number of directories recursive searches read in
+// parallel on Android, where storage is slower and battery more precious
+// than on a desktop.
+const Concurrency = 2
+
+// sharedStorage lists the paths under which Android exposes shared storage.
+var sharedStorage = []string{"/storage/emulated", "/storage/self/primary", "/sdcard", "/mnt/sdcard"}
+
+// Detected reports whether folder-search runs on Android, either built for
+// it or inside Termux.
+func Detected() bool {
+	return detect(runtime.GOOS, os.Getenv)
+}
+
+// detect reports whether the system is Android using the given operating
+// system name and environment lookup function.
+func detect(goos string, getenv func(string) string) bool {
+	return goos == "android" || getenv("TERMUX_VERSION") != "" ||
+		strings.Contains(getenv("PREFIX"), "/com.termux/")
+}
+
+// StorageError explains a permission error for path caused by Android's
+// storage restrictions, such as shared storage that Termux was not granted
+// access to. Other errors, and all errors off Android, are returned as they
+// are.
+//
+// Parameters:
+//   - path: the directory that could not be read
+//   - err: the error reading it
+//
+// Returns err, wrapped with a hint if one applies.
+func StorageError(path string, err error) error {
+	if !Detected() {
+		return err
+	}
+	return storageError(path, err)
+}
+
+// storageError implements StorageError for Android.
+func storageError(path string, err error) error {
+	if !errors.Is(err, fs.ErrPermission) {
+		return err
+	}
+	if hint := storageHint(path); hint != "" {
+		return fmt.Errorf("%w (%s)", err, hint)
+	}
+	return err
+}
+
+// storageHint returns the reason Android denies access to dir, or an
+// empty string if it is not a known restricted location.
+func storageHint(dir string) string {
+	dir = path.Clean(dir)
+	for _, root := range sharedStorage {
+		if dir != root && !strings.HasPrefix(dir, root+"/") {
+			continue
+		}
+		if strings.Contains(dir+"/", "/Android/data/") || strings.Contains(dir+"/", "/Android/obb/") {
+			return "Android only lets apps read their own folders in Android/data and Android/obb"
+		}
+		return "run termux-setup-storage and allow access to files to read shared storage"
+	}
+	if dir == "/data" || (strings.HasPrefix(dir, "/data/") && !strings.HasPrefix(dir, "/data/data/com.termux")) {
+		return "Android keeps the files of other apps private; Termux files are under $HOME"
+	}
+	return ""
+}
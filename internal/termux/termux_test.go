@@ -0,0 +1,70 @@
+package termux
+
+import (
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name string
+		goos string
+		env  map[string]string
+		want bool
+	}{
+		{"android build", "android", map[string]string{}, true},
+		{"termux version", "linux", map[string]string{"TERMUX_VERSION": "0.118.0"}, true},
+		{"termux prefix", "linux", map[string]string{"PREFIX": "/data/data/com.termux/files/usr"}, true},
+		{"other prefix", "linux", map[string]string{"PREFIX": "/usr/local"}, false},
+		{"linux", "linux", map[string]string{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := detect(tt.goos, func(key string) string { return tt.env[key] })
+			if got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestStorageError(t *testing.T) {
+	denied := &fs.PathError{Op: "open", Path: "x", Err: fs.ErrPermission}
+
+	tests := []struct {
+		name string
+		path string
+		err  error
+		hint string // Expected part of the hint; empty for none
+	}{
+		{"shared storage", "/storage/emulated/0/DCIM", denied, "termux-setup-storage"},
+		{"sdcard link", "/sdcard", denied, "termux-setup-storage"},
+		{"app data", "/storage/emulated/0/Android/data/org.example", denied, "Android/data"},
+		{"app obb", "/sdcard/Android/obb", denied, "Android/obb"},
+		{"private data", "/data/data/org.example", denied, "private"},
+		{"termux home", "/data/data/com.termux/files/home/secret", denied, ""},
+		{"other path", "/proc/1", denied, ""},
+		{"not a permission error", "/sdcard", fs.ErrNotExist, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := storageError(tt.path, tt.err)
+			if !errors.Is(got, tt.err) {
+				t.Errorf("expected error wrapping %v, got %v", tt.err, got)
+			}
+			if tt.hint == "" {
+				if got != tt.err {
+					t.Errorf("expected no hint, got %q", got)
+				}
+				return
+			}
+			if !strings.Contains(got.Error(), tt.hint) {
+				t.Errorf("expected hint containing %q, got %q", tt.hint, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+// Package timefmt renders timestamps for people: relative to the current
+// time, such as "3 days ago", or as dates written the way the user's locale
+// writes them, such as "31.12.2024" in Germany.
+package timefmt
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Style selects how Formatter.Format renders a timestamp.
+type Style string
+
+const (
+	// Relative renders how long ago a timestamp was, e.g. "3 days ago"
+	Relative Style = "relative"
+
+	// Absolute renders the date of a timestamp in the locale's format
+	Absolute Style = "absolute"
+)
+
+// ISO layouts, used for locales without a layout of their own.
+const (
+	isoDate     = time.DateOnly
+	isoDateTime = "2006-01-02 15:04"
+)
+
+// layouts holds the date layout and date and time layout of a locale.
+type layouts struct {
+	date, dateTime string
+}
+
+// localeLayouts maps locales, as language or language_TERRITORY, to the way
+// they write dates. A locale missing from the map uses the entry of its
+// language, and a language missing too uses ISO dates.
+var localeLayouts = map[string]layouts{
+	"en":    {"02/01/2006", "02/01/2006 15:04"},
+	"en_US": {"Jan 2, 2006", "Jan 2, 2006 3:04 PM"},
+	"en_CA": {isoDate, isoDateTime},
+	"de":    {"02.01.2006", "02.01.2006 15:04"},
+	"fr":    {"02/01/2006", "02/01/2006 15:04"},
+	"es":    {"02/01/2006", "02/01/2006 15:04"},
+	"it":    {"02/01/2006", "02/01/2006 15:04"},
+	"pt":    {"02/01/2006", "02/01/2006 15:04"},
+	"nl":    {"02-01-2006", "02-01-2006 15:04"},
+	"da":    {"02.01.2006", "02.01.2006 15.04"},
+	"nb":    {"02.01.2006", "02.01.2006 15:04"},
+	"fi":    {"2.1.2006", "2.1.2006 15.04"},
+	"pl":    {"02.01.2006", "02.01.2006 15:04"},
+	"cs":    {"2. 1. 2006", "2. 1. 2006 15:04"},
+	"ru":    {"02.01.2006", "02.01.2006 15:04"},
+	"uk":    {"02.01.2006", "02.01.2006 15:04"},
+	"tr":    {"02.01.2006", "02.01.2006 15:04"},
+	"ja":    {"2006/01/02", "2006/01/02 15:04"},
+	"zh":    {"2006/01/02", "2006/01/02 15:04"},
+	"ko":    {"2006. 1. 2.", "2006. 1. 2. 15:04"},
+	"sv":    {isoDate, isoDateTime},
+}
+
+// Formatter renders timestamps in a Style with the layouts of a locale.
+// The zero value renders relative times and ISO dates.
+type Formatter struct {
+	// Style is how Format and FormatTime render timestamps; empty is
+	// Relative
+	Style Style
+
+	// DateLayout is the Go layout of dates, e.g. "02.01.2006"; empty is
+	// "2006-01-02"
+	DateLayout string
+
+	// DateTimeLayout is the Go layout of dates with a time of day; empty
+	// is "2006-01-02 15:04"
+	DateTimeLayout string
+}
+
+// New returns the Formatter of a style and locale.
+//
+// Parameters:
+//   - style: "relative", "absolute" or empty for relative
+//   - locale: a locale such as "de_DE.UTF-8" choosing the date layouts;
+//     empty uses the locale of the environment, see EnvLocale
+//   - dateLayout: a Go layout such as "02 Jan 2006" replacing the date
+//     layout of the locale; empty keeps it
+//
+// Returns an error for an unknown style.
+func New(style, locale, dateLayout string) (Formatter, error) {
+	switch Style(style) {
+	case "", Relative, Absolute:
+	default:
+		return Formatter{}, fmt.Errorf("invalid time style %q: use %s or %s", style, Relative, Absolute)
+	}
+	if locale == "" {
+		locale = EnvLocale(os.Getenv)
+	}
+
+	l := layoutsOf(locale)
+	f := Formatter{Style: Style(style), DateLayout: l.date, DateTimeLayout: l.dateTime}
+	if dateLayout != "" {
+		f.DateLayout = dateLayout
+		f.DateTimeLayout = dateLayout + " 15:04"
+	}
+	return f, nil
+}
+
+// EnvLocale returns the locale used for times, read with getenv from the
+// first set of LC_ALL, LC_TIME and LANG, as in the C library.
+func EnvLocale(getenv func(string) string) string {
+	for _, name := range []string{"LC_ALL", "LC_TIME", "LANG"} {
+		if locale := getenv(name); locale != "" {
+			return locale
+		}
+	}
+	return ""
+}
+
+// layoutsOf returns the layouts of a locale such as "de_DE.UTF-8",
+// "en-GB" or "C".
+func layoutsOf(locale string) layouts {
+	locale, _, _ = strings.Cut(locale, ".")
+	locale, _, _ = strings.Cut(locale, "@")
+	locale = strings.ReplaceAll(locale, "-", "_")
+
+	lang, territory, _ := strings.Cut(locale, "_")
+	lang = strings.ToLower(lang)
+	if l, ok := localeLayouts[lang+"_"+strings.ToUpper(territory)]; ok {
+		return l
+	}
+	if l, ok := localeLayouts[lang]; ok {
+		return l
+	}
+	return layouts{isoDate, isoDateTime}
+}
+
+// Format renders t, a modification time for example, as seen at now: how
+// long ago it was, or its date.
+func (f Formatter) Format(t, now time.Time) string {
+	if f.Style == Absolute {
+		return f.Date(t)
+	}
+	return Ago(t, now)
+}
+
+// FormatTime is Format for timestamps whose time of day matters, such as
+// the time a file was deleted: absolute times include the time of day.
+func (f Formatter) FormatTime(t, now time.Time) string {
+	if f.Style == Absolute {
+		return f.DateTime(t)
+	}
+	return Ago(t, now)
+}
+
+// Date renders the date of t.
+func (f Formatter) Date(t time.Time) string {
+	return t.Format(cmp.Or(f.DateLayout, isoDate))
+}
+
+// DateTime renders the date and time of day of t.
+func (f Formatter) DateTime(t time.Time) string {
+	return t.Format(cmp.Or(f.DateTimeLayout, isoDateTime))
+}
+
+// Ago renders how long before now t was, in the largest whole unit: "just
+// now", "1 minute ago", "5 hours ago", "3 days ago", "2 months ago" or
+// "4 years ago". Months count as 30 days and years as 365. Times after now,
+// such as those of a file written on a machine with a clock ahead, are
+// "just now".
+func Ago(t, now time.Time) string {
+	age := now.Sub(t)
+	const day = 24 * time.Hour
+	switch {
+	case age < time.Minute:
+		return "just now"
+	case age < time.Hour:
+		return plural(int(age/time.Minute), "minute") + " ago"
+	case age < day:
+		return plural(int(age/time.Hour), "hour") + " ago"
+	case age < 30*day:
+		return plural(int(age/day), "day") + " ago"
+	case age < 365*day:
+		return plural(int(age/(30*day)), "month") + " ago"
+	}
+	return plural(int(age/(365*day)), "year") + " ago"
+}
+
+// plural renders n of unit, e.g. "1 day" or "3 days".
+func plural(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
@@ -0,0 +1,96 @@
+package timefmt
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAgo(t *testing.T) {
+	now := time.Date(2024, 12, 31, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		age      time.Duration
+		expected string
+	}{
+		{-time.Hour, "just now"},
+		{59 * time.Second, "just now"},
+		{time.Minute, "1 minute ago"},
+		{59 * time.Minute, "59 minutes ago"},
+		{time.Hour, "1 hour ago"},
+		{23 * time.Hour, "23 hours ago"},
+		{24 * time.Hour, "1 day ago"},
+		{3 * 24 * time.Hour, "3 days ago"},
+		{30 * 24 * time.Hour, "1 month ago"},
+		{364 * 24 * time.Hour, "12 months ago"},
+		{365 * 24 * time.Hour, "1 year ago"},
+		{4 * 365 * 24 * time.Hour, "4 years ago"},
+	}
+
+	for _, tt := range tests {
+		if got := Ago(now.Add(-tt.age), now); got != tt.expected {
+			t.Errorf("%v: expected %q, got %q", tt.age, tt.expected, got)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	date := time.Date(2024, 3, 7, 16, 30, 0, 0, time.UTC)
+	tests := []struct {
+		locale, dateLayout     string
+		expected, expectedTime string
+	}{
+		{"de_DE.UTF-8", "", "07.03.2024", "07.03.2024 16:30"},
+		{"en_US", "", "Mar 7, 2024", "Mar 7, 2024 4:30 PM"},
+		{"en-GB", "", "07/03/2024", "07/03/2024 16:30"},
+		{"ja_JP.UTF-8", "", "2024/03/07", "2024/03/07 16:30"},
+		{"sr_RS@latin", "", "2024-03-07", "2024-03-07 16:30"},
+		{"C", "", "2024-03-07", "2024-03-07 16:30"},
+		{"de_DE", "02 Jan 2006", "07 Mar 2024", "07 Mar 2024 16:30"},
+	}
+
+	for _, tt := range tests {
+		f, err := New("absolute", tt.locale, tt.dateLayout)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := f.Format(date, date.AddDate(0, 0, 3)); got != tt.expected {
+			t.Errorf("%s: expected %q, got %q", tt.locale, tt.expected, got)
+		}
+		if got := f.FormatTime(date, date.AddDate(0, 0, 3)); got != tt.expectedTime {
+			t.Errorf("%s: expected %q, got %q", tt.locale, tt.expectedTime, got)
+		}
+	}
+
+	f, err := New("", "de_DE", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := f.Format(date, date.AddDate(0, 0, 3)); got != "3 days ago" {
+		t.Errorf("expected relative times by default, got %q", got)
+	}
+
+	if _, err := New("fuzzy", "", ""); err == nil {
+		t.Error("expected error for an unknown style, got nil")
+	}
+}
+
+func TestEnvLocale(t *testing.T) {
+	env := map[string]string{"LANG": "en_US.UTF-8", "LC_TIME": "de_DE.UTF-8"}
+	if got := EnvLocale(func(name string) string { return env[name] }); got != "de_DE.UTF-8" {
+		t.Errorf("expected LC_TIME to override LANG, got %q", got)
+	}
+	env["LC_ALL"] = "C"
+	if got := EnvLocale(func(name string) string { return env[name] }); got != "C" {
+		t.Errorf("expected LC_ALL to override LC_TIME, got %q", got)
+	}
+}
+
+func TestFormatter_Zero(t *testing.T) {
+	var f Formatter
+	date := time.Date(2024, 3, 7, 16, 30, 0, 0, time.UTC)
+	if got := f.Date(date); got != "2024-03-07" {
+		t.Errorf("expected an ISO date, got %q", got)
+	}
+	if got := f.DateTime(date); got != "2024-03-07 16:30" {
+		t.Errorf("expected an ISO date and time, got %q", got)
+	}
+}
@@ -0,0 +1,188 @@
+// Package trash reads and restores items from the desktop trash can.
+//
+// It implements the home trash of the FreeDesktop.org Trash specification used
+// by Linux and BSD desktops: deleted entries live in $XDG_DATA_HOME/Trash/files
+// and each has a matching .trashinfo file in Trash/info recording its original
+// location and deletion time.
+package trash
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	infoExt        = ".trashinfo"
+	infoHeader     = "[Trash Info]"
+	deletionLayout = "2006-01-02T15:04:05"
+)
+
+// ErrUnsupported is returned on platforms without a FreeDesktop.org trash.
+var ErrUnsupported = errors.New("trash browsing is not supported on this platform")
+
+// Trash is a FreeDesktop.org trash directory.
+type Trash struct {
+	// Dir is the trash root containing the files and info subdirectories
+	Dir string
+}
+
+// Item is an entry in the trash.
+type Item struct {
+	// Name is the entry name inside the trash's files directory
+	Name string
+
+	// OriginalPath is the absolute path the entry was deleted from
+	OriginalPath string
+
+	// DeletedAt is the local time the entry was moved to the trash
+	DeletedAt time.Time
+
+	// IsDir reports whether the entry is a directory
+	IsDir bool
+}
+
+// Home returns the current user's home trash.
+//
+// Returns ErrUnsupported on macOS and Windows, whose trash cans do not record
+// original locations in a readable format.
+func Home() (*Trash, error) {
+	if runtime.GOOS == "darwin" || runtime.GOOS == "windows" {
+		return nil, ErrUnsupported
+	}
+
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("cannot locate trash: %w", err)
+		}
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+
+	return &Trash{Dir: filepath.Join(dataHome, "Trash")}, nil
+}
+
+// List returns the items in the trash, most recently deleted first.
+//
+// Entries without a readable .trashinfo file are skipped. A trash that does
+// not exist yet is reported as empty.
+func (t *Trash) List() ([]Item, error) {
+	entries, err := os.ReadDir(t.infoDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []Item{}, nil
+		}
+		return nil, err
+	}
+
+	items := make([]Item, 0, len(entries))
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), infoExt)
+		if !ok || entry.IsDir() {
+			continue
+		}
+
+		item, err := t.readInfo(name)
+		if err != nil {
+			continue
+		}
+		items = append(items, item)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].DeletedAt.After(items[j].DeletedAt)
+	})
+	return items, nil
+}
+
+// Restore moves an item back to its original path and removes its trash info.
+//
+// Missing parent directories are recreated. Restoring fails, leaving the
+// trash untouched, if something already exists at the original path.
+func (t *Trash) Restore(item Item) error {
+	if _, err := os.Lstat(item.OriginalPath); err == nil {
+		return fmt.Errorf("cannot restore %q: destination already exists", item.OriginalPath)
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("cannot restore %q: %w", item.OriginalPath, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(item.OriginalPath), 0o755); err != nil {
+		return fmt.Errorf("cannot recreate parent directory: %w", err)
+	}
+
+	if err := os.Rename(filepath.Join(t.filesDir(), item.Name), item.OriginalPath); err != nil {
+		return fmt.Errorf("cannot restore %q: %w", item.OriginalPath, err)
+	}
+
+	if err := os.Remove(filepath.Join(t.infoDir(), item.Name+infoExt)); err != nil {
+		return fmt.Errorf("restored %q but failed to remove trash info: %w", item.OriginalPath, err)
+	}
+	return nil
+}
+
+func (t *Trash) filesDir() string { return filepath.Join(t.Dir, "files") }
+func (t *Trash) infoDir() string  { return filepath.Join(t.Dir, "info") }
+
+// readInfo parses the .trashinfo file of the named trash entry.
+func (t *Trash) readInfo(name string) (Item, error) {
+	file, err := os.Open(filepath.Join(t.infoDir(), name+infoExt))
+	if err != nil {
+		return Item{}, err
+	}
+	defer file.Close()
+
+	item := Item{Name: name}
+	inHeader := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			inHeader = line == infoHeader
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !inHeader || !ok {
+			continue
+		}
+
+		switch key {
+		case "Path":
+			path, err := url.PathUnescape(value)
+			if err != nil {
+				return Item{}, fmt.Errorf("invalid path in %s: %w", name, err)
+			}
+			// The home trash stores absolute paths; anchor relative
+			// ones (used by per-volume trashes) at the filesystem root
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(string(filepath.Separator), path)
+			}
+			item.OriginalPath = path
+		case "DeletionDate":
+			if deletedAt, err := time.ParseInLocation(deletionLayout, value, time.Local); err == nil {
+				item.DeletedAt = deletedAt
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Item{}, err
+	}
+	if item.OriginalPath == "" {
+		return Item{}, fmt.Errorf("missing original path in %s", name)
+	}
+
+	info, err := os.Lstat(filepath.Join(t.filesDir(), name))
+	if err != nil {
+		return Item{}, err
+	}
+	item.IsDir = info.IsDir()
+
+	return item, nil
+}
@@ -0,0 +1,145 @@
+package trash
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// newTestTrash creates a trash directory with one trashed directory and one
+// trashed file, returning the trash and the directory that held the originals.
+func newTestTrash(t *testing.T) (*Trash, string) {
+	t.Helper()
+
+	root, err := os.MkdirTemp("", "trash-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(root) })
+
+	tr := &Trash{Dir: filepath.Join(root, "Trash")}
+	origin := filepath.Join(root, "home")
+
+	for _, dir := range []string{tr.filesDir(), tr.infoDir(), origin} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", dir, err)
+		}
+	}
+
+	if err := os.Mkdir(filepath.Join(tr.filesDir(), "old project"), 0755); err != nil {
+		t.Fatalf("failed to create trashed dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tr.filesDir(), "notes.txt"), []byte("x"), 0644); err != nil {
+		t.Fatalf("failed to create trashed file: %v", err)
+	}
+
+	writeInfo(t, tr, "old project", filepath.Join(origin, "old%20project"), "2024-05-02T10:00:00")
+	writeInfo(t, tr, "notes.txt", filepath.Join(origin, "nested", "notes.txt"), "2024-05-01T10:00:00")
+
+	return tr, origin
+}
+
+func writeInfo(t *testing.T, tr *Trash, name, path, date string) {
+	t.Helper()
+	content := "[Trash Info]\nPath=" + path + "\nDeletionDate=" + date + "\n"
+	if err := os.WriteFile(filepath.Join(tr.infoDir(), name+infoExt), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write trash info: %v", err)
+	}
+}
+
+func TestList(t *testing.T) {
+	tr, origin := newTestTrash(t)
+
+	items, err := tr.List()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(items))
+	}
+
+	// Most recently deleted first
+	if items[0].Name != "old project" {
+		t.Errorf("expected 'old project' first, got %q", items[0].Name)
+	}
+	if want := filepath.Join(origin, "old project"); items[0].OriginalPath != want {
+		t.Errorf("expected decoded original path %q, got %q", want, items[0].OriginalPath)
+	}
+	if !items[0].IsDir {
+		t.Error("expected 'old project' to be a directory")
+	}
+	if items[1].IsDir {
+		t.Error("expected 'notes.txt' not to be a directory")
+	}
+	if items[0].DeletedAt.IsZero() {
+		t.Error("expected deletion date to be parsed")
+	}
+}
+
+func TestList_SkipsOrphanedInfo(t *testing.T) {
+	tr, origin := newTestTrash(t)
+	writeInfo(t, tr, "gone", filepath.Join(origin, "gone"), "2024-05-03T10:00:00")
+
+	items, err := tr.List()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 2 {
+		t.Errorf("expected orphaned info to be skipped, got %d items", len(items))
+	}
+}
+
+func TestList_MissingTrash(t *testing.T) {
+	tr := &Trash{Dir: filepath.Join(os.TempDir(), "trash-test-does-not-exist")}
+
+	items, err := tr.List()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 0 {
+		t.Errorf("expected empty trash, got %d items", len(items))
+	}
+}
+
+func TestRestore(t *testing.T) {
+	tr, origin := newTestTrash(t)
+
+	items, err := tr.List()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// notes.txt was deleted from a directory that no longer exists
+	notes := items[1]
+	if err := tr.Restore(notes); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(origin, "nested", "notes.txt")); err != nil {
+		t.Errorf("expected restored file at original path: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tr.infoDir(), "notes.txt"+infoExt)); !os.IsNotExist(err) {
+		t.Error("expected trash info to be removed after restore")
+	}
+}
+
+func TestRestore_DestinationExists(t *testing.T) {
+	tr, origin := newTestTrash(t)
+
+	if err := os.Mkdir(filepath.Join(origin, "old project"), 0755); err != nil {
+		t.Fatalf("failed to create conflicting dir: %v", err)
+	}
+
+	items, err := tr.List()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := tr.Restore(items[0]); err == nil {
+		t.Fatal("expected error when destination exists, got nil")
+	}
+	if _, err := os.Stat(filepath.Join(tr.filesDir(), "old project")); err != nil {
+		t.Errorf("expected item to remain in trash: %v", err)
+	}
+}
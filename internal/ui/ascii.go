@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// asciiReplacer swaps the symbols used by the interface, including those
+// drawn by the list component, for ASCII equivalents of the same width
+// where possible.
+var asciiReplacer = strings.NewReplacer(
+	"↑", "^",
+	"↓", "v",
+	"←", "<",
+	"→", ">",
+	"•", "*",
+	"·", "-",
+	"›", ">",
+	"—", "-",
+	"…", "...",
+)
+
+// asciiBorderStyle replaces borderStyle in ASCII mode.
+var asciiBorderStyle = borderStyle.Border(lipgloss.ASCIIBorder())
+
+// useASCII reports whether the interface is limited to ASCII: when forced,
+// or when the locale shows the terminal does not expect UTF-8.
+func useASCII(forced bool) bool {
+	return forced || !unicodeSupported(os.Getenv)
+}
+
+// unicodeSupported reports whether the terminal can display the symbols of
+// the interface, judging by the TERM and locale environment variables read
+// with getenv. The first locale variable that is set decides, as in the C
+// library; without any, UTF-8 is assumed, as most terminals use it today.
+func unicodeSupported(getenv func(string) string) bool {
+	if getenv("TERM") == "dumb" {
+		return false
+	}
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if locale := getenv(name); locale != "" {
+			locale = strings.ToLower(locale)
+			return strings.Contains(locale, "utf-8") || strings.Contains(locale, "utf8")
+		}
+	}
+	return true
+}
+
+// toASCII returns view with its symbols swapped for ASCII if ascii is set.
+func toASCII(view string, ascii bool) string {
+	if !ascii {
+		return view
+	}
+	return asciiReplacer.Replace(view)
+}
@@ -0,0 +1,317 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kaczmarekdaniel/folder-search/internal/app"
+	"github.com/kaczmarekdaniel/folder-search/internal/dirsearch"
+	"github.com/kaczmarekdaniel/folder-search/internal/fileops"
+	"github.com/kaczmarekdaniel/folder-search/internal/logging"
+)
+
+const (
+	// reportChromeHeight is the number of rows used by the title, status and help
+	reportChromeHeight = 6
+
+	brokenLinksHelpText = "↑/↓ move • space select • a select all • d delete selected • E skipped • q quit"
+)
+
+// brokenLink is a dangling symbolic link found by the scan.
+type brokenLink struct {
+	path   string
+	target string
+}
+
+type brokenLinksFoundMsg struct {
+	links   []brokenLink
+	skipped []dirsearch.PathError
+	err     error
+}
+
+type linksDeletedMsg struct {
+	deleted map[string]bool
+	errs    []error
+}
+
+// brokenLinksModel is the interactive report of dangling symbolic links.
+type brokenLinksModel struct {
+	root        string
+	ignore      []string
+	logger      *slog.Logger
+	links       []brokenLink
+	skipped     skippedList // Directories the scan could not read
+	selected    map[string]bool
+	cursor      int
+	offset      int // First visible row
+	height      int // Visible rows for the list
+	scanning    bool
+	confirming  bool
+	showSkipped bool
+	status      string
+	err         error
+	ascii       bool // Limits the view to ASCII symbols
+}
+
+// findBrokenLinks scans root for dangling links without blocking the UI.
+func findBrokenLinks(root string, ignore []string) tea.Cmd {
+	return func() tea.Msg {
+		paths, skipped, err := dirsearch.FindBrokenLinks(context.Background(), root, ignore)
+		if err != nil {
+			return brokenLinksFoundMsg{err: err}
+		}
+		links := make([]brokenLink, 0, len(paths))
+		for _, p := range paths {
+			target, _ := os.Readlink(p)
+			links = append(links, brokenLink{path: p, target: target})
+		}
+		return brokenLinksFoundMsg{links: links, skipped: skipped}
+	}
+}
+
+// deleteLinks removes the given symbolic links.
+func deleteLinks(paths []string) tea.Cmd {
+	return func() tea.Msg {
+		msg := linksDeletedMsg{deleted: make(map[string]bool)}
+		for _, p := range paths {
+			if err := fileops.RemoveSymlink(p); err != nil {
+				msg.errs = append(msg.errs, err)
+				continue
+			}
+			msg.deleted[p] = true
+		}
+		return msg
+	}
+}
+
+func (m brokenLinksModel) Init() tea.Cmd {
+	return findBrokenLinks(m.root, m.ignore)
+}
+
+func (m brokenLinksModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.height = max(knownSize(msg).Height-reportChromeHeight, 1)
+		m.scroll()
+		return m, nil
+	case brokenLinksFoundMsg:
+		m.scanning = false
+		if msg.err != nil {
+			m.logger.Error("broken link scan failed", "root", m.root, "error", msg.err)
+			m.err = msg.err
+			return m, nil
+		}
+		m.logger.Info("broken link scan completed", "root", m.root, "count", len(msg.links), "skipped", len(msg.skipped))
+		m.links = msg.links
+		m.skipped = newSkippedList(msg.skipped)
+		return m, nil
+	case linksDeletedMsg:
+		kept := m.links[:0]
+		for _, l := range m.links {
+			if !msg.deleted[l.path] {
+				kept = append(kept, l)
+			}
+		}
+		m.links = kept
+		m.selected = make(map[string]bool)
+		m.cursor = min(m.cursor, max(len(m.links)-1, 0))
+		m.scroll()
+		m.status = fmt.Sprintf("deleted %d links", len(msg.deleted))
+		for _, err := range msg.errs {
+			m.logger.Warn("failed to delete link", "error", err)
+		}
+		if len(msg.errs) > 0 {
+			m.status += fmt.Sprintf(", %d failed: %v", len(msg.errs), msg.errs[0])
+		}
+		return m, nil
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+	}
+	return m, nil
+}
+
+func (m brokenLinksModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.status = ""
+
+	if m.confirming {
+		m.confirming = false
+		if msg.String() == "y" {
+			return m, deleteLinks(m.selectedPaths())
+		}
+		m.status = "deletion canceled"
+		return m, nil
+	}
+
+	if m.showSkipped {
+		switch key := msg.String(); key {
+		case "E", "esc":
+			m.showSkipped = false
+		case "q", "ctrl+c":
+			return m, tea.Quit
+		default:
+			m.skipped.update(key, m.height)
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "q", "esc", "ctrl+c":
+		return m, tea.Quit
+	case "E":
+		if len(m.skipped.entries) > 0 {
+			m.skipped.cursor, m.skipped.offset = 0, 0
+			m.showSkipped = true
+		} else {
+			m.status = "no directories were skipped"
+		}
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.links)-1 {
+			m.cursor++
+		}
+	case " ":
+		if m.cursor < len(m.links) {
+			p := m.links[m.cursor].path
+			m.selected[p] = !m.selected[p]
+		}
+	case "a":
+		all := len(m.selectedPaths()) < len(m.links)
+		for _, l := range m.links {
+			m.selected[l.path] = all
+		}
+	case "d":
+		if n := len(m.selectedPaths()); n > 0 {
+			m.confirming = true
+		} else {
+			m.status = "select links with space first"
+		}
+	}
+
+	m.scroll()
+	return m, nil
+}
+
+// selectedPaths returns the selected links in list order.
+func (m brokenLinksModel) selectedPaths() []string {
+	var paths []string
+	for _, l := range m.links {
+		if m.selected[l.path] {
+			paths = append(paths, l.path)
+		}
+	}
+	return paths
+}
+
+// scroll keeps the cursor inside the visible window.
+func (m *brokenLinksModel) scroll() {
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+m.height {
+		m.offset = m.cursor - m.height + 1
+	}
+}
+
+func (m brokenLinksModel) View() string {
+	if m.showSkipped {
+		return toASCII(m.skipped.view("Skipped under "+m.root, m.height), m.ascii)
+	}
+
+	var b strings.Builder
+	b.WriteString(panelTitleStyle.Render("Broken links under " + m.root))
+	b.WriteString("\n\n")
+
+	switch {
+	case m.err != nil:
+		b.WriteString(jobFailedStyle.Render(fmt.Sprintf("Error: %v", m.err)))
+		b.WriteString("\n")
+	case m.scanning:
+		b.WriteString(itemStyle.Render("Scanning..."))
+		b.WriteString("\n")
+	case len(m.links) == 0:
+		b.WriteString(itemStyle.Render("No broken links found"))
+		b.WriteString("\n")
+	}
+
+	end := min(m.offset+m.height, len(m.links))
+	for i := m.offset; i < end; i++ {
+		l := m.links[i]
+		box := "[ ]"
+		if m.selected[l.path] {
+			box = "[x]"
+		}
+		rel, err := filepath.Rel(m.root, l.path)
+		if err != nil {
+			rel = l.path
+		}
+		line := fmt.Sprintf("%s %s %s", box, rel, dimStyle.Render("-> "+l.target))
+		if i == m.cursor {
+			b.WriteString(selectedItemStyle.Render("> " + line))
+		} else {
+			b.WriteString(itemStyle.Render(line))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	switch {
+	case m.confirming:
+		b.WriteString(statusStyle.Render(fmt.Sprintf("Delete %d links? (y/n)", len(m.selectedPaths()))))
+	case m.status != "":
+		b.WriteString(statusStyle.Render(m.status))
+	default:
+		status := fmt.Sprintf("%d broken, %d selected", len(m.links), len(m.selectedPaths()))
+		if summary := m.skipped.summary(); summary != "" {
+			status += ", " + summary
+		}
+		b.WriteString(statusStyle.Render(status))
+	}
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render(brokenLinksHelpText))
+	return toASCII(b.String(), m.ascii)
+}
+
+// RunBrokenLinks scans root for symbolic links whose targets no longer exist
+// and shows them in an interactive list for cleanup.
+//
+// The user can select links with space (or all with a) and delete the
+// selection with d after confirming. Only the links themselves are removed.
+// Directories in the application's ignore patterns are not scanned, and
+// directories that cannot be read are counted below the list and listed
+// with E.
+//
+// Parameters:
+//   - app: The application instance providing search options and logging
+//   - root: The directory to scan
+//   - ascii: Limits the interface to ASCII symbols, as it is anyway when
+//     the terminal does not use UTF-8
+//
+// Returns an error if the Bubble Tea program fails.
+func RunBrokenLinks(app *app.Application, root string, ascii bool) error {
+	logger := app.ModuleLogger(logging.ModuleUI)
+	logger.Info("starting broken link report", "root", root)
+
+	_, height := terminalSize()
+	m := brokenLinksModel{
+		root:     root,
+		ignore:   app.Dirsearch.Options.IgnorePatterns,
+		logger:   logger,
+		selected: make(map[string]bool),
+		height:   max(height-reportChromeHeight, 1),
+		ascii:    useASCII(ascii),
+		scanning: true,
+	}
+
+	if _, err := tea.NewProgram(m).Run(); err != nil {
+		return fmt.Errorf("failed to run broken link report: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package ui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kaczmarekdaniel/folder-search/internal/app"
+	"github.com/kaczmarekdaniel/folder-search/internal/bugreport"
+)
+
+// bugReportMsg reports the outcome of saving a bug report.
+type bugReportMsg struct {
+	path string
+	err  error
+}
+
+// bugReporter returns a function saving a bug report with the log records
+// of the session to the default report directory, and returning its path.
+func bugReporter(app *app.Application, version string) func() (string, error) {
+	return func() (string, error) {
+		dir, err := bugreport.DefaultDir()
+		if err != nil {
+			return "", err
+		}
+		r := bugreport.Report{Version: version, Time: time.Now(), Config: app.Config, Logs: app.Logs.Records()}
+		return r.Save(dir)
+	}
+}
+
+// writeBugReport saves a bug report without blocking the UI.
+func (m model) writeBugReport() tea.Cmd {
+	save := m.saveBugReport
+	return func() tea.Msg {
+		path, err := save()
+		return bugReportMsg{path: path, err: err}
+	}
+}
+
+// showBugReport reports where the bug report was saved.
+func (m model) showBugReport(msg bugReportMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.logger.Warn("failed to save bug report", "error", msg.err)
+		m.status = "cannot save bug report: " + msg.err.Error()
+		return m, nil
+	}
+	m.logger.Info("saved bug report", "path", msg.path)
+	m.status = "bug report saved to " + msg.path
+	return m, nil
+}
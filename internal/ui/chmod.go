@@ -0,0 +1,135 @@
+package ui
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kaczmarekdaniel/folder-search/internal/fileops"
+)
+
+const (
+	// permBits is the number of toggleable permission bits (rwx for owner, group, other)
+	permBits = 9
+
+	chmodHelpText = "arrows move • space toggle • R recursive • enter apply • esc cancel"
+)
+
+var (
+	permClasses = []string{"owner", "group", "other"}
+	permNames   = []string{"read", "write", "exec"}
+)
+
+type chmodDoneMsg struct {
+	path string
+	perm fs.FileMode
+	err  error
+}
+
+// permBit returns the mode bit for the permission at cursor position i,
+// where rows are owner/group/other and columns are read/write/execute.
+func permBit(i int) fs.FileMode {
+	return 1 << (permBits - 1 - i)
+}
+
+// applyChmod changes permissions in the background so recursive changes do
+// not block the UI.
+func applyChmod(path string, perm fs.FileMode, recursive bool) tea.Cmd {
+	return func() tea.Msg {
+		return chmodDoneMsg{path: path, perm: perm, err: fileops.Chmod(path, perm, recursive)}
+	}
+}
+
+// startChmodDialog opens the permissions editor for the highlighted entry.
+func (m model) startChmodDialog() (tea.Model, tea.Cmd) {
+	i, ok := m.list.SelectedItem().(item)
+	if !ok || m.err != nil {
+		return m, nil
+	}
+
+	target := filepath.Join(m.currentDir, string(i))
+	info, err := os.Stat(target)
+	if err != nil {
+		m.status = fmt.Sprintf("cannot read permissions: %v", err)
+		return m, nil
+	}
+
+	m.chmodTarget = target
+	m.chmodPerm = info.Mode().Perm()
+	m.chmodCursor = 0
+	m.chmodRecursive = false
+	m.showChmod = true
+	return m, nil
+}
+
+// updateChmodDialog handles key presses while the permissions editor is open.
+func (m model) updateChmodDialog(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	row, col := m.chmodCursor/3, m.chmodCursor%3
+
+	switch msg.String() {
+	case "up", "k":
+		row = (row + 2) % 3
+	case "down", "j":
+		row = (row + 1) % 3
+	case "left", "h":
+		col = (col + 2) % 3
+	case "right", "l":
+		col = (col + 1) % 3
+	case " ", "x":
+		m.chmodPerm ^= permBit(m.chmodCursor)
+	case "R":
+		m.chmodRecursive = !m.chmodRecursive
+	case "enter":
+		m.showChmod = false
+		return m, applyChmod(m.chmodTarget, m.chmodPerm, m.chmodRecursive)
+	case "esc":
+		m.showChmod = false
+	}
+
+	m.chmodCursor = row*3 + col
+	return m, nil
+}
+
+// chmodView renders the permissions editor.
+func (m model) chmodView() string {
+	var b strings.Builder
+	b.WriteString(panelTitleStyle.Render("Permissions: " + filepath.Base(m.chmodTarget)))
+	b.WriteString("\n\n")
+
+	header := fmt.Sprintf("%-7s", "")
+	for _, name := range permNames {
+		header += fmt.Sprintf("%-7s", name)
+	}
+	b.WriteString(itemStyle.Render(dimStyle.Render(header)))
+	b.WriteString("\n")
+
+	for row, class := range permClasses {
+		line := fmt.Sprintf("%-7s", class)
+		for col := range permNames {
+			i := row*3 + col
+			box := "[ ]"
+			if m.chmodPerm&permBit(i) != 0 {
+				box = "[x]"
+			}
+			if i == m.chmodCursor {
+				box = selectedItemStyle.UnsetPaddingLeft().Render(box)
+			}
+			line += box + "    "
+		}
+		b.WriteString(itemStyle.Render(line))
+		b.WriteString("\n")
+	}
+
+	recursive := "[ ]"
+	if m.chmodRecursive {
+		recursive = "[x]"
+	}
+	b.WriteString("\n")
+	b.WriteString(itemStyle.Render(fmt.Sprintf("mode %04o (%s)   recursive %s", uint32(m.chmodPerm), m.chmodPerm, recursive)))
+	b.WriteString("\n\n")
+	b.WriteString(helpStyle.Render(chmodHelpText))
+	return b.String()
+}
@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/kaczmarekdaniel/folder-search/internal/config"
+)
+
+const (
+	// defaultTitleTemplate shows the current path and the active filters
+	defaultTitleTemplate = "{{.Path}}{{if .Tag}} #{{.Tag}}{{end}}{{if .Query}} /{{.Query}}{{end}}{{if .LargerThan}} >{{.LargerThan}}{{end}}{{if .Project}} [{{.Project}}]{{end}}{{range .Refinements}} › {{.}}{{end}}{{with .Hidden}} [{{len .}} ignored]{{end}}"
+
+	// defaultPrompt marks the highlighted directory
+	defaultPrompt = "> "
+)
+
+// chromeData holds the variables available to the title and prompt templates.
+type chromeData struct {
+	Path        string   // Directory being shown
+	Name        string   // Base name of Path
+	Count       int      // Number of listed directories
+	Profile     string   // Name of the active profile
+	Branch      string   // Git branch of Path, empty outside a repository
+	Tag         string   // Active tag filter, empty if none
+	Query       string   // Active name filter, empty if none
+	LargerThan  string   // Active size filter as typed, e.g. "1G"; empty if none
+	Project     string   // Active project type filter, e.g. "git,go"; empty if none
+	Refinements []string // Patterns narrowing the listing, oldest first
+	Hidden      []string // Patterns ignored for this session only
+}
+
+// chrome renders the user-configurable parts of the list.
+type chrome struct {
+	title  *template.Template
+	prompt *template.Template
+}
+
+// newChrome parses the title and prompt templates from cfg, using the
+// built-in ones for templates that are not set. The config package has
+// already validated the templates, so parse errors fall back to the
+// defaults as well.
+func newChrome(cfg *config.Config) chrome {
+	return chrome{
+		title:  parseTemplate("title", cfg.TitleTemplate, defaultTitleTemplate),
+		prompt: parseTemplate("prompt", cfg.PromptTemplate, defaultPrompt),
+	}
+}
+
+// parseTemplate parses text, or fallback if text is empty or invalid.
+func parseTemplate(name, text, fallback string) *template.Template {
+	if text != "" {
+		if t, err := template.New(name).Parse(text); err == nil {
+			return t
+		}
+	}
+	return template.Must(template.New(name).Parse(fallback))
+}
+
+// render executes t with data. A template that fails at run time, e.g. by
+// calling a missing method, renders as fallback.
+func render(t *template.Template, data chromeData, fallback string) string {
+	if t == nil {
+		return fallback
+	}
+	var b strings.Builder
+	if err := t.Execute(&b, data); err != nil {
+		return fallback
+	}
+	return b.String()
+}
+
+// chromeData returns the template variables for the current view.
+func (m model) chromeData() chromeData {
+	path := m.currentDir
+	if m.pendingDir != "" {
+		path = m.pendingDir
+	}
+	return chromeData{
+		Path:        path,
+		Name:        filepath.Base(path),
+		Count:       len(m.list.Items()),
+		Profile:     m.profile,
+		Branch:      m.branch,
+		Tag:         m.tagFilter,
+		Query:       m.query,
+		LargerThan:  m.largerThan,
+		Project:     m.projects.String(),
+		Refinements: m.refinements,
+		Hidden:      m.sessionIgnore,
+	}
+}
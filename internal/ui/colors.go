@@ -0,0 +1,25 @@
+package ui
+
+import (
+	"path/filepath"
+
+	"github.com/kaczmarekdaniel/folder-search/internal/config"
+	"github.com/kaczmarekdaniel/folder-search/internal/dirsearch"
+)
+
+// ruleColor returns the color of the first rule matching the absolute
+// directory path, or an empty string if no rule matches.
+func ruleColor(rules []config.ColorRule, path string) string {
+	for _, r := range rules {
+		if r.Under != "" && !dirsearch.IsUnder(r.Under, path) {
+			continue
+		}
+		if r.Pattern != "" {
+			if ok, _ := filepath.Match(r.Pattern, filepath.Base(path)); !ok {
+				continue
+			}
+		}
+		return r.TerminalColor()
+	}
+	return ""
+}
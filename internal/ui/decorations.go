@@ -0,0 +1,135 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kaczmarekdaniel/folder-search/internal/dirmeta"
+	"github.com/kaczmarekdaniel/folder-search/internal/sizefmt"
+	"github.com/kaczmarekdaniel/folder-search/internal/timefmt"
+)
+
+// maxMetaBatch is the most metadata results delivered in one message, so
+// the list is redrawn regularly while collection continues.
+const maxMetaBatch = 64
+
+// metaMsg delivers metadata collected for the entries of dir.
+type metaMsg struct {
+	dir   string
+	infos []dirmeta.Info
+	more  <-chan dirmeta.Info // Remaining results; nil once collection is done
+}
+
+// collectMeta starts collecting the configured decorations for the scanned
+// entries of dir in the background, replacing any collection still running.
+// The names are already shown; decorations appear as they arrive.
+func (m *model) collectMeta(dir string) tea.Cmd {
+	m.cancelMeta()
+	m.meta = make(map[string]dirmeta.Info)
+	m.list.SetDelegate(m.delegate())
+	if m.metaFields == 0 || len(m.listedDirs) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.stopMeta = cancel
+	return waitForMeta(dir, dirmeta.Collect(ctx, dir, m.listedDirs, m.metaFields, 0))
+}
+
+// cancelMeta stops the running metadata collection, if any.
+func (m *model) cancelMeta() {
+	if m.stopMeta != nil {
+		m.stopMeta()
+		m.stopMeta = nil
+	}
+}
+
+// waitForMeta blocks until metadata arrives on infos and delivers it
+// together with whatever else is ready, up to maxMetaBatch results.
+func waitForMeta(dir string, infos <-chan dirmeta.Info) tea.Cmd {
+	return func() tea.Msg {
+		info, ok := <-infos
+		if !ok {
+			return metaMsg{dir: dir}
+		}
+
+		batch := []dirmeta.Info{info}
+		for len(batch) < maxMetaBatch {
+			select {
+			case info, ok := <-infos:
+				if !ok {
+					return metaMsg{dir: dir, infos: batch}
+				}
+				batch = append(batch, info)
+			default:
+				return metaMsg{dir: dir, infos: batch, more: infos}
+			}
+		}
+		return metaMsg{dir: dir, infos: batch, more: infos}
+	}
+}
+
+// storeMeta records the metadata of msg and keeps waiting for more.
+// Metadata for a directory that is no longer listed is dropped.
+func (m *model) storeMeta(msg metaMsg) tea.Cmd {
+	if msg.dir != m.listedDir {
+		return nil
+	}
+	for _, info := range msg.infos {
+		if info.Err != nil {
+			m.logger.Debug("cannot collect directory metadata", "dir", info.Path, "error", info.Err)
+			continue
+		}
+		m.meta[info.Path] = info
+	}
+	if msg.more == nil {
+		return nil
+	}
+	return waitForMeta(msg.dir, msg.more)
+}
+
+// formatMeta renders the requested fields of info, e.g. "3 days ago · 12
+// entries", with modification times rendered by times and sizes in sizes.
+func formatMeta(info dirmeta.Info, fields dirmeta.Field, times timefmt.Formatter, sizes sizefmt.Units, now time.Time) string {
+	var parts []string
+	if fields&dirmeta.ModTime != 0 {
+		parts = append(parts, times.Format(info.ModTime, now))
+	}
+	if fields&dirmeta.Entries != 0 {
+		if info.Entries == 1 {
+			parts = append(parts, "1 entry")
+		} else {
+			parts = append(parts, fmt.Sprintf("%d entries", info.Entries))
+		}
+	}
+	if fields&dirmeta.Git != 0 && info.Branch != "" {
+		parts = append(parts, "git:"+info.Branch)
+	}
+	if fields&dirmeta.Project != 0 && info.Project != 0 {
+		parts = append(parts, "["+strings.Join(info.Project.Names(), "/")+"]")
+	}
+	if fields&dirmeta.Size != 0 {
+		parts = append(parts, sizes.Format(info.Size))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// metaDecoration returns the function rendering the decorations of the
+// entries of dir, or nil if none are configured.
+func (m model) metaDecoration(dir string) func(name string) string {
+	if m.metaFields == 0 {
+		return nil
+	}
+	meta, fields, times, sizes := m.meta, m.metaFields, m.times, m.sizes
+	return func(name string) string {
+		info, ok := meta[filepath.Join(dir, name)]
+		if !ok {
+			return ""
+		}
+		return formatMeta(info, fields, times, sizes, time.Now())
+	}
+}
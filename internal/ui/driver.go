@@ -0,0 +1,263 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kaczmarekdaniel/folder-search/internal/app"
+)
+
+// DefaultWaitTimeout is how long scripts wait for the interface to show some
+// text before giving up.
+const DefaultWaitTimeout = 5 * time.Second
+
+// settleDelay is how long Settle waits for another message before deciding
+// that the interface is idle. It is longer than the default navigation
+// debounce, so a scan triggered by a key press is waited for.
+const settleDelay = 200 * time.Millisecond
+
+// Driver runs the interface without a terminal, for tests and automation.
+// Messages such as key presses are delivered to the interface the way a
+// Bubble Tea program delivers them, the commands they return run in the
+// background, and the rendered frame can be read at any time.
+//
+// Every update and every read of the frame happens on the goroutine calling
+// the Driver's methods, so a script observes the interface between two
+// messages, never in the middle of one. Messages produced by background
+// commands, such as scan results, are only delivered by Settle and WaitFor.
+// A Driver is not safe for concurrent use.
+type Driver struct {
+	model   tea.Model
+	cleanup func()
+	msgs    chan tea.Msg  // Messages returned by background commands
+	done    chan struct{} // Closed by Close to release pending commands
+	quit    bool
+}
+
+// NewDriver scans startDir and starts the interface on it without a
+// terminal, sized as a terminal of width columns and height lines.
+//
+// Parameters:
+//   - app: The application instance, as for InitUI
+//   - startDir: The directory shown first
+//   - opts: Initial view settings
+//   - width, height: The size of the simulated terminal
+//
+// Returns the Driver once the interface is idle, see Settle; it must be
+// closed. Returns an error if the start directory cannot be scanned.
+func NewDriver(app *app.Application, startDir string, opts Options, width, height int) (*Driver, error) {
+	m, cleanup, err := newModel(app, startDir, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Driver{
+		model:   m,
+		cleanup: cleanup,
+		msgs:    make(chan tea.Msg, 64),
+		done:    make(chan struct{}),
+	}
+	d.run(m.Init())
+	d.Resize(width, height)
+	// Keys pressed before the first scan result would be overridden by it
+	d.Settle()
+	return d, nil
+}
+
+// run executes cmd in the background and queues the message it returns.
+func (d *Driver) run(cmd tea.Cmd) {
+	if cmd == nil {
+		return
+	}
+	go func() {
+		msg := cmd()
+		if msg == nil {
+			return
+		}
+		select {
+		case d.msgs <- msg:
+		case <-d.done:
+		}
+	}()
+}
+
+// Send delivers msg to the interface and starts the command it returns.
+// Once the interface has quit, messages are dropped like a finished
+// program drops them.
+func (d *Driver) Send(msg tea.Msg) {
+	switch msg := msg.(type) {
+	case tea.BatchMsg:
+		for _, cmd := range msg {
+			d.run(cmd)
+		}
+		return
+	case tea.QuitMsg:
+		d.quit = true
+		return
+	}
+	if d.quit {
+		return
+	}
+
+	var cmd tea.Cmd
+	d.model, cmd = d.model.Update(msg)
+	d.run(cmd)
+}
+
+// Press delivers key presses to the interface, one per name. Names are
+// those of tea.KeyMsg.String, such as "enter", "up", "ctrl+c", "alt+x",
+// "space" or a single character like "q".
+//
+// Returns an error for an unknown key name, before any key is delivered.
+func (d *Driver) Press(names ...string) error {
+	keys := make([]tea.KeyMsg, 0, len(names))
+	for _, name := range names {
+		key, err := parseKey(name)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, key)
+	}
+	for _, key := range keys {
+		d.Send(key)
+	}
+	return nil
+}
+
+// Type delivers text to the interface as one key press per character.
+func (d *Driver) Type(text string) {
+	for _, r := range text {
+		d.Send(runeKey(r))
+	}
+}
+
+// Resize delivers a new terminal size to the interface.
+func (d *Driver) Resize(width, height int) {
+	d.Send(tea.WindowSizeMsg{Width: width, Height: height})
+}
+
+// Frame returns the interface as it would be drawn on the terminal now.
+// It carries no colors unless standard output is a color terminal.
+func (d *Driver) Frame() string {
+	return d.model.View()
+}
+
+// Settle delivers the messages of background commands until none has
+// arrived for a short while, e.g. until a scan started by a key press has
+// finished and its result is shown. Commands that never finish, such as a
+// scan of a hung network mount, are not waited for.
+func (d *Driver) Settle() {
+	timer := time.NewTimer(settleDelay)
+	defer timer.Stop()
+	for {
+		select {
+		case msg := <-d.msgs:
+			d.Send(msg)
+			timer.Reset(settleDelay)
+		case <-timer.C:
+			return
+		}
+	}
+}
+
+// WaitFor delivers the messages of background commands until the frame
+// satisfies ready.
+//
+// Parameters:
+//   - timeout: how long to wait (DefaultWaitTimeout if not positive)
+//   - ready: reports whether the frame shows what is waited for
+//
+// Returns an error holding the last frame if ready was not satisfied in time.
+func (d *Driver) WaitFor(timeout time.Duration, ready func(frame string) bool) error {
+	if timeout <= 0 {
+		timeout = DefaultWaitTimeout
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		frame := d.Frame()
+		if ready(frame) {
+			return nil
+		}
+		select {
+		case msg := <-d.msgs:
+			d.Send(msg)
+		case <-timer.C:
+			return fmt.Errorf("interface not ready after %v; last frame:\n%s", timeout, frame)
+		}
+	}
+}
+
+// Done reports whether the interface has quit, e.g. after "q" or "enter".
+// A quit message is delivered like any other, by Settle or WaitFor.
+func (d *Driver) Done() bool {
+	return d.quit
+}
+
+// Selection returns the absolute path of the directory chosen with Enter,
+// as InitUI returns it, or an empty string if none was chosen.
+func (d *Driver) Selection() string {
+	m, ok := d.model.(model)
+	if !ok {
+		return ""
+	}
+	return m.selection()
+}
+
+// Close stops the background scanner and the directory watcher of the
+// interface. Commands still running are abandoned.
+func (d *Driver) Close() {
+	select {
+	case <-d.done:
+		return
+	default:
+	}
+	close(d.done)
+	d.cleanup()
+}
+
+// keyTypes maps the names of the special keys to their type.
+var keyTypes = func() map[string]tea.KeyType {
+	types := map[string]tea.KeyType{"space": tea.KeySpace}
+	// Special keys are small negative numbers, the others control
+	// characters up to backspace (DEL)
+	for k := tea.KeyType(-128); k <= tea.KeyBackspace; k++ {
+		if name := k.String(); name != "" && k != tea.KeyRunes && k != tea.KeySpace {
+			types[name] = k
+		}
+	}
+	return types
+}()
+
+// parseKey returns the key press named like tea.KeyMsg.String names it.
+func parseKey(name string) (tea.KeyMsg, error) {
+	alt := false
+	rest := name
+	if after, ok := strings.CutPrefix(name, "alt+"); ok && after != "" {
+		alt, rest = true, after
+	}
+
+	var key tea.KeyMsg
+	if k, ok := keyTypes[rest]; ok && k == tea.KeySpace {
+		key = runeKey(' ')
+	} else if ok {
+		key = tea.KeyMsg{Type: k}
+	} else if r, size := utf8.DecodeRuneInString(rest); r != utf8.RuneError && size == len(rest) {
+		key = runeKey(r)
+	} else {
+		return tea.KeyMsg{}, fmt.Errorf("unknown key %q", name)
+	}
+	key.Alt = alt
+	return key, nil
+}
+
+// runeKey returns the key press typing r.
+func runeKey(r rune) tea.KeyMsg {
+	if r == ' ' {
+		return tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{r}}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}
+}
@@ -0,0 +1,41 @@
+package ui
+
+// recall steps through past entries of a prompt, like the history of a
+// shell: up goes back to older entries and down forward again, until the
+// text typed before recalling is restored.
+type recall struct {
+	entries []string // Past entries, newest first
+	pos     int      // Index in entries of the shown entry, -1 while showing the draft
+	draft   string   // Text typed before recalling
+}
+
+// newRecall returns a recall over entries, ordered newest first.
+func newRecall(entries []string) recall {
+	return recall{entries: entries, pos: -1}
+}
+
+// older returns the entry before the shown one, saving current as the draft
+// when leaving it. It returns false at the oldest entry.
+func (r *recall) older(current string) (string, bool) {
+	if r.pos+1 >= len(r.entries) {
+		return "", false
+	}
+	if r.pos < 0 {
+		r.draft = current
+	}
+	r.pos++
+	return r.entries[r.pos], true
+}
+
+// newer returns the entry after the shown one, or the draft after the
+// newest entry. It returns false while the draft is shown.
+func (r *recall) newer() (string, bool) {
+	if r.pos < 0 {
+		return "", false
+	}
+	r.pos--
+	if r.pos < 0 {
+		return r.draft, true
+	}
+	return r.entries[r.pos], true
+}
@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestQueryRecall(t *testing.T) {
+	a := newTestApp(t)
+	a.SearchHistory.AddSearch("alpha")
+	a.SearchHistory.AddSearch("beta")
+
+	root := makeRenderTree(t)
+	d, err := NewDriver(a, root, Options{}, 80, 20)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer d.Close()
+
+	if err := d.Press("/"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d.Type("do")
+	for _, step := range []struct {
+		key  string
+		want string
+	}{
+		{"up", "beta"},
+		{"up", "alpha"},
+		{"up", "alpha"},
+		{"down", "beta"},
+		{"down", "do"},
+		{"down", "do"},
+		{"up", "beta"},
+	} {
+		if err := d.Press(step.key); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := d.model.(model).queryInput.Value(); got != step.want {
+			t.Fatalf("expected %q after %s, got %q", step.want, step.key, got)
+		}
+	}
+
+	if err := d.Press("enter"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d.Settle()
+	if err := d.Press("enter"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := a.SearchHistory.Searches(""); len(got) != 2 || got[0] != "beta" {
+		t.Errorf("expected the recalled filter to become the newest search, got %v", got)
+	}
+	selections := a.SearchHistory.Selections(0)
+	if len(selections) != 1 {
+		t.Fatalf("expected 1 selection, got %v", selections)
+	}
+	if want := filepath.Join(root, "beta"); selections[0].Path != want || selections[0].Pattern != "beta" {
+		t.Errorf("expected %s selected with pattern beta, got %+v", want, selections[0])
+	}
+}
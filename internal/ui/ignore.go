@@ -0,0 +1,202 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kaczmarekdaniel/folder-search/internal/config"
+	"github.com/kaczmarekdaniel/folder-search/internal/dirsearch"
+	"github.com/kaczmarekdaniel/folder-search/internal/ignorefile"
+)
+
+const ignoreHelpText = "tab pattern • ↑/↓ scope • enter add rule • esc cancel"
+
+// ignoreScope is where an ignore rule added from the UI is kept.
+type ignoreScope int
+
+const (
+	scopeSession ignoreScope = iota // Until the UI exits
+	scopeProject                    // In the project's ignore file
+	scopeGlobal                     // In the "ignore" setting of the config file
+)
+
+var ignoreScopes = []ignoreScope{scopeSession, scopeProject, scopeGlobal}
+
+// String returns the label of the scope in the ignore dialog.
+func (s ignoreScope) String() string {
+	switch s {
+	case scopeProject:
+		return "project"
+	case scopeGlobal:
+		return "global"
+	}
+	return "session"
+}
+
+// ignoreCandidates returns the patterns offered for ignoring name: the name
+// itself and, where one can be derived, globs matching similar names, e.g.
+// "build-*" for "build-2024" or "*.egg-info" for "pkg.egg-info".
+func ignoreCandidates(name string) []string {
+	candidates := []string{dirsearch.EscapeIgnore(name)}
+	if i := strings.LastIndexByte(name, '.'); i > 0 && i < len(name)-1 {
+		candidates = append(candidates, "*"+dirsearch.EscapeIgnore(name[i:]))
+	}
+	if i := strings.IndexAny(name, "-_"); i > 0 && i < len(name)-1 {
+		candidates = append(candidates, dirsearch.EscapeIgnore(name[:i+1])+"*")
+	} else if stem := strings.TrimRight(name, "0123456789"); stem != "" && stem != name {
+		candidates = append(candidates, dirsearch.EscapeIgnore(stem)+"*")
+	}
+	return candidates
+}
+
+// startIgnoreDialog opens the dialog for adding an ignore rule for the
+// highlighted directory.
+func (m model) startIgnoreDialog() (tea.Model, tea.Cmd) {
+	i, ok := m.list.SelectedItem().(item)
+	if !ok || m.err != nil || m.pendingDir != "" {
+		return m, nil
+	}
+
+	m.ignoreCandidates = ignoreCandidates(string(i))
+	m.ignorePattern = 0
+	m.ignoreScope = scopeSession
+	m.showIgnore = true
+	return m, nil
+}
+
+// updateIgnoreDialog handles key presses while the ignore dialog is open.
+func (m model) updateIgnoreDialog(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.showIgnore = false
+	case "tab", "right":
+		m.ignorePattern = (m.ignorePattern + 1) % len(m.ignoreCandidates)
+	case "shift+tab", "left":
+		m.ignorePattern = (m.ignorePattern + len(m.ignoreCandidates) - 1) % len(m.ignoreCandidates)
+	case "up", "k":
+		if m.ignoreScope > 0 {
+			m.ignoreScope--
+		}
+	case "down", "j":
+		if int(m.ignoreScope) < len(ignoreScopes)-1 {
+			m.ignoreScope++
+		}
+	case "enter":
+		m.showIgnore = false
+		return m.addIgnoreRule(m.ignoreCandidates[m.ignorePattern], m.ignoreScope)
+	}
+	return m, nil
+}
+
+// addIgnoreRule hides the directories matching pattern from now on, keeping
+// the rule at the given scope, and rescans the current directory.
+func (m model) addIgnoreRule(pattern string, scope ignoreScope) (tea.Model, tea.Cmd) {
+	switch scope {
+	case scopeSession:
+		if !slices.Contains(m.sessionIgnore, pattern) {
+			m.sessionIgnore = append(m.sessionIgnore, pattern)
+		}
+		m.status = fmt.Sprintf("ignoring '%s' for this session", pattern)
+	case scopeProject:
+		path := m.projectIgnoreFile()
+		if err := ignorefile.Append(path, pattern); err != nil {
+			m.logger.Error("failed to add ignore rule", "pattern", pattern, "file", path, "error", err)
+			m.status = fmt.Sprintf("cannot add ignore rule: %v", err)
+			return m, nil
+		}
+		m.status = fmt.Sprintf("added '%s' to %s", pattern, path)
+	case scopeGlobal:
+		path, err := config.Path()
+		if err == nil {
+			err = config.AddIgnore(path, pattern)
+		}
+		if err != nil {
+			m.logger.Error("failed to add ignore rule", "pattern", pattern, "file", path, "error", err)
+			m.status = fmt.Sprintf("cannot add ignore rule: %v", err)
+			return m, nil
+		}
+		if !slices.Contains(m.configIgnore, pattern) {
+			m.configIgnore = append(m.configIgnore, pattern)
+		}
+		m.status = fmt.Sprintf("added '%s' to %s", pattern, path)
+	}
+
+	m.logger.Info("added ignore rule", "pattern", pattern, "scope", scope.String())
+	return m.scan(m.currentDir)
+}
+
+// projectIgnoreFile returns the ignore file for rules at project scope: the
+// one applying to the current directory, or a new one at its project root.
+func (m model) projectIgnoreFile() string {
+	if path := ignorefile.Find(m.currentDir); path != "" {
+		return path
+	}
+	return filepath.Join(ignorefile.ProjectRoot(m.currentDir), ignorefile.Name)
+}
+
+// ignoreScopeTarget describes where a rule at the given scope is written.
+func (m model) ignoreScopeTarget(scope ignoreScope) string {
+	switch scope {
+	case scopeProject:
+		return m.projectIgnoreFile()
+	case scopeGlobal:
+		if path, err := config.Path(); err == nil {
+			return path
+		}
+		return "config file"
+	}
+	return "until folder-search exits"
+}
+
+// ignoreDialogView renders the ignore dialog below the list.
+func (m model) ignoreDialogView() string {
+	var b strings.Builder
+	b.WriteString(itemStyle.Render("Ignore: "))
+	for i, pattern := range m.ignoreCandidates {
+		if i == m.ignorePattern {
+			b.WriteString(selectedItemStyle.Render("[" + pattern + "]"))
+		} else {
+			b.WriteString(dimStyle.Render(" " + pattern + " "))
+		}
+	}
+	b.WriteString("\n")
+
+	for _, scope := range ignoreScopes {
+		line := fmt.Sprintf("%-8s %s", scope, dimStyle.Render(m.ignoreScopeTarget(scope)))
+		if scope == m.ignoreScope {
+			b.WriteString(selectedItemStyle.Render("> " + line))
+		} else {
+			b.WriteString(itemStyle.Render(line))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(helpStyle.Render(ignoreHelpText))
+	return b.String()
+}
+
+// hideForSession ignores the highlighted directory until the UI exits,
+// without touching any configuration.
+func (m model) hideForSession() (tea.Model, tea.Cmd) {
+	i, ok := m.list.SelectedItem().(item)
+	if !ok || m.err != nil || m.pendingDir != "" {
+		return m, nil
+	}
+	return m.addIgnoreRule(dirsearch.EscapeIgnore(string(i)), scopeSession)
+}
+
+// unhideLast drops the most recent session ignore rule and rescans, so the
+// directories it hid are listed again.
+func (m model) unhideLast() (tea.Model, tea.Cmd) {
+	if len(m.sessionIgnore) == 0 || m.pendingDir != "" {
+		return m, nil
+	}
+
+	pattern := m.sessionIgnore[len(m.sessionIgnore)-1]
+	m.sessionIgnore = m.sessionIgnore[:len(m.sessionIgnore)-1]
+	m.logger.Info("removed session ignore rule", "pattern", pattern)
+	m.status = fmt.Sprintf("no longer ignoring '%s'", pattern)
+	return m.scan(m.currentDir)
+}
@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/kaczmarekdaniel/folder-search/internal/ignorefile"
+)
+
+func TestScanIgnores(t *testing.T) {
+	root := makeRenderTree(t)
+	if err := os.WriteFile(filepath.Join(root, ignorefile.Name), []byte("docs\n"), 0644); err != nil {
+		t.Fatalf("failed to write ignore file: %v", err)
+	}
+
+	// The ignore setting is merged into the search options the way app.New
+	// does it
+	a := newTestApp(t)
+	a.Config.Ignore = []string{"api-prod"}
+	a.Dirsearch.Options.IgnorePatterns = append(a.Dirsearch.Options.IgnorePatterns, a.Config.Ignore...)
+
+	d, err := NewDriver(a, root, Options{Ignore: []string{"beta"}}, 80, 20)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer d.Close()
+
+	check := func(when string) {
+		t.Helper()
+		frame := d.Frame()
+		for _, name := range []string{"docs", "api-prod", "beta"} {
+			if strings.Contains(frame, name) {
+				t.Errorf("expected %s to be hidden %s, got:\n%s", name, when, frame)
+			}
+		}
+		if !strings.Contains(frame, "alpha") {
+			t.Errorf("expected alpha to be listed %s, got:\n%s", when, frame)
+		}
+	}
+	check("by the initial scan")
+
+	m := d.model.(model)
+	ignore := ignoreList(m.ignore, m.configIgnore, m.sessionIgnore)
+	if n := len(slices.DeleteFunc(slices.Clone(ignore), func(p string) bool { return p != "api-prod" })); n != 1 {
+		t.Errorf("expected the ignore setting once in %v, got %d times", ignore, n)
+	}
+
+	// Leaving the directory and coming back scans it again
+	if err := d.Press("left"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d.Settle()
+	if err := d.Press("right"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d.Settle()
+	check("by a rescan")
+}
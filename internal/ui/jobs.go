@@ -0,0 +1,228 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/kaczmarekdaniel/folder-search/internal/archive"
+	"github.com/kaczmarekdaniel/folder-search/internal/dirsearch"
+	"github.com/kaczmarekdaniel/folder-search/internal/jobs"
+	"github.com/kaczmarekdaniel/folder-search/internal/sizefmt"
+)
+
+const (
+	// progressBarWidth is the number of cells used by a job's progress bar
+	progressBarWidth = 20
+
+	jobsPanelHelpText = "↑/↓ select • x cancel • c clear finished • J/esc close"
+)
+
+var jobFailedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+
+type jobsUpdatedMsg struct{}
+
+// waitForJobUpdates blocks until the job queue reports a change.
+func waitForJobUpdates(q *jobs.Queue) tea.Cmd {
+	return func() tea.Msg {
+		<-q.Updates()
+		return jobsUpdatedMsg{}
+	}
+}
+
+// sizeJob returns a job that computes the total size of dir, rendered in
+// sizes.
+func sizeJob(dir string, sizes sizefmt.Units) jobs.Func {
+	return func(ctx context.Context, report jobs.ReportFunc) (string, error) {
+		size, err := dirsearch.DirSize(ctx, dir, func(total int64) {
+			report(total, 0)
+		})
+		if err != nil {
+			return "", err
+		}
+		return sizes.Format(size), nil
+	}
+}
+
+// fingerprintJob returns a job that computes the content fingerprint of dir,
+// with its size rendered in sizes.
+func fingerprintJob(dir string, sizes sizefmt.Units) jobs.Func {
+	return func(ctx context.Context, report jobs.ReportFunc) (string, error) {
+		fp, err := dirsearch.DirFingerprint(ctx, dir, func(total int64) {
+			report(total, 0)
+		})
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d files, %s, %s", fp.Files, sizes.Format(fp.Size), fp.ShortHash()), nil
+	}
+}
+
+// archiveJob returns a job that compresses dir into an archive next to it,
+// reporting its size in sizes. The job fails with
+// fileops.ErrInsufficientSpace, before anything is written, if the
+// filesystem cannot hold the content of dir.
+func archiveJob(dir string, format archive.Format, sizes sizefmt.Units) jobs.Func {
+	return func(ctx context.Context, report jobs.ReportFunc) (string, error) {
+		dst := dir + format.Ext()
+		size, err := archive.Create(ctx, dir, dst, format, archive.ReportFunc(report))
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s (%s)", filepath.Base(dst), sizes.Format(size)), nil
+	}
+}
+
+// updateJobsPanel handles key presses while the jobs panel is open.
+func (m model) updateJobsPanel(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		if m.jobCursor > 0 {
+			m.jobCursor--
+		}
+	case "down", "j":
+		if m.jobCursor < len(m.jobInfos)-1 {
+			m.jobCursor++
+		}
+	case "x":
+		if m.jobCursor < len(m.jobInfos) {
+			info := m.jobInfos[m.jobCursor]
+			if m.jobs.Cancel(info.ID) {
+				m.logger.Info("job canceled by user", "job", info.ID, "name", info.Name)
+			}
+		}
+	case "c":
+		m.jobs.Prune()
+		m.jobInfos = m.jobs.Snapshot()
+		m.jobCursor = 0
+	case "J", "esc":
+		m.showJobs = false
+	}
+	return m, nil
+}
+
+// jobsView renders the jobs panel.
+func (m model) jobsView() string {
+	var b strings.Builder
+	b.WriteString(panelTitleStyle.Render("Jobs"))
+	b.WriteString("\n\n")
+
+	if len(m.jobInfos) == 0 {
+		b.WriteString(itemStyle.Render("No jobs"))
+		b.WriteString("\n")
+	}
+
+	for i, info := range m.jobInfos {
+		line := fmt.Sprintf("%-9s %s  %s", info.Status, info.Name, jobDetail(info, m.sizes))
+		switch {
+		case info.Status == jobs.Failed:
+			line = jobFailedStyle.Render(line)
+		case info.Status.Finished():
+			line = dimStyle.Render(line)
+		}
+		if i == m.jobCursor {
+			b.WriteString(selectedItemStyle.Render("> " + line))
+		} else {
+			b.WriteString(itemStyle.Render(line))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render(jobsPanelHelpText))
+	return b.String()
+}
+
+// jobDetail describes the progress or outcome of a job, with the bytes
+// processed so far rendered in sizes.
+func jobDetail(info jobs.Info, sizes sizefmt.Units) string {
+	switch info.Status {
+	case jobs.Running:
+		if p := info.Percent(); p >= 0 {
+			return progressBar(p)
+		}
+		return sizes.Format(info.Done) + " so far"
+	case jobs.Done:
+		return info.Result
+	case jobs.Failed:
+		return info.Err.Error()
+	default:
+		return ""
+	}
+}
+
+// jobFinished reports whether the job with the given ID is finished in infos.
+func jobFinished(infos []jobs.Info, id int) bool {
+	for _, info := range infos {
+		if info.ID == id {
+			return info.Status.Finished()
+		}
+	}
+	return false
+}
+
+// jobSummary describes the outcome of a finished job in a few words.
+func jobSummary(info jobs.Info) string {
+	switch info.Status {
+	case jobs.Canceled:
+		return "canceled"
+	case jobs.Failed:
+		return "failed: " + info.Err.Error()
+	default:
+		return info.Result
+	}
+}
+
+// progressBar renders a fixed-width textual progress bar for fraction p.
+func progressBar(p float64) string {
+	filled := int(p * progressBarWidth)
+	return fmt.Sprintf("[%s%s] %3.0f%%",
+		strings.Repeat("#", filled),
+		strings.Repeat("-", progressBarWidth-filled),
+		p*100)
+}
+
+// submitSizeJob queues a size calculation for the highlighted directory.
+func (m model) submitSizeJob() (tea.Model, tea.Cmd) {
+	i, ok := m.list.SelectedItem().(item)
+	if !ok || m.err != nil {
+		return m, nil
+	}
+
+	dir := filepath.Join(m.currentDir, string(i))
+	id := m.jobs.Submit("size of "+string(i), sizeJob(dir, m.sizes))
+	m.logger.Debug("submitted size job", "job", id, "dir", dir)
+	m.status = fmt.Sprintf("calculating size of '%s' (J to view jobs)", string(i))
+	return m, nil
+}
+
+// submitFingerprintJob queues a fingerprint calculation for the highlighted directory.
+func (m model) submitFingerprintJob() (tea.Model, tea.Cmd) {
+	i, ok := m.list.SelectedItem().(item)
+	if !ok || m.err != nil {
+		return m, nil
+	}
+
+	dir := filepath.Join(m.currentDir, string(i))
+	id := m.jobs.Submit("fingerprint of "+string(i), fingerprintJob(dir, m.sizes))
+	m.logger.Debug("submitted fingerprint job", "job", id, "dir", dir)
+	m.status = fmt.Sprintf("fingerprinting '%s' (J to view jobs)", string(i))
+	return m, nil
+}
+
+// submitArchiveJob queues compression of the highlighted directory.
+func (m model) submitArchiveJob(format archive.Format) (tea.Model, tea.Cmd) {
+	i, ok := m.list.SelectedItem().(item)
+	if !ok || m.err != nil {
+		return m, nil
+	}
+
+	dir := filepath.Join(m.currentDir, string(i))
+	id := m.jobs.Submit("archive "+string(i)+format.Ext(), archiveJob(dir, format, m.sizes))
+	m.logger.Debug("submitted archive job", "job", id, "dir", dir, "format", format)
+	m.status = fmt.Sprintf("compressing '%s' (J to view jobs)", string(i))
+	return m, nil
+}
@@ -0,0 +1,180 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// borderSize is the number of rows and columns taken by the border
+	borderSize = 2
+
+	// minListHeight keeps the list usable with very small height settings
+	minListHeight = 3
+
+	// compactWidth is the terminal width below which the compact layout is
+	// used, e.g. on phones
+	compactWidth = 60
+
+	// compactPreviewLines is the height of the preview pane in the compact
+	// layout
+	compactPreviewLines = 4
+
+	// fallbackWidth and fallbackHeight are the terminal size assumed when
+	// it cannot be determined and COLUMNS and LINES are not set
+	fallbackWidth  = 80
+	fallbackHeight = 24
+)
+
+var borderStyle = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("241"))
+
+// Height is the height of the interface, as a number of lines or as a
+// percentage of the terminal height. The zero value sizes the list to the
+// number of directories.
+type Height struct {
+	Lines   int
+	Percent int
+}
+
+// ParseHeight parses a height such as "20" (lines) or "40%" (of the
+// terminal height). An empty string yields the zero Height.
+func ParseHeight(s string) (Height, error) {
+	if s == "" {
+		return Height{}, nil
+	}
+
+	if pct, ok := strings.CutSuffix(s, "%"); ok {
+		n, err := strconv.Atoi(pct)
+		if err != nil || n <= 0 || n > 100 {
+			return Height{}, fmt.Errorf("invalid height %q: percentage must be between 1%% and 100%%", s)
+		}
+		return Height{Percent: n}, nil
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil || n <= 0 {
+		return Height{}, fmt.Errorf("invalid height %q: use a number of lines or a percentage", s)
+	}
+	return Height{Lines: n}, nil
+}
+
+// lines returns the height in lines for a terminal of the given height, or
+// zero if the height is not set or the terminal size is still unknown.
+func (h Height) lines(terminalHeight int) int {
+	if h.Percent > 0 {
+		return terminalHeight * h.Percent / 100
+	}
+	return h.Lines
+}
+
+// terminalSize returns the size to lay out the interface with until the
+// terminal reports its own: the COLUMNS and LINES environment variables, or
+// fallbackWidth and fallbackHeight. Bubble Tea only reports the size of a
+// terminal it draws on, so this is the size used for good when the output
+// is redirected to a file or pipe.
+func terminalSize() (width, height int) {
+	return envSize(os.Getenv)
+}
+
+// envSize implements terminalSize using the given environment lookup
+// function. Missing or invalid values are replaced one by one.
+func envSize(getenv func(string) string) (width, height int) {
+	width, height = fallbackWidth, fallbackHeight
+	if n, err := strconv.Atoi(getenv("COLUMNS")); err == nil && n > 0 {
+		width = n
+	}
+	if n, err := strconv.Atoi(getenv("LINES")); err == nil && n > 0 {
+		height = n
+	}
+	return width, height
+}
+
+// knownSize replaces the dimensions a terminal reports as zero, as some
+// serial consoles and emulators do, with those of terminalSize.
+func knownSize(msg tea.WindowSizeMsg) tea.WindowSizeMsg {
+	if msg.Width > 0 && msg.Height > 0 {
+		return msg
+	}
+	width, height := terminalSize()
+	if msg.Width <= 0 {
+		msg.Width = width
+	}
+	if msg.Height <= 0 {
+		msg.Height = height
+	}
+	return msg
+}
+
+// fitList sizes the list for the given number of entries. With a
+// configured height, the list fills it minus the space used by the border,
+// preview pane and status line; otherwise it grows with the entries.
+func (m *model) fitList(entries int) {
+	total := m.height.lines(m.terminalHeight)
+	if total <= 0 {
+		m.list.SetHeight(min(entries+listHeightPadding, maxDynamicListHeight))
+		return
+	}
+
+	// Status line
+	total--
+	if m.border {
+		total -= borderSize
+	}
+	if m.previewCmd != "" {
+		total -= m.previewHeight() + 1
+	}
+	m.list.SetHeight(max(total, minListHeight))
+}
+
+// compactLayout reports whether the interface is drawn for a small screen:
+// when asked to, or when the terminal is narrower than compactWidth.
+func (m model) compactLayout() bool {
+	return m.compact || (m.terminalWidth > 0 && m.terminalWidth < compactWidth)
+}
+
+// previewHeight returns the number of lines of the preview pane.
+func (m model) previewHeight() int {
+	if m.compactLayout() {
+		return compactPreviewLines
+	}
+	return previewLines
+}
+
+// stack joins the list view with the blocks drawn below it, such as the
+// preview pane, the status line or a prompt. The bottom-up layout turns the
+// list upside down, with its title at the bottom, and draws the blocks above
+// it in reverse order; the lines of each block keep their reading order.
+func (m model) stack(list string, blocks ...string) string {
+	if !m.bottomUp {
+		return strings.Join(append([]string{list}, blocks...), "\n")
+	}
+	lines := strings.Split(list, "\n")
+	slices.Reverse(lines)
+	blocks = slices.Clone(blocks)
+	slices.Reverse(blocks)
+	return strings.Join(append(blocks, strings.Join(lines, "\n")), "\n")
+}
+
+// View renders the interface, applying the layout options to the view of
+// the current screen.
+func (m model) View() string {
+	view := toASCII(m.screenView(), m.ascii)
+	if m.border {
+		if m.terminalWidth > borderSize {
+			// Lines wider than the space inside the border would wrap
+			view = lipgloss.NewStyle().MaxWidth(m.terminalWidth - borderSize).Render(view)
+		}
+		if m.ascii {
+			view = asciiBorderStyle.Render(view)
+		} else {
+			view = borderStyle.Render(view)
+		}
+	}
+	return view
+}
@@ -0,0 +1,108 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kaczmarekdaniel/folder-search/internal/scaffold"
+)
+
+const (
+	// noTemplate labels the option that creates an empty folder
+	noTemplate = "none"
+
+	newDirHelpText = "enter create • tab/shift+tab template • esc cancel"
+)
+
+// templateNames returns the configured template names sorted alphabetically,
+// preceded by the empty-folder option.
+func templateNames(templates map[string][]string) []string {
+	names := make([]string, 0, len(templates)+1)
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return append([]string{noTemplate}, names...)
+}
+
+// startNewDirPrompt opens the prompt for creating a folder in the current directory.
+func (m model) startNewDirPrompt() (tea.Model, tea.Cmd) {
+	input := textinput.New()
+	input.Placeholder = "folder name"
+	input.Prompt = "New folder: "
+	m.nameInput = input
+	m.templateIdx = 0
+	m.creating = true
+	return m, m.nameInput.Focus()
+}
+
+// updateNewDirPrompt handles key presses while the new folder prompt is open.
+func (m model) updateNewDirPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	names := templateNames(m.templates)
+
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		m.creating = false
+		return m, nil
+	case "tab":
+		m.templateIdx = (m.templateIdx + 1) % len(names)
+		return m, nil
+	case "shift+tab":
+		m.templateIdx = (m.templateIdx + len(names) - 1) % len(names)
+		return m, nil
+	case "enter":
+		return m.createDir(names[m.templateIdx])
+	}
+
+	var cmd tea.Cmd
+	m.nameInput, cmd = m.nameInput.Update(msg)
+	return m, cmd
+}
+
+// createDir creates the folder typed in the prompt, scaffolded from the
+// named template, and rescans the current directory.
+func (m model) createDir(template string) (tea.Model, tea.Cmd) {
+	name := strings.TrimSpace(m.nameInput.Value())
+	if name == "" || strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+		m.status = "enter a folder name without path separators"
+		return m, nil
+	}
+
+	target := filepath.Join(m.currentDir, name)
+	if err := scaffold.Create(target, m.templates[template]); err != nil {
+		m.logger.Warn("failed to create directory", "dir", target, "template", template, "error", err)
+		m.status = fmt.Sprintf("cannot create '%s': %v", name, err)
+		return m, nil
+	}
+
+	m.logger.Info("created directory", "dir", target, "template", template)
+	m.creating = false
+	m.status = fmt.Sprintf("created '%s' from template %s", name, template)
+	return m.scan(m.currentDir)
+}
+
+// newDirView renders the new folder prompt below the list.
+func (m model) newDirView() string {
+	names := templateNames(m.templates)
+	template := names[m.templateIdx]
+
+	var b strings.Builder
+	b.WriteString(itemStyle.Render(m.nameInput.View()))
+	b.WriteString("\n")
+	line := "Template: " + template
+	if dirs := m.templates[template]; len(dirs) > 0 {
+		line += dimStyle.Render("  (" + strings.Join(dirs, " ") + ")")
+	}
+	b.WriteString(itemStyle.Render(line))
+	b.WriteString("\n")
+	if m.status != "" {
+		b.WriteString(statusStyle.Render(m.status))
+		b.WriteString("\n")
+	}
+	b.WriteString(helpStyle.Render(newDirHelpText))
+	return b.String()
+}
@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// maxInlineNote is the number of characters of a note shown next to a
+	// directory name; the full note is shown below the list
+	maxInlineNote = 40
+
+	noteHelpText = "enter save • empty note removes it • esc cancel"
+)
+
+// startNoteEditor opens the prompt for editing the note of the highlighted directory.
+func (m model) startNoteEditor() (tea.Model, tea.Cmd) {
+	i, ok := m.list.SelectedItem().(item)
+	if !ok || m.err != nil || m.pendingDir != "" {
+		return m, nil
+	}
+
+	m.noteTarget = filepath.Join(m.currentDir, string(i))
+	input := textinput.New()
+	input.Placeholder = "e.g. prod config, don't touch"
+	input.Prompt = "Note: "
+	input.SetValue(m.notes.Get(m.noteTarget))
+	input.CursorEnd()
+	m.noteInput = input
+	m.editingNote = true
+	return m, m.noteInput.Focus()
+}
+
+// updateNoteEditor handles key presses while the note prompt is open.
+func (m model) updateNoteEditor(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		m.editingNote = false
+		return m, nil
+	case "enter":
+		name := filepath.Base(m.noteTarget)
+		m.notes.Set(m.noteTarget, m.noteInput.Value())
+		if m.notes.Get(m.noteTarget) == "" {
+			m.status = fmt.Sprintf("removed note from '%s'", name)
+		} else {
+			m.status = fmt.Sprintf("saved note for '%s'", name)
+		}
+		m.logger.Debug("updated note", "dir", m.noteTarget)
+		m.editingNote = false
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.noteInput, cmd = m.noteInput.Update(msg)
+	return m, cmd
+}
+
+// noteEditorView renders the note prompt below the list.
+func (m model) noteEditorView() string {
+	var b strings.Builder
+	b.WriteString(itemStyle.Render(m.noteInput.View()))
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render(noteHelpText))
+	return b.String()
+}
+
+// selectedNote returns the note of the highlighted directory, if any.
+func (m model) selectedNote() string {
+	i, ok := m.list.SelectedItem().(item)
+	if !ok || m.pendingDir != "" {
+		return ""
+	}
+	return m.notes.Get(filepath.Join(m.listedDir, string(i)))
+}
+
+// shortNote truncates note to maxInlineNote characters.
+func shortNote(note string) string {
+	runes := []rune(note)
+	if len(runes) <= maxInlineNote {
+		return note
+	}
+	return string(runes[:maxInlineNote-1]) + "…"
+}
@@ -0,0 +1,45 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kaczmarekdaniel/folder-search/internal/hook"
+	"github.com/kaczmarekdaniel/folder-search/internal/jobs"
+)
+
+// notifyFailedMsg reports that the notify command failed for a job.
+type notifyFailedMsg struct {
+	job string
+	err error
+}
+
+// notifyJob returns a command running the notify command for the finished
+// job described by info, or nil if notifications are off or the job was too
+// quick to be worth one.
+func (m model) notifyJob(info jobs.Info) tea.Cmd {
+	if m.notify.Command == "" || info.Duration() < m.notify.MinDuration() {
+		return nil
+	}
+
+	command, env := m.notify.Command, jobEnv(info)
+	return func() tea.Msg {
+		if err := hook.Run(context.Background(), command, env); err != nil {
+			return notifyFailedMsg{job: info.Name, err: err}
+		}
+		return nil
+	}
+}
+
+// jobEnv returns the hook environment describing a finished job:
+// hook.MessageVar and hook.EventVar, FOLDER_SEARCH_JOB holding its name and
+// FOLDER_SEARCH_STATUS its status, such as "done" or "failed".
+func jobEnv(info jobs.Info) map[string]string {
+	return map[string]string{
+		hook.MessageVar:        fmt.Sprintf("%s: %s", info.Name, jobSummary(info)),
+		hook.EventVar:          "job",
+		"FOLDER_SEARCH_JOB":    info.Name,
+		"FOLDER_SEARCH_STATUS": info.Status.String(),
+	}
+}
@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/kaczmarekdaniel/folder-search/internal/config"
+	"github.com/kaczmarekdaniel/folder-search/internal/jobs"
+)
+
+func TestNotifyJob(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the notify command uses a POSIX shell")
+	}
+
+	dir, err := os.MkdirTemp("", "ui-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	out := filepath.Join(dir, "out")
+	m := model{notify: config.NotifyConfig{
+		Command:    `printf '%s|%s' "$FOLDER_SEARCH_STATUS" "$FOLDER_SEARCH_MESSAGE" > ` + out,
+		MinSeconds: 60,
+	}}
+	started := time.Now().Add(-2 * time.Minute)
+	info := jobs.Info{Name: "archive photos.tar.gz", Status: jobs.Done, Result: "photos.tar.gz (2.0 GiB)", Started: started, Ended: time.Now()}
+
+	if cmd := m.notifyJob(jobs.Info{Status: jobs.Done, Started: time.Now(), Ended: time.Now()}); cmd != nil {
+		t.Error("expected no notification for a quick job")
+	}
+
+	cmd := m.notifyJob(info)
+	if cmd == nil {
+		t.Fatal("expected a notification for a long job")
+	}
+	if msg := cmd(); msg != nil {
+		t.Fatalf("expected the command to succeed, got %#v", msg)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	if got, want := string(data), "done|archive photos.tar.gz: photos.tar.gz (2.0 GiB)"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	m.notify.Command = "exit 1"
+	if _, ok := m.notifyJob(info)().(notifyFailedMsg); !ok {
+		t.Error("expected a failing command to be reported")
+	}
+}
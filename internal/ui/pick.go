@@ -0,0 +1,106 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kaczmarekdaniel/folder-search/internal/app"
+	"github.com/kaczmarekdaniel/folder-search/internal/logging"
+)
+
+// pickModel lets the user choose one entry from a fixed list of options.
+type pickModel struct {
+	list   list.Model
+	logger *slog.Logger
+	choice string
+	done   bool
+	ascii  bool // Limits the view to ASCII symbols
+}
+
+func (m pickModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m pickModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		msg = knownSize(msg)
+		m.list.SetSize(msg.Width, msg.Height)
+		return m, nil
+	case tea.KeyMsg:
+		// While typing a filter, keys belong to the filter input
+		if m.list.FilterState() == list.Filtering {
+			break
+		}
+		switch msg.String() {
+		case "ctrl+c", "q", "esc":
+			// esc first clears an applied filter
+			if msg.String() == "esc" && m.list.FilterState() == list.FilterApplied {
+				break
+			}
+			m.logger.Info("pick canceled")
+			m.done = true
+			return m, tea.Quit
+		case "enter":
+			if i, ok := m.list.SelectedItem().(item); ok {
+				m.choice = string(i)
+			}
+			m.done = true
+			return m, tea.Quit
+		}
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+func (m pickModel) View() string {
+	if m.done {
+		return ""
+	}
+	return toASCII(m.list.View(), m.ascii)
+}
+
+// RunPicker shows options in a filterable list and returns the one the user
+// selected, or an empty string if they canceled.
+//
+// It follows the same terminal rules as InitUI: the list is drawn on
+// standard error when standard output is redirected, and keys are read from
+// the controlling terminal when standard input is redirected.
+//
+// Parameters:
+//   - app: The application instance providing the logger
+//   - options: The entries to choose from, shown in the given order
+//   - ascii: Limits the interface to ASCII symbols, as it is anyway when
+//     the terminal does not use UTF-8
+func RunPicker(app *app.Application, options []string, ascii bool) (string, error) {
+	logger := app.ModuleLogger(logging.ModuleUI)
+	logger.Info("starting picker", "options", len(options))
+
+	width, height := terminalSize()
+	l := list.New(stringsToItems(options), itemDelegate{}, width, height)
+	l.Title = "Pick"
+	l.SetShowStatusBar(false)
+	l.Styles.Title = titleStyle
+	l.Styles.PaginationStyle = paginationStyle
+	l.Styles.HelpStyle = helpStyle
+
+	m := pickModel{list: l, logger: logger, ascii: useASCII(ascii)}
+	if m.ascii {
+		// Keeps the truncated help line within the width once converted
+		m.list.Help.Ellipsis = "..."
+	}
+	final, err := tea.NewProgram(m, programOptions()...).Run()
+	if err != nil {
+		return "", fmt.Errorf("failed to run picker: %w", err)
+	}
+
+	fm, ok := final.(pickModel)
+	if !ok {
+		return "", nil
+	}
+	return fm.choice, nil
+}
@@ -0,0 +1,153 @@
+package ui
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kaczmarekdaniel/folder-search/internal/preview"
+)
+
+const (
+	// previewLines is the height of the preview pane
+	previewLines = 10
+
+	// previewCacheSize is the number of previews kept before the cache is reset
+	previewCacheSize = 256
+
+	// previewWatchInterval is how often the previewed directory is checked
+	// for changes
+	previewWatchInterval = time.Second
+
+	// lowPowerPreviewInterval replaces previewWatchInterval in low power mode
+	lowPowerPreviewInterval = 10 * time.Second
+)
+
+// previewMsg carries the output of a preview command.
+type previewMsg struct {
+	path    string
+	output  string
+	err     error
+	modTime time.Time // Modification time of path when the command started
+}
+
+// previewTickMsg asks to check the previewed directory for changes.
+type previewTickMsg struct{}
+
+// previewChangedMsg reports that the previewed directory was modified after
+// its cached preview was made.
+type previewChangedMsg struct {
+	path    string
+	modTime time.Time
+}
+
+// runPreview runs the preview command for path without blocking the UI.
+func runPreview(command, path string) tea.Cmd {
+	return func() tea.Msg {
+		var modTime time.Time
+		if info, err := os.Stat(path); err == nil {
+			modTime = info.ModTime()
+		}
+		output, err := preview.Run(context.Background(), command, path)
+		return previewMsg{path: path, output: output, err: err, modTime: modTime}
+	}
+}
+
+// watchPreview schedules the next check of the previewed directory.
+func watchPreview(interval time.Duration) tea.Cmd {
+	return tea.Tick(interval, func(time.Time) tea.Msg {
+		return previewTickMsg{}
+	})
+}
+
+// checkPreview checks whether entries were created, removed or renamed in
+// the previewed directory since its cached preview was made, e.g. by a
+// build running next to folder-search. Only the directory itself is
+// watched, not its subdirectories.
+func (m model) checkPreview() tea.Cmd {
+	result, ok := m.previews[m.previewPath]
+	if !ok || m.pendingDir != "" {
+		// The preview is still being made, or the list is being replaced
+		return nil
+	}
+
+	path, seen := m.previewPath, result.modTime
+	return func() tea.Msg {
+		info, err := os.Stat(path)
+		if err != nil || info.ModTime().Equal(seen) {
+			return nil
+		}
+		return previewChangedMsg{path: path, modTime: info.ModTime()}
+	}
+}
+
+// refreshPreview reruns the preview command for a changed directory. The
+// cached output stays on screen until the new output arrives.
+func (m model) refreshPreview(msg previewChangedMsg) tea.Cmd {
+	result, ok := m.previews[msg.path]
+	if !ok || msg.path != m.previewPath || result.modTime.Equal(msg.modTime) {
+		return nil
+	}
+	// Record the change now, so later checks do not rerun the command
+	// while it is still running
+	result.modTime = msg.modTime
+	m.previews[msg.path] = result
+	return runPreview(m.previewCmd, msg.path)
+}
+
+// requestPreview starts the preview command for the highlighted directory
+// unless its output is already cached or on its way.
+func (m *model) requestPreview() tea.Cmd {
+	if m.previewCmd == "" {
+		return nil
+	}
+	i, ok := m.list.SelectedItem().(item)
+	if !ok || m.pendingDir != "" {
+		return nil
+	}
+
+	path := filepath.Join(m.currentDir, string(i))
+	if path == m.previewPath {
+		return nil
+	}
+	m.previewPath = path
+	if _, cached := m.previews[path]; cached {
+		return nil
+	}
+	return runPreview(m.previewCmd, path)
+}
+
+// storePreview caches the output of a preview command.
+func (m model) storePreview(msg previewMsg) {
+	if len(m.previews) >= previewCacheSize {
+		clear(m.previews)
+	}
+	if msg.err != nil {
+		m.logger.Debug("preview command failed", "dir", msg.path, "error", msg.err)
+	}
+	m.previews[msg.path] = msg
+}
+
+// previewView renders the first lines of the highlighted directory's preview.
+func (m model) previewView() string {
+	result, ok := m.previews[m.previewPath]
+	if !ok {
+		return statusStyle.Render("loading preview…")
+	}
+
+	output := strings.TrimRight(result.output, "\n")
+	if result.err != nil {
+		output = strings.TrimSpace(output + "\n" + result.err.Error())
+	}
+	lines := strings.Split(output, "\n")
+	if len(lines) > m.previewHeight() {
+		lines = lines[:m.previewHeight()]
+	}
+	for i, line := range lines {
+		lines[i] = dimStyle.Render(strings.ReplaceAll(line, "\t", "    "))
+	}
+	return itemStyle.Render(strings.Join(lines, "\n"))
+}
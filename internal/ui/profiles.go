@@ -0,0 +1,121 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kaczmarekdaniel/folder-search/internal/config"
+)
+
+const profilesHelpText = "↑/↓ select • enter switch • w/esc close"
+
+// defaultProfileName labels the built-in profile that restores the
+// settings folder-search started with.
+const defaultProfileName = "default"
+
+// profileNames returns the configured profile names in alphabetical order,
+// preceded by the default profile.
+func (m model) profileNames() []string {
+	names := make([]string, 0, len(m.profiles)+1)
+	for name := range m.profiles {
+		if name != defaultProfileName {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return append([]string{defaultProfileName}, names...)
+}
+
+// startProfileSwitcher opens the profile switcher with the active profile highlighted.
+func (m model) startProfileSwitcher() (tea.Model, tea.Cmd) {
+	m.profileCursor = max(slices.Index(m.profileNames(), m.profile), 0)
+	m.showProfiles = true
+	return m, nil
+}
+
+// updateProfileSwitcher handles key presses while the profile switcher is open.
+func (m model) updateProfileSwitcher(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	names := m.profileNames()
+	switch msg.String() {
+	case "up", "k":
+		if m.profileCursor > 0 {
+			m.profileCursor--
+		}
+	case "down", "j":
+		if m.profileCursor < len(names)-1 {
+			m.profileCursor++
+		}
+	case "enter":
+		m.showProfiles = false
+		return m.switchProfile(names[m.profileCursor])
+	case "w", "esc":
+		m.showProfiles = false
+	}
+	return m, nil
+}
+
+// switchProfile activates the named profile: its ignore list applies to all
+// following scans and the UI moves to its root, or rescans the current
+// directory if the profile has no root.
+func (m model) switchProfile(name string) (tea.Model, tea.Cmd) {
+	p, ok := m.profiles[name]
+	if !ok && name != defaultProfileName {
+		return m, nil
+	}
+	if name == defaultProfileName && !ok {
+		p = config.Profile{Ignore: m.defaultIgnore}
+	}
+
+	dir := m.currentDir
+	if p.Root != "" {
+		root, err := p.RootDir()
+		if err != nil {
+			m.status = fmt.Sprintf("cannot switch to %s: %v", name, err)
+			return m, nil
+		}
+		if err := checkDirPermission(root); err != nil {
+			m.status = fmt.Sprintf("cannot switch to %s: %v", name, err)
+			return m, nil
+		}
+		dir = root
+	}
+
+	m.profile = name
+	m.ignore = p.Ignore
+	if m.ignore == nil {
+		m.ignore = m.defaultIgnore
+	}
+	m.logger.Info("switched profile", "profile", name, "root", dir, "ignore", m.ignore)
+	m.status = "profile: " + name
+	return m.navigate(dir)
+}
+
+// profilesView renders the profile switcher.
+func (m model) profilesView() string {
+	var b strings.Builder
+	b.WriteString(panelTitleStyle.Render("Profiles"))
+	b.WriteString("\n\n")
+
+	for i, name := range m.profileNames() {
+		line := name
+		if p, ok := m.profiles[name]; ok && p.Root != "" {
+			line += "  " + dimStyle.Render(p.Root)
+		}
+		if name == m.profile {
+			line += "  " + dimStyle.Render("(active)")
+		}
+		if i == m.profileCursor {
+			b.WriteString(selectedItemStyle.Render("> " + line))
+		} else {
+			b.WriteString(itemStyle.Render(line))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render(profilesHelpText))
+	return b.String()
+}
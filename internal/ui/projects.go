@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kaczmarekdaniel/folder-search/internal/dirsearch"
+)
+
+const projectFilterHelpText = "enter filter • empty type shows all directories • esc cancel"
+
+// startProjectFilter opens the prompt for the project types the listing is
+// limited to, pre-populated with the current ones.
+func (m model) startProjectFilter() (tea.Model, tea.Cmd) {
+	if m.err != nil {
+		return m, nil
+	}
+
+	input := textinput.New()
+	input.Prompt = "Project type: "
+	input.Placeholder = "git, go, node, rust or python"
+	input.SetValue(strings.Join(m.projects.Names(), ", "))
+	input.CursorEnd()
+	m.projectInput = input
+	m.editingProject = true
+	return m, m.projectInput.Focus()
+}
+
+// updateProjectFilter handles key presses while the project type prompt is
+// open.
+func (m model) updateProjectFilter(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		m.editingProject = false
+		return m, nil
+	case "enter":
+		m.editingProject = false
+		return m.setProjectFilter(m.projectInput.Value())
+	}
+
+	var cmd tea.Cmd
+	m.projectInput, cmd = m.projectInput.Update(msg)
+	return m, cmd
+}
+
+// setProjectFilter limits the listing to the roots of projects of the types
+// named in value, such as "git" or "go, rust", or removes the limit if value
+// names none, and rescans the current directory. Unknown types keep the
+// current filter.
+func (m model) setProjectFilter(value string) (tea.Model, tea.Cmd) {
+	projects, err := dirsearch.ParseProjectTypes(value)
+	if err != nil {
+		m.status = err.Error()
+		return m, nil
+	}
+
+	m.projects = projects
+	if projects == 0 {
+		m.status = "showing all directories"
+	} else {
+		m.status = fmt.Sprintf("showing %s projects", strings.Join(projects.Names(), " and "))
+	}
+	return m.scan(m.currentDir)
+}
+
+// projectFilterView renders the project type prompt below the list.
+func (m model) projectFilterView() string {
+	var b strings.Builder
+	b.WriteString(itemStyle.Render(m.projectInput.View()))
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render(projectFilterHelpText))
+	return b.String()
+}
@@ -0,0 +1,45 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kaczmarekdaniel/folder-search/internal/dirsearch"
+)
+
+func TestProjectFilter(t *testing.T) {
+	root := makeRenderTree(t)
+	for _, marker := range []string{"alpha/go.mod", "beta/Cargo.toml"} {
+		if err := os.WriteFile(filepath.Join(root, marker), nil, 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", marker, err)
+		}
+	}
+
+	d, err := NewDriver(newTestApp(t), root, Options{Projects: dirsearch.ProjectGo}, 80, 20)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer d.Close()
+
+	if frame := d.Frame(); !strings.Contains(frame, "alpha") || strings.Contains(frame, "beta") {
+		t.Errorf("expected only the Go module, got:\n%s", frame)
+	}
+
+	if err := d.Press("y"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d.Type(", rust")
+	if err := d.Press("enter"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d.Settle()
+	frame := d.Frame()
+	if !strings.Contains(frame, "alpha") || !strings.Contains(frame, "beta") || strings.Contains(frame, "docs") {
+		t.Errorf("expected the Go module and the Rust crate, got:\n%s", frame)
+	}
+	if !strings.Contains(frame, "showing go and rust projects") {
+		t.Errorf("expected the filter in the status line, got:\n%s", frame)
+	}
+}
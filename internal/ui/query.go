@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const queryHelpText = "enter filter • ↑/↓ past filters • empty shows all directories • esc cancel"
+
+// startQueryPrompt opens the prompt for the name filter, pre-populated with
+// the current query. Up and down recall past filters.
+func (m model) startQueryPrompt() (tea.Model, tea.Cmd) {
+	if m.err != nil {
+		return m, nil
+	}
+
+	input := textinput.New()
+	input.Prompt = "Filter: "
+	input.Placeholder = "part of a directory name"
+	input.SetValue(m.query)
+	input.CursorEnd()
+	m.queryInput = input
+	m.queryRecall = newRecall(m.history.Searches(""))
+	m.editingQuery = true
+	return m, m.queryInput.Focus()
+}
+
+// updateQueryPrompt handles key presses while the filter prompt is open.
+func (m model) updateQueryPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		m.editingQuery = false
+		return m, nil
+	case "enter":
+		m.editingQuery = false
+		m.query = strings.TrimSpace(m.queryInput.Value())
+		m.history.AddSearch(m.query)
+		if m.query == "" {
+			m.status = "showing all directories"
+		} else {
+			m.status = fmt.Sprintf("showing directories matching '%s'", m.query)
+		}
+		return m.scan(m.currentDir)
+	case "up":
+		if past, ok := m.queryRecall.older(m.queryInput.Value()); ok {
+			m.queryInput.SetValue(past)
+			m.queryInput.CursorEnd()
+		}
+		return m, nil
+	case "down":
+		if past, ok := m.queryRecall.newer(); ok {
+			m.queryInput.SetValue(past)
+			m.queryInput.CursorEnd()
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.queryInput, cmd = m.queryInput.Update(msg)
+	return m, cmd
+}
+
+// queryPromptView renders the filter prompt below the list.
+func (m model) queryPromptView() string {
+	var b strings.Builder
+	b.WriteString(itemStyle.Render(m.queryInput.View()))
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render(queryHelpText))
+	return b.String()
+}
@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kaczmarekdaniel/folder-search/internal/dirsearch"
+)
+
+const refineHelpText = "enter narrow the listed directories • backspace in the list undoes • esc cancel"
+
+// startRefinePrompt opens the prompt for narrowing the listed directories.
+func (m model) startRefinePrompt() (tea.Model, tea.Cmd) {
+	if m.err != nil || m.pendingDir != "" {
+		return m, nil
+	}
+
+	input := textinput.New()
+	input.Prompt = "Refine: "
+	input.Placeholder = "part of a directory name"
+	m.refineInput = input
+	m.editingRefine = true
+	return m, m.refineInput.Focus()
+}
+
+// updateRefinePrompt handles key presses while the refine prompt is open.
+func (m model) updateRefinePrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		m.editingRefine = false
+		return m, nil
+	case "enter":
+		m.editingRefine = false
+		if pattern := strings.TrimSpace(m.refineInput.Value()); pattern != "" {
+			m.refinements = append(m.refinements, pattern)
+			m.showDirs(m.listedDir, m.listedDirs)
+			m.list.Select(0)
+			m.status = fmt.Sprintf("%d directories matching '%s'", len(m.list.Items()), pattern)
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.refineInput, cmd = m.refineInput.Update(msg)
+	return m, cmd
+}
+
+// popRefinement undoes the latest refinement, showing the directories it
+// had filtered out again without rescanning.
+func (m model) popRefinement() (tea.Model, tea.Cmd) {
+	if len(m.refinements) == 0 || m.pendingDir != "" {
+		return m, nil
+	}
+
+	pattern := m.refinements[len(m.refinements)-1]
+	m.refinements = m.refinements[:len(m.refinements)-1]
+	m.showDirs(m.listedDir, m.listedDirs)
+	m.status = fmt.Sprintf("removed refinement '%s'", pattern)
+	return m, nil
+}
+
+// refineDirs returns the names containing every refinement, ignoring case.
+func (m model) refineDirs(names []string) []string {
+	if len(m.refinements) == 0 {
+		return names
+	}
+
+	patterns := make([]string, len(m.refinements))
+	for i, p := range m.refinements {
+		patterns[i] = dirsearch.FoldCase(p)
+	}
+
+	refined := make([]string, 0, len(names))
+	for _, name := range names {
+		folded := dirsearch.FoldCase(name)
+		matches := true
+		for _, p := range patterns {
+			if !strings.Contains(folded, p) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			refined = append(refined, name)
+		}
+	}
+	return refined
+}
+
+// refinePromptView renders the refine prompt below the list.
+func (m model) refinePromptView() string {
+	var b strings.Builder
+	b.WriteString(itemStyle.Render(m.refineInput.View()))
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render(refineHelpText))
+	return b.String()
+}
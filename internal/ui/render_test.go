@@ -0,0 +1,158 @@
+package ui
+
+import (
+	"flag"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/kaczmarekdaniel/folder-search/internal/app"
+	"github.com/kaczmarekdaniel/folder-search/internal/config"
+	"github.com/kaczmarekdaniel/folder-search/internal/dirsearch"
+	"github.com/kaczmarekdaniel/folder-search/internal/features"
+	"github.com/kaczmarekdaniel/folder-search/internal/jobs"
+	"github.com/kaczmarekdaniel/folder-search/internal/logging"
+	"github.com/kaczmarekdaniel/folder-search/internal/notes"
+	"github.com/kaczmarekdaniel/folder-search/internal/pins"
+	"github.com/kaczmarekdaniel/folder-search/internal/scanhistory"
+	"github.com/kaczmarekdaniel/folder-search/internal/searchhistory"
+	"github.com/kaczmarekdaniel/folder-search/internal/stats"
+	"github.com/kaczmarekdaniel/folder-search/internal/tags"
+	"github.com/muesli/termenv"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files of the rendering tests")
+
+// newTestApp returns an application that keeps its state in memory and
+// whose rendering does not depend on the machine: the title shows the
+// directory name instead of its path and the directory watcher is off.
+func newTestApp(t *testing.T) *app.Application {
+	t.Helper()
+	cfg := config.Default()
+	cfg.TitleTemplate = "{{.Name}}{{if .Query}} /{{.Query}}{{end}}"
+	cfg.Colors = []config.ColorRule{{Pattern: "*-prod", Color: "red"}}
+	flags, _ := features.New(map[string]bool{string(features.Watch): false})
+
+	a := &app.Application{
+		Dirsearch:     dirsearch.NewDirSearch(),
+		Logger:        slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Jobs:          jobs.NewQueue(1),
+		Config:        cfg,
+		ScanHistory:   scanhistory.New("", scanhistory.DefaultThreshold),
+		SearchHistory: searchhistory.New(""),
+		Stats:         stats.New(""),
+		Pins:          pins.New(""),
+		Notes:         notes.New(""),
+		Tags:          tags.New(""),
+		Logs:          logging.NewRing(logging.DefaultRingSize),
+		Features:      flags,
+	}
+	t.Cleanup(a.Jobs.Close)
+	return a
+}
+
+// makeRenderTree creates the directories listed by the rendering tests.
+func makeRenderTree(t *testing.T) string {
+	t.Helper()
+	tempDir, err := os.MkdirTemp("", "ui-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(tempDir) })
+
+	root := filepath.Join(tempDir, "project")
+	for _, dir := range []string{
+		"alpha/one", "alpha/two", "beta", "api-prod", "docs",
+		"a-directory-with-a-name-long-enough-to-be-truncated", "node_modules",
+	} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", dir, err)
+		}
+	}
+	return root
+}
+
+func TestRender(t *testing.T) {
+	// Fix the terminal capabilities so frames do not depend on where the
+	// tests run
+	lipgloss.SetColorProfile(termenv.ANSI256)
+	lipgloss.SetHasDarkBackground(true)
+	t.Setenv("TERM", "xterm-256color")
+	t.Setenv("LC_ALL", "en_US.UTF-8")
+
+	fixed, _ := ParseHeight("8")
+	tests := []struct {
+		name          string
+		width, height int
+		opts          Options
+		keys          []string
+	}{
+		{name: "list-80", width: 80, height: 20},
+		{name: "list-120", width: 120, height: 20},
+		{name: "narrow-40", width: 40, height: 20},
+		{name: "bottom-up", width: 80, height: 20, opts: Options{BottomUp: true}},
+		{name: "bottom-up-preview", width: 80, height: 20, opts: Options{BottomUp: true, Preview: `printf 'first\nsecond\nthird\n'`}},
+		{name: "border", width: 80, height: 20, opts: Options{Border: true}},
+		{name: "border-ascii", width: 80, height: 20, opts: Options{Border: true, ASCII: true}},
+		{name: "compact", width: 80, height: 20, opts: Options{Compact: true}},
+		{name: "fixed-height", width: 80, height: 20, opts: Options{Height: fixed}},
+		{name: "query", width: 80, height: 20, opts: Options{Query: "a"}},
+		{name: "navigated", width: 80, height: 20, keys: []string{"down", "right"}},
+		{name: "query-prompt", width: 80, height: 20, keys: []string{"/", "d", "o"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := makeRenderTree(t)
+			d, err := NewDriver(newTestApp(t), root, tt.opts, tt.width, tt.height)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer d.Close()
+
+			if err := d.Press(tt.keys...); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			d.Settle()
+			// The free space of the test machine would change the status line
+			d.Send(freeSpaceMsg{dir: d.model.(model).currentDir, free: 42 << 30})
+
+			checkGolden(t, filepath.Join("testdata", "render", tt.name+".golden"), d.Frame())
+		})
+	}
+}
+
+// checkGolden compares frame with the golden file at path, or rewrites the
+// file with frame when the tests run with -update.
+func checkGolden(t *testing.T, path, frame string) {
+	t.Helper()
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create golden dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(frame), 0644); err != nil {
+			t.Fatalf("failed to write golden file: %v", err)
+		}
+		return
+	}
+
+	golden, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file (run go test -update to create it): %v", err)
+	}
+	if expected := string(golden); frame != expected {
+		t.Errorf("frame differs from %s (run go test -update to accept it)\nexpected:\n%s\ngot:\n%s",
+			path, visible(expected), visible(frame))
+	}
+}
+
+// visible makes the escape sequences and line ends of a frame readable in
+// test output.
+func visible(frame string) string {
+	frame = strings.ReplaceAll(frame, "\x1b", `\e`)
+	return strings.ReplaceAll(frame, "\n", "⏎\n")
+}
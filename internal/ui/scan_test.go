@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kaczmarekdaniel/folder-search/internal/dirsearch"
+	"github.com/kaczmarekdaniel/folder-search/internal/scanhistory"
+	"github.com/kaczmarekdaniel/folder-search/internal/stats"
+)
+
+func TestAdaptiveScan_SlowMatchesFast(t *testing.T) {
+	root, err := os.MkdirTemp("", "ui-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(root)
+
+	// Created in reverse order so the order of the directory listing is
+	// unlikely to be sorted already
+	for i := 30; i > 0; i-- {
+		if err := os.Mkdir(filepath.Join(root, fmt.Sprintf("data%02d", i)), 0755); err != nil {
+			t.Fatalf("failed to create test dir: %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, "data07", "go.mod"), []byte("module data\n"), 0644); err != nil {
+		t.Fatalf("failed to write go.mod: %v", err)
+	}
+
+	ds := dirsearch.NewDirSearch()
+	ds.Options.DetectProjects = true
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	req := scanRequest{dir: root, pattern: "ta"}
+
+	fast := adaptiveScan(ds, scanhistory.New("", time.Hour), stats.New(""), logger)
+	want := fast(context.Background(), req, func([]string) {
+		t.Error("expected no partial results from a fast scan")
+	})
+
+	history := scanhistory.New("", time.Nanosecond)
+	history.Record(root, time.Second)
+	slow := adaptiveScan(ds, history, stats.New(""), logger)
+	partials := 0
+	got := slow(context.Background(), req, func([]string) { partials++ })
+
+	if partials == 0 {
+		t.Error("expected partial results from a slow scan")
+	}
+	if got.Error != nil || want.Error != nil {
+		t.Fatalf("unexpected errors: %v, %v", got.Error, want.Error)
+	}
+	if len(want.Directories) != 30 || want.Projects == nil || want.MatchedRanges == nil {
+		t.Fatalf("expected 30 directories with projects and matched ranges, got %+v", want)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected the slow scan to return %+v, got %+v", want, got)
+	}
+}
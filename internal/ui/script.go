@@ -0,0 +1,91 @@
+package ui
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// RunScript drives the interface with the commands read from r, one per
+// line, and writes the output they ask for to w. Blank lines and lines
+// starting with # are skipped. The commands are:
+//   - press KEY...: press the keys, named as for Driver.Press
+//   - type TEXT: type the rest of the line
+//   - resize WIDTH HEIGHT: resize the simulated terminal
+//   - settle: wait until the interface is idle, see Driver.Settle
+//   - wait TEXT: wait until the frame contains the rest of the line
+//   - frame: write the frame followed by an empty line
+//   - selection: write the directory chosen with Enter, or an empty line
+//
+// Parameters:
+//   - d: the driver of the interface
+//   - r: the script
+//   - w: receives frames and selections
+//
+// Returns an error naming the line of the first command that failed.
+func RunScript(d *Driver, r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		if err := runCommand(d, line, w); err != nil {
+			return fmt.Errorf("line %d: %w", n, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read script: %w", err)
+	}
+	return nil
+}
+
+// runCommand runs a single line of a script.
+func runCommand(d *Driver, line string, w io.Writer) error {
+	name, text, _ := strings.Cut(strings.TrimLeft(line, " \t"), " ")
+	args := strings.Fields(text)
+
+	switch name {
+	case "press":
+		if len(args) == 0 {
+			return errors.New("press needs at least one key")
+		}
+		return d.Press(args...)
+	case "type":
+		d.Type(text)
+	case "resize":
+		if len(args) != 2 {
+			return errors.New("resize needs a width and a height")
+		}
+		width, err := strconv.Atoi(args[0])
+		if err != nil || width <= 0 {
+			return fmt.Errorf("invalid width %q", args[0])
+		}
+		height, err := strconv.Atoi(args[1])
+		if err != nil || height <= 0 {
+			return fmt.Errorf("invalid height %q", args[1])
+		}
+		d.Resize(width, height)
+	case "settle":
+		d.Settle()
+	case "wait":
+		if text == "" {
+			return errors.New("wait needs the text to wait for")
+		}
+		return d.WaitFor(DefaultWaitTimeout, func(frame string) bool {
+			return strings.Contains(frame, text)
+		})
+	case "frame":
+		_, err := fmt.Fprintf(w, "%s\n\n", d.Frame())
+		return err
+	case "selection":
+		_, err := fmt.Fprintln(w, d.Selection())
+		return err
+	default:
+		return fmt.Errorf("unknown command %q", name)
+	}
+	return nil
+}
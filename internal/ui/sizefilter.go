@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kaczmarekdaniel/folder-search/internal/dirsearch"
+)
+
+const sizeFilterHelpText = "enter filter • empty size shows all directories • esc cancel"
+
+// startSizeFilter opens the prompt for the size directories must exceed to
+// be listed, pre-populated with the current threshold.
+func (m model) startSizeFilter() (tea.Model, tea.Cmd) {
+	if m.err != nil {
+		return m, nil
+	}
+
+	input := textinput.New()
+	input.Prompt = "Larger than: "
+	input.Placeholder = "e.g. 500M or 1G"
+	input.SetValue(m.largerThan)
+	input.CursorEnd()
+	m.sizeInput = input
+	m.editingSize = true
+	return m, m.sizeInput.Focus()
+}
+
+// updateSizeFilter handles key presses while the size prompt is open.
+func (m model) updateSizeFilter(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		m.editingSize = false
+		return m, nil
+	case "enter":
+		m.editingSize = false
+		return m.setSizeFilter(m.sizeInput.Value())
+	}
+
+	var cmd tea.Cmd
+	m.sizeInput, cmd = m.sizeInput.Update(msg)
+	return m, cmd
+}
+
+// setSizeFilter limits the listing to directories whose total size is at
+// least value, such as "1G", or removes the limit if value is empty, and
+// rescans the current directory. An invalid size keeps the current limit.
+func (m model) setSizeFilter(value string) (tea.Model, tea.Cmd) {
+	value = strings.TrimSpace(value)
+	var size int64
+	if value != "" {
+		var err error
+		if size, err = dirsearch.ParseSize(value); err != nil {
+			m.status = err.Error()
+			return m, nil
+		}
+	}
+
+	m.largerThan, m.minSize = value, size
+	if size == 0 {
+		m.largerThan = ""
+		m.status = "showing directories of any size"
+	} else {
+		m.status = fmt.Sprintf("showing directories larger than %s, measuring each one", m.sizes.Format(size))
+	}
+	return m.scan(m.currentDir)
+}
+
+// sizeFilterView renders the size prompt below the list.
+func (m model) sizeFilterView() string {
+	var b strings.Builder
+	b.WriteString(itemStyle.Render(m.sizeInput.View()))
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render(sizeFilterHelpText))
+	return b.String()
+}
@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSizeFilter(t *testing.T) {
+	root := makeRenderTree(t)
+	for _, file := range []string{"alpha/one/data", "beta/data"} {
+		if err := os.WriteFile(filepath.Join(root, file), make([]byte, 4096), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", file, err)
+		}
+	}
+
+	d, err := NewDriver(newTestApp(t), root, Options{LargerThan: "2K"}, 80, 20)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer d.Close()
+
+	frame := d.Frame()
+	for _, name := range []string{"alpha", "beta"} {
+		if !strings.Contains(frame, name) {
+			t.Errorf("expected %s to be listed, got:\n%s", name, frame)
+		}
+	}
+	if strings.Contains(frame, "docs") {
+		t.Errorf("expected empty directories to be hidden, got:\n%s", frame)
+	}
+
+	// An invalid size keeps the current filter
+	if err := d.Press(">", "backspace", "backspace"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d.Type("huge")
+	if err := d.Press("enter"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if frame := d.Frame(); !strings.Contains(frame, `invalid size "huge"`) || strings.Contains(frame, "docs") {
+		t.Errorf("expected an error and the filter kept, got:\n%s", frame)
+	}
+
+	if err := d.Press(">", "backspace", "backspace", "enter"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d.Settle()
+	if frame := d.Frame(); !strings.Contains(frame, "docs") {
+		t.Errorf("expected all directories once the filter is cleared, got:\n%s", frame)
+	}
+}
+
+func TestNewModel_InvalidSizeFilter(t *testing.T) {
+	if _, err := NewDriver(newTestApp(t), makeRenderTree(t), Options{LargerThan: "1X"}, 80, 20); err == nil {
+		t.Error("expected error for an invalid size filter, got nil")
+	}
+}
@@ -0,0 +1,166 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kaczmarekdaniel/folder-search/internal/dirsearch"
+)
+
+const skippedPanelHelpText = "↑/↓ scroll • pgup/pgdown page • E/esc close"
+
+// skippedList holds the entries a scan skipped because they could not be
+// read. Walking a large tree may skip thousands of them, e.g. system
+// directories without read permission, so the views only show their count
+// and reasons until the panel listing every path is opened.
+type skippedList struct {
+	entries []dirsearch.PathError
+	cursor  int // Highlighted entry in the panel
+	offset  int // First visible entry in the panel
+}
+
+// newSkippedList returns the list of the given skipped entries.
+func newSkippedList(entries []dirsearch.PathError) skippedList {
+	return skippedList{entries: entries}
+}
+
+// skipReason names the reason an entry was skipped in a few words.
+func skipReason(err error) string {
+	switch {
+	case errors.Is(err, fs.ErrPermission):
+		return "permission denied"
+	case errors.Is(err, fs.ErrNotExist):
+		return "vanished"
+	default:
+		return "unreadable"
+	}
+}
+
+// summary describes the skipped entries in one line, counting them by
+// reason, e.g. "1200 skipped: 1198 permission denied, 2 vanished". It is
+// empty if nothing was skipped.
+func (s skippedList) summary() string {
+	if len(s.entries) == 0 {
+		return ""
+	}
+
+	var reasons []string
+	counts := make(map[string]int)
+	for _, e := range s.entries {
+		reason := skipReason(e.Err)
+		if counts[reason] == 0 {
+			reasons = append(reasons, reason)
+		}
+		counts[reason]++
+	}
+	if len(reasons) == 1 {
+		return fmt.Sprintf("%d skipped (%s)", len(s.entries), reasons[0])
+	}
+	parts := make([]string, len(reasons))
+	for i, reason := range reasons {
+		parts[i] = fmt.Sprintf("%d %s", counts[reason], reason)
+	}
+	return fmt.Sprintf("%d skipped: %s", len(s.entries), strings.Join(parts, ", "))
+}
+
+// move moves the cursor by delta entries and scrolls it into a window of
+// height rows.
+func (s *skippedList) move(delta, height int) {
+	s.cursor = max(min(s.cursor+delta, len(s.entries)-1), 0)
+	if s.cursor < s.offset {
+		s.offset = s.cursor
+	}
+	if s.cursor >= s.offset+height {
+		s.offset = s.cursor - height + 1
+	}
+}
+
+// update handles the scrolling keys of the panel, showing height rows.
+// Other keys are ignored.
+func (s *skippedList) update(key string, height int) {
+	switch key {
+	case "up", "k":
+		s.move(-1, height)
+	case "down", "j":
+		s.move(1, height)
+	case "pgup":
+		s.move(-height, height)
+	case "pgdown":
+		s.move(height, height)
+	case "home", "g":
+		s.move(-len(s.entries), height)
+	case "end", "G":
+		s.move(len(s.entries), height)
+	}
+}
+
+// view renders the panel listing the skipped entries below title, height
+// rows at a time.
+func (s skippedList) view(title string, height int) string {
+	var b strings.Builder
+	b.WriteString(panelTitleStyle.Render(title))
+	b.WriteString("\n\n")
+
+	if len(s.entries) == 0 {
+		b.WriteString(itemStyle.Render("Nothing was skipped"))
+		b.WriteString("\n")
+	}
+
+	end := min(s.offset+height, len(s.entries))
+	for i := s.offset; i < end; i++ {
+		e := s.entries[i]
+		line := fmt.Sprintf("%s %s", e.Path, dimStyle.Render(e.Err.Error()))
+		if i == s.cursor {
+			b.WriteString(selectedItemStyle.Render("> " + line))
+		} else {
+			b.WriteString(itemStyle.Render(line))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	status := s.summary()
+	if len(s.entries) > height {
+		status += fmt.Sprintf(" • %d-%d shown", s.offset+1, end)
+	}
+	b.WriteString(statusStyle.Render(status))
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render(skippedPanelHelpText))
+	return b.String()
+}
+
+// openSkippedPanel opens the panel listing the entries the latest scan
+// skipped.
+func (m model) openSkippedPanel() (tea.Model, tea.Cmd) {
+	if len(m.skipped.entries) == 0 {
+		m.status = "no entries were skipped"
+		return m, nil
+	}
+	m.skipped.cursor, m.skipped.offset = 0, 0
+	m.showSkipped = true
+	return m, nil
+}
+
+// updateSkippedPanel handles key presses while the skipped entries panel
+// is open.
+func (m model) updateSkippedPanel(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch key := msg.String(); key {
+	case "E", "esc":
+		m.showSkipped = false
+	default:
+		m.skipped.update(key, m.panelHeight())
+	}
+	return m, nil
+}
+
+// panelHeight returns the number of rows available to the list of a panel.
+func (m model) panelHeight() int {
+	height := m.terminalHeight
+	if height == 0 {
+		_, height = terminalSize()
+	}
+	return max(height-reportChromeHeight, 1)
+}
@@ -0,0 +1,108 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/kaczmarekdaniel/folder-search/internal/dirsearch"
+)
+
+// deniedEntries returns n skipped entries without read permission.
+func deniedEntries(n int) []dirsearch.PathError {
+	entries := make([]dirsearch.PathError, n)
+	for i := range entries {
+		entries[i] = dirsearch.PathError{Path: fmt.Sprintf("locked-%04d", i), Err: fs.ErrPermission}
+	}
+	return entries
+}
+
+func TestSkippedList_Summary(t *testing.T) {
+	if summary := newSkippedList(nil).summary(); summary != "" {
+		t.Errorf("expected empty summary, got %q", summary)
+	}
+
+	s := newSkippedList(deniedEntries(1200))
+	if expected := "1200 skipped (permission denied)"; s.summary() != expected {
+		t.Errorf("expected %q, got %q", expected, s.summary())
+	}
+
+	s.entries = append(s.entries,
+		dirsearch.PathError{Path: "gone", Err: fs.ErrNotExist},
+		dirsearch.PathError{Path: "broken", Err: errors.New("input/output error")})
+	if expected := "1202 skipped: 1200 permission denied, 1 vanished, 1 unreadable"; s.summary() != expected {
+		t.Errorf("expected %q, got %q", expected, s.summary())
+	}
+}
+
+func TestSkippedList_Scroll(t *testing.T) {
+	s := newSkippedList(deniedEntries(100))
+	const height = 10
+
+	s.update("pgdown", height)
+	s.update("down", height)
+	if s.cursor != 11 || s.offset != 2 {
+		t.Errorf("expected cursor 11 at offset 2, got %d at offset %d", s.cursor, s.offset)
+	}
+	s.update("end", height)
+	if s.cursor != 99 || s.offset != 90 {
+		t.Errorf("expected cursor 99 at offset 90, got %d at offset %d", s.cursor, s.offset)
+	}
+	s.update("up", height)
+	s.update("home", height)
+	if s.cursor != 0 || s.offset != 0 {
+		t.Errorf("expected cursor 0 at offset 0, got %d at offset %d", s.cursor, s.offset)
+	}
+
+	view := s.view("Skipped", height)
+	if !strings.Contains(view, "locked-0009") || strings.Contains(view, "locked-0010") {
+		t.Errorf("expected the first %d entries in view, got:\n%s", height, view)
+	}
+	if !strings.Contains(view, "1-10 shown") {
+		t.Errorf("expected the shown range in view, got:\n%s", view)
+	}
+}
+
+func TestSkippedPanel(t *testing.T) {
+	d, err := NewDriver(newTestApp(t), makeRenderTree(t), Options{}, 80, 20)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer d.Close()
+
+	if err := d.Press("E"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if frame := d.Frame(); !strings.Contains(frame, "no entries were skipped") {
+		t.Errorf("expected a note that nothing was skipped, got:\n%s", frame)
+	}
+
+	// Skipped entries are counted below the list instead of listed
+	m := d.model.(model)
+	m.skipped = newSkippedList(deniedEntries(3000))
+	d.model = m
+	frame := d.Frame()
+	if !strings.Contains(frame, "3000 skipped (permission denied), E to list") {
+		t.Errorf("expected the skipped count in the status line, got:\n%s", frame)
+	}
+	if strings.Contains(frame, "locked-0000") {
+		t.Errorf("expected skipped paths to stay hidden, got:\n%s", frame)
+	}
+
+	if err := d.Press("E", "down"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	frame = d.Frame()
+	if !strings.Contains(frame, "> locked-0001") {
+		t.Errorf("expected the panel with the second entry highlighted, got:\n%s", frame)
+	}
+
+	if err := d.Press("esc"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if frame := d.Frame(); strings.Contains(frame, "locked-0001") {
+		t.Errorf("expected the panel to close, got:\n%s", frame)
+	}
+}
@@ -0,0 +1,141 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kaczmarekdaniel/folder-search/internal/tags"
+)
+
+const (
+	tagEditorHelpText = "enter save • separate tags with commas or spaces • esc cancel"
+	tagFilterHelpText = "enter filter • empty tag shows all directories • esc cancel"
+)
+
+// startTagEditor opens the prompt for editing the tags of the highlighted directory.
+func (m model) startTagEditor() (tea.Model, tea.Cmd) {
+	i, ok := m.list.SelectedItem().(item)
+	if !ok || m.err != nil || m.pendingDir != "" {
+		return m, nil
+	}
+
+	m.tagTarget = filepath.Join(m.currentDir, string(i))
+	return m.openTagPrompt("Tags: ", "e.g. work, todo", strings.Join(m.tags.Get(m.tagTarget), ", "))
+}
+
+// startTagFilter opens the prompt for choosing the tag the listing is
+// filtered by.
+func (m model) startTagFilter() (tea.Model, tea.Cmd) {
+	if m.err != nil {
+		return m, nil
+	}
+
+	m.tagTarget = ""
+	return m.openTagPrompt("Filter by tag: ", "e.g. work", m.tagFilter)
+}
+
+// openTagPrompt shows the tag prompt with the given label and initial value.
+func (m model) openTagPrompt(prompt, placeholder, value string) (tea.Model, tea.Cmd) {
+	input := textinput.New()
+	input.Prompt = prompt
+	input.Placeholder = placeholder
+	input.SetValue(value)
+	input.CursorEnd()
+	m.tagInput = input
+	m.editingTags = true
+	return m, m.tagInput.Focus()
+}
+
+// updateTagPrompt handles key presses while the tag prompt is open.
+func (m model) updateTagPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		m.editingTags = false
+		return m, nil
+	case "enter":
+		m.editingTags = false
+		if m.tagTarget == "" {
+			return m.setTagFilter(m.tagInput.Value())
+		}
+		return m.saveTags()
+	}
+
+	var cmd tea.Cmd
+	m.tagInput, cmd = m.tagInput.Update(msg)
+	return m, cmd
+}
+
+// saveTags stores the tags typed in the prompt. While a tag filter is
+// active the current directory is rescanned, so a directory that lost the
+// tag disappears from the list.
+func (m model) saveTags() (tea.Model, tea.Cmd) {
+	name := filepath.Base(m.tagTarget)
+	list := tags.Parse(m.tagInput.Value())
+	m.tags.Set(m.tagTarget, list)
+	m.logger.Debug("updated tags", "dir", m.tagTarget, "tags", list)
+	if len(list) == 0 {
+		m.status = fmt.Sprintf("removed tags from '%s'", name)
+	} else {
+		m.status = fmt.Sprintf("tagged '%s' %s", name, formatTags(list))
+	}
+
+	if m.tagFilter == "" {
+		return m, nil
+	}
+	return m.scan(m.currentDir)
+}
+
+// setTagFilter limits the listing to directories carrying the first tag of
+// value, or removes the filter if value holds no tag, and rescans the
+// current directory.
+func (m model) setTagFilter(value string) (tea.Model, tea.Cmd) {
+	m.tagFilter = ""
+	if list := tags.Parse(value); len(list) > 0 {
+		m.tagFilter = list[0]
+	}
+
+	if m.tagFilter == "" {
+		m.status = "showing all directories"
+	} else {
+		m.status = fmt.Sprintf("showing directories tagged %s", formatTags([]string{m.tagFilter}))
+	}
+	return m.scan(m.currentDir)
+}
+
+// filterTagged returns the entries of dir carrying the active tag filter,
+// or all entries if no filter is set.
+func (m model) filterTagged(dir string, names []string) []string {
+	if m.tagFilter == "" {
+		return names
+	}
+
+	filtered := make([]string, 0, len(names))
+	for _, name := range names {
+		if m.tags.Has(filepath.Join(dir, name), m.tagFilter) {
+			filtered = append(filtered, name)
+		}
+	}
+	return filtered
+}
+
+// tagPromptView renders the tag prompt below the list.
+func (m model) tagPromptView() string {
+	help := tagEditorHelpText
+	if m.tagTarget == "" {
+		help = tagFilterHelpText
+	}
+
+	var b strings.Builder
+	b.WriteString(itemStyle.Render(m.tagInput.View()))
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render(help))
+	return b.String()
+}
+
+// formatTags renders tags as "#work #todo".
+func formatTags(list []string) string {
+	return "#" + strings.Join(list, " #")
+}
@@ -0,0 +1,101 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kaczmarekdaniel/folder-search/internal/trash"
+)
+
+const trashPanelHelpText = "↑/↓ select • r restore • T/esc close"
+
+type trashLoadedMsg struct {
+	trash *trash.Trash
+	items []trash.Item
+	err   error
+}
+
+type trashRestoredMsg struct {
+	item trash.Item
+	err  error
+}
+
+// loadTrash reads the contents of the user's trash without blocking the UI.
+func loadTrash() tea.Cmd {
+	return func() tea.Msg {
+		t, err := trash.Home()
+		if err != nil {
+			return trashLoadedMsg{err: err}
+		}
+		items, err := t.List()
+		return trashLoadedMsg{trash: t, items: items, err: err}
+	}
+}
+
+// restoreTrashItem moves a trashed item back to its original location.
+func restoreTrashItem(t *trash.Trash, item trash.Item) tea.Cmd {
+	return func() tea.Msg {
+		return trashRestoredMsg{item: item, err: t.Restore(item)}
+	}
+}
+
+// updateTrashPanel handles key presses while the trash browser is open.
+func (m model) updateTrashPanel(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		if m.trashCursor > 0 {
+			m.trashCursor--
+		}
+	case "down", "j":
+		if m.trashCursor < len(m.trashItems)-1 {
+			m.trashCursor++
+		}
+	case "r":
+		if m.trashCursor < len(m.trashItems) {
+			return m, restoreTrashItem(m.trash, m.trashItems[m.trashCursor])
+		}
+	case "T", "esc":
+		m.showTrash = false
+	}
+	return m, nil
+}
+
+// trashView renders the trash browser.
+func (m model) trashView() string {
+	var b strings.Builder
+	b.WriteString(panelTitleStyle.Render("Trash"))
+	b.WriteString("\n\n")
+
+	if len(m.trashItems) == 0 {
+		b.WriteString(itemStyle.Render("Trash is empty"))
+		b.WriteString("\n")
+	}
+
+	now := time.Now()
+	for i, it := range m.trashItems {
+		name := it.Name
+		if it.IsDir {
+			name += "/"
+		}
+		line := fmt.Sprintf("%s  %s  %s", m.times.FormatTime(it.DeletedAt, now), name,
+			dimStyle.Render("from "+it.OriginalPath))
+		if i == m.trashCursor {
+			b.WriteString(selectedItemStyle.Render("> " + line))
+		} else {
+			b.WriteString(itemStyle.Render(line))
+		}
+		b.WriteString("\n")
+	}
+
+	if m.status != "" {
+		b.WriteString("\n")
+		b.WriteString(statusStyle.Render(m.status))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render(trashPanelHelpText))
+	return b.String()
+}
@@ -11,25 +11,53 @@
 package ui
 
 import (
+	"cmp"
+	"context"
 	"fmt"
 	"io"
+	"io/fs"
 	"log/slog"
 	"math"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/kaczmarekdaniel/folder-search/internal/app"
+	"github.com/kaczmarekdaniel/folder-search/internal/archive"
+	"github.com/kaczmarekdaniel/folder-search/internal/config"
+	"github.com/kaczmarekdaniel/folder-search/internal/dirmeta"
 	"github.com/kaczmarekdaniel/folder-search/internal/dirsearch"
+	"github.com/kaczmarekdaniel/folder-search/internal/features"
+	"github.com/kaczmarekdaniel/folder-search/internal/fileops"
+	"github.com/kaczmarekdaniel/folder-search/internal/gitinfo"
+	"github.com/kaczmarekdaniel/folder-search/internal/ignorefile"
+	"github.com/kaczmarekdaniel/folder-search/internal/jobs"
+	"github.com/kaczmarekdaniel/folder-search/internal/logging"
+	"github.com/kaczmarekdaniel/folder-search/internal/notes"
+	"github.com/kaczmarekdaniel/folder-search/internal/pins"
+	"github.com/kaczmarekdaniel/folder-search/internal/scanhistory"
+	"github.com/kaczmarekdaniel/folder-search/internal/searchhistory"
+	"github.com/kaczmarekdaniel/folder-search/internal/sizefmt"
+	"github.com/kaczmarekdaniel/folder-search/internal/stats"
+	"github.com/kaczmarekdaniel/folder-search/internal/tags"
+	"github.com/kaczmarekdaniel/folder-search/internal/terminal"
+	"github.com/kaczmarekdaniel/folder-search/internal/termux"
+	"github.com/kaczmarekdaniel/folder-search/internal/timefmt"
+	"github.com/kaczmarekdaniel/folder-search/internal/trash"
+	"github.com/kaczmarekdaniel/folder-search/internal/watch"
 )
 
 const (
 	// UI dimension constants
-	defaultListWidth     = 20
 	listHeightPadding    = 8
 	maxListHeight        = 64
 	maxDynamicListHeight = 24
@@ -42,6 +70,12 @@ const (
 	quitTextBottomMargin = 2
 	quitTextLeftMargin   = 4
 	helpBottomPadding    = 1
+
+	// bundleMarker is appended to macOS bundles while they are treated as opaque
+	bundleMarker = "[bundle]"
+
+	// pinMarker is appended to pinned directories
+	pinMarker = "[pinned]"
 )
 
 var (
@@ -51,33 +85,222 @@ var (
 	paginationStyle   = list.DefaultStyles().PaginationStyle.PaddingLeft(itemPaddingLeft)
 	helpStyle         = list.DefaultStyles().HelpStyle.PaddingLeft(itemPaddingLeft).PaddingBottom(helpBottomPadding)
 	quitTextStyle     = lipgloss.NewStyle().Margin(quitTextTopMargin, 0, quitTextBottomMargin, quitTextLeftMargin)
+	statusStyle       = lipgloss.NewStyle().PaddingLeft(itemPaddingLeft).Foreground(lipgloss.Color("241"))
+	panelTitleStyle   = lipgloss.NewStyle().MarginLeft(titleMarginLeft).Bold(true)
+	dimStyle          = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
 )
 
+// actionNames maps main view keys to the action names recorded in the
+// usage statistics. Navigation is recorded as directory visits instead.
+var actionNames = map[string]string{
+	"S":     "size",
+	"F":     "fingerprint",
+	"z":     "archive zip",
+	"Z":     "archive tar.gz",
+	"J":     "jobs panel",
+	"E":     "skipped entries",
+	"T":     "trash",
+	"n":     "new folder",
+	"p":     "permissions",
+	"P":     "pin",
+	"N":     "note",
+	"#":     "tags",
+	"*":     "tag filter",
+	"/":     "filter",
+	">":     "size filter",
+	"y":     "project filter",
+	"&":     "refine",
+	"I":     "ignore",
+	"H":     "hide for session",
+	"U":     "unhide",
+	".":     "toggle hidden",
+	"o":     "toggle sort",
+	"w":     "switch profile",
+	"t":     "new tab",
+	"b":     "toggle bundles",
+	"!":     "bug report",
+	"enter": "select",
+}
+
 // Types
 type item string
 
 type model struct {
-	requestChan chan string
-	resultChan  chan dirsearch.Result
-	doneChan    chan struct{}
+	requestChan chan scanRequest
+	resultChan  chan responseMsg
+	stopScans   context.CancelFunc // Stops the background scanner
 	list        list.Model
 	choice      string
 	quitting    bool
 	search      func(dir string) dirsearch.Result
 	currentDir  string
+	pendingDir  string        // Directory being navigated to, until its scan result arrives
+	scanned     int           // Directories found so far by a progressive scan of pendingDir
+	navSeq      int           // Incremented on every navigation to discard superseded scan requests
+	debounce    time.Duration // Delay before scanning after navigation, so held keys scan only once
 	err         error
 	logger      *slog.Logger
-	dirIndexMap map[string]int // Stores cursor position for each directory
+	dirIndexMap map[string]int        // Stores cursor position for each directory
+	status      string                // One-line feedback shown below the list
+	freeSpace   int64                 // Bytes available on the current filesystem, -1 if unknown
+	ranking     []dirsearch.BoostRule // Reorders listed directories
+	colors      []config.ColorRule    // Colors listed directories
+	pins        *pins.Pins
+	notes       *notes.Notes
+	inlineNotes bool // Shows notes next to directory names
+	tags        *tags.Tags
+	history     *searchhistory.History  // Past filters and selected directories
+	tagFilter   string                  // Only directories carrying this tag are listed; empty lists all
+	query       string                  // Only directories whose names contain this text are listed
+	largerThan  string                  // Size filter as typed, e.g. "1G"; empty lists all
+	minSize     int64                   // Only directories of at least this many bytes are listed
+	projects    dirsearch.ProjectType   // Only roots of projects of these types are listed; zero lists all
+	listedDir   string                  // Directory whose entries the list shows
+	listedDirs  []string                // Entries of listedDir in scan order, before tag filtering and ranking
+	metaFields  dirmeta.Field           // Decorations shown next to directory names
+	meta        map[string]dirmeta.Info // Collected decorations by directory path
+	times       timefmt.Formatter       // Renders modification and deletion times
+	sizes       sizefmt.Units           // Units of the sizes shown
+	stopMeta    context.CancelFunc      // Stops the running metadata collection; nil if none
+	branch      string                  // Git branch of listedDir, empty outside a repository
+	chrome      chrome                  // Title and prompt templates
+	previewCmd  string                  // Shell command previewing the highlighted directory; empty disables the pane
+	previewPath string                  // Directory whose preview is shown
+	previews    map[string]previewMsg   // Cached preview output by directory
+	previewTick time.Duration           // How often the previewed directory is checked for changes
+	notify      config.NotifyConfig     // Command told when long jobs finish
+	watcher     *watch.Watcher          // Reports changes to the current directory; nil if unavailable
+
+	// Layout options
+	height         Height
+	terminalHeight int  // Height of the terminal, zero until known
+	terminalWidth  int  // Width of the terminal, zero until known
+	bottomUp       bool // Draws the list upside down, with the title at the bottom
+	border         bool
+	compact        bool // Always uses the compact layout, not only in narrow terminals
+	ascii          bool // Limits the view to ASCII symbols
+	peekBundles    bool // Allows entering macOS bundles like regular directories
+	showHidden     bool // Lists directories whose names start with a dot
+	recentFirst    bool // Lists recently modified directories first instead of by name
+	stats          *stats.Stats
+	jobs           *jobs.Queue
+	jobInfos       []jobs.Info // Latest snapshot of the job queue
+	jobCursor      int         // Highlighted job in the jobs panel
+	showJobs       bool
+	skipped        skippedList // Entries below the current directory the latest scan could not read
+	showSkipped    bool
+	trash          *trash.Trash
+	trashItems     []trash.Item
+	trashCursor    int // Highlighted item in the trash browser
+	showTrash      bool
+	templates      map[string][]string // Directory templates by project type
+	nameInput      textinput.Model     // Name of the folder being created
+	templateIdx    int                 // Selected template in the new folder prompt
+	creating       bool
+	noteInput      textinput.Model // Note being edited
+	noteTarget     string          // Directory whose note is being edited
+	editingNote    bool
+	tagInput       textinput.Model // Tags or tag filter being edited
+	tagTarget      string          // Directory whose tags are being edited; empty when editing the filter
+	editingTags    bool
+	queryInput     textinput.Model // Name filter being edited
+	queryRecall    recall          // Past filters offered by up and down in the filter prompt
+	editingQuery   bool
+	sizeInput      textinput.Model // Size filter being edited
+	editingSize    bool
+	projectInput   textinput.Model // Project type filter being edited
+	editingProject bool
+	refineInput    textinput.Model // Refinement being typed
+	editingRefine  bool
+	refinements    []string // Patterns narrowing the listing in the order applied, without rescanning
+
+	// Permissions editor state
+	chmodTarget    string
+	chmodPerm      fs.FileMode
+	chmodCursor    int // Highlighted permission bit, row-major over owner/group/other
+	chmodRecursive bool
+	showChmod      bool
+
+	// Profile switcher state
+	profiles      map[string]config.Profile
+	profile       string   // Name of the active profile
+	ignore        []string // Directory names hidden by the active profile
+	defaultIgnore []string // Ignore list of the default profile
+	profileCursor int      // Highlighted profile in the switcher
+	showProfiles  bool
+
+	// Ignore rule builder state
+	configIgnore     []string    // Directory names hidden by the "ignore" setting, in every profile
+	sessionIgnore    []string    // Directory names hidden until the UI exits
+	ignoreCandidates []string    // Patterns offered for the highlighted directory
+	ignorePattern    int         // Selected pattern in the ignore dialog
+	ignoreScope      ignoreScope // Selected scope in the ignore dialog
+	showIgnore       bool
+
+	// saveBugReport writes a bug report with the log records of the
+	// session and returns its path
+	saveBugReport func() (string, error)
 }
 
 type responseMsg struct {
-	result dirsearch.Result
+	id      uint64 // ID of the scan request
+	dir     string
+	result  dirsearch.Result
+	partial bool // Result holds the directories found so far; the scan continues
 }
 
-type itemDelegate struct{}
+// scanIDKey is the log attribute holding the ID of a scan request, which
+// ties together the records of the request, the scan and its result.
+const scanIDKey = "scan"
+
+// lastScanID is the ID of the latest scan request.
+var lastScanID atomic.Uint64
+
+// scanRequest asks the background scanner to list a directory.
+type scanRequest struct {
+	id         uint64 // Identifies the request in log records
+	dir        string
+	ignore     []string              // Directory names hidden by the active profile and added rules
+	pattern    string                // Only names containing this text are listed
+	minSize    int64                 // Only directories of at least this many bytes are listed
+	projects   dirsearch.ProjectType // Only roots of projects of these types are listed
+	showHidden bool                  // Lists directories whose names start with a dot
+	recent     bool                  // Orders directories by modification time, newest first
+}
+
+// scanFunc performs req, optionally reporting the directories found so far
+// through partial before returning the complete result. It stops early when
+// ctx is canceled.
+type scanFunc func(ctx context.Context, req scanRequest, partial func(dirs []string)) dirsearch.Result
+
+// scanRequestMsg fires once the navigation debounce delay has elapsed.
+type scanRequestMsg struct {
+	seq int
+}
+
+type freeSpaceMsg struct {
+	dir  string
+	free int64
+	err  error
+}
+
+type tabOpenedMsg struct {
+	dir string
+	err error
+}
+
+type itemDelegate struct {
+	opaqueBundles bool                       // Marks macOS bundles as single items
+	pinned        func(name string) bool     // Reports pinned entries; nil if none
+	note          func(name string) string   // Returns the note shown inline; nil to hide notes
+	tags          func(name string) []string // Returns the tags of an entry; nil if none
+	meta          func(name string) string   // Returns the decorations of an entry; nil if none are configured
+	color         func(name string) string   // Returns the color of an entry, empty for the default; nil if none
+	prompt        string                     // Marks the highlighted entry; empty for the default
+}
 
 // Helpers
-func (i item) FilterValue() string { return "" }
+func (i item) FilterValue() string { return string(i) }
 
 func stringsToItems(strs []string) []list.Item {
 	items := make([]list.Item, 0, len(strs))
@@ -97,39 +320,185 @@ func (d itemDelegate) Render(w io.Writer, m list.Model, index int, listItem list
 	}
 
 	str := fmt.Sprintf("%d. %s", index+1, i)
+	if d.opaqueBundles && dirsearch.IsBundle(string(i)) {
+		str += " " + bundleMarker
+	}
+	if d.pinned != nil && d.pinned(string(i)) {
+		str += " " + pinMarker
+	}
+	if d.tags != nil {
+		if list := d.tags(string(i)); len(list) > 0 {
+			str += " " + dimStyle.Render(formatTags(list))
+		}
+	}
+	if d.meta != nil {
+		if meta := d.meta(string(i)); meta != "" {
+			str += " " + dimStyle.Render(meta)
+		}
+	}
+	if d.note != nil {
+		if note := d.note(string(i)); note != "" {
+			str += " " + dimStyle.Render("— "+shortNote(note))
+		}
+	}
 	fn := itemStyle.Render
+	if d.color != nil {
+		if c := d.color(string(i)); c != "" {
+			fn = itemStyle.Foreground(lipgloss.Color(c)).Render
+		}
+	}
 	if index == m.Index() {
+		prompt := d.prompt
+		if prompt == "" {
+			prompt = defaultPrompt
+		}
 		fn = func(s ...string) string {
-			return selectedItemStyle.Render("> " + strings.Join(s, " "))
+			return selectedItemStyle.Render(prompt + strings.Join(s, " "))
 		}
 	}
 	fmt.Fprint(w, fn(str))
 }
 
-func scanInBackground(requestChan chan string, resultChan chan dirsearch.Result, doneChan chan struct{}, searchFunc func(dir string) dirsearch.Result) {
+// scanInBackground serves scan requests until ctx is canceled, then closes
+// resultChan. Each scan runs with its own context, canceled as soon as a
+// newer request arrives, so navigating away from a huge directory stops
+// reading it instead of queueing behind it. Scans never overlap because
+// searchFunc owns the shared DirSearch.
+func scanInBackground(ctx context.Context, requestChan chan scanRequest, resultChan chan responseMsg, searchFunc scanFunc) {
+	cancelScan := func() {}
+	scanDone := make(chan struct{})
+	close(scanDone)
+
+	defer func() {
+		cancelScan()
+		<-scanDone
+		close(resultChan)
+	}()
+
 	for {
 		select {
-		case <-doneChan:
-			close(requestChan)
-			close(resultChan)
+		case <-ctx.Done():
 			return
-		case dir := <-requestChan:
-			result := searchFunc(dir)
-			select {
-			case resultChan <- result:
-			case <-doneChan:
-				close(requestChan)
-				close(resultChan)
-				return
-			}
+		case req := <-requestChan:
+			cancelScan()
+			<-scanDone
+			cancelScan, scanDone = startScan(ctx, req, resultChan, searchFunc)
 		}
 	}
 }
 
-func waitForResults(resultChan chan dirsearch.Result) tea.Cmd {
+// startScan runs req in a new goroutine.
+//
+// Returns a function canceling the scan and a channel closed once it ended.
+func startScan(ctx context.Context, req scanRequest, resultChan chan responseMsg, searchFunc scanFunc) (context.CancelFunc, chan struct{}) {
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		runScan(ctx, req, resultChan, searchFunc)
+	}()
+	return cancel, done
+}
+
+// runScan performs one scan request and sends its result, unless the scan
+// was canceled in the meantime.
+func runScan(ctx context.Context, req scanRequest, resultChan chan responseMsg, searchFunc scanFunc) {
+	dir := req.dir
+	ctx = logging.NewContext(ctx, scanIDKey, req.id)
+	result := searchFunc(ctx, req, func(dirs []string) {
+		// Partial results are cumulative, so a batch the UI is not
+		// ready to receive can be dropped without losing anything.
+		// Blocking here could deadlock with a new scan request.
+		select {
+		case resultChan <- responseMsg{id: req.id, dir: dir, result: dirsearch.Result{Directories: dirs}, partial: true}:
+		default:
+		}
+	})
+	if ctx.Err() != nil {
+		// Superseded by a newer request or the UI is shutting down
+		return
+	}
+
+	select {
+	case resultChan <- responseMsg{id: req.id, dir: dir, result: result}:
+	case <-ctx.Done():
+	}
+}
+
+// scanOptions returns a copy of base adjusted to perform req, with the
+// ignore file of the project containing the directory applied on top of the
+// requested ignore list.
+func scanOptions(ctx context.Context, base *dirsearch.Options, req scanRequest, logger *slog.Logger) *dirsearch.Options {
+	project, err := ignorefile.Load(req.dir)
+	if err != nil {
+		logger.WarnContext(ctx, "ignoring unreadable project ignore file", "dir", req.dir, "error", err)
+	}
+	opts := base.Clone()
+	opts.IgnorePatterns = slices.Concat(req.ignore, project)
+	opts.SearchPattern = req.pattern
+	opts.Hidden = dirsearch.HiddenHide
+	if req.showHidden {
+		opts.Hidden = dirsearch.HiddenShow
+	}
+	opts.MinSize = req.minSize
+	opts.ProjectTypes = req.projects
+	opts.SortBy, opts.SortOrder = dirsearch.SortDefault, dirsearch.Ascending
+	if req.recent {
+		opts.SortBy, opts.SortOrder = dirsearch.SortModTime, dirsearch.Descending
+	}
+	return opts
+}
+
+// ignoreList returns the directory names hidden by a scan: those of the
+// active profile, of the "ignore" setting and of the session.
+func ignoreList(profile, configured, session []string) []string {
+	return slices.Concat(profile, configured, session)
+}
+
+// adaptiveScan returns a scanFunc that picks a strategy from the scan history.
+// Directories that were slow to scan before are read in batches with partial
+// results reported after each batch; all others are scanned in one go. Every
+// scan is timed and recorded so the strategy adapts as directories change,
+// and its duration is added to the usage statistics. The ignore file of the
+// project containing the directory applies on top of the requested ignore
+// list.
+//
+// Each scan uses its own copy of the options of ds, which are only read.
+func adaptiveScan(ds *dirsearch.DirSearch, history *scanhistory.History, usage *stats.Stats, logger *slog.Logger) scanFunc {
+	return func(ctx context.Context, req scanRequest, partial func(dirs []string)) dirsearch.Result {
+		dir := req.dir
+		opts := scanOptions(ctx, ds.Options, req, logger)
+		scanner := ds.WithOptions(opts)
+
+		start := time.Now()
+		var result dirsearch.Result
+		// Fuzzy results are ordered by score and recent ones by
+		// modification time, which are only known once the whole
+		// directory has been read
+		ordered := (opts.Fuzzy && req.pattern != "") || req.recent
+		if history.IsSlow(dir) && !ordered {
+			found := []string{}
+			result = scanner.ScanDirsStream(ctx, dir, dirsearch.DefaultBatchSize, func(dirs []string) {
+				found = append(found, dirs...)
+				partial(slices.Clone(found))
+			})
+		} else {
+			result = scanner.ScanDirsContext(ctx, dir)
+		}
+		// Scans measuring directory sizes or looking for project
+		// markers say nothing about how long listing the directory takes
+		if result.Error == nil && req.minSize == 0 && req.projects == 0 {
+			elapsed := time.Since(start)
+			history.Record(dir, elapsed)
+			usage.RecordScan(elapsed)
+		}
+		return result
+	}
+}
+
+func waitForResults(resultChan chan responseMsg) tea.Cmd {
 	return func() tea.Msg {
-		result := <-resultChan
-		return responseMsg{result: result}
+		return <-resultChan
 	}
 }
 
@@ -157,9 +526,149 @@ func checkDirPermission(dir string) error {
 	return nil
 }
 
+// openTab opens a new tab of the hosting terminal in dir without blocking the UI.
+func openTab(dir string) tea.Cmd {
+	return func() tea.Msg {
+		return tabOpenedMsg{dir: dir, err: terminal.OpenTab(dir)}
+	}
+}
+
+// showDirs fills the list with the subdirectories of dir, ordered by the
+// ranking rules with pinned directories first. Directories without the
+// active tag filter are left out.
+func (m *model) showDirs(dir string, dirs []string) {
+	if dir != m.listedDir {
+		m.cancelMeta()
+		m.refinements = nil
+	}
+	m.listedDir = dir
+	m.listedDirs = dirs
+	if branch, err := gitinfo.Branch(dir); err == nil {
+		m.branch = branch
+	} else {
+		m.logger.Debug("cannot read git branch", "dir", dir, "error", err)
+		m.branch = ""
+	}
+	dirs = m.refineDirs(m.filterTagged(dir, dirs))
+	m.list.SetItems(stringsToItems(m.pins.Order(dir, dirsearch.Rank(dir, dirs, m.ranking))))
+	m.list.SetDelegate(m.delegate())
+}
+
+// delegate returns the list delegate for the current display settings.
+func (m model) delegate() itemDelegate {
+	p, n, t, dir := m.pins, m.notes, m.tags, m.listedDir
+	d := itemDelegate{
+		opaqueBundles: !m.peekBundles,
+		pinned:        func(name string) bool { return p.IsPinned(filepath.Join(dir, name)) },
+	}
+	d.prompt = render(m.chrome.prompt, m.chromeData(), defaultPrompt)
+	if rules := m.colors; len(rules) > 0 {
+		d.color = func(name string) string { return ruleColor(rules, filepath.Join(dir, name)) }
+	}
+	if m.compactLayout() {
+		// Leave the little width there is to the names
+		return d
+	}
+	d.tags = func(name string) []string { return t.Get(filepath.Join(dir, name)) }
+	d.meta = m.metaDecoration(dir)
+	if m.inlineNotes {
+		d.note = func(name string) string { return n.Get(filepath.Join(dir, name)) }
+	}
+	return d
+}
+
+// togglePin pins or unpins the highlighted directory and reorders the list,
+// keeping the cursor on the same directory.
+func (m model) togglePin() (tea.Model, tea.Cmd) {
+	i, ok := m.list.SelectedItem().(item)
+	if !ok || m.err != nil || m.pendingDir != "" {
+		return m, nil
+	}
+
+	dir := filepath.Join(m.currentDir, string(i))
+	if m.pins.Toggle(dir) {
+		m.status = fmt.Sprintf("pinned '%s'", string(i))
+	} else {
+		m.status = fmt.Sprintf("unpinned '%s'", string(i))
+	}
+
+	m.showDirs(m.currentDir, m.listedDirs)
+	for idx, it := range m.list.Items() {
+		if it.(item) == i {
+			m.list.Select(idx)
+			break
+		}
+	}
+	return m, nil
+}
+
+// checkFreeSpace queries the free space of the filesystem containing dir
+// without blocking the UI.
+func checkFreeSpace(dir string) tea.Cmd {
+	return func() tea.Msg {
+		free, err := fileops.FreeSpace(dir)
+		return freeSpaceMsg{dir: dir, free: free, err: err}
+	}
+}
+
+// scan requests a background scan of dir, which becomes the current
+// directory once the result arrives.
+func (m model) scan(dir string) (model, tea.Cmd) {
+	m.pendingDir = dir
+	m.requestScan(dir)
+	return m, waitForResults(m.resultChan)
+}
+
+// requestScan sends the request for listing dir with the current filters to
+// the background scanner.
+func (m model) requestScan(dir string) {
+	req := scanRequest{
+		id:         lastScanID.Add(1),
+		dir:        dir,
+		ignore:     ignoreList(m.ignore, m.configIgnore, m.sessionIgnore),
+		pattern:    m.query,
+		minSize:    m.minSize,
+		projects:   m.projects,
+		showHidden: m.showHidden,
+		recent:     m.recentFirst,
+	}
+	m.logger.Debug("scan requested", scanIDKey, req.id, "dir", dir, "pattern", req.pattern)
+	m.requestChan <- req
+}
+
+// navigate moves towards dir. The scan is delayed by the debounce interval
+// and only issued if no further navigation happened in the meantime, so
+// holding an arrow key scans just the final directory.
+func (m model) navigate(dir string) (model, tea.Cmd) {
+	// Remember the cursor of the directory we are leaving, unless we are
+	// passing through a directory that was never displayed
+	if m.pendingDir == "" {
+		m.dirIndexMap[m.currentDir] = m.list.Index()
+	}
+
+	m.navSeq++
+	m.pendingDir = dir
+	m.err = nil
+	if m.debounce <= 0 {
+		return m.scan(dir)
+	}
+
+	seq := m.navSeq
+	return m, tea.Tick(m.debounce, func(time.Time) tea.Msg {
+		return scanRequestMsg{seq: seq}
+	})
+}
+
 func (m model) Init() tea.Cmd {
-	m.requestChan <- m.currentDir
-	return waitForResults(m.resultChan)
+	m.requestScan(m.currentDir)
+	cmds := []tea.Cmd{waitForResults(m.resultChan), waitForJobUpdates(m.jobs)}
+	if m.watcher != nil {
+		cmds = append(cmds, waitForChanges(m.watcher))
+	}
+	if m.previewCmd != "" {
+		cmds = append(cmds, watchPreview(m.previewTick))
+	}
+	return tea.Batch(cmds...)
 }
 
 // Update handles different types of events around the list and returns an updated model and command.
@@ -170,28 +679,134 @@ func (m model) Init() tea.Cmd {
 //   - right: enter the higlighted folder
 //   - left: go to parent folder
 //   - enter: select the current item and quit
+//   - t: open the highlighted folder in a new terminal tab
+//   - b: toggle peeking inside macOS bundles (.app, .framework)
+//   - S: calculate the size of the highlighted folder in the background
+//   - z/Z: compress the highlighted folder into .zip/.tar.gz in the background
+//   - F: compute a content fingerprint of the highlighted folder
+//   - J: toggle the background jobs panel
+//   - T: browse the trash and restore items
+//   - n: create a new folder, optionally scaffolded from a template
+//   - p: edit permissions of the highlighted folder
+//   - N: edit the note of the highlighted folder
+//   - #: edit the tags of the highlighted folder
+//   - *: filter the listing by tag
+//   - /: filter the listing by name
+//   - >: only list folders larger than a size
+//   - y: only list git repositories, Go modules or other project roots
+//   - &: narrow the listed directories without rescanning; backspace undoes
+//   - I: add an ignore rule for the highlighted folder
+//   - H/U: hide the highlighted folder for this session / undo the last hide
+//   - .: show or hide hidden directories
+//   - o: list recently modified directories first, or by name again
 //
 // Response messages trigger addition of new items to the list.
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
-		m.list.SetWidth(msg.Width)
+		msg = knownSize(msg)
+		m.terminalHeight = msg.Height
+		m.terminalWidth = msg.Width
+		if m.border {
+			m.list.SetWidth(msg.Width - borderSize)
+		} else {
+			m.list.SetWidth(msg.Width)
+		}
+		// The width decides whether the compact layout is used
+		m.list.SetDelegate(m.delegate())
+		m.fitList(len(m.list.Items()))
 		return m, nil
 	case tea.KeyMsg:
+		m.status = ""
+		if m.creating {
+			return m.updateNewDirPrompt(msg)
+		}
+		if m.editingNote {
+			return m.updateNoteEditor(msg)
+		}
+		if m.editingTags {
+			return m.updateTagPrompt(msg)
+		}
+		if m.editingQuery {
+			return m.updateQueryPrompt(msg)
+		}
+		if m.editingSize {
+			return m.updateSizeFilter(msg)
+		}
+		if m.editingProject {
+			return m.updateProjectFilter(msg)
+		}
+		if m.editingRefine {
+			return m.updateRefinePrompt(msg)
+		}
 		switch keypress := msg.String(); keypress {
 		case "q", "ctrl+c":
 			m.logger.Info("user quit application")
 			m.quitting = true
-			close(m.doneChan)
+			m.stopScans()
+			m.cancelMeta()
 			return m, tea.Quit
+		}
+		if m.showJobs {
+			return m.updateJobsPanel(msg)
+		}
+		if m.showTrash {
+			return m.updateTrashPanel(msg)
+		}
+		if m.showSkipped {
+			return m.updateSkippedPanel(msg)
+		}
+		if m.showChmod {
+			return m.updateChmodDialog(msg)
+		}
+		if m.showProfiles {
+			return m.updateProfileSwitcher(msg)
+		}
+		if m.showIgnore {
+			return m.updateIgnoreDialog(msg)
+		}
+		if name, ok := actionNames[msg.String()]; ok {
+			m.stats.RecordAction(name)
+		}
+		switch keypress := msg.String(); keypress {
+		case "S":
+			return m.submitSizeJob()
+		case "F":
+			return m.submitFingerprintJob()
+		case "z":
+			return m.submitArchiveJob(archive.Zip)
+		case "Z":
+			return m.submitArchiveJob(archive.TarGz)
+		case "J":
+			m.showJobs = true
+			m.jobCursor = 0
+			return m, nil
+		case "T":
+			return m, loadTrash()
+		case "E":
+			return m.openSkippedPanel()
+		case "!":
+			return m, m.writeBugReport()
+		case "n":
+			if m.err == nil {
+				return m.startNewDirPrompt()
+			}
+		case "p":
+			return m.startChmodDialog()
+		case "w":
+			return m.startProfileSwitcher()
 		case "left":
-			parentDir := filepath.Dir(m.currentDir)
+			fromDir := m.currentDir
+			if m.pendingDir != "" {
+				fromDir = m.pendingDir
+			}
+			parentDir := filepath.Dir(fromDir)
 
 			// Check if we have permission to access the parent directory
 			if err := checkDirPermission(parentDir); err != nil {
 				m.logger.Warn("parent directory access error", "dir", parentDir, "error", err)
 				if os.IsPermission(err) {
-					m.err = fmt.Errorf("permission denied: cannot access parent directory")
+					m.err = termux.StorageError(parentDir, fmt.Errorf("cannot access parent directory: %w", fs.ErrPermission))
 				} else if os.IsNotExist(err) {
 					m.err = fmt.Errorf("parent directory not found")
 				} else {
@@ -200,24 +815,24 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 
-			// Save current index before leaving
-			m.dirIndexMap[m.currentDir] = m.list.Index()
-
-			m.currentDir = parentDir
-			m.logger.Debug("navigating to parent directory", "dir", m.currentDir)
-			m.err = nil
-			m.requestChan <- m.currentDir
-			return m, waitForResults(m.resultChan)
+			m.logger.Debug("navigating to parent directory", "dir", parentDir)
+			return m.navigate(parentDir)
 		case "right":
-			if m.err == nil {
+			// The list still shows the directory we are leaving until the
+			// pending scan completes, so its selection is not meaningful
+			if m.err == nil && m.pendingDir == "" {
 				i, _ := m.list.SelectedItem().(item)
+				if !m.peekBundles && dirsearch.IsBundle(string(i)) {
+					m.status = fmt.Sprintf("'%s' is a bundle, press b to peek inside", string(i))
+					return m, nil
+				}
 				targetDir := filepath.Join(m.currentDir, string(i))
 
 				// Check if we have permission to access the target directory
 				if err := checkDirPermission(targetDir); err != nil {
 					m.logger.Warn("directory access error", "dir", targetDir, "error", err)
 					if os.IsPermission(err) {
-						m.err = fmt.Errorf("permission denied: cannot access '%s'", string(i))
+						m.err = termux.StorageError(targetDir, fmt.Errorf("cannot access '%s': %w", string(i), fs.ErrPermission))
 					} else if os.IsNotExist(err) {
 						m.err = fmt.Errorf("directory not found: '%s'", string(i))
 					} else {
@@ -226,54 +841,269 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return m, nil
 				}
 
-				// Save current index before leaving
-				m.dirIndexMap[m.currentDir] = m.list.Index()
-
-				m.currentDir = targetDir
-				m.logger.Debug("navigating into directory", "dir", m.currentDir)
-				m.requestChan <- m.currentDir
-				return m, waitForResults(m.resultChan)
+				m.logger.Debug("navigating into directory", "dir", targetDir)
+				return m.navigate(targetDir)
+			}
+		case "P":
+			return m.togglePin()
+		case "N":
+			return m.startNoteEditor()
+		case "#":
+			return m.startTagEditor()
+		case "*":
+			return m.startTagFilter()
+		case "/":
+			return m.startQueryPrompt()
+		case ">":
+			return m.startSizeFilter()
+		case "y":
+			return m.startProjectFilter()
+		case "&":
+			return m.startRefinePrompt()
+		case "I":
+			return m.startIgnoreDialog()
+		case "H":
+			return m.hideForSession()
+		case "U":
+			return m.unhideLast()
+		case ".":
+			m.showHidden = !m.showHidden
+			if m.showHidden {
+				m.status = "showing hidden directories"
+			} else {
+				m.status = "hiding hidden directories"
 			}
+			return m.scan(m.currentDir)
+		case "o":
+			m.recentFirst = !m.recentFirst
+			if m.recentFirst {
+				m.status = "recently modified first"
+			} else {
+				m.status = "sorted by name"
+			}
+			return m.scan(m.currentDir)
+		case "backspace":
+			return m.popRefinement()
+		case "t":
+			i, ok := m.list.SelectedItem().(item)
+			if m.err == nil && ok {
+				targetDir := filepath.Join(m.currentDir, string(i))
+				m.logger.Debug("opening directory in new terminal tab", "dir", targetDir)
+				return m, openTab(targetDir)
+			}
+		case "b":
+			m.peekBundles = !m.peekBundles
+			m.list.SetDelegate(m.delegate())
+			if m.peekBundles {
+				m.status = "bundles can be entered like directories"
+			} else {
+				m.status = "bundles are shown as single items"
+			}
+			return m, nil
 		case "enter":
 			i, ok := m.list.SelectedItem().(item)
-			if ok {
+			if ok && m.err == nil {
 				m.choice = string(i)
+				m.history.AddSelection(m.selection(), m.query)
 			}
-			close(m.doneChan)
+			m.stopScans()
+			m.cancelMeta()
 			return m, tea.Quit
 		}
+	case scanRequestMsg:
+		if msg.seq != m.navSeq {
+			return m, nil
+		}
+		return m.scan(m.pendingDir)
 	case responseMsg:
+		// Partial results are followed by more messages for the same scan
+		var cmd tea.Cmd
+		if msg.partial {
+			cmd = waitForResults(m.resultChan)
+		}
+
+		// Ignore results for directories we have navigated away from
+		if msg.dir != m.pendingDir {
+			m.logger.Debug("discarding stale scan result", scanIDKey, msg.id, "dir", msg.dir)
+			return m, cmd
+		}
+
+		if msg.partial {
+			m.scanned = len(msg.result.Directories)
+			m.err = nil
+			m.showDirs(msg.dir, msg.result.Directories)
+			m.fitList(m.scanned)
+			m.logger.Debug("showing partial scan result", scanIDKey, msg.id, "dir", msg.dir, "count", m.scanned)
+			return m, cmd
+		}
+
+		m.currentDir = msg.dir
+		m.pendingDir = ""
+		m.scanned = 0
+		m.skipped = newSkippedList(msg.result.Warnings)
+
 		result := msg.result
+		var metaCmd tea.Cmd
 		if result.Error != nil {
-			m.logger.Error("directory scan failed", "error", result.Error, "dir", m.currentDir)
-			m.err = result.Error
+			m.logger.Error("directory scan failed", scanIDKey, msg.id, "error", result.Error, "dir", m.currentDir)
+			m.err = termux.StorageError(m.currentDir, result.Error)
+			m.cancelMeta()
 		} else {
-			m.logger.Debug("directory scan completed", "dir", m.currentDir, "count", len(result.Directories))
+			m.logger.Debug("directory scan completed", scanIDKey, msg.id, "dir", m.currentDir, "count", len(result.Directories))
+			m.stats.RecordVisit(m.currentDir)
 			m.err = nil
-			m.list.SetItems(stringsToItems(result.Directories))
-			height := int(math.Min(float64(len(result.Directories)+listHeightPadding), maxDynamicListHeight))
-			m.list.SetHeight(height)
+			m.showDirs(m.currentDir, result.Directories)
+			m.fitList(len(result.Directories))
 
 			// Restore cursor position if we have a saved index for this directory
 			if savedIndex, exists := m.dirIndexMap[m.currentDir]; exists && savedIndex < len(result.Directories) {
 				m.list.Select(savedIndex)
-				m.logger.Debug("restored cursor position", "dir", m.currentDir, "index", savedIndex)
+				m.logger.Debug("restored cursor position", scanIDKey, msg.id, "dir", m.currentDir, "index", savedIndex)
 			} else {
 				// Default to first item
 				m.list.Select(0)
-				m.logger.Debug("reset cursor to first item", "dir", m.currentDir)
+				m.logger.Debug("reset cursor to first item", scanIDKey, msg.id, "dir", m.currentDir)
 			}
+			metaCmd = m.collectMeta(m.currentDir)
+			m.watchDir(m.currentDir)
+		}
+		previewCmd := m.requestPreview()
+		return m, tea.Batch(checkFreeSpace(m.currentDir), previewCmd, metaCmd)
+	case previewMsg:
+		m.storePreview(msg)
+		return m, nil
+	case previewTickMsg:
+		return m, tea.Batch(m.checkPreview(), watchPreview(m.previewTick))
+	case previewChangedMsg:
+		return m, m.refreshPreview(msg)
+	case dirChangedMsg:
+		return m.refreshChanged(msg)
+	case metaMsg:
+		cmd := m.storeMeta(msg)
+		return m, cmd
+	case freeSpaceMsg:
+		// A slow query may finish after navigating elsewhere
+		if msg.dir != m.currentDir {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.logger.Debug("free space unavailable", "dir", msg.dir, "error", msg.err)
+			m.freeSpace = -1
+		} else {
+			m.freeSpace = msg.free
+		}
+		return m, nil
+	case jobsUpdatedMsg:
+		infos := m.jobs.Snapshot()
+		cmds := []tea.Cmd{waitForJobUpdates(m.jobs)}
+		for _, info := range infos {
+			if info.Status.Finished() && !jobFinished(m.jobInfos, info.ID) {
+				m.status = fmt.Sprintf("%s: %s", info.Name, jobSummary(info))
+				cmds = append(cmds, m.notifyJob(info))
+			}
+		}
+		m.jobInfos = infos
+		if m.jobCursor >= len(infos) {
+			m.jobCursor = max(len(infos)-1, 0)
+		}
+		return m, tea.Batch(cmds...)
+	case notifyFailedMsg:
+		m.logger.Warn("notify command failed", "job", msg.job, "error", msg.err)
+		return m, nil
+	case trashLoadedMsg:
+		if msg.err != nil {
+			m.logger.Warn("failed to read trash", "error", msg.err)
+			m.status = fmt.Sprintf("cannot open trash: %v", msg.err)
+			return m, nil
+		}
+		m.trash = msg.trash
+		m.trashItems = msg.items
+		if !m.showTrash || m.trashCursor >= len(msg.items) {
+			m.trashCursor = 0
+		}
+		m.showTrash = true
+		return m, nil
+	case trashRestoredMsg:
+		if msg.err != nil {
+			m.logger.Warn("failed to restore from trash", "item", msg.item.Name, "error", msg.err)
+			m.status = msg.err.Error()
+			return m, nil
+		}
+		m.logger.Info("restored from trash", "path", msg.item.OriginalPath)
+		m.status = fmt.Sprintf("restored %s", msg.item.OriginalPath)
+		// Reload the trash and rescan in case the item returned to the current directory
+		m, cmd := m.scan(m.currentDir)
+		return m, tea.Batch(loadTrash(), cmd)
+	case chmodDoneMsg:
+		if msg.err != nil {
+			m.logger.Warn("failed to change permissions", "path", msg.path, "error", msg.err)
+			m.status = fmt.Sprintf("cannot change permissions: %v", msg.err)
+		} else {
+			m.logger.Info("changed permissions", "path", msg.path, "mode", msg.perm)
+			m.status = fmt.Sprintf("%s is now %s", filepath.Base(msg.path), msg.perm)
+		}
+		return m, nil
+	case bugReportMsg:
+		return m.showBugReport(msg)
+	case tabOpenedMsg:
+		if msg.err != nil {
+			m.logger.Warn("failed to open terminal tab", "dir", msg.dir, "error", msg.err)
+			m.status = fmt.Sprintf("cannot open tab: %v", msg.err)
+		} else {
+			m.status = fmt.Sprintf("opened %s in a new tab", filepath.Base(msg.dir))
 		}
 		return m, nil
 	}
 
+	if m.creating {
+		var cmd tea.Cmd
+		m.nameInput, cmd = m.nameInput.Update(msg)
+		return m, cmd
+	}
+	if m.editingNote {
+		var cmd tea.Cmd
+		m.noteInput, cmd = m.noteInput.Update(msg)
+		return m, cmd
+	}
+	if m.editingTags {
+		var cmd tea.Cmd
+		m.tagInput, cmd = m.tagInput.Update(msg)
+		return m, cmd
+	}
+	if m.editingQuery {
+		var cmd tea.Cmd
+		m.queryInput, cmd = m.queryInput.Update(msg)
+		return m, cmd
+	}
+	if m.editingSize {
+		var cmd tea.Cmd
+		m.sizeInput, cmd = m.sizeInput.Update(msg)
+		return m, cmd
+	}
+	if m.editingProject {
+		var cmd tea.Cmd
+		m.projectInput, cmd = m.projectInput.Update(msg)
+		return m, cmd
+	}
+	if m.editingRefine {
+		var cmd tea.Cmd
+		m.refineInput, cmd = m.refineInput.Update(msg)
+		return m, cmd
+	}
+
 	var cmd tea.Cmd
 	m.list, cmd = m.list.Update(msg)
-	return m, cmd
+	previewCmd := m.requestPreview()
+	return m, tea.Batch(cmd, previewCmd)
 }
 
-func (m model) View() string {
-	m.list.Title = m.currentDir
+// screenView renders the list or the open panel.
+func (m model) screenView() string {
+	data := m.chromeData()
+	m.list.Title = render(m.chrome.title, data, data.Path)
+	if m.pendingDir != "" && m.scanned > 0 {
+		m.list.Title += fmt.Sprintf(" (scanning… %d directories)", m.scanned)
+	}
 
 	if m.choice != "" {
 		return quitTextStyle.Render(fmt.Sprintf("%s? navigating to %s", m.choice, m.choice))
@@ -282,8 +1112,28 @@ func (m model) View() string {
 		return quitTextStyle.Render("See ya later, aligator")
 	}
 
+	if m.showJobs {
+		return m.jobsView()
+	}
+	if m.showTrash {
+		return m.trashView()
+	}
+	if m.showSkipped {
+		return m.skipped.view("Skipped below "+m.currentDir, m.panelHeight())
+	}
+	if m.showChmod {
+		return m.chmodView()
+	}
+	if m.showProfiles {
+		return m.profilesView()
+	}
+
 	if m.err != nil {
 		errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Margin(1, 2)
+		if m.terminalWidth > 4 {
+			// Wrap long messages, such as storage hints, on small screens
+			errorStyle = errorStyle.Width(m.terminalWidth - 4)
+		}
 		errorMsg := fmt.Sprintf("Error: %v\n\nPress ← to go back or q to quit", m.err)
 		return errorStyle.Render(errorMsg)
 	}
@@ -303,11 +1153,140 @@ func (m model) View() string {
 		key.WithHelp("→/l", "enter dir"),
 	)
 
+	tab := key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "new tab"),
+	)
+
 	m.list.AdditionalShortHelpKeys = func() []key.Binding {
-		return []key.Binding{left, right, enter}
+		return []key.Binding{left, right, enter, tab}
+	}
+
+	if m.creating {
+		m.list.SetShowHelp(false)
+		return m.stack(m.list.View(), m.newDirView())
+	}
+	if m.editingNote {
+		m.list.SetShowHelp(false)
+		return m.stack(m.list.View(), m.noteEditorView())
+	}
+	if m.editingTags {
+		m.list.SetShowHelp(false)
+		return m.stack(m.list.View(), m.tagPromptView())
+	}
+	if m.editingQuery {
+		m.list.SetShowHelp(false)
+		return m.stack(m.list.View(), m.queryPromptView())
+	}
+	if m.editingSize {
+		m.list.SetShowHelp(false)
+		return m.stack(m.list.View(), m.sizeFilterView())
+	}
+	if m.editingProject {
+		m.list.SetShowHelp(false)
+		return m.stack(m.list.View(), m.projectFilterView())
+	}
+	if m.editingRefine {
+		m.list.SetShowHelp(false)
+		return m.stack(m.list.View(), m.refinePromptView())
+	}
+	if m.showIgnore {
+		m.list.SetShowHelp(false)
+		return m.stack(m.list.View(), m.ignoreDialogView())
 	}
 
-	return m.list.View()
+	if m.compactLayout() {
+		m.list.SetShowHelp(false)
+	}
+	var blocks []string
+	if m.previewCmd != "" && m.pendingDir == "" && len(m.list.Items()) > 0 {
+		blocks = append(blocks, m.previewView())
+	}
+	if line := m.statusLine(); line != "" {
+		blocks = append(blocks, statusStyle.Render(line))
+	}
+	return m.stack(m.list.View(), blocks...)
+}
+
+// statusLine combines the note of the highlighted directory, the free space
+// of the current filesystem, the number of entries the scan skipped and the
+// latest feedback message.
+func (m model) statusLine() string {
+	var parts []string
+	if note := m.selectedNote(); note != "" {
+		parts = append(parts, "note: "+note)
+	}
+	if m.freeSpace >= 0 {
+		parts = append(parts, m.sizes.Format(m.freeSpace)+" free")
+	}
+	if summary := m.skipped.summary(); summary != "" {
+		parts = append(parts, summary+", E to list")
+	}
+	if m.status != "" {
+		parts = append(parts, m.status)
+	}
+	return strings.Join(parts, " • ")
+}
+
+// Options are the initial view settings of InitUI.
+type Options struct {
+	// Tag limits the listing to directories carrying this tag
+	Tag string
+
+	// Query limits the listing to directories whose names contain this
+	// text, ignoring case
+	Query string
+
+	// LargerThan limits the listing to directories whose total size is at
+	// least this size, such as "1G"; see dirsearch.ParseSize
+	LargerThan string
+
+	// Projects limits the listing to the roots of projects of these types
+	Projects dirsearch.ProjectType
+
+	// Preview is a shell command whose output is shown for the highlighted
+	// directory, overriding the configured preview command
+	Preview string
+
+	// Height limits the height of the interface; the zero value sizes the
+	// list to the number of directories
+	Height Height
+
+	// BottomUp draws the list upside down with the title at the bottom,
+	// like fzf's default layout
+	BottomUp bool
+
+	// Border draws a border around the interface
+	Border bool
+
+	// Ignore lists directory names or patterns hidden for this session
+	// only, on top of the configured ignore lists
+	Ignore []string
+
+	// LowPower cuts down background work to save energy: git and size
+	// decorations are not collected and the current and previewed
+	// directories are checked for changes less often
+	LowPower bool
+
+	// Compact always uses the layout of narrow terminals: names without
+	// tags, decorations or notes, no key help and a shorter preview pane
+	Compact bool
+
+	// ASCII swaps arrows, bullets and ellipses for ASCII equivalents, as
+	// is done anyway when the terminal does not use UTF-8
+	ASCII bool
+
+	// Version is the version of folder-search, included in bug reports
+	Version string
+}
+
+// tagFilter returns the normalized tag of the Tag option, or an empty
+// string if none is set.
+func (o Options) tagFilter() string {
+	if list := tags.Parse(o.Tag); len(list) > 0 {
+		return list[0]
+	}
+	return ""
 }
 
 // InitUI initializes and runs the terminal user interface.
@@ -324,28 +1303,124 @@ func (m model) View() string {
 //   - Right or l: Enter selected directory
 //   - Left or h: Go to parent directory
 //   - Enter: Select directory and exit
+//   - t: Open selected directory in a new terminal tab (kitty, WezTerm, iTerm2)
+//   - b: Toggle peeking inside macOS bundles
+//   - S: Calculate the size of the selected directory in the background
+//   - z/Z: Compress the selected directory into .zip/.tar.gz in the background
+//   - F: Fingerprint the selected directory (file count, size, hash of names and sizes)
+//   - J: Show background jobs with progress; x cancels the highlighted job
+//   - E: List the entries the scan skipped because they could not be read
+//   - T: Browse the trash; r restores the highlighted item
+//   - n: Create a new folder from a configurable template
+//   - p: Edit permissions of the selected directory, optionally recursively
+//   - P: Pin or unpin the selected directory at the top of its parent
+//   - N: Attach a note to the selected directory, shown below the list
+//   - #: Edit the tags of the selected directory
+//   - *: Only list directories carrying a tag
+//   - /: Only list directories whose names contain some text
+//   - >: Only list directories larger than a size such as 1G
+//   - y: Only list project roots of some types, e.g. git repositories
+//   - w: Switch between profiles configured in the config file
+//   - !: Save a bug report with the log records of the session
 //   - q or Ctrl+C: Quit application
 //
+// When standard output is not a terminal (e.g. inside $(...)), the interface is
+// rendered on standard error so the caller can capture the selection cleanly.
+//
+// When standard input is not a terminal, keyboard input is read from the
+// controlling terminal instead, so the UI also works at the end of a pipe.
+//
 // Parameters:
 //   - app: The application instance containing the directory searcher and logger
+//   - startDir: The directory shown first
+//   - opts: Initial view settings
 //
-// Returns an error if:
+// Returns the absolute path of the selected directory, or an empty string if
+// the user quit without selecting. Returns an error if:
 //   - Initial directory scan fails
-//   - Current working directory cannot be determined
+//   - The start directory cannot be resolved
 //   - Bubble Tea program encounters an error
-func InitUI(app *app.Application) error {
-	app.Logger.Info("initializing UI")
-	result := app.Dirsearch.ScanDirs(".")
+func InitUI(app *app.Application, startDir string, opts Options) (string, error) {
+	m, cleanup, err := newModel(app, startDir, opts)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+
+	m.logger.Info("starting UI event loop")
+
+	final, err := tea.NewProgram(m, programOptions()...).Run()
+	if err != nil {
+		return "", fmt.Errorf("failed to run UI program: %w", err)
+	}
+
+	fm, ok := final.(model)
+	if !ok {
+		return "", nil
+	}
+	return fm.selection(), nil
+}
+
+// selection returns the absolute path of the directory chosen with Enter, or
+// an empty string if none was chosen.
+func (m model) selection() string {
+	if m.choice == "" {
+		return ""
+	}
+	return filepath.Join(m.currentDir, m.choice)
+}
+
+// newModel performs the initial scan of startDir and builds the model shown
+// by InitUI, starting the background scanner and the directory watcher.
+//
+// Returns the model and a function stopping the scanner and the watcher
+// once the model is no longer used, or an error if the start directory
+// cannot be resolved or scanned.
+func newModel(app *app.Application, startDir string, opts Options) (model, func(), error) {
+	logger := app.ModuleLogger(logging.ModuleUI)
+	logger.Info("initializing UI")
+	currentDir, err := filepath.Abs(startDir)
+	if err != nil {
+		return model{}, nil, fmt.Errorf("failed to resolve start directory: %w", err)
+	}
+
+	largerThan, minSize := strings.TrimSpace(opts.LargerThan), int64(0)
+	if largerThan != "" {
+		if minSize, err = dirsearch.ParseSize(largerThan); err != nil {
+			return model{}, nil, fmt.Errorf("invalid size filter: %w", err)
+		}
+	}
+	if minSize == 0 {
+		largerThan = ""
+	}
+
+	// The ignore setting is part of the options of app.Dirsearch but is
+	// kept apart from the profile ignore lists, so rules added to it at
+	// runtime apply to every profile
+	defaultIgnore := slices.DeleteFunc(slices.Clone(app.Dirsearch.Options.IgnorePatterns), func(pattern string) bool {
+		return slices.Contains(app.Config.Ignore, pattern)
+	})
+	initial := scanOptions(context.Background(), app.Dirsearch.Options, scanRequest{
+		dir:        currentDir,
+		ignore:     ignoreList(defaultIgnore, app.Config.Ignore, opts.Ignore),
+		pattern:    opts.Query,
+		minSize:    minSize,
+		projects:   opts.Projects,
+		showHidden: app.Dirsearch.Options.Hidden != dirsearch.HiddenHide,
+	}, logger)
+	result := app.Dirsearch.WithOptions(initial).ScanDirs(currentDir)
 	const title = ""
 	if result.Error != nil {
-		app.Logger.Error("initial directory scan failed", "error", result.Error)
-		return fmt.Errorf("initial directory scan failed: %w", result.Error)
+		logger.Error("initial directory scan failed", "error", result.Error)
+		return model{}, nil, fmt.Errorf("initial directory scan failed: %w", termux.StorageError(currentDir, result.Error))
 	}
-	app.Logger.Debug("initial scan completed", "count", len(result.Directories))
+	logger.Debug("initial scan completed", "count", len(result.Directories))
 
-	items := stringsToItems(result.Directories)
-	height := int(math.Min(float64(len(items)+listHeightPadding), maxListHeight))
-	l := list.New(items, itemDelegate{}, defaultListWidth, height)
+	height := int(math.Min(float64(len(result.Directories)+listHeightPadding), maxListHeight))
+	// Bundles are opaque by default only on macOS, where Finder treats them as files
+	peekBundles := runtime.GOOS != "darwin"
+	width, terminalHeight := terminalSize()
+	l := list.New(nil, itemDelegate{opaqueBundles: !peekBundles}, width, height)
 	l.Title = title
 	l.SetShowStatusBar(false)
 	l.SetFilteringEnabled(false)
@@ -353,34 +1428,131 @@ func InitUI(app *app.Application) error {
 	l.Styles.PaginationStyle = paginationStyle
 	l.Styles.HelpStyle = helpStyle
 	// l.SetFilterText("")
+	ascii := useASCII(opts.ASCII)
+	if ascii {
+		// Keeps the truncated help line within the width once converted
+		l.Help.Ellipsis = "..."
+	}
 
-	currentDir, err := os.Getwd()
+	metaFields, err := dirmeta.ParseFields(app.Config.Decorations)
 	if err != nil {
-		return fmt.Errorf("failed to get current directory: %w", err)
+		return model{}, nil, fmt.Errorf("invalid decorations: %w", err)
+	}
+
+	times, err := app.Config.Time.Formatter()
+	if err != nil {
+		return model{}, nil, fmt.Errorf("invalid time settings: %w", err)
+	}
+
+	watchDelay, previewInterval := watch.DefaultDelay, previewWatchInterval
+	if opts.LowPower {
+		logger.Info("low power mode: skipping git and size decorations")
+		metaFields &^= dirmeta.Git | dirmeta.Size
+		watchDelay, previewInterval = lowPowerWatchDelay, lowPowerPreviewInterval
 	}
 
-	requestChan := make(chan string)
-	resultChan := make(chan dirsearch.Result)
-	doneChan := make(chan struct{})
+	requestChan := make(chan scanRequest)
+	resultChan := make(chan responseMsg)
+	scanCtx, stopScans := context.WithCancel(context.Background())
+
+	go scanInBackground(scanCtx, requestChan, resultChan, adaptiveScan(app.Dirsearch, app.ScanHistory, app.Stats, logger))
 
-	go scanInBackground(requestChan, resultChan, doneChan, app.Dirsearch.ScanDirs)
+	var watcher *watch.Watcher
+	if app.Features.Enabled(features.Watch) {
+		if watcher, err = watch.New(watchDelay, logger); err != nil {
+			logger.Warn("directory changes will not be picked up", "error", err)
+		}
+	}
 
 	m := model{
 		list:        l,
 		currentDir:  currentDir,
+		pendingDir:  currentDir,
+		debounce:    app.Config.NavigationDebounce(),
 		requestChan: requestChan,
 		resultChan:  resultChan,
-		doneChan:    doneChan,
+		stopScans:   stopScans,
 		search:      app.Dirsearch.ScanDirs,
-		logger:      app.Logger,
+		logger:      logger,
 		dirIndexMap: make(map[string]int),
+		peekBundles: peekBundles,
+		freeSpace:   -1,
+		stats:       app.Stats,
+		jobs:        app.Jobs,
+		templates:   app.Config.Templates,
+		ranking:     app.Config.Ranking,
+		colors:      app.Config.Colors,
+		chrome:      newChrome(app.Config),
+		previewCmd:  cmp.Or(opts.Preview, app.Config.PreviewCommand),
+		previews:    make(map[string]previewMsg),
+		previewTick: previewInterval,
+		notify:      app.Config.Notify,
+		watcher:     watcher,
+		height:      opts.Height,
+		bottomUp:    opts.BottomUp,
+		border:      opts.Border,
+		compact:     opts.Compact,
+		ascii:       ascii,
+		pins:        app.Pins,
+		notes:       app.Notes,
+		tags:        app.Tags,
+		history:     app.SearchHistory,
+		tagFilter:   opts.tagFilter(),
+		query:       opts.Query,
+		largerThan:  largerThan,
+		minSize:     minSize,
+		projects:    opts.Projects,
+		inlineNotes: app.Config.InlineNotes,
+		showHidden:  app.Dirsearch.Options.Hidden != dirsearch.HiddenHide,
+		metaFields:  metaFields,
+		times:       times,
+		sizes:       app.Config.Sizes(),
+
+		profiles:      app.Config.Profiles,
+		profile:       defaultProfileName,
+		ignore:        defaultIgnore,
+		defaultIgnore: defaultIgnore,
+		configIgnore:  slices.Clone(app.Config.Ignore),
+		sessionIgnore: slices.Clone(opts.Ignore),
+
+		// Replaced once the terminal reports its size
+		terminalWidth:  width,
+		terminalHeight: terminalHeight,
+
+		saveBugReport: bugReporter(app, opts.Version),
 	}
 
-	app.Logger.Info("starting UI event loop")
+	m.showDirs(currentDir, result.Directories)
+	m.fitList(len(result.Directories))
+	m.watchDir(currentDir)
+
+	cleanup := func() {
+		stopScans()
+		if watcher != nil {
+			watcher.Close()
+		}
+	}
+	return m, cleanup, nil
+}
 
-	if _, err := tea.NewProgram(m).Run(); err != nil {
-		return fmt.Errorf("failed to run UI program: %w", err)
+// programOptions returns the Bubble Tea options that keep the UI usable when
+// standard output or standard input is redirected.
+func programOptions() []tea.ProgramOption {
+	var opts []tea.ProgramOption
+	if !isTerminal(os.Stdout) {
+		opts = append(opts, tea.WithOutput(os.Stderr))
+	}
+	if !isTerminal(os.Stdin) {
+		opts = append(opts, tea.WithInputTTY())
 	}
+	return opts
+}
 
-	return nil
+// isTerminal reports whether f is connected to a character device.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
 }
@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kaczmarekdaniel/folder-search/internal/watch"
+)
+
+// lowPowerWatchDelay is how long the current directory must be quiet
+// before a change is picked up in low power mode, so a running build
+// causes few rescans.
+const lowPowerWatchDelay = 2 * time.Second
+
+// dirChangedMsg reports that entries of a watched directory changed.
+type dirChangedMsg struct {
+	dir string
+}
+
+// waitForChanges blocks until the watcher reports a change. It returns nil
+// once the watcher is closed.
+func waitForChanges(w *watch.Watcher) tea.Cmd {
+	return func() tea.Msg {
+		dir, ok := <-w.Changes()
+		if !ok {
+			return nil
+		}
+		return dirChangedMsg{dir: dir}
+	}
+}
+
+// watchDir makes the watcher follow dir. Failing to watch only costs the
+// automatic refresh, so the error is logged and otherwise ignored.
+func (m model) watchDir(dir string) {
+	if m.watcher == nil {
+		return
+	}
+	if err := m.watcher.Watch(dir); err != nil {
+		m.logger.Debug("cannot watch directory", "dir", dir, "error", err)
+	}
+}
+
+// refreshChanged rescans the current directory after a directory was
+// created, removed or renamed in it outside folder-search. The cursor keeps
+// its position.
+func (m model) refreshChanged(msg dirChangedMsg) (tea.Model, tea.Cmd) {
+	next := waitForChanges(m.watcher)
+	// A scan already in progress lists the directory anew anyway
+	if msg.dir != m.currentDir || m.pendingDir != "" {
+		return m, next
+	}
+
+	m.logger.Debug("directory changed, refreshing", "dir", msg.dir)
+	m.dirIndexMap[m.currentDir] = m.list.Index()
+	m, cmd := m.scan(m.currentDir)
+	return m, tea.Batch(cmd, next)
+}
@@ -0,0 +1,163 @@
+// Package watch reports when entries are created, removed or renamed in a
+// directory, so listings can refresh without being asked to.
+//
+// A Watcher follows one directory at a time, non-recursively: changes to
+// files inside subdirectories are not reported. Bursts of changes, such as
+// a build creating many folders, are coalesced into a single notification
+// sent once the directory has been quiet for the watcher's delay.
+package watch
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultDelay is how long a directory must be quiet after a change before
+// it is reported.
+const DefaultDelay = 200 * time.Millisecond
+
+// Watcher reports changes to the entries of the watched directory.
+type Watcher struct {
+	fs      *fsnotify.Watcher
+	delay   time.Duration
+	logger  *slog.Logger
+	changes chan string
+
+	mu  sync.Mutex
+	dir string // Watched directory; empty if none
+}
+
+// New starts a watcher that is not watching anything yet.
+//
+// Parameters:
+//   - delay: how long a directory must be quiet before a change is
+//     reported; zero or negative reports every change at once
+//   - logger: receives errors reported by the operating system
+//
+// Returns an error if the operating system offers no file watching or its
+// limits are exhausted.
+func New(delay time.Duration, logger *slog.Logger) (*Watcher, error) {
+	fs, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start watcher: %w", err)
+	}
+	w := &Watcher{fs: fs, delay: delay, logger: logger, changes: make(chan string, 1)}
+	go w.run()
+	return w, nil
+}
+
+// Watch makes dir the watched directory, replacing the previous one. An
+// empty dir stops watching.
+//
+// Returns an error if dir cannot be watched; nothing is watched then.
+func (w *Watcher) Watch(dir string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if dir == w.dir {
+		return nil
+	}
+	if w.dir != "" {
+		// Fails if the directory was removed, which drops the watch anyway
+		_ = w.fs.Remove(w.dir)
+		w.dir = ""
+	}
+	if dir == "" {
+		return nil
+	}
+	if err := w.fs.Add(dir); err != nil {
+		return fmt.Errorf("failed to watch %s: %w", dir, err)
+	}
+	w.dir = dir
+	return nil
+}
+
+// Changes returns the channel on which the watched directory is sent after
+// its entries changed. A notification that is not received before the next
+// change is merged with it.
+func (w *Watcher) Changes() <-chan string {
+	return w.changes
+}
+
+// Close stops watching. The Changes channel is closed.
+func (w *Watcher) Close() error {
+	return w.fs.Close()
+}
+
+// run turns file system events into debounced notifications until the
+// watcher is closed.
+func (w *Watcher) run() {
+	defer close(w.changes)
+
+	var timer *time.Timer
+	var fire <-chan time.Time
+	pending := ""
+	for {
+		select {
+		case ev, ok := <-w.fs.Events:
+			if !ok {
+				if timer != nil {
+					timer.Stop()
+				}
+				return
+			}
+			if !ev.Has(fsnotify.Create) && !ev.Has(fsnotify.Remove) && !ev.Has(fsnotify.Rename) {
+				continue
+			}
+			dir := filepath.Dir(ev.Name)
+			if dir != w.watched() {
+				// Left over from a directory watched before
+				continue
+			}
+			pending = dir
+			if w.delay <= 0 {
+				w.notify(pending)
+				continue
+			}
+			if timer == nil {
+				timer = time.NewTimer(w.delay)
+			} else {
+				timer.Reset(w.delay)
+			}
+			fire = timer.C
+		case <-fire:
+			fire = nil
+			if pending == w.watched() {
+				w.notify(pending)
+			}
+		case err, ok := <-w.fs.Errors:
+			if !ok {
+				return
+			}
+			w.logger.Warn("file watcher error", "error", err)
+		}
+	}
+}
+
+// watched returns the watched directory.
+func (w *Watcher) watched() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.dir
+}
+
+// notify sends dir without blocking. If an earlier notification has not
+// been received yet, it is replaced.
+func (w *Watcher) notify(dir string) {
+	for {
+		select {
+		case w.changes <- dir:
+			return
+		default:
+		}
+		select {
+		case <-w.changes:
+		default:
+		}
+	}
+}
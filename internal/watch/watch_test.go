@@ -0,0 +1,125 @@
+package watch
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newTestWatcher returns a watcher with a short delay, closed when the test ends.
+func newTestWatcher(t *testing.T) *Watcher {
+	t.Helper()
+	w, err := New(20*time.Millisecond, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("failed to create watcher: %v", err)
+	}
+	t.Cleanup(func() { w.Close() })
+	return w
+}
+
+// expectChange waits for a notification about dir.
+func expectChange(t *testing.T, w *Watcher, dir string) {
+	t.Helper()
+	select {
+	case got := <-w.Changes():
+		if got != dir {
+			t.Errorf("expected change in %s, got %s", dir, got)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("expected change in %s, got none", dir)
+	}
+}
+
+// expectQuiet checks that no notification arrives for a while.
+func expectQuiet(t *testing.T, w *Watcher) {
+	t.Helper()
+	select {
+	case got := <-w.Changes():
+		t.Errorf("expected no change, got %s", got)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	root, err := os.MkdirTemp("", "watch-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(root)
+
+	w := newTestWatcher(t)
+	if err := w.Watch(root); err != nil {
+		t.Fatalf("failed to watch: %v", err)
+	}
+
+	// A burst of changes is reported once
+	for _, name := range []string{"a", "b", "c"} {
+		if err := os.Mkdir(filepath.Join(root, name), 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", name, err)
+		}
+	}
+	expectChange(t, w, root)
+	expectQuiet(t, w)
+
+	// Changes below subdirectories are not reported
+	if err := os.Mkdir(filepath.Join(root, "a", "nested"), 0755); err != nil {
+		t.Fatalf("failed to create nested dir: %v", err)
+	}
+	expectQuiet(t, w)
+
+	if err := os.Remove(filepath.Join(root, "b")); err != nil {
+		t.Fatalf("failed to remove b: %v", err)
+	}
+	expectChange(t, w, root)
+}
+
+func TestWatcher_Switch(t *testing.T) {
+	root, err := os.MkdirTemp("", "watch-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(root)
+
+	first, second := filepath.Join(root, "first"), filepath.Join(root, "second")
+	for _, dir := range []string{first, second} {
+		if err := os.Mkdir(dir, 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", dir, err)
+		}
+	}
+
+	w := newTestWatcher(t)
+	if err := w.Watch(first); err != nil {
+		t.Fatalf("failed to watch: %v", err)
+	}
+	if err := w.Watch(second); err != nil {
+		t.Fatalf("failed to watch: %v", err)
+	}
+
+	if err := os.Mkdir(filepath.Join(first, "x"), 0755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	expectQuiet(t, w)
+
+	if err := os.Mkdir(filepath.Join(second, "x"), 0755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	expectChange(t, w, second)
+
+	if err := w.Watch(""); err != nil {
+		t.Fatalf("failed to stop watching: %v", err)
+	}
+	if err := os.Mkdir(filepath.Join(second, "y"), 0755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	expectQuiet(t, w)
+}
+
+func TestWatcher_Missing(t *testing.T) {
+	w := newTestWatcher(t)
+	if err := w.Watch(filepath.Join(os.TempDir(), "watch-test-does-not-exist")); err == nil {
+		t.Error("expected error for missing directory, got nil")
+	}
+}
@@ -1,25 +1,843 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/kaczmarekdaniel/folder-search/internal/alerts"
 	"github.com/kaczmarekdaniel/folder-search/internal/app"
+	"github.com/kaczmarekdaniel/folder-search/internal/bench"
+	"github.com/kaczmarekdaniel/folder-search/internal/bugreport"
+	"github.com/kaczmarekdaniel/folder-search/internal/config"
+	"github.com/kaczmarekdaniel/folder-search/internal/dirsearch"
+	"github.com/kaczmarekdaniel/folder-search/internal/explain"
+	"github.com/kaczmarekdaniel/folder-search/internal/hook"
+	"github.com/kaczmarekdaniel/folder-search/internal/index"
+	"github.com/kaczmarekdaniel/folder-search/internal/logging"
+	"github.com/kaczmarekdaniel/folder-search/internal/mcp"
+	"github.com/kaczmarekdaniel/folder-search/internal/report"
+	"github.com/kaczmarekdaniel/folder-search/internal/termux"
 	"github.com/kaczmarekdaniel/folder-search/internal/ui"
 )
 
+// version is reported to MCP clients and in bug reports; release builds set
+// it with -ldflags "-X main.version=...".
+var version = "dev"
+
+// statsTopN is the number of directories and actions listed by the stats command.
+const statsTopN = 10
+
+// Values of the --layout flag, named after the fzf layouts they mimic.
+const (
+	layoutReverse = "reverse"
+	layoutDefault = "default"
+)
+
+// Exit codes of --pick mode, part of its documented contract.
+const (
+	pickExitSelected = 0
+	pickExitCanceled = 1
+	pickExitError    = 2
+)
+
 func main() {
+	os.Exit(run())
+}
+
+// run parses the command line, runs the interface or the given command and
+// returns the process exit code. The application is closed before it
+// returns, on every path.
+func run() int {
+	outputModeFlag := flag.String("output-mode", string(app.OutputAbsolute), "how to print the selected directory: abs, rel or name")
+	pick := flag.Bool("pick", false, "pick one path for an editor integration: options are read from stdin, or directories browsed from [path]")
+	tag := flag.String("tag", "", "only list directories carrying this tag")
+	query := flag.String("query", "", "start with the listing filtered to directory names containing this text")
+	largerThan := flag.String("larger-than", "", "only list directories whose total size is at least this, such as 500M or 1G")
+	project := flag.String("project", "", "only list roots of projects of these comma-separated types: git, go, node, rust or python")
+	preview := flag.String("preview", "", "shell command previewing the highlighted directory; {} is replaced by its path")
+	heightFlag := flag.String("height", "", "height of the interface in lines or as a percentage of the terminal, e.g. 40%")
+	layout := flag.String("layout", layoutReverse, "list layout as in fzf: reverse (title on top) or default (title at the bottom)")
+	border := flag.Bool("border", false, "draw a border around the interface")
+	ignore := flag.String("ignore", "", "comma-separated directory names or patterns to hide for this session only, e.g. dist,build-*")
+	lowPower := flag.Bool("low-power", false, "cut down background work to save energy, as on battery; see the low_power setting")
+	compact := flag.Bool("compact", false, "use the layout of narrow terminals: names only, no key help and a shorter preview")
+	ascii := flag.Bool("ascii", false, "draw arrows, bullets and ellipses with ASCII characters, as for terminals without UTF-8")
+	flag.Usage = usage
+	flag.Parse()
+
+	outputMode, err := app.ParseOutputMode(*outputModeFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	height, err := ui.ParseHeight(*heightFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	if *layout != layoutReverse && *layout != layoutDefault {
+		fmt.Fprintf(os.Stderr, "Error: invalid layout %q: use reverse or default\n", *layout)
+		return 2
+	}
+	if *largerThan != "" {
+		if _, err := dirsearch.ParseSize(*largerThan); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid --larger-than: %v\n", err)
+			return 2
+		}
+	}
+	projects, err := dirsearch.ParseProjectTypes(*project)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid --project: %v\n", err)
+		return 2
+	}
+	for _, pattern := range splitList(*ignore) {
+		if err := dirsearch.CheckIgnorePattern(pattern); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid --ignore pattern: %v\n", err)
+			return 2
+		}
+	}
+	uiOpts := ui.Options{
+		Tag:        *tag,
+		Query:      *query,
+		LargerThan: *largerThan,
+		Projects:   projects,
+		Preview:    *preview,
+		Height:     height,
+		BottomUp:   *layout == layoutDefault,
+		Border:     *border,
+		Ignore:     splitList(*ignore),
+		Compact:    *compact,
+		ASCII:      *ascii,
+		Version:    version,
+	}
+
+	startDir, err := os.Getwd()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting current directory: %v\n", err)
+		return 1
+	}
+
 	app, err := app.NewApplication()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error initializing application: %v\n", err)
-		os.Exit(1)
+		return 1
+	}
+	defer app.Close()
+	uiOpts.LowPower = *lowPower || app.Config.LowPowerEnabled()
+	if termux.Detected() {
+		// Phones have small screens and slow shared storage
+		uiOpts.Compact = true
+		app.Dirsearch.Options.Concurrency = termux.Concurrency
+	}
+
+	if *pick {
+		return runPick(app, outputMode, startDir, uiOpts)
+	}
+
+	switch flag.Arg(0) {
+	case "":
+	case "bench":
+		root := "."
+		if flag.NArg() > 1 {
+			root = flag.Arg(1)
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		report, err := bench.Run(ctx, root, app.Dirsearch.Options.Clone(), bench.DefaultHotSpots)
+		stop()
+		if err == nil {
+			err = report.WriteReport(os.Stdout)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		return 0
+	case "broken-links":
+		root := "."
+		if flag.NArg() > 1 {
+			root = flag.Arg(1)
+		}
+		if err := ui.RunBrokenLinks(app, root, uiOpts.ASCII); err != nil {
+			app.Logger.Error("failed to run broken link report", "error", err)
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		return 0
+	case "mcp":
+		server := mcp.NewServer(version, app.Logger,
+			mcp.FindDirectoriesTool(app.Dirsearch.Options.IgnorePatterns, app.Config.Ranking),
+			mcp.ListDirectoriesTool(app.Dirsearch.Options.IgnorePatterns))
+		if err := server.Serve(context.Background(), os.Stdin, os.Stdout); err != nil {
+			app.Logger.Error("mcp server stopped", "error", err)
+			return 1
+		}
+		return 0
+	case "report":
+		return runReport(app, flag.Args()[1:])
+	case "index":
+		return runIndex(app, flag.Args()[1:])
+	case "find":
+		return runFind(app, startDir, flag.Args()[1:])
+	case "why":
+		return runWhy(app, uiOpts.Ignore, flag.Args()[1:])
+	case "bugreport":
+		return runBugReport(app, flag.Args()[1:])
+	case "script":
+		return runScript(app, startDir, uiOpts, flag.Args()[1:])
+	case "features":
+		if err := app.Features.WriteReport(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		return 0
+	case "stats":
+		if err := app.Stats.WriteReport(os.Stdout, statsTopN); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		return 0
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown command %q\n", flag.Arg(0))
+		flag.Usage()
+		return 2
 	}
 
 	app.Logger.Info("starting UI")
-	if err := ui.InitUI(app); err != nil {
+	selected, err := ui.InitUI(app, startDir, uiOpts)
+	if err != nil {
 		app.Logger.Error("failed to run UI", "error", err)
 		fmt.Fprintf(os.Stderr, "Error running UI: %v\n", err)
-		os.Exit(1)
+		return 1
+	}
+
+	if selected != "" {
+		out, err := outputMode.Format(selected, startDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error formatting selection: %v\n", err)
+			return 1
+		}
+		fmt.Println(out)
 	}
 	app.Logger.Info("application exiting normally")
+	return 0
+}
+
+// runPick implements --pick mode and returns the process exit code.
+//
+// With a path argument, directories are browsed starting there and the
+// selection is printed according to outputMode. Otherwise, if standard input
+// is not a terminal, each non-empty input line is an option and the chosen
+// line is printed unchanged. Exactly one line is written to standard output
+// on success; nothing is written on cancel or error.
+func runPick(app *app.Application, outputMode app.OutputMode, startDir string, opts ui.Options) int {
+	var (
+		selected string
+		err      error
+	)
+	switch {
+	case flag.NArg() > 0:
+		selected, err = ui.InitUI(app, flag.Arg(0), opts)
+		if err == nil && selected != "" {
+			selected, err = outputMode.Format(selected, startDir)
+		}
+	case !isTerminal(os.Stdin):
+		var options []string
+		options, err = readOptions(os.Stdin)
+		if err == nil {
+			if len(options) == 0 {
+				fmt.Fprintln(os.Stderr, "Error: no options on standard input")
+				return pickExitError
+			}
+			selected, err = ui.RunPicker(app, options, opts.ASCII)
+		}
+	default:
+		selected, err = ui.InitUI(app, startDir, opts)
+		if err == nil && selected != "" {
+			selected, err = outputMode.Format(selected, startDir)
+		}
+	}
+
+	if err != nil {
+		app.Logger.Error("pick failed", "error", err)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return pickExitError
+	}
+	if selected == "" {
+		return pickExitCanceled
+	}
+	fmt.Println(selected)
+	return pickExitSelected
+}
+
+// runReport implements the report command and returns the process exit
+// code: 2 for invalid arguments or report files, 1 if the report cannot be
+// written or any search or audit failed, 0 otherwise.
+func runReport(app *app.Application, args []string) int {
+	fs := flag.NewFlagSet("report", flag.ContinueOnError)
+	configPath := fs.String("config", "", "report file listing the searches and audits to run (required)")
+	format := fs.String("format", report.FormatJSON, "output format: json or markdown")
+	output := fs.String("output", "", "file to write the report to instead of standard output")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *configPath == "" {
+		fmt.Fprintln(os.Stderr, "Error: report requires --config")
+		return 2
+	}
+	if *format != report.FormatJSON && *format != report.FormatMarkdown {
+		fmt.Fprintf(os.Stderr, "Error: invalid format %q: use json or markdown\n", *format)
+		return 2
+	}
+
+	cfg, err := report.LoadConfig(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	r := report.Run(ctx, cfg, app.Dirsearch.Options.IgnorePatterns, time.Now())
+	// The time settings were checked when the configuration was loaded
+	r.Times, _ = app.Config.Time.Formatter()
+	r.Sizes = app.Config.Sizes()
+
+	if *output == "" {
+		err = r.Write(os.Stdout, *format)
+	} else {
+		f, createErr := os.Create(*output)
+		if createErr != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to create report: %v\n", createErr)
+			return 1
+		}
+		err = r.Write(f, *format)
+		// Write errors can surface only when the file is closed
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to write report: %v\n", err)
+		return 1
+	}
+
+	if r.Failed() {
+		for _, s := range r.Sections {
+			if s.Error != "" {
+				app.Logger.Error("report section failed", "section", s.Name, "error", s.Error)
+				fmt.Fprintf(os.Stderr, "Error: %s: %s\n", s.Name, s.Error)
+			}
+		}
+		return 1
+	}
+	return 0
+}
+
+// runWhy implements the why command, which explains why a path is missing
+// from listings, and returns the process exit code.
+//
+// The rules checked are those the UI would apply with the given session
+// ignores: the built-in ones, the default or profile ignore list, the
+// "ignore" setting and project ignore files.
+func runWhy(app *app.Application, sessionIgnore []string, args []string) int {
+	fs := flag.NewFlagSet("why", flag.ContinueOnError)
+	profile := fs.String("profile", "", "apply the ignore list of this profile instead of the default one")
+	showHidden := fs.Bool("show-hidden", app.Dirsearch.Options.Hidden != dirsearch.HiddenHide, "whether names starting with a dot are listed")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: folder-search [--ignore names] why [options] path")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	var rules []explain.Rule
+	add := func(patterns []string, source string) {
+		for _, p := range patterns {
+			rules = append(rules, explain.Rule{Pattern: p, Source: source})
+		}
+	}
+	p, ok := app.Config.Profiles[*profile]
+	if *profile != "" && !ok {
+		fmt.Fprintf(os.Stderr, "Error: unknown profile %q\n", *profile)
+		return 2
+	}
+	if p.Ignore != nil {
+		add(p.Ignore, fmt.Sprintf("ignore list of profile %q", *profile))
+	} else {
+		add(dirsearch.DefaultOptions().IgnorePatterns, "default ignore list")
+	}
+	configSource := `"ignore" setting of the config file`
+	if path, err := config.Path(); err == nil {
+		configSource = `"ignore" setting in ` + path
+	}
+	add(app.Config.Ignore, configSource)
+	add(sessionIgnore, "--ignore for this session")
+
+	e := explain.Explainer{Rules: rules, ShowHidden: *showHidden}
+	reason, hidden, err := e.Explain(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if !hidden {
+		fmt.Printf("%s is not hidden by any rule\n", fs.Arg(0))
+		return 0
+	}
+	fmt.Println(reason)
+	return 0
+}
+
+// runIndex implements the index command, which walks a root and saves its
+// directory index, and returns the process exit code.
+//
+// Once the index is saved the configured alerts are checked; broken rules
+// are printed and passed to the alert command but do not fail the command.
+func runIndex(app *app.Application, args []string) int {
+	root, err := os.UserHomeDir()
+	if err != nil {
+		root = "."
+	}
+	if len(args) > 1 {
+		fmt.Fprintln(os.Stderr, "Usage: folder-search index [root]")
+		return 2
+	}
+	if len(args) == 1 {
+		root = args[0]
+	}
+
+	dir, err := index.DefaultDir()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	started := time.Now()
+	ix, err := index.Build(ctx, root, app.Dirsearch.Options.IgnorePatterns)
+	if err == nil {
+		err = ix.Save(dir)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		notifyJob(ctx, app, "index "+root, "failed", err.Error(), time.Since(started))
+		return 1
+	}
+	app.ModuleLogger(logging.ModuleIndex).Info("index saved", "root", ix.Root, "directories", len(ix.Dirs))
+	fmt.Printf("indexed %d directories under %s\n", len(ix.Dirs), ix.Root)
+	notifyJob(ctx, app, "index "+ix.Root, "done", fmt.Sprintf("%d directories", len(ix.Dirs)), time.Since(started))
+	checkAlerts(ctx, app, ix.Root)
+	return 0
+}
+
+// notifyJob runs the configured notify command for a job of the command
+// line that finished with status after running for took, if it ran long
+// enough. A failing command is reported as a warning.
+func notifyJob(ctx context.Context, app *app.Application, name, status, summary string, took time.Duration) {
+	cfg := app.Config.Notify
+	if cfg.Command == "" || took < cfg.MinDuration() {
+		return
+	}
+
+	env := map[string]string{
+		hook.MessageVar:        name + ": " + summary,
+		hook.EventVar:          "job",
+		"FOLDER_SEARCH_JOB":    name,
+		"FOLDER_SEARCH_STATUS": status,
+	}
+	if err := hook.Run(ctx, cfg.Command, env); err != nil {
+		app.ModuleLogger(logging.ModuleIndex).Warn("notify command failed", "job", name, "error", err)
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
+
+// checkAlerts checks the configured alert rules below root, printing every
+// broken rule and running the alert command for it.
+func checkAlerts(ctx context.Context, app *app.Application, root string) {
+	cfg := app.Config.Alerts
+	if len(cfg.Rules) == 0 {
+		return
+	}
+
+	logger := app.ModuleLogger(logging.ModuleIndex)
+	found, err := alerts.Check(ctx, cfg.Rules, root)
+	if err != nil {
+		logger.Warn("checking alerts failed", "error", err)
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	sizes := app.Config.Sizes()
+	for _, a := range found {
+		logger.Warn("alert", "rule", a.Rule.Name, "path", a.Path, "size", a.Size, "limit", a.Limit)
+		fmt.Printf("Alert: %s\n", a.Message(sizes))
+		if cfg.Command == "" {
+			continue
+		}
+		if err := hook.Run(ctx, cfg.Command, a.Env(sizes)); err != nil {
+			logger.Warn("alert command failed", "error", err)
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+}
+
+// runFind implements the find command, which searches every directory below
+// one or more roots using their saved indexes, and returns the process exit
+// code.
+//
+// Without a fresh index covering a root, the root is walked and its index
+// saved for the next search. Matches are printed as absolute paths, one per
+// line, root by root; with several roots each is prefixed by the label of
+// its root, see dirsearch.RootLabels. The exit code is 1 if nothing matched.
+func runFind(app *app.Application, startDir string, args []string) int {
+	fs := flag.NewFlagSet("find", flag.ContinueOnError)
+	var roots []string
+	fs.Func("root", "directory to search below; repeat to search several, labeling each match with its root", func(dir string) error {
+		roots = append(roots, dir)
+		return nil
+	})
+	only := fs.String("only", "", "with several roots, only search the root with this label or path")
+	maxAge := fs.Duration("max-age", index.DefaultMaxAge, "rebuild the index once it is older than this")
+	rebuild := fs.Bool("rebuild", false, "walk the root again even if its index is fresh")
+	limit := fs.Int("limit", 0, "stop after this many matches (0 for no limit)")
+	minSize := fs.String("min-size", "", "only match directories of at least this size, such as 500M or 1G")
+	maxSize := fs.String("max-size", "", "only match directories of at most this size")
+	within := fs.String("modified-within", "", "only match directories modified within this age, such as 7d")
+	notWithin := fs.String("not-modified-for", "", "only match directories not modified for this age, such as 1y")
+	contains := fs.String("contains", "", "only match directories holding a file that contains this text")
+	in := fs.String("in", "", "only read files whose names match this pattern for --contains, such as go.mod")
+	project := fs.String("project", "", "only match roots of projects of these comma-separated types: git, go, node, rust or python")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: folder-search find [options] pattern")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	var sizes [2]int64
+	for i, s := range []string{*minSize, *maxSize} {
+		if s == "" {
+			continue
+		}
+		size, err := dirsearch.ParseSize(s)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 2
+		}
+		sizes[i] = size
+	}
+	projects, err := dirsearch.ParseProjectTypes(*project)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	var ages [2]time.Time
+	for i, s := range []string{*within, *notWithin} {
+		if s == "" {
+			continue
+		}
+		age, err := dirsearch.ParseAge(s)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 2
+		}
+		ages[i] = time.Now().Add(-age)
+	}
+
+	if len(roots) == 0 {
+		roots = []string{startDir}
+	}
+	labels := dirsearch.RootLabels(roots)
+	var searched []int
+	for i, root := range roots {
+		if *only == "" || *only == labels[i] || filepath.Clean(*only) == filepath.Clean(root) {
+			searched = append(searched, i)
+		}
+	}
+	if len(searched) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: no root labeled %q; roots are labeled %s\n", *only, strings.Join(labels, ", "))
+		return 2
+	}
+
+	dir, err := index.DefaultDir()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	printed := make(map[string]bool)
+	for _, i := range searched {
+		if *limit > 0 && len(printed) >= *limit {
+			break
+		}
+		root := roots[i]
+		prefix := ""
+		if len(roots) > 1 {
+			prefix = "[" + labels[i] + "] "
+		}
+
+		opts := app.Dirsearch.Options.Clone()
+		opts.StartDir = root
+		opts.SearchPattern = fs.Arg(0)
+		opts.MaxDepth = dirsearch.UnlimitedDepth
+		opts.MinSize, opts.MaxSize = sizes[0], sizes[1]
+		opts.ModifiedAfter, opts.ModifiedBefore = ages[0], ages[1]
+		opts.ContentPattern, opts.ContentFiles = *contains, *in
+		opts.ProjectTypes = projects
+		if *limit > 0 {
+			opts.MaxResults = *limit - len(printed)
+		}
+		result, err := findInIndex(ctx, app, dir, opts, *rebuild, *maxAge)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+
+		start, _ := filepath.Abs(root)
+		for _, d := range result.Directories {
+			// Overlapping roots find the same directories
+			path := filepath.Join(start, d)
+			if printed[path] {
+				continue
+			}
+			printed[path] = true
+			fmt.Println(prefix + path)
+		}
+		if result.Truncated {
+			fmt.Fprintf(os.Stderr, "stopped after %d matches\n", *limit)
+			break
+		}
+	}
+	if len(printed) == 0 {
+		return 1
+	}
+	return 0
+}
+
+// findInIndex searches the index covering opts.StartDir, which is stored in
+// dir, for the find command. The root is walked and its index saved first
+// if rebuild is set or there is no index younger than maxAge; an index that
+// turns out to be outdated is saved so the next search rebuilds it.
+//
+// Returns the search result, or an error if the root cannot be indexed or
+// searched.
+func findInIndex(ctx context.Context, app *app.Application, dir string, opts *dirsearch.Options, rebuild bool, maxAge time.Duration) (dirsearch.Result, error) {
+	root := opts.StartDir
+	ignore := opts.IgnorePatterns
+	logger := app.ModuleLogger(logging.ModuleIndex)
+
+	var ix *index.Index
+	var err error
+	if !rebuild {
+		if ix, err = index.Lookup(dir, root); err != nil {
+			// A damaged index is rebuilt below
+			logger.Warn("failed to load index", "root", root, "error", err)
+		}
+	}
+	if ix == nil || ix.Stale(time.Now(), maxAge, ignore) {
+		indexRoot := root
+		if ix != nil {
+			indexRoot = ix.Root
+		}
+		if ix, err = index.Build(ctx, indexRoot, ignore); err != nil {
+			return dirsearch.Result{}, err
+		}
+		logger.Debug("index built", "root", ix.Root, "directories", len(ix.Dirs))
+		if err := ix.Save(dir); err != nil {
+			logger.Warn("failed to save index", "root", ix.Root, "error", err)
+		}
+	} else {
+		logger.Debug("using saved index", "root", ix.Root, "directories", len(ix.Dirs))
+	}
+
+	result := ix.Search(ctx, opts)
+	if ix.Outdated {
+		// Directories were deleted since the index was built: save it so
+		// the next search rebuilds it
+		logger.Debug("index outdated", "root", ix.Root)
+		if err := ix.Save(dir); err != nil {
+			logger.Warn("failed to save index", "root", ix.Root, "error", err)
+		}
+	}
+	return result, result.Error
+}
+
+// runScript implements the script command, which runs the interface
+// without a terminal, starting in startDir, and drives it with the commands
+// of a script file or of standard input. Frames and selections the script
+// asks for are written to standard output. It returns the process exit code.
+func runScript(app *app.Application, startDir string, opts ui.Options, args []string) int {
+	fs := flag.NewFlagSet("script", flag.ContinueOnError)
+	size := fs.String("size", "80x24", "size of the simulated terminal, as COLUMNSxLINES")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: folder-search script [--size 80x24] [file]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return 2
+	}
+	var width, height int
+	if n, err := fmt.Sscanf(*size, "%dx%d", &width, &height); err != nil || n != 2 || width <= 0 || height <= 0 {
+		fmt.Fprintf(os.Stderr, "Error: invalid size %q: use COLUMNSxLINES, such as 80x24\n", *size)
+		return 2
+	}
+
+	script := io.Reader(os.Stdin)
+	if path := fs.Arg(0); path != "" && path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		script = f
+	}
+
+	d, err := ui.NewDriver(app, startDir, opts, width, height)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer d.Close()
+
+	if err := ui.RunScript(d, script, os.Stdout); err != nil {
+		app.Logger.Error("script failed", "error", err)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// runBugReport implements the bugreport command, which writes a bug report
+// to the given file ("-" for stdout) or to a new file in the current
+// directory, and returns the process exit code.
+//
+// The log records are taken from the log file if one is configured, so they
+// cover earlier sessions; otherwise only the records of this command itself
+// are known.
+func runBugReport(app *app.Application, args []string) int {
+	if len(args) > 1 {
+		fmt.Fprintln(os.Stderr, "Usage: folder-search bugreport [file]")
+		return 2
+	}
+
+	r := bugreport.Report{Version: version, Time: time.Now(), Config: app.Config, Logs: app.Logs.Records()}
+	if file := app.Config.Log.File; file != "" {
+		if lines, err := bugreport.TailFile(file, logging.DefaultRingSize); err != nil {
+			app.Logger.Warn("cannot read log file for bug report", "file", file, "error", err)
+		} else {
+			r.Logs = lines
+		}
+	}
+
+	var path string
+	var err error
+	switch {
+	case len(args) == 0:
+		path, err = r.Save(".")
+	case args[0] == "-":
+		err = r.Write(os.Stdout)
+	default:
+		path = args[0]
+		err = writeReportFile(path, r)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if path != "" {
+		fmt.Printf("bug report written to %s\n", path)
+	}
+	return 0
+}
+
+// writeReportFile writes r to the file at path, replacing it if it exists.
+func writeReportFile(path string, r bugreport.Report) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := r.Write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// readOptions returns the non-empty lines of r.
+func readOptions(r io.Reader) ([]string, error) {
+	var options []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if line := strings.TrimRight(scanner.Text(), "\r"); strings.TrimSpace(line) != "" {
+			options = append(options, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read options: %w", err)
+	}
+	return options, nil
+}
+
+// splitList returns the non-empty, trimmed elements of a comma-separated list.
+func splitList(s string) []string {
+	var items []string
+	for item := range strings.SplitSeq(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// isTerminal reports whether f is connected to a character device.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage: %s [options] [command]\n\n", filepath.Base(os.Args[0]))
+	fmt.Fprintln(out, "Commands:")
+	fmt.Fprintln(out, "  bench [root]         scan every directory under root and report throughput, peak memory and slow directories")
+	fmt.Fprintln(out, "  broken-links [root]  list symlinks whose targets no longer exist")
+	fmt.Fprintln(out, "  mcp                  serve directory search as an MCP tool server on stdio")
+	fmt.Fprintln(out, "  report --config FILE run saved searches and audits; see report -h")
+	fmt.Fprintln(out, "  index [root]         save an index of every directory under root (default: home) for find")
+	fmt.Fprintln(out, "  find PATTERN         search all directories below the current one using the saved index")
+	fmt.Fprintln(out, "  why PATH             explain which ignore rule hides PATH from listings")
+	fmt.Fprintln(out, "  stats                show local usage statistics")
+	fmt.Fprintln(out, "  features             list the features the experimental config section turns on or off")
+	fmt.Fprintln(out, "  bugreport [FILE]     write version, platform, redacted config and recent logs to FILE for an issue")
+	fmt.Fprintln(out, "  script [FILE]        drive the interface without a terminal with the commands in FILE (default: stdin)")
+	fmt.Fprintln(out, "\nOptions:")
+	flag.PrintDefaults()
 }
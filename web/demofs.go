@@ -0,0 +1,39 @@
+//go:build js && wasm
+
+package main
+
+import (
+	"io/fs"
+	"testing/fstest"
+)
+
+// demoDirs lists the directories of the demo tree.
+var demoDirs = []string{
+	"projects/folder-search/internal/dirsearch",
+	"projects/folder-search/internal/ui",
+	"projects/folder-search/internal/config",
+	"projects/folder-search/web",
+	"projects/folder-search/.git/objects",
+	"projects/website/src/components",
+	"projects/website/src/pages/blog",
+	"projects/website/public/images",
+	"projects/website/node_modules/react",
+	"projects/scripts/backup",
+	"documents/invoices/2025",
+	"documents/invoices/2026",
+	"documents/notes/meetings",
+	"photos/2025/holidays",
+	"photos/2026/birthday",
+	"downloads/archives",
+	".config/folder-search",
+	".config/nvim/lua",
+}
+
+// demoFS returns the in-memory tree the demo searches.
+func demoFS() fstest.MapFS {
+	fsys := fstest.MapFS{}
+	for _, dir := range demoDirs {
+		fsys[dir] = &fstest.MapFile{Mode: 0755 | fs.ModeDir}
+	}
+	return fsys
+}
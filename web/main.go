@@ -0,0 +1,124 @@
+//go:build js && wasm
+
+// Command web is a browser demo of folder-search: the directory search runs
+// as WebAssembly against an in-memory tree and a simplified picker is drawn
+// in an xterm.js terminal by index.html.
+//
+// The page passes key presses to folderSearchInput and receives screen
+// updates through folderSearchWrite, both globals on window.
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"syscall/js"
+
+	"github.com/kaczmarekdaniel/folder-search/internal/dirsearch"
+)
+
+// visibleResults is the number of results the picker shows at once.
+const visibleResults = 15
+
+// picker holds the state of the demo picker.
+type picker struct {
+	opts    *dirsearch.Options
+	query   string
+	results []string
+	cursor  int
+	picked  string // Last selected path; empty if none
+}
+
+// newPicker returns a picker searching the demo tree.
+func newPicker() *picker {
+	opts := dirsearch.DefaultOptions()
+	opts.FS = demoFS()
+	opts.MaxDepth = dirsearch.UnlimitedDepth
+	opts.Fuzzy = true
+	p := &picker{opts: opts}
+	p.search()
+	return p
+}
+
+// search reruns the search for the current query.
+func (p *picker) search() {
+	p.opts.SearchPattern = p.query
+	result := dirsearch.SearchContext(context.Background(), p.opts)
+	p.results = result.Directories
+	p.cursor = 0
+}
+
+// input handles the bytes xterm.js reports for a key press.
+func (p *picker) input(data string) {
+	switch data {
+	case "\x1b[A", "\x10": // Up, ctrl+p
+		p.cursor = max(p.cursor-1, 0)
+	case "\x1b[B", "\x0e": // Down, ctrl+n
+		p.cursor = min(p.cursor+1, max(len(p.results)-1, 0))
+	case "\r":
+		if p.cursor < len(p.results) {
+			p.picked = p.results[p.cursor]
+		}
+	case "\x7f", "\b":
+		if p.query != "" {
+			runes := []rune(p.query)
+			p.query = string(runes[:len(runes)-1])
+			p.search()
+		}
+	case "\x15": // ctrl+u
+		p.query = ""
+		p.search()
+	default:
+		if strings.HasPrefix(data, "\x1b") || strings.ContainsFunc(data, func(r rune) bool { return r < ' ' }) {
+			return
+		}
+		p.query += data
+		p.search()
+	}
+}
+
+// view renders the whole screen.
+func (p *picker) view() string {
+	var b strings.Builder
+	b.WriteString("\x1b[H\x1b[2J")
+	fmt.Fprintf(&b, "\x1b[1mfolder-search\x1b[0m  \x1b[2m%d matches\x1b[0m\r\n\r\n", len(p.results))
+
+	start := max(p.cursor-visibleResults+1, 0)
+	end := min(start+visibleResults, len(p.results))
+	for i := start; i < end; i++ {
+		if i == p.cursor {
+			fmt.Fprintf(&b, "\x1b[7m> %s\x1b[0m\r\n", p.results[i])
+		} else {
+			fmt.Fprintf(&b, "  %s\r\n", p.results[i])
+		}
+	}
+	for i := end - start; i < visibleResults; i++ {
+		b.WriteString("\r\n")
+	}
+
+	b.WriteString("\r\n")
+	if p.picked != "" {
+		fmt.Fprintf(&b, "\x1b[32mselected %s\x1b[0m\r\n", p.picked)
+	} else {
+		b.WriteString("\x1b[2m↑/↓ move · enter select · ctrl+u clear\x1b[0m\r\n")
+	}
+	fmt.Fprintf(&b, "\r\nsearch: %s", p.query)
+	return b.String()
+}
+
+func main() {
+	p := newPicker()
+	write := js.Global().Get("folderSearchWrite")
+
+	js.Global().Set("folderSearchInput", js.FuncOf(func(this js.Value, args []js.Value) any {
+		if len(args) > 0 {
+			p.input(args[0].String())
+		}
+		write.Invoke(p.view())
+		return nil
+	}))
+	write.Invoke(p.view())
+
+	// Keep the callbacks alive for the lifetime of the page
+	select {}
+}